@@ -64,6 +64,7 @@ func commandMirrorTraffic(cmd *cobra.Command, args []string) error {
 		Workflow:    BaseOptions.Workflow,
 		TabletTypes: MirrorTrafficOptions.TabletTypes,
 		Percent:     MirrorTrafficOptions.Percent,
+		Compare:     MirrorTrafficOptions.Compare,
 	}
 	resp, err := GetClient().WorkflowMirrorTraffic(GetCommandCtx(), req)
 	if err != nil {
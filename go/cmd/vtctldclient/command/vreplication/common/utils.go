@@ -309,6 +309,7 @@ var MirrorTrafficOptions = struct {
 	DryRun      bool
 	Percent     float32
 	TabletTypes []topodatapb.TabletType
+	Compare     bool
 }{}
 
 var SwitchTrafficOptions = struct {
@@ -84,6 +84,7 @@ func registerCommands(root *cobra.Command) {
 	mirrorTrafficCommand := common.GetMirrorTrafficCommand(opts)
 	mirrorTrafficCommand.Flags().Var((*topoproto.TabletTypeListFlag)(&common.MirrorTrafficOptions.TabletTypes), "tablet-types", "Tablet types to mirror traffic for.")
 	mirrorTrafficCommand.Flags().Float32Var(&common.MirrorTrafficOptions.Percent, "percent", 1.0, "Percentage of traffic to mirror.")
+	mirrorTrafficCommand.Flags().BoolVar(&common.MirrorTrafficOptions.Compare, "compare", false, "Compare the results of mirrored queries with those of the source keyspace, and report any differences.")
 	base.AddCommand(mirrorTrafficCommand)
 
 	switchTrafficCommand := common.GetSwitchTrafficCommand(opts)
@@ -69,6 +69,8 @@ var (
 		MaxDiffDuration             time.Duration
 		RowDiffColumnTruncateAt     int64
 		AutoStart                   bool
+		Checksum                    bool
+		SamplePct                   int64
 	}{}
 
 	deleteOptions = struct {
@@ -127,6 +129,9 @@ var (
 		if createOptions.MaxExtraRowsToCompare < 0 {
 			return fmt.Errorf("--max-extra-rows-to-compare must not be a negative value")
 		}
+		if createOptions.SamplePct < 1 || createOptions.SamplePct > 100 {
+			return fmt.Errorf("--sample-pct must be between 1 and 100")
+		}
 		return nil
 	}
 
@@ -300,6 +305,8 @@ func commandCreate(cmd *cobra.Command, args []string) error {
 		MaxDiffDuration:             protoutil.DurationToProto(createOptions.MaxDiffDuration),
 		RowDiffColumnTruncateAt:     createOptions.RowDiffColumnTruncateAt,
 		AutoStart:                   &createOptions.AutoStart,
+		Checksum:                    createOptions.Checksum,
+		SamplePct:                   createOptions.SamplePct,
 	})
 
 	if err != nil {
@@ -683,6 +690,8 @@ func registerCommands(root *cobra.Command) {
 	create.Flags().DurationVar(&createOptions.MaxDiffDuration, "max-diff-duration", 0, "How long should an individual table diff run before being stopped and restarted in order to lessen the impact on tablets due to holding open database snapshots for long periods of time (0 is the default and means no time limit).")
 	create.Flags().Int64Var(&createOptions.RowDiffColumnTruncateAt, "row-diff-column-truncate-at", 128, "When showing row differences, truncate the non Primary Key column values to this length. A value less than 1 means do not truncate.")
 	create.Flags().BoolVar(&createOptions.AutoStart, "auto-start", true, "Start the vdiff upon creation. When false, the vdiff will be created but will not run until resumed.")
+	create.Flags().BoolVar(&createOptions.Checksum, "checksum", false, "Compare checksums of primary key ranges on the source and target, and only diff the rows of the ranges whose checksums differ. The replication of the source tablets, which must be replicas, is stopped while each table is diffed.")
+	create.Flags().Int64Var(&createOptions.SamplePct, "sample-pct", 100, "The percentage of primary key ranges to diff, picked at random. Only a sample of the rows is compared when less than 100.")
 	base.AddCommand(create)
 
 	base.AddCommand(delete)
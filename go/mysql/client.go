@@ -174,7 +174,7 @@ func Connect(ctx context.Context, params *ConnParams) (*Conn, error) {
 // Ping implements mysql ping command.
 func (c *Conn) Ping() error {
 	// This is a new command, need to reset the sequence.
-	c.sequence = 0
+	c.resetSequence()
 	data, pos := c.startEphemeralPacketWithHeader(1)
 	data[pos] = ComPing
 
@@ -306,6 +306,15 @@ func (c *Conn) clientHandshake(params *ConnParams) error {
 		return err
 	}
 
+	// The packets that follow the OK packet are compressed, if
+	// the server supports the algorithm we asked for.
+	switch {
+	case c.Capabilities&CapabilityClientCompress != 0:
+		c.enableCompression(CompressionZlib, 0)
+	case c.Capabilities&CapabilityClientZstdCompressionAlgorithm != 0:
+		c.enableCompression(CompressionZstd, defaultZstdCompressionLevel)
+	}
+
 	// If the server didn't support DbName in its handshake, set
 	// it now. This is what the 'mysql' client does.
 	if capabilities&CapabilityClientConnectWithDB == 0 && params.DbName != "" {
@@ -558,6 +567,17 @@ func (c *Conn) writeHandshakeResponse41(capabilities uint32, scrambledPassword [
 		length += lenNullString(params.DbName)
 	}
 
+	// Ask for compression if the server supports the algorithm.
+	// The zstd compression level is added at the end of the packet.
+	switch {
+	case params.Compression == CompressionZlib && capabilities&CapabilityClientCompress != 0:
+		capabilityFlags |= CapabilityClientCompress
+	case params.Compression == CompressionZstd && capabilities&CapabilityClientZstdCompressionAlgorithm != 0:
+		capabilityFlags |= CapabilityClientZstdCompressionAlgorithm
+		length++
+	}
+	c.Capabilities |= capabilityFlags & (CapabilityClientCompress | CapabilityClientZstdCompressionAlgorithm)
+
 	if capabilities&CapabilityClientPluginAuthLenencClientData != 0 {
 		length += lenEncIntSize(uint64(len(scrambledPassword)))
 	} else {
@@ -600,6 +620,10 @@ func (c *Conn) writeHandshakeResponse41(capabilities uint32, scrambledPassword [
 	// Assume native client during response
 	pos = writeNullString(data, pos, string(c.authPluginName))
 
+	if capabilityFlags&CapabilityClientZstdCompressionAlgorithm != 0 {
+		pos = writeByte(data, pos, defaultZstdCompressionLevel)
+	}
+
 	// Sanity-check the length.
 	if pos != len(data) {
 		return sqlerror.NewSQLErrorf(sqlerror.CRMalformedPacket, sqlerror.SSUnknownSQLState, "writeHandshakeResponse41: only packed %v bytes, out of %v allocated", pos, len(data))
@@ -0,0 +1,320 @@
+/*
+Copyright 2025 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package mysql
+
+import (
+	"bytes"
+	"io"
+	"sync"
+
+	"github.com/klauspost/compress/zlib"
+	"github.com/klauspost/compress/zstd"
+
+	vtrpcpb "vitess.io/vitess/go/vt/proto/vtrpc"
+	"vitess.io/vitess/go/vt/vterrors"
+)
+
+// This file implements the compressed protocol:
+// https://dev.mysql.com/doc/dev/mysql-server/latest/page_protocol_basic_compression.html
+//
+// Once the handshake negotiated compression, the packets are sent inside
+// compressed packets, which have their own header and sequence. A compressed
+// packet can hold several packets, or only a part of a packet.
+
+// Compression algorithms of the compressed protocol.
+const (
+	// CompressionZlib is negotiated with CapabilityClientCompress.
+	CompressionZlib = "zlib"
+
+	// CompressionZstd is negotiated with CapabilityClientZstdCompressionAlgorithm.
+	CompressionZstd = "zstd"
+)
+
+const (
+	// compressedHeaderSize is the size of the header of a compressed packet:
+	// the length of the compressed payload on 3 bytes, the sequence on
+	// 1 byte, and the length of the payload before compression on 3 bytes.
+	compressedHeaderSize = 7
+
+	// minCompressLength is the size under which payloads are sent
+	// uncompressed, as MySQL does.
+	minCompressLength = 50
+
+	// defaultZstdCompressionLevel is the zstd level a client asks for.
+	defaultZstdCompressionLevel = 3
+)
+
+var (
+	zlibWriters = sync.Pool{New: func() any {
+		w, _ := zlib.NewWriterLevel(nil, zlib.DefaultCompression)
+		return w
+	}}
+
+	zlibReaders sync.Pool
+
+	// zstdEncoders holds a *zstd.Encoder per zstd.EncoderLevel. The
+	// encoders and the decoder are safe for concurrent use with
+	// EncodeAll and DecodeAll.
+	zstdEncoders sync.Map
+
+	zstdDecoder = sync.OnceValues(func() (*zstd.Decoder, error) {
+		return zstd.NewReader(nil, zstd.WithDecoderConcurrency(0), zstd.WithDecoderMaxMemory(MaxPacketSize))
+	})
+)
+
+// IsValidCompressionAlgorithm returns true if algorithm can be used
+// for the compressed protocol.
+func IsValidCompressionAlgorithm(algorithm string) bool {
+	return algorithm == CompressionZlib || algorithm == CompressionZstd
+}
+
+// compressedConn reads and writes the compressed packets of a Conn.
+// It is set as the reader and writer of the packets once the
+// compressed protocol is in use.
+type compressedConn struct {
+	c         *Conn
+	algorithm string
+	level     int
+
+	// reader is where the compressed packets are read from.
+	reader io.Reader
+
+	// sequence is the sequence of the compressed packets. It is reset
+	// with the sequence of the packets at the start of each command.
+	sequence uint8
+
+	// writing is true if the last compressed packet was written
+	// rather than read.
+	writing bool
+
+	// pending is the part of the last read payload that has not been
+	// consumed yet.
+	pending []byte
+
+	readBuf     []byte
+	inflatedBuf []byte
+	writeBuf    []byte
+}
+
+// enableCompression switches the connection to the compressed protocol.
+// It is called on both sides once the server sent the OK packet that
+// ends the handshake.
+func (c *Conn) enableCompression(algorithm string, level int) {
+	c.compression = &compressedConn{
+		c:         c,
+		algorithm: algorithm,
+		level:     level,
+		reader:    c.getReader(),
+	}
+}
+
+// Compression returns the compression algorithm in use on the
+// connection, or an empty string if it is not compressed.
+func (c *Conn) Compression() string {
+	if c.compression == nil {
+		return ""
+	}
+	return c.compression.algorithm
+}
+
+// startRead and startWrite keep the sequence of the packets in line with
+// the sequence of the compressed packets when the direction of the
+// exchange changes, as MySQL does when it flushes compressed packets.
+func (cc *compressedConn) startRead() {
+	if cc.writing {
+		cc.writing = false
+		cc.c.sequence = cc.sequence
+	}
+}
+
+func (cc *compressedConn) startWrite() {
+	if !cc.writing {
+		cc.writing = true
+		cc.c.sequence = cc.sequence
+	}
+}
+
+// Read is part of the io.Reader interface.
+func (cc *compressedConn) Read(data []byte) (int, error) {
+	for len(cc.pending) == 0 {
+		if err := cc.readCompressedPacket(); err != nil {
+			return 0, err
+		}
+	}
+	n := copy(data, cc.pending)
+	cc.pending = cc.pending[n:]
+	return n, nil
+}
+
+func (cc *compressedConn) readCompressedPacket() error {
+	var header [compressedHeaderSize]byte
+	if _, err := io.ReadFull(cc.reader, header[:]); err != nil {
+		// The error is returned as is, so the callers can tell
+		// when the connection is closed.
+		return err
+	}
+
+	if sequence := header[3]; sequence != cc.sequence {
+		return vterrors.Errorf(vtrpcpb.Code_INTERNAL, "invalid compressed packet sequence, expected %v got %v", cc.sequence, sequence)
+	}
+	cc.sequence++
+
+	length := int(uint32(header[0]) | uint32(header[1])<<8 | uint32(header[2])<<16)
+	uncompressedLength := int(uint32(header[4]) | uint32(header[5])<<8 | uint32(header[6])<<16)
+
+	if cap(cc.readBuf) < length {
+		cc.readBuf = make([]byte, length)
+	}
+	payload := cc.readBuf[:length]
+	if _, err := io.ReadFull(cc.reader, payload); err != nil {
+		return vterrors.Wrapf(err, "io.ReadFull(compressed packet body of length %v) failed", length)
+	}
+
+	// The payload was sent uncompressed.
+	if uncompressedLength == 0 {
+		cc.pending = payload
+		return nil
+	}
+
+	if cap(cc.inflatedBuf) < uncompressedLength {
+		cc.inflatedBuf = make([]byte, uncompressedLength)
+	}
+	inflated, err := cc.decompress(cc.inflatedBuf[:0], payload, uncompressedLength)
+	if err != nil {
+		return vterrors.Wrapf(err, "cannot decompress %v packet", cc.algorithm)
+	}
+	if len(inflated) != uncompressedLength {
+		return vterrors.Errorf(vtrpcpb.Code_INTERNAL, "decompressed packet has length %v, expected %v", len(inflated), uncompressedLength)
+	}
+	cc.pending = inflated
+	return nil
+}
+
+func (cc *compressedConn) decompress(dst, payload []byte, uncompressedLength int) ([]byte, error) {
+	if cc.algorithm == CompressionZstd {
+		decoder, err := zstdDecoder()
+		if err != nil {
+			return nil, err
+		}
+		return decoder.DecodeAll(payload, dst)
+	}
+
+	var zr io.ReadCloser
+	if r := zlibReaders.Get(); r != nil {
+		zr = r.(io.ReadCloser)
+		if err := zr.(zlib.Resetter).Reset(bytes.NewReader(payload), nil); err != nil {
+			return nil, err
+		}
+	} else {
+		var err error
+		if zr, err = zlib.NewReader(bytes.NewReader(payload)); err != nil {
+			return nil, err
+		}
+	}
+	defer zlibReaders.Put(zr)
+
+	dst = dst[:uncompressedLength]
+	n, err := io.ReadFull(zr, dst)
+	return dst[:n], err
+}
+
+// Write is part of the io.Writer interface. It sends data in as many
+// compressed packets as needed.
+func (cc *compressedConn) Write(data []byte) (int, error) {
+	written := 0
+	for len(data) > 0 {
+		payload := data[:min(len(data), MaxPacketSize)]
+		if err := cc.writeCompressedPacket(payload); err != nil {
+			return written, err
+		}
+		written += len(payload)
+		data = data[len(payload):]
+	}
+	return written, nil
+}
+
+func (cc *compressedConn) writeCompressedPacket(payload []byte) error {
+	packet := append(cc.writeBuf[:0], make([]byte, compressedHeaderSize)...)
+
+	// Small payloads, and the ones compression does not make
+	// smaller, are sent as is.
+	uncompressedLength := 0
+	if len(payload) >= minCompressLength {
+		compressed, err := cc.compress(packet, payload)
+		if err != nil {
+			return vterrors.Wrapf(err, "cannot compress %v packet", cc.algorithm)
+		}
+		if len(compressed)-compressedHeaderSize < len(payload) {
+			packet = compressed
+			uncompressedLength = len(payload)
+		}
+	}
+	if uncompressedLength == 0 {
+		packet = append(packet[:compressedHeaderSize], payload...)
+	}
+
+	length := len(packet) - compressedHeaderSize
+	packet[0] = byte(length)
+	packet[1] = byte(length >> 8)
+	packet[2] = byte(length >> 16)
+	packet[3] = cc.sequence
+	packet[4] = byte(uncompressedLength)
+	packet[5] = byte(uncompressedLength >> 8)
+	packet[6] = byte(uncompressedLength >> 16)
+	cc.writeBuf = packet
+
+	if n, err := cc.c.conn.Write(packet); err != nil {
+		return vterrors.Wrapf(err, "Write(compressed packet) failed")
+	} else if n != len(packet) {
+		return vterrors.Errorf(vtrpcpb.Code_INTERNAL, "Write(compressed packet) returned a short write: %v < %v", n, len(packet))
+	}
+	cc.sequence++
+	return nil
+}
+
+// compress appends the compressed payload to dst.
+func (cc *compressedConn) compress(dst, payload []byte) ([]byte, error) {
+	if cc.algorithm == CompressionZstd {
+		return zstdEncoder(cc.level).EncodeAll(payload, dst), nil
+	}
+
+	buf := bytes.NewBuffer(dst)
+	zw := zlibWriters.Get().(*zlib.Writer)
+	defer zlibWriters.Put(zw)
+	zw.Reset(buf)
+	if _, err := zw.Write(payload); err != nil {
+		return nil, err
+	}
+	if err := zw.Close(); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+func zstdEncoder(level int) *zstd.Encoder {
+	encoderLevel := zstd.EncoderLevelFromZstd(level)
+	if encoder, ok := zstdEncoders.Load(encoderLevel); ok {
+		return encoder.(*zstd.Encoder)
+	}
+	// NewWriter only fails on invalid options.
+	encoder, _ := zstd.NewWriter(nil, zstd.WithEncoderLevel(encoderLevel))
+	actual, loaded := zstdEncoders.LoadOrStore(encoderLevel, encoder)
+	if loaded {
+		encoder.Close()
+	}
+	return actual.(*zstd.Encoder)
+}
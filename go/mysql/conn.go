@@ -166,6 +166,10 @@ type Conn struct {
 	// PrepareData is the map to use a prepared statement.
 	PrepareData map[uint32]*PrepareData
 
+	// cursors are the cursors open on the prepared statements, by
+	// statement ID. See cursor.go.
+	cursors map[uint32]*cursor
+
 	// protects the bufferedWriter and bufferedReader
 	bufMu sync.Mutex
 
@@ -209,6 +213,14 @@ type Conn struct {
 	// Packet encoding variables.
 	sequence uint8
 
+	// compression reads and writes the compressed packets once the
+	// handshake negotiated the compressed protocol. It is nil otherwise.
+	compression *compressedConn
+
+	// zstdCompressionLevel is the zstd level the client asked for in
+	// its handshake. It is only used by the server.
+	zstdCompressionLevel int
+
 	// ExpectSemiSyncIndicator is applicable when the connection is used for replication (ComBinlogDump).
 	// When 'true', events are assumed to be padded with 2-byte semi-sync information
 	// See https://dev.mysql.com/doc/internals/en/semi-sync-binlog-event.html
@@ -242,6 +254,9 @@ type PrepareData struct {
 	BindVars    map[string]*querypb.BindVariable
 	StatementID uint32
 	ParamsCount uint16
+	// CursorType is the cursor type the statement is executed with.
+	// The handler can use it to stream the rows of a cursor.
+	CursorType byte
 }
 
 // execResult is an enum signifying the result of executing a query
@@ -338,7 +353,7 @@ func (c *Conn) startWriterBuffering() {
 	defer c.bufMu.Unlock()
 
 	c.bufferedWriter = writersPool.Get().(*bufio.Writer)
-	c.bufferedWriter.Reset(c.conn)
+	c.bufferedWriter.Reset(c.getWriter())
 }
 
 // endWriterBuffering must be called to terminate startWriteBuffering.
@@ -360,6 +375,19 @@ func (c *Conn) endWriterBuffering() error {
 	return c.bufferedWriter.Flush()
 }
 
+// flush writes the buffered data to the connection right away.
+// It is used when the server needs an answer from the client
+// before the current command is done.
+func (c *Conn) flush() error {
+	c.bufMu.Lock()
+	defer c.bufMu.Unlock()
+
+	if c.bufferedWriter == nil {
+		return nil
+	}
+	return c.bufferedWriter.Flush()
+}
+
 func (c *Conn) returnReader() {
 	if c.bufferedReader == nil {
 		return
@@ -386,15 +414,41 @@ func (c *Conn) startFlushTimer() {
 }
 
 // getReader returns reader for connection. It can be *bufio.Reader or net.Conn
-// depending on which buffer size was passed to newServerConn.
+// depending on which buffer size was passed to newServerConn, or the
+// compressed packets reader if the connection uses compression.
 func (c *Conn) getReader() io.Reader {
+	if c.compression != nil {
+		return c.compression
+	}
 	if c.bufferedReader != nil {
 		return c.bufferedReader
 	}
 	return c.conn
 }
 
+// getWriter returns the unbuffered writer for connection. It is the
+// compressed packets writer if the connection uses compression.
+func (c *Conn) getWriter() io.Writer {
+	if c.compression != nil {
+		return c.compression
+	}
+	return c.conn
+}
+
+// resetSequence resets the sequence of the packets at the start of
+// a new command.
+func (c *Conn) resetSequence() {
+	c.sequence = 0
+	if c.compression != nil {
+		c.compression.sequence = 0
+	}
+}
+
 func (c *Conn) readHeaderFrom(r io.Reader) (int, error) {
+	if c.compression != nil {
+		c.compression.startRead()
+	}
+
 	// Note io.ReadFull will return two different types of errors:
 	// 1. if the socket is already closed, and the go runtime knows it,
 	//   then ReadFull will return an error (different than EOF),
@@ -615,6 +669,10 @@ func (c *Conn) writePacket(data []byte) error {
 	index := 0
 	dataLength := len(data) - packetHeaderSize
 
+	if c.compression != nil {
+		c.compression.startWrite()
+	}
+
 	var w io.Writer
 
 	c.bufMu.Lock()
@@ -626,7 +684,7 @@ func (c *Conn) writePacket(data []byte) error {
 		}()
 	} else {
 		c.bufMu.Unlock()
-		w = c.conn
+		w = c.getWriter()
 	}
 
 	var header [packetHeaderSize]byte
@@ -730,7 +788,7 @@ func (c *Conn) recycleWritePacket() {
 // Returns SQLError(CRServerGone) if it can't.
 func (c *Conn) writeComQuit() error {
 	// This is a new command, need to reset the sequence.
-	c.sequence = 0
+	c.resetSequence()
 
 	data, pos := c.startEphemeralPacketWithHeader(1)
 	data[pos] = ComQuit
@@ -903,7 +961,7 @@ func (c *Conn) writeEOFPacket(flags uint16, warnings uint16) error {
 // handleNextCommand is called in the server loop to process
 // incoming packets.
 func (c *Conn) handleNextCommand(handler Handler) bool {
-	c.sequence = 0
+	c.resetSequence()
 	data, err := c.readEphemeralPacket()
 	if err != nil {
 		// Don't log EOF errors. They cause too much spam.
@@ -948,10 +1006,13 @@ func (c *Conn) handleNextCommand(handler Handler) bool {
 		stmtID, ok := c.parseComStmtClose(data)
 		c.recycleReadPacket()
 		if ok {
+			c.closeCursor(stmtID)
 			delete(c.PrepareData, stmtID)
 		}
 	case ComStmtReset:
 		return c.handleComStmtReset(data)
+	case ComStmtFetch:
+		return c.handleComStmtFetch(handler, data)
 	case ComResetConnection:
 		c.handleComResetConnection(handler)
 		return true
@@ -1046,6 +1107,7 @@ func (c *Conn) handleComBinlogDumpGTID(handler Handler, data []byte) (kontinue b
 func (c *Conn) handleComResetConnection(handler Handler) {
 	// Clean up and reset the connection
 	c.recycleReadPacket()
+	c.closeCursors()
 	handler.ComResetConnection(c)
 	// Reset prepared statements
 	c.PrepareData = make(map[uint32]*PrepareData)
@@ -1073,6 +1135,8 @@ func (c *Conn) handleComStmtReset(data []byte) bool {
 		}
 	}
 
+	c.closeCursor(stmtID)
+
 	if prepare.BindVars != nil {
 		for k := range prepare.BindVars {
 			prepare.BindVars[k] = nil
@@ -1125,7 +1189,7 @@ func (c *Conn) handleComStmtExecute(handler Handler, data []byte) (kontinue bool
 		}
 	}()
 	queryStart := time.Now()
-	stmtID, _, err := c.parseComStmtExecute(c.PrepareData, data)
+	stmtID, cursorType, err := c.parseComStmtExecute(c.PrepareData, data)
 	c.recycleReadPacket()
 
 	if stmtID != uint32(0) {
@@ -1140,10 +1204,21 @@ func (c *Conn) handleComStmtExecute(handler Handler, data []byte) (kontinue bool
 		return c.writeErrorPacketFromErrorAndLog(err)
 	}
 
+	// Executing the statement again closes its cursor.
+	c.closeCursor(stmtID)
+
+	prepare := c.PrepareData[stmtID]
+	if cursorType&CursorTypeReadOnly != 0 {
+		if !c.executeWithCursor(handler, stmtID, prepare) {
+			return false
+		}
+		timings.Record(queryTimingKey, queryStart)
+		return true
+	}
+
 	receivedResult := false
 	// sendFinished is set if the response should just be an OK packet.
 	sendFinished := false
-	prepare := c.PrepareData[stmtID]
 	err = handler.ComStmtExecute(c, prepare, func(qr *sqltypes.Result) error {
 		if sendFinished {
 			// Failsafe: Unreachable if server is well-behaved.
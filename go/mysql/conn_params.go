@@ -64,6 +64,11 @@ type ConnParams struct {
 	// FlushDelay is the delay after which buffered response will be flushed to the client.
 	FlushDelay time.Duration
 
+	// Compression is the algorithm of the compressed protocol to use,
+	// zlib or zstd. The protocol is not compressed if it is empty, or
+	// if the server does not support the algorithm.
+	Compression string
+
 	TruncateErrLen int
 }
 
@@ -76,15 +76,17 @@ const (
 	// CLIENT_NO_SCHEMA 1 << 4
 	// Do not permit database.table.column. We do permit it.
 
-	// CLIENT_COMPRESS 1 << 5
-	// We do not support compression. CPU is usually our bottleneck.
+	// CapabilityClientCompress is CLIENT_COMPRESS.
+	// Use the compressed protocol with zlib, after the handshake.
+	CapabilityClientCompress = 1 << 5
 
 	// CLIENT_ODBC 1 << 6
 	// No special behavior since 3.22.
 
-	// CLIENT_LOCAL_FILES 1 << 7
+	// CapabilityClientLocalFiles is CLIENT_LOCAL_FILES.
 	// Client can use LOCAL INFILE request of LOAD DATA|XML.
-	// We do not set it.
+	// The server side uses it for LOAD DATA LOCAL INFILE.
+	CapabilityClientLocalFiles = 1 << 7
 
 	// CLIENT_IGNORE_SPACE 1 << 8
 	// Parser can ignore spaces before '('.
@@ -148,6 +150,14 @@ const (
 	// CapabilityClientDeprecateEOF is CLIENT_DEPRECATE_EOF
 	// Expects an OK (instead of EOF) after the resultset rows of a Text Resultset.
 	CapabilityClientDeprecateEOF = 1 << 24
+
+	// CLIENT_OPTIONAL_RESULTSET_METADATA 1 << 25
+	// Not supported.
+
+	// CapabilityClientZstdCompressionAlgorithm is CLIENT_ZSTD_COMPRESSION_ALGORITHM
+	// Use the compressed protocol with zstd, after the handshake.
+	// The client sends the compression level it wants in its handshake response.
+	CapabilityClientZstdCompressionAlgorithm = 1 << 26
 )
 
 // Status flags. They are returned by the server in a few cases.
@@ -257,6 +267,10 @@ const (
 
 	// NullValue is the encoded value of NULL.
 	NullValue = 0xfb
+
+	// LocalInfilePacket is the header of the packet the server sends
+	// to request a file from the client for LOAD DATA LOCAL INFILE.
+	LocalInfilePacket = 0xfb
 )
 
 // Auth packet types
@@ -274,6 +288,16 @@ const (
 	AuthSwitchRequestPacket = 0xfe
 )
 
+// Cursor types of COM_STMT_EXECUTE.
+const (
+	// CursorTypeNoCursor executes the statement without a cursor.
+	CursorTypeNoCursor = 0x00
+
+	// CursorTypeReadOnly opens a read-only cursor, the rows are
+	// then returned by COM_STMT_FETCH.
+	CursorTypeReadOnly = 0x01
+)
+
 var typeInt24, _ = sqltypes.TypeToMySQL(sqltypes.Int24)
 var typeTimestamp, _ = sqltypes.TypeToMySQL(sqltypes.Timestamp)
 var typeYear, _ = sqltypes.TypeToMySQL(sqltypes.Year)
@@ -0,0 +1,282 @@
+/*
+Copyright 2025 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package mysql
+
+import (
+	"errors"
+
+	"vitess.io/vitess/go/mysql/sqlerror"
+	"vitess.io/vitess/go/sqltypes"
+	"vitess.io/vitess/go/vt/log"
+	querypb "vitess.io/vitess/go/vt/proto/query"
+	vtrpcpb "vitess.io/vitess/go/vt/proto/vtrpc"
+	"vitess.io/vitess/go/vt/vterrors"
+)
+
+// This file implements the server-side cursors:
+// https://dev.mysql.com/doc/dev/mysql-server/latest/page_protocol_com_stmt_fetch.html
+//
+// A COM_STMT_EXECUTE with CURSOR_TYPE_READ_ONLY only returns the columns
+// of the result set, with SERVER_STATUS_CURSOR_EXISTS set. The rows are
+// then returned in batches by COM_STMT_FETCH, until the last one is sent
+// with SERVER_STATUS_LAST_ROW_SENT.
+
+// errCursorClosed is returned to the handler when the cursor is closed
+// before the execution is done.
+var errCursorClosed = errors.New("cursor closed")
+
+// cursor keeps the execution of a prepared statement open between the
+// COM_STMT_FETCH commands. The execution runs in its own goroutine, which
+// is only resumed when the connection needs more rows: the execution and
+// the connection never run at the same time.
+type cursor struct {
+	fields []*querypb.Field
+
+	// rows are the rows received from the execution that were not
+	// sent yet.
+	rows [][]sqltypes.Value
+
+	results chan *sqltypes.Result
+	resume  chan bool
+	done    chan error
+
+	// waiting is set when the execution waits to be resumed, and
+	// finished once it returned.
+	waiting  bool
+	finished bool
+}
+
+// openCursor starts the execution of a prepared statement with
+// a cursor.
+func (c *Conn) openCursor(handler Handler, prepare *PrepareData) *cursor {
+	cur := &cursor{
+		results: make(chan *sqltypes.Result),
+		resume:  make(chan bool),
+		done:    make(chan error, 1),
+	}
+
+	// The handler gets its own copy of the statement, as the bind
+	// variables of the statement are reset once the command is done.
+	stmt := *prepare
+	stmt.CursorType = CursorTypeReadOnly
+	go func() {
+		closed := false
+		cur.done <- handler.ComStmtExecute(c, &stmt, func(qr *sqltypes.Result) error {
+			if closed {
+				return errCursorClosed
+			}
+			cur.results <- qr
+			if !<-cur.resume {
+				closed = true
+				return errCursorClosed
+			}
+			return nil
+		})
+	}()
+	return cur
+}
+
+// next resumes the execution until it returns a result. It returns
+// a nil result once the execution is done.
+func (cur *cursor) next() (*sqltypes.Result, error) {
+	if cur.finished {
+		return nil, nil
+	}
+	if cur.waiting {
+		cur.waiting = false
+		cur.resume <- true
+	}
+	select {
+	case qr := <-cur.results:
+		cur.waiting = true
+		return qr, nil
+	case err := <-cur.done:
+		cur.finished = true
+		return nil, err
+	}
+}
+
+// close stops the execution if it is not done yet.
+func (cur *cursor) close() {
+	if cur.waiting {
+		cur.waiting = false
+		cur.resume <- false
+		<-cur.done
+		cur.finished = true
+	}
+}
+
+// closeCursor closes the cursor of a statement, if it has one.
+func (c *Conn) closeCursor(stmtID uint32) {
+	if cur, ok := c.cursors[stmtID]; ok {
+		cur.close()
+		delete(c.cursors, stmtID)
+		cursorCount.Add(-1)
+	}
+}
+
+// closeCursors closes all the cursors of the connection.
+func (c *Conn) closeCursors() {
+	for stmtID := range c.cursors {
+		c.closeCursor(stmtID)
+	}
+}
+
+// executeWithCursor executes a prepared statement with a read-only
+// cursor. If the statement returns a result set, only its columns are
+// sent and the cursor is kept open for COM_STMT_FETCH.
+func (c *Conn) executeWithCursor(handler Handler, stmtID uint32, prepare *PrepareData) bool {
+	if c.listener != nil && c.listener.MaxOpenCursors > 0 && len(c.cursors) >= c.listener.MaxOpenCursors {
+		err := vterrors.Errorf(vtrpcpb.Code_RESOURCE_EXHAUSTED, "too many open cursors on the connection: %d", len(c.cursors))
+		return c.writeErrorPacketFromErrorAndLog(err)
+	}
+
+	cur := c.openCursor(handler, prepare)
+	qr, err := cur.next()
+	if err != nil || qr == nil {
+		if err == nil {
+			// This is just a failsafe. Should never happen.
+			err = sqlerror.NewSQLErrorFromError(errors.New("unexpected: query ended without no results and no error"))
+		}
+		return c.writeErrorPacketFromErrorAndLog(err)
+	}
+
+	// Statements without a result set have nothing to fetch, they
+	// are run to completion.
+	if len(qr.Fields) == 0 {
+		for {
+			next, err := cur.next()
+			if err != nil {
+				return c.writeErrorPacketFromErrorAndLog(err)
+			}
+			if next == nil {
+				break
+			}
+		}
+		if err := c.writeOKPacket(&PacketOK{
+			affectedRows:     qr.RowsAffected,
+			lastInsertID:     qr.InsertID,
+			statusFlags:      c.StatusFlags,
+			sessionStateData: qr.SessionStateChanges,
+		}); err != nil {
+			log.Errorf("Error writing result to %s: %v", c, err)
+			return false
+		}
+		return true
+	}
+
+	cur.fields = qr.Fields
+	cur.rows = qr.Rows
+	if c.cursors == nil {
+		c.cursors = make(map[uint32]*cursor)
+	}
+	c.cursors[stmtID] = cur
+	cursorCount.Add(1)
+
+	if err := c.sendColumnCount(uint64(len(cur.fields))); err != nil {
+		log.Errorf("Error writing result to %s: %v", c, err)
+		return false
+	}
+	for _, field := range cur.fields {
+		if err := c.writeColumnDefinition(field); err != nil {
+			log.Errorf("Error writing result to %s: %v", c, err)
+			return false
+		}
+	}
+	// The columns of a cursor are always followed by the status, even
+	// with CapabilityClientDeprecateEOF.
+	if err := c.writeCursorStatus(c.StatusFlags|ServerStatusCursorExists, 0); err != nil {
+		log.Errorf("Error writing result to %s: %v", c, err)
+		return false
+	}
+	return true
+}
+
+func (c *Conn) handleComStmtFetch(handler Handler, data []byte) (kontinue bool) {
+	c.startWriterBuffering()
+	defer func() {
+		if err := c.endWriterBuffering(); err != nil {
+			log.Errorf("conn %v: flush() failed: %v", c.ID(), err)
+			kontinue = false
+		}
+	}()
+
+	stmtID, numRows, ok := c.parseComStmtFetch(data)
+	c.recycleReadPacket()
+	if !ok {
+		err := sqlerror.NewSQLError(sqlerror.CRMalformedPacket, sqlerror.SSUnknownSQLState, "error parsing statement fetch")
+		return c.writeErrorPacketFromErrorAndLog(err)
+	}
+
+	cur, ok := c.cursors[stmtID]
+	if !ok {
+		err := sqlerror.NewSQLErrorf(sqlerror.ERStmtHasNoOpenCursor, sqlerror.SSUnknownSQLState, "The statement (%d) has no open cursor.", stmtID)
+		return c.writeErrorPacketFromErrorAndLog(err)
+	}
+
+	sent := uint32(0)
+	for sent < numRows {
+		if len(cur.rows) == 0 {
+			qr, err := cur.next()
+			if err != nil {
+				c.closeCursor(stmtID)
+				if sent == 0 {
+					return c.writeErrorPacketFromErrorAndLog(err)
+				}
+				// We can't send an error in the middle of a stream.
+				// All we can do is abort the send, which will cause a 2013.
+				log.Errorf("Error in the middle of a stream to %s: %v", c, err)
+				return false
+			}
+			if qr == nil {
+				break
+			}
+			cur.rows = qr.Rows
+			continue
+		}
+
+		if err := c.writeBinaryRow(cur.fields, cur.rows[0]); err != nil {
+			log.Errorf("Error writing result to %s: %v", c, err)
+			return false
+		}
+		cur.rows = cur.rows[1:]
+		sent++
+	}
+
+	flags := c.StatusFlags | ServerStatusCursorExists
+	if cur.finished && len(cur.rows) == 0 {
+		flags |= ServerStatusLastRowSent
+		c.closeCursor(stmtID)
+	}
+	if err := c.writeCursorStatus(flags, handler.WarningCount(c)); err != nil {
+		log.Errorf("Error writing result to %s: %v", c, err)
+		return false
+	}
+	return true
+}
+
+// writeCursorStatus sends the status of a cursor, after its columns
+// or after a batch of rows.
+func (c *Conn) writeCursorStatus(flags uint16, warnings uint16) error {
+	if c.Capabilities&CapabilityClientDeprecateEOF == 0 {
+		return c.writeEOFPacket(flags, warnings)
+	}
+	return c.writeOKPacketWithEOFHeader(&PacketOK{
+		statusFlags: flags,
+		warnings:    warnings,
+	})
+}
@@ -0,0 +1,282 @@
+/*
+Copyright 2025 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package mysql
+
+import (
+	"encoding/binary"
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"vitess.io/vitess/go/mysql/sqlerror"
+	"vitess.io/vitess/go/sqltypes"
+	querypb "vitess.io/vitess/go/vt/proto/query"
+)
+
+// cursorHandler streams the fields of a result, and then its rows
+// in batches of two.
+type cursorHandler struct {
+	testRun
+	result *sqltypes.Result
+
+	cursorType  byte
+	executions  int
+	callbackErr error
+}
+
+func (h *cursorHandler) ComStmtExecute(c *Conn, prepare *PrepareData, callback func(*sqltypes.Result) error) error {
+	h.executions++
+	h.cursorType = prepare.CursorType
+	h.callbackErr = nil
+	if len(h.result.Fields) == 0 {
+		return callback(h.result)
+	}
+	if err := callback(&sqltypes.Result{Fields: h.result.Fields}); err != nil {
+		h.callbackErr = err
+		return err
+	}
+	for rows := h.result.Rows; len(rows) > 0; {
+		batch := rows[:min(2, len(rows))]
+		rows = rows[len(batch):]
+		if err := callback(&sqltypes.Result{Fields: h.result.Fields, Rows: batch}); err != nil {
+			h.callbackErr = err
+			return err
+		}
+	}
+	return nil
+}
+
+func cursorResult(rows int) *sqltypes.Result {
+	result := &sqltypes.Result{
+		Fields: []*querypb.Field{{Name: "id", Type: querypb.Type_INT64}},
+	}
+	for i := range rows {
+		result.Rows = append(result.Rows, []sqltypes.Value{sqltypes.NewInt64(int64(i))})
+	}
+	return result
+}
+
+func writeCursorCommand(t *testing.T, cConn *Conn, command byte, stmtID uint32, arg ...byte) {
+	data := make([]byte, packetHeaderSize+5, packetHeaderSize+5+len(arg))
+	data[packetHeaderSize] = command
+	binary.LittleEndian.PutUint32(data[packetHeaderSize+1:], stmtID)
+	data = append(data, arg...)
+	cConn.sequence = 0
+	require.NoError(t, cConn.writePacket(data))
+}
+
+func executeWithCursorType(t *testing.T, cConn *Conn, stmtID uint32, cursorType byte) {
+	writeCursorCommand(t, cConn, ComStmtExecute, stmtID, cursorType, 1, 0, 0, 0)
+}
+
+func fetch(t *testing.T, cConn *Conn, stmtID uint32, numRows uint32) {
+	writeCursorCommand(t, cConn, ComStmtFetch, stmtID, binary.LittleEndian.AppendUint32(nil, numRows)...)
+}
+
+// readCursorRows reads binary rows until the EOF packet, and returns
+// their count and the status flags.
+func readCursorRows(t *testing.T, cConn *Conn) (int, uint16) {
+	rows := 0
+	for {
+		data, err := cConn.ReadPacket()
+		require.NoError(t, err)
+		if cConn.isEOFPacket(data) {
+			_, flags, err := parseEOFPacket(data)
+			require.NoError(t, err)
+			return rows, flags
+		}
+		require.EqualValues(t, OKPacket, data[0], "unexpected packet: %v", data)
+		rows++
+	}
+}
+
+func TestCursorFetch(t *testing.T) {
+	listener, sConn, cConn := createSocketPair(t)
+	defer func() {
+		listener.Close()
+		sConn.Close()
+		cConn.Close()
+	}()
+
+	sConn.PrepareData[1] = &PrepareData{StatementID: 1, PrepareStmt: "select id from t", BindVars: map[string]*querypb.BindVariable{}}
+	handler := &cursorHandler{result: cursorResult(5)}
+
+	// The execution only returns the columns.
+	executeWithCursorType(t, cConn, 1, CursorTypeReadOnly)
+	require.True(t, sConn.handleNextCommand(handler))
+	assert.EqualValues(t, CursorTypeReadOnly, handler.cursorType)
+
+	data, err := cConn.ReadPacket()
+	require.NoError(t, err)
+	assert.Equal(t, []byte{1}, data)
+	_, err = cConn.ReadPacket()
+	require.NoError(t, err)
+	rows, flags := readCursorRows(t, cConn)
+	assert.Zero(t, rows)
+	assert.NotZero(t, flags&ServerStatusCursorExists)
+
+	for _, tcase := range []struct {
+		numRows     uint32
+		rows        int
+		lastRowSent bool
+	}{
+		{numRows: 1, rows: 1},
+		{numRows: 3, rows: 3},
+		{numRows: 3, rows: 1, lastRowSent: true},
+	} {
+		fetch(t, cConn, 1, tcase.numRows)
+		require.True(t, sConn.handleNextCommand(handler))
+		rows, flags := readCursorRows(t, cConn)
+		assert.Equal(t, tcase.rows, rows)
+		assert.NotZero(t, flags&ServerStatusCursorExists)
+		assert.Equal(t, tcase.lastRowSent, flags&ServerStatusLastRowSent != 0)
+	}
+	assert.NoError(t, handler.callbackErr)
+	assert.Empty(t, sConn.cursors)
+
+	// The cursor is closed once the last row is sent.
+	fetch(t, cConn, 1, 1)
+	require.True(t, sConn.handleNextCommand(handler))
+	data, err = cConn.ReadPacket()
+	require.NoError(t, err)
+	var sqlErr *sqlerror.SQLError
+	require.ErrorAs(t, ParseErrorPacket(data), &sqlErr)
+	assert.Equal(t, sqlerror.ERStmtHasNoOpenCursor, sqlErr.Number())
+}
+
+func TestCursorClose(t *testing.T) {
+	listener, sConn, cConn := createSocketPair(t)
+	defer func() {
+		listener.Close()
+		sConn.Close()
+		cConn.Close()
+	}()
+
+	sConn.PrepareData[1] = &PrepareData{StatementID: 1, PrepareStmt: "select id from t", BindVars: map[string]*querypb.BindVariable{}}
+	handler := &cursorHandler{result: cursorResult(5)}
+
+	openCursor := func() {
+		executeWithCursorType(t, cConn, 1, CursorTypeReadOnly)
+		require.True(t, sConn.handleNextCommand(handler))
+		for range 3 {
+			_, err := cConn.ReadPacket()
+			require.NoError(t, err)
+		}
+		require.Contains(t, sConn.cursors, uint32(1))
+	}
+
+	for _, tcase := range []struct {
+		name  string
+		close func()
+	}{{
+		name: "execute",
+		close: func() {
+			executeWithCursorType(t, cConn, 1, CursorTypeNoCursor)
+			require.True(t, sConn.handleNextCommand(handler))
+			for range 2 {
+				_, err := cConn.ReadPacket()
+				require.NoError(t, err)
+			}
+			_, _ = readCursorRows(t, cConn)
+			rows, _ := readCursorRows(t, cConn)
+			assert.Equal(t, 5, rows)
+		},
+	}, {
+		name: "reset",
+		close: func() {
+			writeCursorCommand(t, cConn, ComStmtReset, 1)
+			require.True(t, sConn.handleNextCommand(handler))
+			_, err := cConn.ReadPacket()
+			require.NoError(t, err)
+		},
+	}, {
+		name: "close",
+		close: func() {
+			writeCursorCommand(t, cConn, ComStmtClose, 1)
+			require.True(t, sConn.handleNextCommand(handler))
+		},
+	}} {
+		t.Run(tcase.name, func(t *testing.T) {
+			openCursor()
+			tcase.close()
+			assert.Empty(t, sConn.cursors)
+			if tcase.name != "execute" {
+				assert.ErrorIs(t, handler.callbackErr, errCursorClosed)
+			}
+		})
+	}
+}
+
+func TestCursorWithoutResultSet(t *testing.T) {
+	listener, sConn, cConn := createSocketPair(t)
+	defer func() {
+		listener.Close()
+		sConn.Close()
+		cConn.Close()
+	}()
+
+	sConn.PrepareData[1] = &PrepareData{StatementID: 1, PrepareStmt: "update t set id = 1", BindVars: map[string]*querypb.BindVariable{}}
+	handler := &cursorHandler{result: &sqltypes.Result{RowsAffected: 3}}
+
+	executeWithCursorType(t, cConn, 1, CursorTypeReadOnly)
+	require.True(t, sConn.handleNextCommand(handler))
+	data, err := cConn.ReadPacket()
+	require.NoError(t, err)
+	var ok PacketOK
+	require.NoError(t, cConn.parseOKPacket(&ok, data))
+	assert.EqualValues(t, 3, ok.affectedRows)
+	assert.Empty(t, sConn.cursors)
+}
+
+func TestCursorLimit(t *testing.T) {
+	listener, sConn, cConn := createSocketPair(t)
+	defer func() {
+		listener.Close()
+		sConn.Close()
+		cConn.Close()
+	}()
+
+	sConn.listener = &Listener{MaxOpenCursors: 2}
+	openCursors := cursorCount.Get()
+	handler := &cursorHandler{result: cursorResult(5)}
+	for stmtID := uint32(1); stmtID <= 3; stmtID++ {
+		sConn.PrepareData[stmtID] = &PrepareData{StatementID: stmtID, PrepareStmt: fmt.Sprintf("select %d from t", stmtID), BindVars: map[string]*querypb.BindVariable{}}
+		executeWithCursorType(t, cConn, stmtID, CursorTypeReadOnly)
+		require.True(t, sConn.handleNextCommand(handler))
+
+		data, err := cConn.ReadPacket()
+		require.NoError(t, err)
+		if stmtID == 3 {
+			require.EqualValues(t, ErrPacket, data[0])
+			assert.ErrorContains(t, ParseErrorPacket(data), "too many open cursors")
+			break
+		}
+		for range 2 {
+			_, err := cConn.ReadPacket()
+			require.NoError(t, err)
+		}
+	}
+	assert.Len(t, sConn.cursors, 2)
+	assert.Equal(t, 2, handler.executions)
+	assert.EqualValues(t, openCursors+2, cursorCount.Get())
+
+	sConn.closeCursors()
+	assert.Empty(t, sConn.cursors)
+	assert.EqualValues(t, openCursors, cursorCount.Get())
+}
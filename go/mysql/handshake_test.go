@@ -17,8 +17,11 @@ limitations under the License.
 package mysql
 
 import (
+	"bytes"
 	"context"
+	"crypto/rand"
 	"crypto/tls"
+	"fmt"
 	"net"
 	"path"
 	"strings"
@@ -28,8 +31,10 @@ import (
 	"github.com/stretchr/testify/require"
 
 	"vitess.io/vitess/go/mysql/collations"
+	"vitess.io/vitess/go/sqltypes"
 	"vitess.io/vitess/go/test/utils"
 
+	querypb "vitess.io/vitess/go/vt/proto/query"
 	"vitess.io/vitess/go/vt/tlstest"
 	"vitess.io/vitess/go/vt/vttls"
 )
@@ -187,3 +192,130 @@ func testSSLConnectionBasics(t *testing.T, ctx context.Context, params *ConnPara
 	// Send a ComQuit to avoid the error message on the server side.
 	conn.writeComQuit()
 }
+
+// TestCompressedProtocol connects a client and a server that use the
+// compressed protocol, and sends results larger than MaxPacketSize.
+func TestCompressedProtocol(t *testing.T) {
+	ctx := utils.LeakCheckContext(t)
+
+	large := make([]byte, MaxPacketSize+1000)
+	_, err := rand.Read(large[:len(large)/2])
+	require.NoError(t, err)
+	result := &sqltypes.Result{
+		Fields: []*querypb.Field{{
+			Name:    "value",
+			Type:    querypb.Type_VARBINARY,
+			Charset: collations.CollationBinaryID,
+		}},
+	}
+	for i := 0; i < 1000; i++ {
+		result.Rows = append(result.Rows, []sqltypes.Value{sqltypes.NewVarBinary(fmt.Sprintf("row %d", i))})
+	}
+	result.Rows = append(result.Rows, []sqltypes.Value{sqltypes.MakeTrusted(querypb.Type_VARBINARY, large)})
+
+	testcases := []struct {
+		name        string
+		algorithms  []string
+		compression string
+		want        string
+	}{{
+		name:        "zlib",
+		algorithms:  []string{CompressionZlib, CompressionZstd},
+		compression: CompressionZlib,
+		want:        CompressionZlib,
+	}, {
+		name:        "zstd",
+		algorithms:  []string{CompressionZlib, CompressionZstd},
+		compression: CompressionZstd,
+		want:        CompressionZstd,
+	}, {
+		// The protocol is not compressed if the server does not
+		// allow the algorithm the client asks for.
+		name:        "unsupported",
+		algorithms:  []string{CompressionZlib},
+		compression: CompressionZstd,
+		want:        "",
+	}}
+	for _, tcase := range testcases {
+		t.Run(tcase.name, func(t *testing.T) {
+			th := &testHandler{}
+
+			authServer := NewAuthServerStatic("", "", 0)
+			authServer.entries["user1"] = []*AuthServerStaticEntry{{
+				Password: "password1",
+			}}
+			defer authServer.close()
+
+			l, err := NewListener("tcp", "127.0.0.1:", authServer, th, 0, 0, false, false, 0, 0)
+			require.NoError(t, err, "NewListener failed: %v", err)
+			defer l.Close()
+			l.CompressionAlgorithms = tcase.algorithms
+			host, port := getHostPort(t, l.Addr())
+			params := &ConnParams{
+				Host:        host,
+				Port:        port,
+				Uname:       "user1",
+				Pass:        "password1",
+				Compression: tcase.compression,
+			}
+			go l.Accept()
+			defer cleanupListener(ctx, l, params)
+
+			conn, err := Connect(ctx, params)
+			require.NoError(t, err)
+			defer conn.Close()
+			assert.Equal(t, tcase.want, conn.Compression())
+
+			qr, err := conn.ExecuteFetch("compression echo", 1, false)
+			require.NoError(t, err)
+			assert.Equal(t, tcase.want, qr.Rows[0][0].ToString())
+
+			th.mu.Lock()
+			th.result = result
+			th.mu.Unlock()
+
+			// Several commands in a row, to check that the
+			// sequences are reset at the start of each one.
+			for i := 0; i < 3; i++ {
+				qr, err := conn.ExecuteFetch("select large", 10000, true)
+				require.NoError(t, err)
+				require.Len(t, qr.Rows, len(result.Rows))
+				assert.Equal(t, "row 999", qr.Rows[999][0].ToString())
+				assert.True(t, bytes.Equal(large, qr.Rows[1000][0].Raw()))
+				require.NoError(t, conn.Ping())
+			}
+
+			// Send a ComQuit to avoid the error message on the server side.
+			conn.writeComQuit()
+		})
+	}
+}
+
+// TestLocalInfileCapability tests that the server only advertises
+// CLIENT_LOCAL_FILES when the listener allows LOAD DATA LOCAL INFILE.
+func TestLocalInfileCapability(t *testing.T) {
+	for _, allow := range []bool{false, true} {
+		t.Run(fmt.Sprintf("allow=%v", allow), func(t *testing.T) {
+			authServer := NewAuthServerStatic("", "", 0)
+			defer authServer.close()
+
+			l, err := NewListener("tcp", "127.0.0.1:", authServer, &testHandler{}, 0, 0, false, false, 0, 0)
+			require.NoError(t, err)
+			defer l.Close()
+			l.AllowLocalInfile = allow
+			go l.Accept()
+
+			netConn, err := net.Dial("tcp", l.Addr().String())
+			require.NoError(t, err)
+			conn := newConn(netConn, 0, 0)
+			defer conn.Close()
+
+			data, err := conn.readEphemeralPacket()
+			require.NoError(t, err)
+			capabilities, _, err := conn.parseInitialHandshakePacket(data)
+			conn.recycleReadPacket()
+			require.NoError(t, err)
+			assert.Equal(t, allow, capabilities&CapabilityClientLocalFiles != 0)
+		})
+	}
+}
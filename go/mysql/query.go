@@ -58,7 +58,7 @@ const (
 // Returns SQLError(CRServerGone) if it can't.
 func (c *Conn) WriteComQuery(query string) error {
 	// This is a new command, need to reset the sequence.
-	c.sequence = 0
+	c.resetSequence()
 
 	data, pos := c.startEphemeralPacketWithHeader(len(query) + 1)
 	data[pos] = ComQuery
@@ -584,6 +584,43 @@ func (c *Conn) parseComQuery(data []byte) string {
 	return string(data[1:])
 }
 
+// ReadLocalInfile requests the contents of a file from the client, as part of
+// executing a LOAD DATA LOCAL INFILE statement. The client sends the file in as
+// many packets as it needs, followed by an empty packet. Every packet is passed to
+// the callback. If the callback fails, the rest of the file is still read and
+// discarded, so the connection can be used for the response.
+func (c *Conn) ReadLocalInfile(filename string, callback func(data []byte) error) error {
+	if c.Capabilities&CapabilityClientLocalFiles == 0 {
+		return sqlerror.NewSQLError(sqlerror.ERNotAllowedCommand, sqlerror.SSClientError, "Loading local data is disabled; this must be enabled on both the client and server sides")
+	}
+
+	data, pos := c.startEphemeralPacketWithHeader(len(filename) + 1)
+	data[pos] = LocalInfilePacket
+	copy(data[pos+1:], filename)
+	if err := c.writeEphemeralPacket(); err != nil {
+		return sqlerror.NewSQLErrorf(sqlerror.CRServerGone, sqlerror.SSUnknownSQLState, "%v", err)
+	}
+	if err := c.flush(); err != nil {
+		return sqlerror.NewSQLErrorf(sqlerror.CRServerGone, sqlerror.SSUnknownSQLState, "%v", err)
+	}
+
+	var cbErr error
+	for {
+		// The file contents are not split on packet boundaries, so each packet
+		// is handled on its own. An empty packet marks the end of the file.
+		packet, err := c.readOnePacket()
+		if err != nil {
+			return sqlerror.NewSQLErrorf(sqlerror.CRServerLost, sqlerror.SSUnknownSQLState, "%v", err)
+		}
+		if len(packet) == 0 {
+			return cbErr
+		}
+		if cbErr == nil {
+			cbErr = callback(packet)
+		}
+	}
+}
+
 func (c *Conn) parseComSetOption(data []byte) (uint16, bool) {
 	val, _, ok := readUint16(data, 1)
 	return val, ok
@@ -957,6 +994,15 @@ func (c *Conn) parseComStmtReset(data []byte) (uint32, bool) {
 	return val, ok
 }
 
+func (c *Conn) parseComStmtFetch(data []byte) (uint32, uint32, bool) {
+	stmtID, pos, ok := readUint32(data, 1)
+	if !ok {
+		return 0, 0, false
+	}
+	numRows, _, ok := readUint32(data, pos)
+	return stmtID, numRows, ok
+}
+
 func (c *Conn) parseComInitDB(data []byte) string {
 	return string(data[1:])
 }
@@ -350,6 +350,43 @@ func TestComStmtClose(t *testing.T) {
 
 }
 
+func TestReadLocalInfile(t *testing.T) {
+	listener, sConn, cConn := createSocketPair(t)
+	defer func() {
+		listener.Close()
+		sConn.Close()
+		cConn.Close()
+	}()
+
+	// The client has to enable local files for the server to request them.
+	err := sConn.ReadLocalInfile("data.csv", func([]byte) error { return nil })
+	require.ErrorContains(t, err, "Loading local data is disabled")
+
+	sConn.Capabilities |= CapabilityClientLocalFiles
+
+	wg := sync.WaitGroup{}
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		data, err := cConn.ReadPacket()
+		assert.NoError(t, err)
+		assert.Equal(t, append([]byte{LocalInfilePacket}, "data.csv"...), data)
+
+		useWritePacket(t, cConn, []byte("1,a\n2,"))
+		useWritePacket(t, cConn, []byte("b\n"))
+		useWritePacket(t, cConn, nil)
+	}()
+
+	var got []byte
+	err = sConn.ReadLocalInfile("data.csv", func(data []byte) error {
+		got = append(got, data...)
+		return nil
+	})
+	wg.Wait()
+	require.NoError(t, err)
+	require.Equal(t, "1,a\n2,b\n", string(got))
+}
+
 // This test has been added to verify that IO errors in a connection lead to SQL Server lost errors
 // So that we end up closing the connection higher up the stack and not reusing it.
 // This test was added in response to a panic that was run into.
@@ -41,7 +41,7 @@ func (c *Conn) WriteComBinlogDump(serverID uint32, binlogFilename string, binlog
 	if binlogPos > math.MaxUint32 {
 		return vterrors.Errorf(vtrpc.Code_INVALID_ARGUMENT, "binlog position %d is too large, it must fit into 32 bits", binlogPos)
 	}
-	c.sequence = 0
+	c.resetSequence()
 	length := 1 + // ComBinlogDump
 		4 + // binlog-pos
 		2 + // flags
@@ -83,7 +83,7 @@ func (c *Conn) AnalyzeSemiSyncAckRequest(buf []byte) (strippedBuf []byte, ackReq
 // See http://dev.mysql.com/doc/internals/en/com-binlog-dump-gtid.html for syntax.
 // sidBlock must be the result of a gtidSet.SIDBlock() function.
 func (c *Conn) WriteComBinlogDumpGTID(serverID uint32, binlogFilename string, binlogPos uint64, flags uint16, sidBlock []byte) error {
-	c.sequence = 0
+	c.resetSequence()
 	length := 1 + // ComBinlogDumpGTID
 		2 + // flags
 		4 + // server-id
@@ -111,7 +111,7 @@ func (c *Conn) WriteComBinlogDumpGTID(serverID uint32, binlogFilename string, bi
 // the source has tagged with a SEMI_SYNC_ACK_REQ
 // see https://dev.mysql.com/doc/internals/en/semi-sync-ack-packet.html
 func (c *Conn) SendSemiSyncAck(binlogFilename string, binlogPos uint64) error {
-	c.sequence = 0
+	c.resetSequence()
 	length := 1 + // ComSemiSyncAck
 		8 + // binlog-pos
 		len(binlogFilename) // binlog-filename
@@ -59,11 +59,12 @@ const (
 
 var (
 	// Metrics
-	timings    = stats.NewTimings("MysqlServerTimings", "MySQL server timings", "operation")
-	connCount  = stats.NewGauge("MysqlServerConnCount", "Active MySQL server connections")
-	connAccept = stats.NewCounter("MysqlServerConnAccepted", "Connections accepted by MySQL server")
-	connRefuse = stats.NewCounter("MysqlServerConnRefused", "Connections refused by MySQL server")
-	connSlow   = stats.NewCounter("MysqlServerConnSlow", "Connections that took more than the configured mysql-slow-connect-warn-threshold to establish")
+	timings     = stats.NewTimings("MysqlServerTimings", "MySQL server timings", "operation")
+	connCount   = stats.NewGauge("MysqlServerConnCount", "Active MySQL server connections")
+	connAccept  = stats.NewCounter("MysqlServerConnAccepted", "Connections accepted by MySQL server")
+	connRefuse  = stats.NewCounter("MysqlServerConnRefused", "Connections refused by MySQL server")
+	connSlow    = stats.NewCounter("MysqlServerConnSlow", "Connections that took more than the configured mysql-slow-connect-warn-threshold to establish")
+	cursorCount = stats.NewGauge("MysqlServerCursorCount", "Open server-side cursors")
 
 	connCountByTLSVer = stats.NewGaugesWithSingleLabel("MysqlServerConnCountByTLSVer", "Active MySQL server connections by TLS version", "tls")
 	connCountPerUser  = stats.NewGaugesWithSingleLabel("MysqlServerConnCountPerUser", "Active MySQL server connections per user", "count")
@@ -185,6 +186,19 @@ type Listener struct {
 	// beyond which a warning is logged to identify the slow connection
 	SlowConnectWarnThreshold atomic.Int64
 
+	// CompressionAlgorithms are the algorithms of the compressed protocol
+	// that the clients can ask for. The protocol is never compressed if
+	// it is empty.
+	CompressionAlgorithms []string
+
+	// MaxOpenCursors is the number of cursors a connection can have
+	// open at the same time. There is no limit if it is zero.
+	MaxOpenCursors int
+
+	// AllowLocalInfile makes the listener advertise CLIENT_LOCAL_FILES, so
+	// that the clients can send files for LOAD DATA LOCAL INFILE.
+	AllowLocalInfile bool
+
 	// The following parameters are changed by the Accept routine.
 
 	// Incrementing ID for connection id.
@@ -389,12 +403,14 @@ func (l *Listener) handle(conn net.Conn, connectionID uint32, acceptTime time.Ti
 	// Tell the handler about the connection coming and going.
 	l.handler.NewConnection(c)
 	defer l.handler.ConnectionClosed(c)
+	// The executions of the open cursors are stopped first.
+	defer c.closeCursors()
 
 	// Adjust the count of open connections
 	defer connCount.Add(-1)
 
 	// First build and send the server handshake packet.
-	serverAuthPluginData, err := c.writeHandshakeV10(l.ServerVersion, l.authServer, uint8(l.charset), l.TLSConfig.Load() != nil)
+	serverAuthPluginData, err := c.writeHandshakeV10(l.ServerVersion, l.authServer, uint8(l.charset), l.TLSConfig.Load() != nil, l.optionalCapabilities())
 	if err != nil {
 		if err != io.EOF {
 			log.Errorf("Cannot send HandshakeV10 packet to %s: %v", c, err)
@@ -537,6 +553,15 @@ func (l *Listener) handle(conn net.Conn, connectionID uint32, acceptTime time.Ti
 		return
 	}
 
+	// The packets that follow the OK packet are compressed, if the
+	// client asked for it.
+	switch {
+	case c.Capabilities&CapabilityClientCompress != 0:
+		c.enableCompression(CompressionZlib, 0)
+	case c.Capabilities&CapabilityClientZstdCompressionAlgorithm != 0:
+		c.enableCompression(CompressionZstd, c.zstdCompressionLevel)
+	}
+
 	// Record how long we took to establish the connection
 	timings.Record(connectTimingKey, acceptTime)
 
@@ -573,9 +598,34 @@ func (l *Listener) Shutdown() {
 	}
 }
 
+// optionalCapabilities returns the capability flags the listener only
+// advertises when they are enabled.
+func (l *Listener) optionalCapabilities() uint32 {
+	capabilities := l.compressionCapabilities()
+	if l.AllowLocalInfile {
+		capabilities |= CapabilityClientLocalFiles
+	}
+	return capabilities
+}
+
+// compressionCapabilities returns the capability flags of the
+// compression algorithms the listener allows.
+func (l *Listener) compressionCapabilities() uint32 {
+	var capabilities uint32
+	for _, algorithm := range l.CompressionAlgorithms {
+		switch algorithm {
+		case CompressionZlib:
+			capabilities |= CapabilityClientCompress
+		case CompressionZstd:
+			capabilities |= CapabilityClientZstdCompressionAlgorithm
+		}
+	}
+	return capabilities
+}
+
 // writeHandshakeV10 writes the Initial Handshake Packet, server side.
 // It returns the salt data.
-func (c *Conn) writeHandshakeV10(serverVersion string, authServer AuthServer, charset uint8, enableTLS bool) ([]byte, error) {
+func (c *Conn) writeHandshakeV10(serverVersion string, authServer AuthServer, charset uint8, enableTLS bool, optionalCapabilities uint32) ([]byte, error) {
 	capabilities := CapabilityClientLongPassword |
 		CapabilityClientFoundRows |
 		CapabilityClientLongFlag |
@@ -592,6 +642,7 @@ func (c *Conn) writeHandshakeV10(serverVersion string, authServer AuthServer, ch
 	if enableTLS {
 		capabilities |= CapabilityClientSSL
 	}
+	capabilities |= int(optionalCapabilities)
 
 	// Grab the default auth method. This can only be either
 	// mysql_native_password or caching_sha2_password. Both
@@ -710,6 +761,9 @@ func (l *Listener) parseClientHandshakePacket(c *Conn, firstTime bool, data []by
 	// after SSL negotiation, do not overwrite capabilities.
 	if firstTime {
 		c.Capabilities = clientFlags & (CapabilityClientDeprecateEOF | CapabilityClientFoundRows)
+		if l.AllowLocalInfile {
+			c.Capabilities |= clientFlags & CapabilityClientLocalFiles
+		}
 	}
 
 	// set connection capability for executing multi statements
@@ -808,9 +862,28 @@ func (l *Listener) parseClientHandshakePacket(c *Conn, firstTime bool, data []by
 	}
 
 	// Decode connection attributes send by the client
+	attrsOK := true
 	if clientFlags&CapabilityClientConnAttr != 0 {
-		if _, _, err := parseConnAttrs(data, pos); err != nil {
+		var err error
+		if _, pos, err = parseConnAttrs(data, pos); err != nil {
 			log.Warningf("Decode connection attributes send by the client: %v", err)
+			attrsOK = false
+		}
+	}
+
+	// Remember the compression the client asked for, if we allow it.
+	// zlib wins if the client asked for both. The zstd compression
+	// level is the last byte of the packet.
+	compression := clientFlags & l.compressionCapabilities()
+	if compression&CapabilityClientCompress != 0 {
+		c.Capabilities |= CapabilityClientCompress
+	} else if compression&CapabilityClientZstdCompressionAlgorithm != 0 {
+		c.Capabilities |= CapabilityClientZstdCompressionAlgorithm
+		c.zstdCompressionLevel = defaultZstdCompressionLevel
+		if attrsOK {
+			if level, _, ok := readByte(data, pos); ok && level > 0 {
+				c.zstdCompressionLevel = int(level)
+			}
 		}
 	}
 
@@ -154,6 +154,21 @@ func (th *testHandler) ComQuery(c *Conn, query string, callback func(*sqltypes.R
 				},
 			},
 		})
+	case "compression echo":
+		callback(&sqltypes.Result{
+			Fields: []*querypb.Field{
+				{
+					Name:    "compression",
+					Type:    querypb.Type_VARCHAR,
+					Charset: uint32(collations.MySQL8().DefaultConnectionCharset()),
+				},
+			},
+			Rows: [][]sqltypes.Value{
+				{
+					sqltypes.MakeTrusted(querypb.Type_VARCHAR, []byte(c.Compression())),
+				},
+			},
+		})
 	case "ssl echo":
 		value := "OFF"
 		if c.Capabilities&CapabilityClientSSL > 0 {
@@ -121,6 +121,7 @@ const (
 	ERSPDoesNotExist                = ErrorCode(1305)
 	ERNoDefaultForField             = ErrorCode(1364)
 	ErSPNotVarArg                   = ErrorCode(1414)
+	ERStmtHasNoOpenCursor           = ErrorCode(1421)
 	ERRowIsReferenced2              = ErrorCode(1451)
 	ErNoReferencedRow2              = ErrorCode(1452)
 	ERInnodbIndexCorrupt            = ErrorCode(1817)
@@ -225,6 +226,7 @@ const (
 	ERDerivedMustHaveAlias         = ErrorCode(1248)
 	ERTableNameNotAllowedHere      = ErrorCode(1250)
 	ERCollationCharsetMismatch     = ErrorCode(1253)
+	ERCutValueGroupConcat          = ErrorCode(1260)
 	ERWarnDataTruncated            = ErrorCode(1265)
 	ERCantAggregate2Collations     = ErrorCode(1267)
 	ERCantAggregate3Collations     = ErrorCode(1270)
@@ -0,0 +1,88 @@
+/*
+Copyright 2025 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package opentelemetrybackend
+
+import (
+	"context"
+	"time"
+
+	"go.opentelemetry.io/otel/sdk/instrumentation"
+	"go.opentelemetry.io/otel/sdk/metric/metricdata"
+	"go.opentelemetry.io/otel/sdk/resource"
+
+	"vitess.io/vitess/go/stats"
+)
+
+// exporter sends the collected metrics somewhere. It is implemented by
+// the OTLP exporters.
+type exporter interface {
+	Export(ctx context.Context, rm *metricdata.ResourceMetrics) error
+	Shutdown(ctx context.Context) error
+}
+
+// backend implements stats.PushBackend
+type backend struct {
+	// The prefix is the name of the binary (vtgate, vttablet, etc.) and will be
+	// prepended to all the metric names, like the prometheus backend does.
+	prefix string
+	// resource describes the binary that reports the metrics.
+	resource *resource.Resource
+	// start is the start time of the cumulative counters and histograms.
+	start    time.Time
+	exporter exporter
+}
+
+func newBackend(prefix string, res *resource.Resource, exp exporter) *backend {
+	return &backend{
+		prefix:   prefix,
+		resource: res,
+		start:    time.Now(),
+		exporter: exp,
+	}
+}
+
+// PushAll pushes all stats to the OTLP collector
+func (b *backend) PushAll() error {
+	collector := b.collector()
+	collector.collectAll()
+	return b.export(collector)
+}
+
+// PushOne pushes a single stat to the OTLP collector
+func (b *backend) PushOne(name string, v stats.Variable) error {
+	collector := b.collector()
+	collector.collectOne(name, v)
+	return b.export(collector)
+}
+
+func (b *backend) collector() *collector {
+	return &collector{
+		prefix: b.prefix,
+		start:  b.start,
+		now:    time.Now(),
+	}
+}
+
+func (b *backend) export(collector *collector) error {
+	return b.exporter.Export(context.Background(), &metricdata.ResourceMetrics{
+		Resource: b.resource,
+		ScopeMetrics: []metricdata.ScopeMetrics{{
+			Scope:   instrumentation.Scope{Name: "vitess.io/vitess/go/stats"},
+			Metrics: collector.metrics,
+		}},
+	})
+}
@@ -0,0 +1,174 @@
+/*
+Copyright 2025 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package opentelemetrybackend
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/sdk/metric/metricdata"
+
+	"vitess.io/vitess/go/stats"
+)
+
+type fakeExporter struct {
+	exported []*metricdata.ResourceMetrics
+}
+
+func (e *fakeExporter) Export(_ context.Context, rm *metricdata.ResourceMetrics) error {
+	e.exported = append(e.exported, rm)
+	return nil
+}
+
+func (e *fakeExporter) Shutdown(context.Context) error {
+	return nil
+}
+
+// pushOne pushes a single stat, and returns the metric that was exported.
+func pushOne(t *testing.T, name string, v stats.Variable) metricdata.Metrics {
+	exp := &fakeExporter{}
+	b := newBackend("vtgate", newResource("vtgate", nil), exp)
+	require.NoError(t, b.PushOne(name, v))
+	require.Len(t, exp.exported, 1)
+	require.Len(t, exp.exported[0].ScopeMetrics, 1)
+	require.Len(t, exp.exported[0].ScopeMetrics[0].Metrics, 1)
+	return exp.exported[0].ScopeMetrics[0].Metrics[0]
+}
+
+func TestCounter(t *testing.T) {
+	c := stats.NewCounter("OtelCounter", "counter help")
+	c.Add(3)
+
+	m := pushOne(t, "OtelCounter", c)
+	assert.Equal(t, "vtgate_otel_counter", m.Name)
+	assert.Equal(t, "counter help", m.Description)
+	sum, ok := m.Data.(metricdata.Sum[int64])
+	require.True(t, ok)
+	assert.True(t, sum.IsMonotonic)
+	assert.Equal(t, metricdata.CumulativeTemporality, sum.Temporality)
+	require.Len(t, sum.DataPoints, 1)
+	assert.EqualValues(t, 3, sum.DataPoints[0].Value)
+	assert.Zero(t, sum.DataPoints[0].Attributes.Len())
+}
+
+func TestCountersWithMultiLabels(t *testing.T) {
+	c := stats.NewCountersWithMultiLabels("OtelCountersWithMultiLabels", "help", []string{"Keyspace", "TabletType"})
+	c.Add([]string{"ks", "primary"}, 2)
+	c.Add([]string{"ks", "replica"}, 5)
+
+	m := pushOne(t, "OtelCountersWithMultiLabels", c)
+	assert.Equal(t, "vtgate_otel_counters_with_multi_labels", m.Name)
+	sum, ok := m.Data.(metricdata.Sum[int64])
+	require.True(t, ok)
+	values := make(map[attribute.Set]int64)
+	for _, point := range sum.DataPoints {
+		values[point.Attributes] = point.Value
+	}
+	assert.Equal(t, map[attribute.Set]int64{
+		attribute.NewSet(attribute.String("keyspace", "ks"), attribute.String("tablet_type", "primary")): 2,
+		attribute.NewSet(attribute.String("keyspace", "ks"), attribute.String("tablet_type", "replica")): 5,
+	}, values)
+}
+
+func TestGauges(t *testing.T) {
+	g := stats.NewGauge("OtelGauge", "help")
+	g.Set(-4)
+	m := pushOne(t, "OtelGauge", g)
+	gauge, ok := m.Data.(metricdata.Gauge[int64])
+	require.True(t, ok)
+	require.Len(t, gauge.DataPoints, 1)
+	assert.EqualValues(t, -4, gauge.DataPoints[0].Value)
+
+	d := stats.NewGaugeDuration("OtelGaugeDuration", "help")
+	d.Set(1500 * time.Millisecond)
+	m = pushOne(t, "OtelGaugeDuration", d)
+	assert.Equal(t, "s", m.Unit)
+	floatGauge, ok := m.Data.(metricdata.Gauge[float64])
+	require.True(t, ok)
+	require.Len(t, floatGauge.DataPoints, 1)
+	assert.Equal(t, 1.5, floatGauge.DataPoints[0].Value)
+}
+
+func TestTimings(t *testing.T) {
+	timings := stats.NewTimings("OtelTimings", "help", "Category")
+	timings.Add("select", 2*time.Millisecond)
+	timings.Add("select", 20*time.Second)
+
+	m := pushOne(t, "OtelTimings", timings)
+	assert.Equal(t, "s", m.Unit)
+	histogram, ok := m.Data.(metricdata.Histogram[float64])
+	require.True(t, ok)
+	require.Len(t, histogram.DataPoints, 1)
+	point := histogram.DataPoints[0]
+	assert.Equal(t, attribute.NewSet(attribute.String("category", "select")), point.Attributes)
+	assert.EqualValues(t, 2, point.Count)
+	assert.InDelta(t, 20.002, point.Sum, 1e-9)
+	require.Len(t, point.BucketCounts, len(point.Bounds)+1)
+	assert.Equal(t, 0.0005, point.Bounds[0])
+	assert.EqualValues(t, 1, point.BucketCounts[2])
+	assert.EqualValues(t, 1, point.BucketCounts[len(point.Bounds)])
+}
+
+func TestHistogram(t *testing.T) {
+	h := stats.NewHistogram("OtelHistogram", "help", []int64{1, 5})
+	h.Add(1)
+	h.Add(3)
+	h.Add(10)
+
+	m := pushOne(t, "OtelHistogram", h)
+	histogram, ok := m.Data.(metricdata.Histogram[float64])
+	require.True(t, ok)
+	require.Len(t, histogram.DataPoints, 1)
+	point := histogram.DataPoints[0]
+	assert.Equal(t, []float64{1, 5}, point.Bounds)
+	assert.Equal(t, []uint64{1, 1, 1}, point.BucketCounts)
+	assert.EqualValues(t, 3, point.Count)
+	assert.EqualValues(t, 14, point.Sum)
+}
+
+func TestUnsupportedVariables(t *testing.T) {
+	b := newBackend("vtgate", newResource("vtgate", nil), &fakeExporter{})
+	collector := b.collector()
+	collector.collectOne("OtelString", stats.NewString("OtelString"))
+	collector.collectOne("OtelStringFunc", stats.StringFunc(func() string { return "value" }))
+	assert.Empty(t, collector.metrics)
+}
+
+func TestResource(t *testing.T) {
+	res := newResource("vttablet", map[string]string{
+		CellAttribute:        "zone1",
+		TabletAliasAttribute: "zone1-0000000100",
+		"empty":              "",
+	})
+	attrs := res.Set()
+	for key, want := range map[string]string{
+		"service.name":       "vttablet",
+		ComponentAttribute:   "vttablet",
+		CellAttribute:        "zone1",
+		TabletAliasAttribute: "zone1-0000000100",
+	} {
+		got, ok := attrs.Value(attribute.Key(key))
+		require.True(t, ok, key)
+		assert.Equal(t, want, got.AsString())
+	}
+	_, ok := attrs.Value("empty")
+	assert.False(t, ok)
+}
@@ -0,0 +1,247 @@
+/*
+Copyright 2025 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package opentelemetrybackend
+
+import (
+	"expvar"
+	"strings"
+	"time"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/sdk/metric/metricdata"
+
+	"vitess.io/vitess/go/stats"
+)
+
+// noLabels are the attributes of the stats without labels.
+var noLabels = *attribute.EmptySet()
+
+// collector tracks state for a single pass of stats reporting / data collection.
+type collector struct {
+	prefix string
+	// start is the start time of the cumulative metrics, and now is the
+	// time of this collection.
+	start   time.Time
+	now     time.Time
+	metrics []metricdata.Metrics
+}
+
+func (dc *collector) collectAll() {
+	expvar.Do(func(kv expvar.KeyValue) {
+		dc.addExpVar(kv)
+	})
+}
+
+func (dc *collector) collectOne(name string, v expvar.Var) {
+	dc.addExpVar(expvar.KeyValue{
+		Key:   name,
+		Value: v,
+	})
+}
+
+// addExpVar adds the metric of a stats variable. Counters are exported as
+// monotonic sums, gauges as gauges, and timings and histograms as explicit
+// bucket histograms. Durations and timings are exported in seconds.
+//
+// The variables that are not stats, or that have no numeric value, are not
+// exported.
+func (dc *collector) addExpVar(kv expvar.KeyValue) {
+	k := kv.Key
+	switch v := kv.Value.(type) {
+	case *stats.Counter:
+		dc.addSum(k, v.Help(), "", []metricdata.DataPoint[int64]{dc.intPoint(v.Get(), noLabels)})
+	case *stats.CounterFunc:
+		dc.addSum(k, v.Help(), "", []metricdata.DataPoint[int64]{dc.intPoint(v.F(), noLabels)})
+	case *stats.CounterDuration:
+		dc.addFloatSum(k, v.Help(), "s", []metricdata.DataPoint[float64]{dc.floatPoint(v.Get().Seconds(), noLabels)})
+	case *stats.CounterDurationFunc:
+		dc.addFloatSum(k, v.Help(), "s", []metricdata.DataPoint[float64]{dc.floatPoint(v.F().Seconds(), noLabels)})
+	case *stats.CountersWithSingleLabel:
+		var points []metricdata.DataPoint[int64]
+		for labelVal, val := range v.Counts() {
+			points = append(points, dc.intPoint(val, makeLabels([]string{v.Label()}, labelVal)))
+		}
+		dc.addSum(k, v.Help(), "", points)
+	case *stats.CountersWithMultiLabels:
+		var points []metricdata.DataPoint[int64]
+		for labelVals, val := range v.Counts() {
+			points = append(points, dc.intPoint(val, makeLabels(v.Labels(), labelVals)))
+		}
+		dc.addSum(k, v.Help(), "", points)
+	case *stats.CountersFuncWithMultiLabels:
+		var points []metricdata.DataPoint[int64]
+		for labelVals, val := range v.Counts() {
+			points = append(points, dc.intPoint(val, makeLabels(v.Labels(), labelVals)))
+		}
+		dc.addSum(k, v.Help(), "", points)
+	case *stats.Gauge:
+		dc.addGauge(k, v.Help(), "", []metricdata.DataPoint[int64]{dc.intPoint(v.Get(), noLabels)})
+	case *stats.GaugeFunc:
+		dc.addGauge(k, v.Help(), "", []metricdata.DataPoint[int64]{dc.intPoint(v.F(), noLabels)})
+	case *stats.GaugeFloat64:
+		dc.addFloatGauge(k, v.Help(), "", []metricdata.DataPoint[float64]{dc.floatPoint(v.Get(), noLabels)})
+	case stats.FloatFunc:
+		dc.addFloatGauge(k, v.Help(), "", []metricdata.DataPoint[float64]{dc.floatPoint(v(), noLabels)})
+	case *stats.GaugeDuration:
+		dc.addFloatGauge(k, v.Help(), "s", []metricdata.DataPoint[float64]{dc.floatPoint(v.Get().Seconds(), noLabels)})
+	case *stats.GaugeDurationFunc:
+		dc.addFloatGauge(k, v.Help(), "s", []metricdata.DataPoint[float64]{dc.floatPoint(v.F().Seconds(), noLabels)})
+	case *stats.GaugesWithSingleLabel:
+		var points []metricdata.DataPoint[int64]
+		for labelVal, val := range v.Counts() {
+			points = append(points, dc.intPoint(val, makeLabels([]string{v.Label()}, labelVal)))
+		}
+		dc.addGauge(k, v.Help(), "", points)
+	case *stats.GaugesWithMultiLabels:
+		var points []metricdata.DataPoint[int64]
+		for labelVals, val := range v.Counts() {
+			points = append(points, dc.intPoint(val, makeLabels(v.Labels(), labelVals)))
+		}
+		dc.addGauge(k, v.Help(), "", points)
+	case *stats.GaugesFuncWithMultiLabels:
+		var points []metricdata.DataPoint[int64]
+		for labelVals, val := range v.Counts() {
+			points = append(points, dc.intPoint(val, makeLabels(v.Labels(), labelVals)))
+		}
+		dc.addGauge(k, v.Help(), "", points)
+	case *stats.Timings:
+		dc.addTimings(k, v.Help(), []string{v.Label()}, v)
+	case *stats.MultiTimings:
+		dc.addTimings(k, v.Help(), v.Labels(), &v.Timings)
+	case *stats.Histogram:
+		dc.addHistogram(k, v.Help(), "", []metricdata.HistogramDataPoint[float64]{dc.histogramPoint(v, 1, noLabels)})
+	}
+}
+
+// addTimings adds the histograms of the categories of a timings stat.
+func (dc *collector) addTimings(name, help string, labels []string, timings *stats.Timings) {
+	var points []metricdata.HistogramDataPoint[float64]
+	for labelVals, histogram := range timings.Histograms() {
+		points = append(points, dc.histogramPoint(histogram, float64(time.Second), makeLabels(labels, labelVals)))
+	}
+	dc.addHistogram(name, help, "s", points)
+}
+
+func (dc *collector) addSum(name, help, unit string, points []metricdata.DataPoint[int64]) {
+	dc.add(name, help, unit, metricdata.Sum[int64]{
+		DataPoints:  points,
+		Temporality: metricdata.CumulativeTemporality,
+		IsMonotonic: true,
+	})
+}
+
+func (dc *collector) addFloatSum(name, help, unit string, points []metricdata.DataPoint[float64]) {
+	dc.add(name, help, unit, metricdata.Sum[float64]{
+		DataPoints:  points,
+		Temporality: metricdata.CumulativeTemporality,
+		IsMonotonic: true,
+	})
+}
+
+func (dc *collector) addGauge(name, help, unit string, points []metricdata.DataPoint[int64]) {
+	dc.add(name, help, unit, metricdata.Gauge[int64]{DataPoints: points})
+}
+
+func (dc *collector) addFloatGauge(name, help, unit string, points []metricdata.DataPoint[float64]) {
+	dc.add(name, help, unit, metricdata.Gauge[float64]{DataPoints: points})
+}
+
+func (dc *collector) addHistogram(name, help, unit string, points []metricdata.HistogramDataPoint[float64]) {
+	dc.add(name, help, unit, metricdata.Histogram[float64]{
+		DataPoints:  points,
+		Temporality: metricdata.CumulativeTemporality,
+	})
+}
+
+func (dc *collector) add(name, help, unit string, data metricdata.Aggregation) {
+	dc.metrics = append(dc.metrics, metricdata.Metrics{
+		Name:        dc.metricName(name),
+		Description: help,
+		Unit:        unit,
+		Data:        data,
+	})
+}
+
+func (dc *collector) intPoint(val int64, attrs attribute.Set) metricdata.DataPoint[int64] {
+	return metricdata.DataPoint[int64]{Attributes: attrs, StartTime: dc.start, Time: dc.now, Value: val}
+}
+
+func (dc *collector) floatPoint(val float64, attrs attribute.Set) metricdata.DataPoint[float64] {
+	return metricdata.DataPoint[float64]{Attributes: attrs, StartTime: dc.start, Time: dc.now, Value: val}
+}
+
+// histogramPoint converts a stats histogram, dividing its values by divideBy.
+// The buckets of a stats histogram are not cumulative, and the last one has
+// no upper bound, like the buckets of an OpenTelemetry histogram.
+func (dc *collector) histogramPoint(histogram *stats.Histogram, divideBy float64, attrs attribute.Set) metricdata.HistogramDataPoint[float64] {
+	cutoffs := histogram.Cutoffs()
+	bounds := make([]float64, len(cutoffs))
+	for i, cutoff := range cutoffs {
+		bounds[i] = float64(cutoff) / divideBy
+	}
+
+	buckets := histogram.Buckets()
+	counts := make([]uint64, len(buckets))
+	var count uint64
+	for i, bucket := range buckets {
+		counts[i] = uint64(bucket)
+		count += uint64(bucket)
+	}
+
+	return metricdata.HistogramDataPoint[float64]{
+		Attributes:   attrs,
+		StartTime:    dc.start,
+		Time:         dc.now,
+		Count:        count,
+		Bounds:       bounds,
+		BucketCounts: counts,
+		Sum:          float64(histogram.Total()) / divideBy,
+	}
+}
+
+// metricName prepends the prefix to the snake case name of a stat, which
+// gives the same names as the prometheus backend.
+func (dc *collector) metricName(name string) string {
+	name = normalizeMetric(name)
+	if dc.prefix == "" {
+		return name
+	}
+	return dc.prefix + "_" + strings.TrimPrefix(name, dc.prefix+"_")
+}
+
+// makeLabels takes the vitess stat representation of label values ("."-separated list) and breaks it
+// apart into a set of attributes.
+func makeLabels(labelNames []string, labelValsCombined string) attribute.Set {
+	labelVals := strings.Split(labelValsCombined, ".")
+	attrs := make([]attribute.KeyValue, 0, len(labelVals))
+	for i, v := range labelVals {
+		if i < len(labelNames) {
+			attrs = append(attrs, attribute.String(normalizeMetric(labelNames[i]), v))
+		}
+	}
+	return attribute.NewSet(attrs...)
+}
+
+// normalizeMetric produces a compliant name by applying
+// special case conversions and then applying a camel case to snake case converter.
+func normalizeMetric(name string) string {
+	// Special cases
+	r := strings.NewReplacer("VSchema", "vschema", "VtGate", "vtgate")
+	name = r.Replace(name)
+
+	return stats.GetSnakeName(name)
+}
@@ -0,0 +1,22 @@
+/*
+Copyright 2025 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package opentelemetrybackend adds support for pushing stats to an
+// OpenTelemetry collector with OTLP.
+//
+// It is enabled with --emit-stats --stats-backend=opentelemetry, and the
+// stats are pushed every --stats-emit-period.
+package opentelemetrybackend
@@ -0,0 +1,41 @@
+/*
+Copyright 2025 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package opentelemetrybackend
+
+import (
+	"github.com/spf13/pflag"
+
+	"vitess.io/vitess/go/vt/servenv"
+	"vitess.io/vitess/go/vt/utils"
+)
+
+var (
+	otelMetricsEndpoint string
+	otelMetricsInsecure bool
+)
+
+func registerFlags(fs *pflag.FlagSet) {
+	utils.SetFlagStringVar(fs, &otelMetricsEndpoint, "otel-metrics-endpoint", otelMetricsEndpoint, "host:port of the OTLP gRPC collector to push metrics to (defaults to the standard OTEL_EXPORTER_OTLP_* environment variables)")
+	utils.SetFlagBoolVar(fs, &otelMetricsInsecure, "otel-metrics-insecure", otelMetricsInsecure, "whether to push metrics to the OTLP collector without TLS")
+}
+
+func init() {
+	servenv.OnParseFor("vtbackup", registerFlags)
+	servenv.OnParseFor("vtctld", registerFlags)
+	servenv.OnParseFor("vtgate", registerFlags)
+	servenv.OnParseFor("vttablet", registerFlags)
+}
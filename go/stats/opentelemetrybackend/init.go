@@ -0,0 +1,114 @@
+/*
+Copyright 2025 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package opentelemetrybackend
+
+import (
+	"context"
+	"time"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/exporters/otlp/otlpmetric/otlpmetricgrpc"
+	"go.opentelemetry.io/otel/sdk/resource"
+
+	"vitess.io/vitess/go/stats"
+	"vitess.io/vitess/go/vt/log"
+	"vitess.io/vitess/go/vt/servenv"
+)
+
+const (
+	// ComponentAttribute is the resource attribute with the name of the
+	// binary (vtgate, vttablet, etc.).
+	ComponentAttribute = "vitess.component"
+	// CellAttribute is the resource attribute with the cell of the binary.
+	CellAttribute = "vitess.cell"
+	// TabletAliasAttribute is the resource attribute with the alias of
+	// the tablet.
+	TabletAliasAttribute = "vitess.tablet_alias"
+)
+
+var singletonBackend stats.PushBackend
+
+// Init attempts to create a singleton *opentelemetrybackend.backend and register it
+// as a PushBackend. If it fails to create one, this is a noop. The component is
+// the name of the binary, and the attributes function returns the additional
+// resource attributes (like CellAttribute) once the flags are parsed.
+func Init(component string, attributes func() map[string]string) {
+	// Needs to happen in servenv.OnRun() instead of init because it requires flag parsing and logging
+	servenv.OnRun(func() {
+		log.Info("Initializing opentelemetry backend...")
+		var attrs map[string]string
+		if attributes != nil {
+			attrs = attributes()
+		}
+		backend, err := InitWithoutServenv(component, attrs)
+		if err != nil {
+			log.Infof("Failed to initialize singleton opentelemetry backend: %v", err)
+		} else {
+			singletonBackend = backend
+			log.Info("Initialized opentelemetry backend.")
+		}
+	})
+	servenv.OnTerm(func() {
+		if b, ok := singletonBackend.(*backend); ok {
+			ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+			defer cancel()
+			if err := b.exporter.Shutdown(ctx); err != nil {
+				log.Warningf("Failed to shut down the opentelemetry metrics exporter: %v", err)
+			}
+		}
+	})
+}
+
+// InitWithoutServenv initializes the opentelemetry backend without servenv.
+// The metrics are pushed with --emit-stats and --stats-backend=opentelemetry,
+// every --stats-emit-period.
+func InitWithoutServenv(component string, attributes map[string]string) (stats.PushBackend, error) {
+	var opts []otlpmetricgrpc.Option
+	if otelMetricsEndpoint != "" {
+		opts = append(opts, otlpmetricgrpc.WithEndpoint(otelMetricsEndpoint))
+	}
+	if otelMetricsInsecure {
+		opts = append(opts, otlpmetricgrpc.WithInsecure())
+	}
+	exporter, err := otlpmetricgrpc.New(context.Background(), opts...)
+	if err != nil {
+		return nil, err
+	}
+
+	b := newBackend(component, newResource(component, attributes), exporter)
+	stats.RegisterPushBackend("opentelemetry", b)
+	return b, nil
+}
+
+// newResource builds the resource describing the binary. The common tags
+// of the stats are added as resource attributes too, since they apply to
+// every metric.
+func newResource(component string, attributes map[string]string) *resource.Resource {
+	attrs := []attribute.KeyValue{
+		attribute.String("service.name", component),
+		attribute.String(ComponentAttribute, component),
+	}
+	for k, v := range stats.ParseCommonTags(stats.CommonTags) {
+		attrs = append(attrs, attribute.String(k, v))
+	}
+	for k, v := range attributes {
+		if v != "" {
+			attrs = append(attrs, attribute.String(k, v))
+		}
+	}
+	return resource.NewSchemaless(attrs...)
+}
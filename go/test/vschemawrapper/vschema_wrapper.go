@@ -55,6 +55,8 @@ type VSchemaWrapper struct {
 	ForeignKeyChecksState *bool
 	Version               plancontext.PlannerVersion
 	EnableViews           bool
+	TenantIsolation       bool
+	ShardCounts           map[string]int
 	TestBuilder           func(query string, vschema plancontext.VSchema, keyspace string) (*engine.Plan, error)
 	Env                   *vtenv.Environment
 }
@@ -333,6 +335,14 @@ func (vw *VSchemaWrapper) IsViewsEnabled() bool {
 	return vw.EnableViews
 }
 
+func (vw *VSchemaWrapper) IsTenantIsolationEnabled() bool {
+	return vw.TenantIsolation
+}
+
+func (vw *VSchemaWrapper) ShardCount(_ context.Context, keyspace string) int {
+	return vw.ShardCounts[keyspace]
+}
+
 // FindMirrorRule finds the mirror rule for the requested keyspace, table
 // name, and the tablet type in the VSchema.
 func (vw *VSchemaWrapper) FindMirrorRule(tab sqlparser.TableName) (*vindexes.MirrorRule, error) {
@@ -0,0 +1,425 @@
+/*
+Copyright 2025 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package trace
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/spf13/pflag"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracegrpc"
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracehttp"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/sdk/resource"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	oteltrace "go.opentelemetry.io/otel/trace"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+
+	"vitess.io/vitess/go/viperutil"
+	"vitess.io/vitess/go/vt/log"
+	vtrpcpb "vitess.io/vitess/go/vt/proto/vtrpc"
+	"vitess.io/vitess/go/vt/vterrors"
+)
+
+/*
+This file makes it easy to build Vitess without including the OpenTelemetry
+binaries. All that is needed is to delete this file.
+
+The spans are exported with OTLP, and their context is propagated with the
+W3C Trace Context headers (traceparent and tracestate).
+*/
+
+const (
+	otelProtocolGRPC = "grpc"
+	otelProtocolHTTP = "http"
+)
+
+var (
+	otelConfigKey = viperutil.KeyPrefixFunc(configKey("opentelemetry"))
+
+	otelEndpoint = viperutil.Configure(
+		otelConfigKey("endpoint"),
+		viperutil.Options[string]{
+			FlagName: "otel-exporter-endpoint",
+		},
+	)
+	otelProtocol = viperutil.Configure(
+		otelConfigKey("protocol"),
+		viperutil.Options[string]{
+			Default:  otelProtocolGRPC,
+			FlagName: "otel-exporter-protocol",
+		},
+	)
+	otelInsecure = viperutil.Configure(
+		otelConfigKey("insecure"),
+		viperutil.Options[bool]{
+			FlagName: "otel-exporter-insecure",
+		},
+	)
+)
+
+func init() {
+	// If compiled with plugin_opentelemetry, ensure that trace.RegisterFlags
+	// includes the OTLP exporter flags.
+	pluginFlags = append(pluginFlags, func(fs *pflag.FlagSet) {
+		fs.String("otel-exporter-endpoint", otelEndpoint.Default(), "host:port of the OTLP collector to send spans to. If empty, the OTEL_EXPORTER_OTLP_ENDPOINT environment variable or the OTLP default is used")
+		fs.String("otel-exporter-protocol", otelProtocol.Default(), "protocol used to send spans to the OTLP collector. possible values are 'grpc' or 'http'")
+		fs.Bool("otel-exporter-insecure", otelInsecure.Default(), "whether to send spans to the OTLP collector without TLS")
+
+		viperutil.BindFlags(fs, otelEndpoint, otelProtocol, otelInsecure)
+	})
+}
+
+// newOpenTelemetryTracer will instantiate a tracingService exporting spans
+// with OTLP. The standard OTEL_EXPORTER_OTLP_* environment variables are
+// honored, and the command line flags override them.
+func newOpenTelemetryTracer(serviceName string) (tracingService, io.Closer, error) {
+	if serviceName == "" {
+		return nil, nil, fmt.Errorf("no service name provided")
+	}
+
+	exporter, err := newOTLPTraceExporter(otelProtocol.Get(), otelEndpoint.Get(), otelInsecure.Get())
+	if err != nil {
+		return nil, nil, err
+	}
+	log.Infof("Tracing to OTLP %v collector at %v as %v", otelProtocol.Get(), otelEndpoint.Get(), serviceName)
+
+	res, err := resource.Merge(resource.Default(), resource.NewSchemaless(attribute.String("service.name", serviceName)))
+	if err != nil {
+		return nil, nil, err
+	}
+
+	// The sampling rate is shared with the other tracers. A span is
+	// always sampled when its parent is.
+	sampler := sdktrace.ParentBased(sdktrace.TraceIDRatioBased(samplingRate.Get()))
+	log.Infof("Tracing sampler ratio %v", samplingRate.Get())
+
+	provider := sdktrace.NewTracerProvider(
+		sdktrace.WithBatcher(exporter),
+		sdktrace.WithResource(res),
+		sdktrace.WithSampler(sampler),
+	)
+	propagator := propagation.NewCompositeTextMapPropagator(propagation.TraceContext{}, propagation.Baggage{})
+
+	otel.SetTracerProvider(provider)
+	otel.SetTextMapPropagator(propagator)
+
+	return newOpenTelemetryService(provider, propagator), &otelCloser{provider: provider}, nil
+}
+
+func newOTLPTraceExporter(protocol, endpoint string, insecure bool) (sdktrace.SpanExporter, error) {
+	ctx := context.Background()
+	switch protocol {
+	case otelProtocolGRPC:
+		var opts []otlptracegrpc.Option
+		if endpoint != "" {
+			opts = append(opts, otlptracegrpc.WithEndpoint(endpoint))
+		}
+		if insecure {
+			opts = append(opts, otlptracegrpc.WithInsecure())
+		}
+		return otlptracegrpc.New(ctx, opts...)
+	case otelProtocolHTTP:
+		var opts []otlptracehttp.Option
+		if endpoint != "" {
+			opts = append(opts, otlptracehttp.WithEndpoint(endpoint))
+		}
+		if insecure {
+			opts = append(opts, otlptracehttp.WithInsecure())
+		}
+		return otlptracehttp.New(ctx, opts...)
+	default:
+		return nil, fmt.Errorf("unknown OTLP protocol %q, possible values are '%s' or '%s'", protocol, otelProtocolGRPC, otelProtocolHTTP)
+	}
+}
+
+func init() {
+	tracingBackendFactories["opentelemetry"] = newOpenTelemetryTracer
+}
+
+var _ io.Closer = (*otelCloser)(nil)
+
+// otelCloser flushes the spans that were not exported yet.
+type otelCloser struct {
+	provider *sdktrace.TracerProvider
+}
+
+func (c *otelCloser) Close() error {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	return c.provider.Shutdown(ctx)
+}
+
+var _ Span = (*openTelemetrySpan)(nil)
+
+type openTelemetrySpan struct {
+	otelSpan oteltrace.Span
+}
+
+// Finish will mark a span as finished
+func (s openTelemetrySpan) Finish() {
+	s.otelSpan.End()
+}
+
+// Annotate will add information to an existing span
+func (s openTelemetrySpan) Annotate(key string, value any) {
+	s.otelSpan.SetAttributes(otelAttribute(key, value))
+}
+
+func otelAttribute(key string, value any) attribute.KeyValue {
+	switch v := value.(type) {
+	case string:
+		return attribute.String(key, v)
+	case bool:
+		return attribute.Bool(key, v)
+	case int:
+		return attribute.Int(key, v)
+	case int32:
+		return attribute.Int64(key, int64(v))
+	case int64:
+		return attribute.Int64(key, v)
+	case uint32:
+		return attribute.Int64(key, int64(v))
+	case float64:
+		return attribute.Float64(key, v)
+	case []string:
+		return attribute.StringSlice(key, v)
+	case fmt.Stringer:
+		return attribute.String(key, v.String())
+	default:
+		return attribute.String(key, fmt.Sprint(v))
+	}
+}
+
+var _ tracingService = (*openTelemetryService)(nil)
+
+type openTelemetryService struct {
+	tracer     oteltrace.Tracer
+	propagator propagation.TextMapPropagator
+}
+
+func newOpenTelemetryService(provider oteltrace.TracerProvider, propagator propagation.TextMapPropagator) openTelemetryService {
+	return openTelemetryService{
+		tracer:     provider.Tracer("vitess.io/vitess/go/trace"),
+		propagator: propagator,
+	}
+}
+
+// New is part of an interface implementation
+func (s openTelemetryService) New(parent Span, label string) Span {
+	ctx := context.Background()
+	if parent != nil {
+		ctx = oteltrace.ContextWithSpan(ctx, parent.(openTelemetrySpan).otelSpan)
+	}
+	_, span := s.tracer.Start(ctx, label)
+	return openTelemetrySpan{otelSpan: span}
+}
+
+// NewFromString is part of an interface implementation. The parent is
+// either a W3C traceparent, or the base64 encoded JSON map of the
+// trace context headers that the other tracers also use.
+func (s openTelemetryService) NewFromString(parent, label string) (Span, error) {
+	parent = strings.TrimSpace(parent)
+	carrier := propagation.MapCarrier{}
+	if strings.Count(parent, "-") == 3 {
+		carrier["traceparent"] = parent
+	} else {
+		textMap, err := extractMapFromString(parent)
+		if err != nil {
+			return nil, err
+		}
+		for k, v := range textMap {
+			carrier[strings.ToLower(k)] = v
+		}
+	}
+
+	ctx := s.propagator.Extract(context.Background(), carrier)
+	if !oteltrace.SpanContextFromContext(ctx).IsValid() {
+		return nil, vterrors.New(vtrpcpb.Code_INVALID_ARGUMENT, "failed to deserialize span context")
+	}
+	_, span := s.tracer.Start(ctx, label)
+	return openTelemetrySpan{otelSpan: span}, nil
+}
+
+// FromContext is part of an interface implementation
+func (s openTelemetryService) FromContext(ctx context.Context) (Span, bool) {
+	span := oteltrace.SpanFromContext(ctx)
+	if !span.SpanContext().IsValid() {
+		return nil, false
+	}
+	return openTelemetrySpan{otelSpan: span}, true
+}
+
+// NewContext is part of an interface implementation
+func (s openTelemetryService) NewContext(parent context.Context, span Span) context.Context {
+	otelSpan, ok := span.(openTelemetrySpan)
+	if !ok {
+		return nil
+	}
+	return oteltrace.ContextWithSpan(parent, otelSpan.otelSpan)
+}
+
+// AddGrpcServerOptions is part of an interface implementation
+func (s openTelemetryService) AddGrpcServerOptions(addInterceptors func(s grpc.StreamServerInterceptor, u grpc.UnaryServerInterceptor)) {
+	addInterceptors(s.streamServerInterceptor, s.unaryServerInterceptor)
+}
+
+// AddGrpcClientOptions is part of an interface implementation
+func (s openTelemetryService) AddGrpcClientOptions(addInterceptors func(s grpc.StreamClientInterceptor, u grpc.UnaryClientInterceptor)) {
+	addInterceptors(s.streamClientInterceptor, s.unaryClientInterceptor)
+}
+
+// startServerSpan starts the span of a gRPC call, as a child of the span
+// the client sent in the metadata, if any.
+func (s openTelemetryService) startServerSpan(ctx context.Context, method string) (context.Context, oteltrace.Span) {
+	md, _ := metadata.FromIncomingContext(ctx)
+	ctx = s.propagator.Extract(ctx, metadataCarrier(md))
+	return s.tracer.Start(ctx, method, oteltrace.WithSpanKind(oteltrace.SpanKindServer))
+}
+
+// startClientSpan starts the span of a gRPC call, and adds it to the
+// outgoing metadata.
+func (s openTelemetryService) startClientSpan(ctx context.Context, method string) (context.Context, oteltrace.Span) {
+	ctx, span := s.tracer.Start(ctx, method, oteltrace.WithSpanKind(oteltrace.SpanKindClient))
+	md, ok := metadata.FromOutgoingContext(ctx)
+	if ok {
+		md = md.Copy()
+	} else {
+		md = metadata.MD{}
+	}
+	s.propagator.Inject(ctx, metadataCarrier(md))
+	return metadata.NewOutgoingContext(ctx, md), span
+}
+
+func finishGrpcSpan(span oteltrace.Span, err error) {
+	span.SetAttributes(attribute.String("rpc.grpc.status_code", status.Code(err).String()))
+	if err != nil {
+		span.SetStatus(codes.Error, err.Error())
+	}
+	span.End()
+}
+
+func (s openTelemetryService) unaryServerInterceptor(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+	ctx, span := s.startServerSpan(ctx, info.FullMethod)
+	resp, err := handler(ctx, req)
+	finishGrpcSpan(span, err)
+	return resp, err
+}
+
+func (s openTelemetryService) streamServerInterceptor(srv any, stream grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+	ctx, span := s.startServerSpan(stream.Context(), info.FullMethod)
+	err := handler(srv, &otelServerStream{ServerStream: stream, ctx: ctx})
+	finishGrpcSpan(span, err)
+	return err
+}
+
+func (s openTelemetryService) unaryClientInterceptor(ctx context.Context, method string, req, reply any, cc *grpc.ClientConn, invoker grpc.UnaryInvoker, opts ...grpc.CallOption) error {
+	ctx, span := s.startClientSpan(ctx, method)
+	err := invoker(ctx, method, req, reply, cc, opts...)
+	finishGrpcSpan(span, err)
+	return err
+}
+
+func (s openTelemetryService) streamClientInterceptor(ctx context.Context, desc *grpc.StreamDesc, cc *grpc.ClientConn, method string, streamer grpc.Streamer, opts ...grpc.CallOption) (grpc.ClientStream, error) {
+	ctx, span := s.startClientSpan(ctx, method)
+	stream, err := streamer(ctx, desc, cc, method, opts...)
+	if err != nil {
+		finishGrpcSpan(span, err)
+		return nil, err
+	}
+
+	cs := &otelClientStream{ClientStream: stream, span: span, finished: make(chan struct{})}
+	// The span is also finished when the stream is done without
+	// a call to RecvMsg returning an error.
+	go func() {
+		select {
+		case <-cs.finished:
+		case <-stream.Context().Done():
+			cs.finish(stream.Context().Err())
+		}
+	}()
+	return cs, nil
+}
+
+// otelServerStream overrides the context of a grpc.ServerStream with
+// the one holding the span.
+type otelServerStream struct {
+	grpc.ServerStream
+	ctx context.Context
+}
+
+func (ss *otelServerStream) Context() context.Context {
+	return ss.ctx
+}
+
+// otelClientStream finishes the span of a client stream once the stream
+// is done.
+type otelClientStream struct {
+	grpc.ClientStream
+	span     oteltrace.Span
+	once     sync.Once
+	finished chan struct{}
+}
+
+func (cs *otelClientStream) RecvMsg(m any) error {
+	err := cs.ClientStream.RecvMsg(m)
+	if err == io.EOF {
+		cs.finish(nil)
+	} else if err != nil {
+		cs.finish(err)
+	}
+	return err
+}
+
+func (cs *otelClientStream) finish(err error) {
+	cs.once.Do(func() {
+		close(cs.finished)
+		finishGrpcSpan(cs.span, err)
+	})
+}
+
+// metadataCarrier adapts gRPC metadata to a propagation.TextMapCarrier.
+type metadataCarrier metadata.MD
+
+func (mc metadataCarrier) Get(key string) string {
+	values := metadata.MD(mc).Get(key)
+	if len(values) == 0 {
+		return ""
+	}
+	return values[0]
+}
+
+func (mc metadataCarrier) Set(key, value string) {
+	metadata.MD(mc).Set(key, value)
+}
+
+func (mc metadataCarrier) Keys() []string {
+	keys := make([]string, 0, len(mc))
+	for k := range mc {
+		keys = append(keys, k)
+	}
+	return keys
+}
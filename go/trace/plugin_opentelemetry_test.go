@@ -0,0 +1,156 @@
+/*
+Copyright 2025 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package trace
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/propagation"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/metadata"
+)
+
+const testTraceParent = "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01"
+
+func newTestOpenTelemetryService() (openTelemetryService, *tracetest.SpanRecorder) {
+	recorder := tracetest.NewSpanRecorder()
+	provider := sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder))
+	return newOpenTelemetryService(provider, propagation.TraceContext{}), recorder
+}
+
+func TestNewOpenTelemetryTracer(t *testing.T) {
+	tracingSvc, closer, err := newOpenTelemetryTracer("noop")
+	require.NoError(t, err)
+	require.NotEmpty(t, tracingSvc)
+	require.NoError(t, closer.Close())
+
+	_, _, err = newOpenTelemetryTracer("")
+	require.ErrorContains(t, err, "no service name provided")
+
+	_, err = newOTLPTraceExporter("thrift", "", false)
+	require.ErrorContains(t, err, "unknown OTLP protocol")
+
+	exporter, err := newOTLPTraceExporter(otelProtocolHTTP, "localhost:4318", true)
+	require.NoError(t, err)
+	require.NoError(t, exporter.Shutdown(context.Background()))
+}
+
+func TestOpenTelemetrySpans(t *testing.T) {
+	svc, recorder := newTestOpenTelemetryService()
+
+	parent := svc.New(nil, "parent")
+	parent.Annotate("keyspace", "ks")
+	parent.Annotate("rows", 3)
+
+	spanFromCtx, ok := svc.FromContext(context.Background())
+	require.False(t, ok)
+	require.Nil(t, spanFromCtx)
+
+	ctx := svc.NewContext(context.Background(), parent)
+	spanFromCtx, ok = svc.FromContext(ctx)
+	require.True(t, ok)
+
+	child := svc.New(spanFromCtx, "child")
+	child.Finish()
+	parent.Finish()
+
+	spans := recorder.Ended()
+	require.Len(t, spans, 2)
+	assert.Equal(t, "child", spans[0].Name())
+	assert.Equal(t, spans[1].SpanContext().TraceID(), spans[0].SpanContext().TraceID())
+	assert.Equal(t, spans[1].SpanContext().SpanID(), spans[0].Parent().SpanID())
+	assert.ElementsMatch(t, []attribute.KeyValue{attribute.String("keyspace", "ks"), attribute.Int("rows", 3)}, spans[1].Attributes())
+
+	assert.Nil(t, svc.NewContext(context.Background(), &mockSpan{}))
+}
+
+func TestOpenTelemetryNewFromString(t *testing.T) {
+	svc, recorder := newTestOpenTelemetryService()
+
+	jsonBytes, err := json.Marshal(map[string]string{"traceparent": testTraceParent})
+	require.NoError(t, err)
+
+	for _, parent := range []string{testTraceParent, base64.StdEncoding.EncodeToString(jsonBytes)} {
+		span, err := svc.NewFromString(parent, "from-string")
+		require.NoError(t, err)
+		span.Finish()
+	}
+
+	for _, span := range recorder.Ended() {
+		assert.Equal(t, "4bf92f3577b34da6a3ce929d0e0e4736", span.SpanContext().TraceID().String())
+		assert.Equal(t, "00f067aa0ba902b7", span.Parent().SpanID().String())
+		assert.True(t, span.Parent().IsRemote())
+	}
+
+	_, err = svc.NewFromString("this is not base64", "from-string")
+	assert.Error(t, err)
+
+	jsonBytes, err = json.Marshal(map[string]string{"uber-trace-id": "123:456:789:1"})
+	require.NoError(t, err)
+	_, err = svc.NewFromString(base64.StdEncoding.EncodeToString(jsonBytes), "from-string")
+	assert.ErrorContains(t, err, "failed to deserialize span context")
+}
+
+func TestOpenTelemetryGrpcPropagation(t *testing.T) {
+	svc, recorder := newTestOpenTelemetryService()
+
+	var unaryClient grpc.UnaryClientInterceptor
+	svc.AddGrpcClientOptions(func(_ grpc.StreamClientInterceptor, u grpc.UnaryClientInterceptor) {
+		unaryClient = u
+	})
+	var unaryServer grpc.UnaryServerInterceptor
+	svc.AddGrpcServerOptions(func(_ grpc.StreamServerInterceptor, u grpc.UnaryServerInterceptor) {
+		unaryServer = u
+	})
+
+	// The client sends the metadata it is invoked with to the server.
+	invoker := func(ctx context.Context, method string, req, reply any, cc *grpc.ClientConn, opts ...grpc.CallOption) error {
+		md, ok := metadata.FromOutgoingContext(ctx)
+		require.True(t, ok)
+		assert.Equal(t, []string{"value"}, md.Get("other"))
+		assert.Len(t, md.Get("traceparent"), 1)
+
+		ctx = metadata.NewIncomingContext(context.Background(), md)
+		_, err := unaryServer(ctx, req, &grpc.UnaryServerInfo{FullMethod: method}, func(ctx context.Context, req any) (any, error) {
+			_, ok := svc.FromContext(ctx)
+			assert.True(t, ok)
+			return nil, nil
+		})
+		return err
+	}
+
+	span, ctx := svc.New(nil, "query"), metadata.AppendToOutgoingContext(context.Background(), "other", "value")
+	ctx = svc.NewContext(ctx, span)
+	require.NoError(t, unaryClient(ctx, "/vtgate.Vitess/Execute", nil, nil, nil, invoker))
+	span.Finish()
+
+	spans := recorder.Ended()
+	require.Len(t, spans, 3)
+	server, client, query := spans[0], spans[1], spans[2]
+	assert.Equal(t, "/vtgate.Vitess/Execute", server.Name())
+	assert.Equal(t, query.SpanContext().TraceID(), server.SpanContext().TraceID())
+	assert.Equal(t, client.SpanContext().SpanID(), server.Parent().SpanID())
+	assert.Equal(t, query.SpanContext().SpanID(), client.Parent().SpanID())
+}
@@ -90,6 +90,7 @@ type DBConfigs struct {
 	ConnectTimeoutMilliseconds int           `json:"connectTimeoutMilliseconds,omitempty"`
 	DBName                     string        `json:"dbName,omitempty"`
 	EnableQueryInfo            bool          `json:"enableQueryInfo,omitempty"`
+	Compression                string        `json:"compression,omitempty"`
 
 	App          UserConfig `json:"app,omitempty"`
 	Dba          UserConfig `json:"dba,omitempty"`
@@ -147,6 +148,7 @@ func registerBaseFlags(fs *pflag.FlagSet) {
 	utils.SetFlagStringVar(fs, &GlobalDBConfigs.ServerName, "db-server-name", "", "server name of the DB we are connecting to.")
 	utils.SetFlagIntVar(fs, &GlobalDBConfigs.ConnectTimeoutMilliseconds, "db-connect-timeout-ms", 0, "connection timeout to mysqld in milliseconds (0 for no timeout)")
 	utils.SetFlagBoolVar(fs, &GlobalDBConfigs.EnableQueryInfo, "db-conn-query-info", false, "enable parsing and processing of QUERY_OK info fields")
+	utils.SetFlagStringVar(fs, &GlobalDBConfigs.Compression, "db-compression", "", "Algorithm of the compressed protocol to use for the connections to MySQL, if MySQL supports it. Options: zlib, zstd. Empty disables compression.")
 }
 
 // The flags will change the global singleton
@@ -360,6 +362,7 @@ func (dbcfgs *DBConfigs) InitWithSocket(defaultSocketFile string, collationEnv *
 		}
 		cp.ConnectTimeoutMs = uint64(dbcfgs.ConnectTimeoutMilliseconds)
 		cp.EnableQueryInfo = dbcfgs.EnableQueryInfo
+		cp.Compression = dbcfgs.Compression
 
 		cp.Uname = uc.User
 		cp.Pass = uc.Password
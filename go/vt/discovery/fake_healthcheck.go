@@ -235,6 +235,7 @@ func (fhc *FakeHealthCheck) RemoveTablet(tablet *topodatapb.Tablet) {
 		return
 	}
 	delete(fhc.items, key)
+	delete(fhc.itemsAlias, tablet.Alias.String())
 }
 
 // ReplaceTablet removes the old tablet and adds the new.
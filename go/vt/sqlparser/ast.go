@@ -617,8 +617,28 @@ type (
 	// DDLAction is an enum for DDL.Action
 	DDLAction int8
 
-	// Load represents a LOAD statement
+	// Load represents a LOAD DATA statement.
+	// The statements loading from S3 are not parsed, and leave the Table empty.
 	Load struct {
+		Local      bool
+		Infile     string
+		Replace    bool
+		Ignore     Ignore
+		Table      TableName
+		Partitions Partitions
+		Charset    ColumnCharset
+
+		// The parser fills in the MySQL defaults for the FIELDS and LINES options that are not given
+		FieldsTerminatedBy       string
+		FieldsEnclosedBy         string
+		FieldsOptionallyEnclosed bool
+		FieldsEscapedBy          string
+		LinesStartingBy          string
+		LinesTerminatedBy        string
+		IgnoreLines              int
+
+		Columns  Columns
+		SetExprs UpdateExprs
 	}
 
 	// PurgeBinaryLogs represents a PURGE BINARY LOGS statement
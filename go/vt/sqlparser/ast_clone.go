@@ -2287,6 +2287,11 @@ func CloneRefOfLoad(n *Load) *Load {
 		return nil
 	}
 	out := *n
+	out.Table = CloneTableName(n.Table)
+	out.Partitions = ClonePartitions(n.Partitions)
+	out.Charset = CloneColumnCharset(n.Charset)
+	out.Columns = CloneColumns(n.Columns)
+	out.SetExprs = CloneUpdateExprs(n.SetExprs)
 	return &out
 }
 
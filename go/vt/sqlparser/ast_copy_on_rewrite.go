@@ -4323,6 +4323,22 @@ func (c *cow) copyOnRewriteRefOfLoad(n *Load, parent SQLNode) (out SQLNode, chan
 	}
 	out = n
 	if c.pre == nil || c.pre(n, parent) {
+		_Table, changedTable := c.copyOnRewriteTableName(n.Table, n)
+		_Partitions, changedPartitions := c.copyOnRewritePartitions(n.Partitions, n)
+		_Columns, changedColumns := c.copyOnRewriteColumns(n.Columns, n)
+		_SetExprs, changedSetExprs := c.copyOnRewriteUpdateExprs(n.SetExprs, n)
+		if changedTable || changedPartitions || changedColumns || changedSetExprs {
+			res := *n
+			res.Table, _ = _Table.(TableName)
+			res.Partitions, _ = _Partitions.(Partitions)
+			res.Columns, _ = _Columns.(Columns)
+			res.SetExprs, _ = _SetExprs.(UpdateExprs)
+			out = &res
+			if c.cloned != nil {
+				c.cloned(n, out)
+			}
+			changed = true
+		}
 	}
 	if c.post != nil {
 		out, changed = c.postVisit(out, parent, changed)
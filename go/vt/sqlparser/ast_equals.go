@@ -3726,7 +3726,22 @@ func (cmp *Comparator) RefOfLoad(a, b *Load) bool {
 	if a == nil || b == nil {
 		return false
 	}
-	return true
+	return a.Local == b.Local &&
+		a.Infile == b.Infile &&
+		a.Replace == b.Replace &&
+		a.Ignore == b.Ignore &&
+		a.FieldsTerminatedBy == b.FieldsTerminatedBy &&
+		a.FieldsEnclosedBy == b.FieldsEnclosedBy &&
+		a.FieldsOptionallyEnclosed == b.FieldsOptionallyEnclosed &&
+		a.FieldsEscapedBy == b.FieldsEscapedBy &&
+		a.LinesStartingBy == b.LinesStartingBy &&
+		a.LinesTerminatedBy == b.LinesTerminatedBy &&
+		a.IgnoreLines == b.IgnoreLines &&
+		cmp.TableName(a.Table, b.Table) &&
+		cmp.Partitions(a.Partitions, b.Partitions) &&
+		cmp.ColumnCharset(a.Charset, b.Charset) &&
+		cmp.Columns(a.Columns, b.Columns) &&
+		cmp.UpdateExprs(a.SetExprs, b.SetExprs)
 }
 
 // RefOfLocateExpr does deep equals between the two objects.
@@ -2314,7 +2314,55 @@ func (node IdentifierCS) Format(buf *TrackedBuffer) {
 
 // Format formats the node.
 func (node *Load) Format(buf *TrackedBuffer) {
-	buf.literal("AST node missing for Load type")
+	if node.Table.IsEmpty() {
+		buf.literal("AST node missing for Load type")
+		return
+	}
+	buf.literal("load data ")
+	if node.Local {
+		buf.literal("local ")
+	}
+	buf.astPrintf(node, "infile %#s ", encodeSQLString(node.Infile))
+	if node.Replace {
+		buf.literal("replace ")
+	}
+	buf.astPrintf(node, "%sinto table %v%v", node.Ignore.ToString(), node.Table, node.Partitions)
+	if node.Charset.Name != "" {
+		buf.astPrintf(node, " character set %#s", node.Charset.Name)
+	}
+	if node.FieldsTerminatedBy != LoadFieldsTerminatedByDefault || node.FieldsEnclosedBy != "" || node.FieldsEscapedBy != LoadFieldsEscapedByDefault {
+		buf.literal(" fields")
+		if node.FieldsTerminatedBy != LoadFieldsTerminatedByDefault {
+			buf.astPrintf(node, " terminated by %#s", encodeSQLString(node.FieldsTerminatedBy))
+		}
+		if node.FieldsEnclosedBy != "" {
+			if node.FieldsOptionallyEnclosed {
+				buf.literal(" optionally")
+			}
+			buf.astPrintf(node, " enclosed by %#s", encodeSQLString(node.FieldsEnclosedBy))
+		}
+		if node.FieldsEscapedBy != LoadFieldsEscapedByDefault {
+			buf.astPrintf(node, " escaped by %#s", encodeSQLString(node.FieldsEscapedBy))
+		}
+	}
+	if node.LinesStartingBy != "" || node.LinesTerminatedBy != LoadLinesTerminatedByDefault {
+		buf.literal(" lines")
+		if node.LinesStartingBy != "" {
+			buf.astPrintf(node, " starting by %#s", encodeSQLString(node.LinesStartingBy))
+		}
+		if node.LinesTerminatedBy != LoadLinesTerminatedByDefault {
+			buf.astPrintf(node, " terminated by %#s", encodeSQLString(node.LinesTerminatedBy))
+		}
+	}
+	if node.IgnoreLines > 0 {
+		buf.astPrintf(node, " ignore %d lines", node.IgnoreLines)
+	}
+	if len(node.Columns) > 0 {
+		buf.astPrintf(node, " %v", node.Columns)
+	}
+	if len(node.SetExprs) > 0 {
+		buf.astPrintf(node, " set %v", node.SetExprs)
+	}
 }
 
 // Format formats the node.
@@ -3039,7 +3039,70 @@ func (node IdentifierCS) FormatFast(buf *TrackedBuffer) {
 
 // FormatFast formats the node.
 func (node *Load) FormatFast(buf *TrackedBuffer) {
-	buf.WriteString("AST node missing for Load type")
+	if node.Table.IsEmpty() {
+		buf.WriteString("AST node missing for Load type")
+		return
+	}
+	buf.WriteString("load data ")
+	if node.Local {
+		buf.WriteString("local ")
+	}
+	buf.WriteString("infile ")
+	buf.WriteString(encodeSQLString(node.Infile))
+	buf.WriteByte(' ')
+	if node.Replace {
+		buf.WriteString("replace ")
+	}
+	buf.WriteString(node.Ignore.ToString())
+	buf.WriteString("into table ")
+	node.Table.FormatFast(buf)
+	node.Partitions.FormatFast(buf)
+	if node.Charset.Name != "" {
+		buf.WriteString(" character set ")
+		buf.WriteString(node.Charset.Name)
+	}
+	if node.FieldsTerminatedBy != LoadFieldsTerminatedByDefault || node.FieldsEnclosedBy != "" || node.FieldsEscapedBy != LoadFieldsEscapedByDefault {
+		buf.WriteString(" fields")
+		if node.FieldsTerminatedBy != LoadFieldsTerminatedByDefault {
+			buf.WriteString(" terminated by ")
+			buf.WriteString(encodeSQLString(node.FieldsTerminatedBy))
+		}
+		if node.FieldsEnclosedBy != "" {
+			if node.FieldsOptionallyEnclosed {
+				buf.WriteString(" optionally")
+			}
+			buf.WriteString(" enclosed by ")
+			buf.WriteString(encodeSQLString(node.FieldsEnclosedBy))
+		}
+		if node.FieldsEscapedBy != LoadFieldsEscapedByDefault {
+			buf.WriteString(" escaped by ")
+			buf.WriteString(encodeSQLString(node.FieldsEscapedBy))
+		}
+	}
+	if node.LinesStartingBy != "" || node.LinesTerminatedBy != LoadLinesTerminatedByDefault {
+		buf.WriteString(" lines")
+		if node.LinesStartingBy != "" {
+			buf.WriteString(" starting by ")
+			buf.WriteString(encodeSQLString(node.LinesStartingBy))
+		}
+		if node.LinesTerminatedBy != LoadLinesTerminatedByDefault {
+			buf.WriteString(" terminated by ")
+			buf.WriteString(encodeSQLString(node.LinesTerminatedBy))
+		}
+	}
+	if node.IgnoreLines > 0 {
+		buf.WriteString(" ignore ")
+		buf.WriteString(fmt.Sprintf("%d", node.IgnoreLines))
+		buf.WriteString(" lines")
+	}
+	if len(node.Columns) > 0 {
+		buf.WriteByte(' ')
+		node.Columns.FormatFast(buf)
+	}
+	if len(node.SetExprs) > 0 {
+		buf.WriteString(" set ")
+		node.SetExprs.FormatFast(buf)
+	}
 }
 
 // FormatFast formats the node.
@@ -549,6 +549,17 @@ func (op ComparisonExprOperator) Inverse() ComparisonExprOperator {
 	panic("unreachable")
 }
 
+// Inverse returns the modifier of the negated comparison: NOT (x > ALL (subquery)) is x <= ANY (subquery)
+func (m ComparisonModifier) Inverse() ComparisonModifier {
+	switch m {
+	case Any:
+		return All
+	case All:
+		return Any
+	}
+	return m
+}
+
 // SwitchSides returns the reversed comparison operator if applicable, along with a boolean indicating success.
 // For symmetric operators like '=', '!=', and '<=>', it returns the same operator and true.
 // For directional comparison operators ('<', '>', '<=', '>='), it returns the opposite operator and true.
@@ -2846,6 +2857,84 @@ func IsDistinct(f AggrFunc) bool {
 	return da.IsDistinct()
 }
 
+// GetOverClause returns the OVER clause of a window function, or of an aggregate
+// function that is used as a window function. For all other nodes it returns nil.
+func GetOverClause(node SQLNode) *OverClause {
+	switch node := node.(type) {
+	case *ArgumentLessWindowExpr:
+		return node.OverClause
+	case *FirstOrLastValueExpr:
+		return node.OverClause
+	case *NtileExpr:
+		return node.OverClause
+	case *NTHValueExpr:
+		return node.OverClause
+	case *LagLeadExpr:
+		return node.OverClause
+	case *Count:
+		return node.OverClause
+	case *CountStar:
+		return node.OverClause
+	case *Avg:
+		return node.OverClause
+	case *Max:
+		return node.OverClause
+	case *Min:
+		return node.OverClause
+	case *Sum:
+		return node.OverClause
+	case *BitAnd:
+		return node.OverClause
+	case *BitOr:
+		return node.OverClause
+	case *BitXor:
+		return node.OverClause
+	case *Std:
+		return node.OverClause
+	case *StdDev:
+		return node.OverClause
+	case *StdPop:
+		return node.OverClause
+	case *StdSamp:
+		return node.OverClause
+	case *VarPop:
+		return node.OverClause
+	case *VarSamp:
+		return node.OverClause
+	case *Variance:
+		return node.OverClause
+	case *JSONArrayAgg:
+		return node.OverClause
+	case *JSONObjectAgg:
+		return node.OverClause
+	}
+	return nil
+}
+
+// IsWindowFunc returns true if the node is a window function call,
+// including aggregate functions used with an OVER clause
+func IsWindowFunc(node SQLNode) bool {
+	return GetOverClause(node) != nil
+}
+
+// ContainsWindowFunc returns true if the expression contains a window function call.
+// Subqueries are not inspected.
+func ContainsWindowFunc(e SQLNode) bool {
+	hasWindow := false
+	_ = Walk(func(node SQLNode) (kontinue bool, err error) {
+		switch node.(type) {
+		case *Offset, *Subquery:
+			return false, nil
+		}
+		if IsWindowFunc(node) {
+			hasWindow = true
+			return false, io.EOF
+		}
+		return true, nil
+	}, e)
+	return hasWindow
+}
+
 // ToString returns the type as a string
 func (ty KillType) ToString() string {
 	switch ty {
@@ -254,3 +254,37 @@ func TestKeyspaceToNonQualifiedTable(t *testing.T) {
 	AddKeyspace(stmt, "ks2")
 	require.Equal(t, "select col, col + (select 1 from ks2.t4) from ks.t join ks2.t2 join (select 1 from ks2.t3) as x where t.id = t2.id and x.id = t.id", String(stmt))
 }
+
+// TestContainsWindowFunc tests that window functions and aggregations used as window functions are detected.
+func TestContainsWindowFunc(t *testing.T) {
+	tcases := []struct {
+		expr     string
+		expected bool
+	}{{
+		expr:     "row_number() over (partition by a order by b)",
+		expected: true,
+	}, {
+		expr:     "1 + lag(a, 2) over (order by b)",
+		expected: true,
+	}, {
+		expr:     "sum(a) over (partition by b)",
+		expected: true,
+	}, {
+		expr:     "sum(a)",
+		expected: false,
+	}, {
+		expr:     "a + 1",
+		expected: false,
+	}, {
+		expr:     "(select row_number() over () from t)",
+		expected: false,
+	}}
+	parser := NewTestParser()
+	for _, tcase := range tcases {
+		t.Run(tcase.expr, func(t *testing.T) {
+			expr, err := parser.ParseExpr(tcase.expr)
+			require.NoError(t, err)
+			require.Equal(t, tcase.expected, ContainsWindowFunc(expr))
+		})
+	}
+}
@@ -306,6 +306,10 @@ const (
 	RefOfLineStringExprPointParamsOffset
 	RefOfLinestrPropertyFuncExprLinestring
 	RefOfLinestrPropertyFuncExprPropertyDefArg
+	RefOfLoadTable
+	RefOfLoadPartitions
+	RefOfLoadColumns
+	RefOfLoadSetExprs
 	RefOfLocateExprSubStr
 	RefOfLocateExprStr
 	RefOfLocateExprPos
@@ -1173,6 +1177,14 @@ func (s ASTStep) DebugString() string {
 		return "(*LinestrPropertyFuncExpr).Linestring"
 	case RefOfLinestrPropertyFuncExprPropertyDefArg:
 		return "(*LinestrPropertyFuncExpr).PropertyDefArg"
+	case RefOfLoadTable:
+		return "(*Load).Table"
+	case RefOfLoadPartitions:
+		return "(*Load).Partitions"
+	case RefOfLoadColumns:
+		return "(*Load).Columns"
+	case RefOfLoadSetExprs:
+		return "(*Load).SetExprs"
 	case RefOfLocateExprSubStr:
 		return "(*LocateExpr).SubStr"
 	case RefOfLocateExprStr:
@@ -2401,6 +2413,14 @@ func GetNodeFromPath(node SQLNode, path ASTPath) SQLNode {
 			node = node.(*LinestrPropertyFuncExpr).Linestring
 		case RefOfLinestrPropertyFuncExprPropertyDefArg:
 			node = node.(*LinestrPropertyFuncExpr).PropertyDefArg
+		case RefOfLoadTable:
+			node = node.(*Load).Table
+		case RefOfLoadPartitions:
+			node = node.(*Load).Partitions
+		case RefOfLoadColumns:
+			node = node.(*Load).Columns
+		case RefOfLoadSetExprs:
+			node = node.(*Load).SetExprs
 		case RefOfLocateExprSubStr:
 			node = node.(*LocateExpr).SubStr
 		case RefOfLocateExprStr:
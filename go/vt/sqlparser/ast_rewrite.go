@@ -8240,12 +8240,48 @@ func (a *application) rewriteRefOfLoad(parent SQLNode, node *Load, replacer repl
 			return true
 		}
 	}
+	if a.collectPaths {
+		a.cur.current.AddStep(uint16(RefOfLoadTable))
+	}
+	if !a.rewriteTableName(node, node.Table, func(newNode, parent SQLNode) {
+		parent.(*Load).Table = newNode.(TableName)
+	}) {
+		return false
+	}
+	if a.collectPaths {
+		a.cur.current.Pop()
+		a.cur.current.AddStep(uint16(RefOfLoadPartitions))
+	}
+	if !a.rewritePartitions(node, node.Partitions, func(newNode, parent SQLNode) {
+		parent.(*Load).Partitions = newNode.(Partitions)
+	}) {
+		return false
+	}
+	if a.collectPaths {
+		a.cur.current.Pop()
+		a.cur.current.AddStep(uint16(RefOfLoadColumns))
+	}
+	if !a.rewriteColumns(node, node.Columns, func(newNode, parent SQLNode) {
+		parent.(*Load).Columns = newNode.(Columns)
+	}) {
+		return false
+	}
+	if a.collectPaths {
+		a.cur.current.Pop()
+		a.cur.current.AddStep(uint16(RefOfLoadSetExprs))
+	}
+	if !a.rewriteUpdateExprs(node, node.SetExprs, func(newNode, parent SQLNode) {
+		parent.(*Load).SetExprs = newNode.(UpdateExprs)
+	}) {
+		return false
+	}
+	if a.collectPaths {
+		a.cur.current.Pop()
+	}
 	if a.post != nil {
-		if a.pre == nil {
-			a.cur.replacer = replacer
-			a.cur.parent = parent
-			a.cur.node = node
-		}
+		a.cur.replacer = replacer
+		a.cur.parent = parent
+		a.cur.node = node
 		if !a.post(&a.cur) {
 			return false
 		}
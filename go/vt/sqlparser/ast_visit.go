@@ -2911,6 +2911,18 @@ func VisitRefOfLoad(in *Load, f Visit) error {
 	if cont, err := f(in); err != nil || !cont {
 		return err
 	}
+	if err := VisitTableName(in.Table, f); err != nil {
+		return err
+	}
+	if err := VisitPartitions(in.Partitions, f); err != nil {
+		return err
+	}
+	if err := VisitColumns(in.Columns, f); err != nil {
+		return err
+	}
+	if err := VisitUpdateExprs(in.SetExprs, f); err != nil {
+		return err
+	}
 	return nil
 }
 func VisitRefOfLocateExpr(in *LocateExpr, f Visit) error {
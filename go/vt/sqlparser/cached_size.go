@@ -2953,6 +2953,53 @@ func (cached *Literal) CachedSize(alloc bool) int64 {
 	size += hack.RuntimeAllocSize(int64(len(cached.Val)))
 	return size
 }
+func (cached *Load) CachedSize(alloc bool) int64 {
+	if cached == nil {
+		return int64(0)
+	}
+	size := int64(0)
+	if alloc {
+		size += int64(256)
+	}
+	// field Infile string
+	size += hack.RuntimeAllocSize(int64(len(cached.Infile)))
+	// field Table vitess.io/vitess/go/vt/sqlparser.TableName
+	size += cached.Table.CachedSize(false)
+	// field Partitions vitess.io/vitess/go/vt/sqlparser.Partitions
+	{
+		size += hack.RuntimeAllocSize(int64(cap(cached.Partitions)) * int64(32))
+		for _, elem := range cached.Partitions {
+			size += elem.CachedSize(false)
+		}
+	}
+	// field Charset vitess.io/vitess/go/vt/sqlparser.ColumnCharset
+	size += cached.Charset.CachedSize(false)
+	// field FieldsTerminatedBy string
+	size += hack.RuntimeAllocSize(int64(len(cached.FieldsTerminatedBy)))
+	// field FieldsEnclosedBy string
+	size += hack.RuntimeAllocSize(int64(len(cached.FieldsEnclosedBy)))
+	// field FieldsEscapedBy string
+	size += hack.RuntimeAllocSize(int64(len(cached.FieldsEscapedBy)))
+	// field LinesStartingBy string
+	size += hack.RuntimeAllocSize(int64(len(cached.LinesStartingBy)))
+	// field LinesTerminatedBy string
+	size += hack.RuntimeAllocSize(int64(len(cached.LinesTerminatedBy)))
+	// field Columns vitess.io/vitess/go/vt/sqlparser.Columns
+	{
+		size += hack.RuntimeAllocSize(int64(cap(cached.Columns)) * int64(32))
+		for _, elem := range cached.Columns {
+			size += elem.CachedSize(false)
+		}
+	}
+	// field SetExprs vitess.io/vitess/go/vt/sqlparser.UpdateExprs
+	{
+		size += hack.RuntimeAllocSize(int64(cap(cached.SetExprs)) * int64(8))
+		for _, elem := range cached.SetExprs {
+			size += elem.CachedSize(true)
+		}
+	}
+	return size
+}
 func (cached *LocateExpr) CachedSize(alloc bool) int64 {
 	if cached == nil {
 		return int64(0)
@@ -3747,6 +3794,8 @@ func (cached *QueryHints) CachedSize(alloc bool) int64 {
 	size += hack.RuntimeAllocSize(int64(len(cached.Priority)))
 	// field Timeout *int
 	size += hack.RuntimeAllocSize(int64(8))
+	// field CacheTTL *int
+	size += hack.RuntimeAllocSize(int64(8))
 	return size
 }
 func (cached *ReferenceDefinition) CachedSize(alloc bool) int64 {
@@ -37,6 +37,9 @@ const (
 	DirectiveSkipQueryPlanCache = "SKIP_QUERY_PLAN_CACHE"
 	// DirectiveQueryTimeout sets a query timeout in vtgate. Only supported for SELECTS.
 	DirectiveQueryTimeout = "QUERY_TIMEOUT_MS"
+	// DirectiveCacheTTL caches the result of the query in vtgate for the given number of milliseconds.
+	// Only supported for SELECTS on replica and rdonly tablets, a value of 0 disables the result cache.
+	DirectiveCacheTTL = "CACHE_TTL_MS"
 	// DirectiveScatterErrorsAsWarnings enables partial success scatter select queries
 	DirectiveScatterErrorsAsWarnings = "SCATTER_ERRORS_AS_WARNINGS"
 	// DirectiveIgnoreMaxPayloadSize skips payload size validation when set.
@@ -569,6 +572,7 @@ type QueryHints struct {
 	ForeignKeyChecks    *bool
 	Priority            string
 	Timeout             *int
+	CacheTTL            *int
 }
 
 func BuildQueryHints(stmt Statement) (qh QueryHints, err error) {
@@ -590,6 +594,7 @@ func BuildQueryHints(stmt Statement) (qh QueryHints, err error) {
 	qh.Workload = getWorkload(directives)
 	qh.ForeignKeyChecks = getForeignKeyChecksState(comment)
 	qh.Timeout = getQueryTimeout(directives)
+	qh.CacheTTL = getCacheTTL(stmt, directives)
 
 	return qh, nil
 }
@@ -662,3 +667,20 @@ func getQueryTimeout(directives *CommentDirectives) *int {
 	}
 	return &timeout
 }
+
+// getCacheTTL gets the result cache TTL from the provided Statement, using DirectiveCacheTTL
+func getCacheTTL(stmt Statement, directives *CommentDirectives) *int {
+	if _, isSelect := stmt.(SelectStatement); !isSelect {
+		return nil
+	}
+	ttlString, ok := directives.GetString(DirectiveCacheTTL, "")
+	if !ok || ttlString == "" {
+		return nil
+	}
+
+	ttl, err := strconv.Atoi(ttlString)
+	if err != nil || ttl < 0 {
+		return nil
+	}
+	return &ttl
+}
@@ -698,3 +698,44 @@ func TestQueryTimeout(t *testing.T) {
 		})
 	}
 }
+
+// TestCacheTTL tests the extraction of CACHE_TTL_MS from the comments.
+func TestCacheTTL(t *testing.T) {
+	testCases := []struct {
+		query  string
+		expTTL int
+		noTTL  bool
+	}{{
+		query: "select * from a_table",
+		noTTL: true,
+	}, {
+		query:  "select /*vt+ CACHE_TTL_MS=5000 */ * from another_table",
+		expTTL: 5000,
+	}, {
+		query:  "select /*vt+ CACHE_TTL_MS=0 */ * from another_table",
+		expTTL: 0,
+	}, {
+		query: "select /*vt+ CACHE_TTL_MS=-1 */ * from another_table",
+		noTTL: true,
+	}, {
+		query: "select /*vt+ CACHE_TTL_MS=abc */ * from another_table",
+		noTTL: true,
+	}, {
+		query: "update /*vt+ CACHE_TTL_MS=5000 */ a_table set a = 1",
+		noTTL: true,
+	}}
+
+	parser := NewTestParser()
+	for _, tc := range testCases {
+		t.Run(tc.query, func(t *testing.T) {
+			stmt, err := parser.Parse(tc.query)
+			assert.NoError(t, err)
+			qh, _ := BuildQueryHints(stmt)
+			if tc.noTTL {
+				assert.Nil(t, qh.CacheTTL)
+			} else {
+				assert.Equal(t, tc.expTTL, *qh.CacheTTL)
+			}
+		})
+	}
+}
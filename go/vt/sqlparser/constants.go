@@ -269,6 +269,11 @@ const (
 	IntoOutfileS3Str = " into outfile s3 "
 	IntoDumpfileStr  = " into dumpfile "
 
+	// Load defaults for the FIELDS and LINES options
+	LoadFieldsTerminatedByDefault = "\t"
+	LoadFieldsEscapedByDefault    = "\\"
+	LoadLinesTerminatedByDefault  = "\n"
+
 	// Order.Direction
 	AscScr  = "asc"
 	DescScr = "desc"
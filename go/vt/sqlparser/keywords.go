@@ -337,7 +337,7 @@ var keywords = []keyword{
 	{"in", IN},
 	{"index", INDEX},
 	{"indexes", INDEXES},
-	{"infile", UNUSED},
+	{"infile", INFILE},
 	{"inout", INOUT},
 	{"inner", INNER},
 	{"inplace", INPLACE},
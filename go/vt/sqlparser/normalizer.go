@@ -88,6 +88,9 @@ const (
 	FoundRowsName = "__vtfrows"
 	// RowCountName is the bind variable name for ROW_COUNT().
 	RowCountName = "__vtrcount"
+	// TenantIDName is the bind variable name for the tenant of the session in
+	// multi-tenant keyspaces.
+	TenantIDName = "__vttenant_id"
 	// UserDefinedVariableName is the prefix for user-defined variable bind names.
 	UserDefinedVariableName = "__vtudv"
 )
@@ -300,9 +303,16 @@ func (nz *normalizer) visitLiteral(cursor *Cursor, node *Literal) {
 		nz.convertLiteral(node, cursor)
 		return
 	}
-	switch cursor.Parent().(type) {
+	switch parent := cursor.Parent().(type) {
 	case *Order, *GroupBy:
 		return
+	case *NtileExpr, *LagLeadExpr, *NTHValueExpr:
+		// The integer argument of these window functions stays a literal, as it
+		// names the column of the function when it is evaluated on the vtgate.
+		if windowFuncN(parent) == node {
+			return
+		}
+		nz.convertLiteralDedup(node, cursor)
 	case *Limit:
 		nz.convertLiteral(node, cursor)
 	default:
@@ -310,6 +320,19 @@ func (nz *normalizer) visitLiteral(cursor *Cursor, node *Literal) {
 	}
 }
 
+// windowFuncN returns the integer argument of the NTILE, LAG, LEAD and NTH_VALUE window functions.
+func windowFuncN(node SQLNode) Expr {
+	switch node := node.(type) {
+	case *NtileExpr:
+		return node.N
+	case *LagLeadExpr:
+		return node.N
+	case *NTHValueExpr:
+		return node.N
+	}
+	return nil
+}
+
 // validateLiteral ensures that a Literal node has a valid value based on its type.
 func validateLiteral(node *Literal) error {
 	switch node.Type {
@@ -651,6 +674,7 @@ func (nz *normalizer) rewriteNotExpr(cursor *Cursor, node *NotExpr) {
 		// Invert comparison operators.
 		if canChange, inverse := inverseOp(inner.Operator); canChange {
 			inner.Operator = inverse
+			inner.Modifier = inner.Modifier.Inverse()
 			cursor.Replace(inner)
 		}
 	case *NotExpr:
@@ -258,6 +258,13 @@ func TestNormalize(t *testing.T) {
 		in:      "select a, b from t group by 1",
 		outstmt: "select a, b from t group by 1",
 		outbv:   map[string]*querypb.BindVariable{},
+	}, {
+		// integer arguments of window functions
+		in:      "select ntile(4) over w, lag(a, 2, 0) over w, nth_value(a, 3) over w from t window w as (order by b)",
+		outstmt: "select ntile(4) over w, lag(a, 2, :bv1 /* INT64 */) over w, nth_value(a, 3) over w from t window w AS ( order by b asc)",
+		outbv: map[string]*querypb.BindVariable{
+			"bv1": sqltypes.Int64BindVariable(0),
+		},
 	}, {
 		// ORDER BY with literal inside complex expression
 		in:      "select a, b from t order by field(a,1,2,3) asc",
@@ -817,6 +824,12 @@ func TestRewrites(in *testing.T) {
 	}, {
 		in:       "SELECT * FROM tbl WHERE not id not regexp '%foobar'",
 		expected: "select * from tbl where id regexp '%foobar'",
+	}, {
+		in:       "SELECT * FROM tbl WHERE not id >= any (select col from other_table)",
+		expected: "SELECT * FROM tbl WHERE id < all (select col from other_table)",
+	}, {
+		in:       "SELECT * FROM tbl WHERE not id = all (select col from other_table)",
+		expected: "SELECT * FROM tbl WHERE id != any (select col from other_table)",
 	}, {
 		in:       "SELECT * FROM tbl WHERE exists(select col1, col2 from other_table where foo > bar)",
 		expected: "SELECT * FROM tbl WHERE exists(select 1 from other_table where foo > bar)",
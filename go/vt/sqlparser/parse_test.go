@@ -4897,18 +4897,50 @@ func TestSubStr(t *testing.T) {
 }
 
 func TestLoadData(t *testing.T) {
-	validSQL := []string{
-		"load data from s3 'x.txt'",
-		"load data from s3 manifest 'x.txt'",
-		"load data from s3 file 'x.txt'",
-		"load data infile 'x.txt' into table 'c'",
-		"load data from s3 'x.txt' into table x"}
+	validSQL := []struct {
+		input  string
+		output string
+	}{{
+		input:  "load data from s3 'x.txt'",
+		output: "AST node missing for Load type",
+	}, {
+		input:  "load data from s3 manifest 'x.txt'",
+		output: "AST node missing for Load type",
+	}, {
+		input:  "load data from s3 file 'x.txt'",
+		output: "AST node missing for Load type",
+	}, {
+		input:  "load data from s3 'x.txt' into table x",
+		output: "AST node missing for Load type",
+	}, {
+		input: "load data infile 'x.txt' into table c",
+	}, {
+		input: "load data local infile '/tmp/x.txt' ignore into table ks.c partition (p0, p1)",
+	}, {
+		input:  "LOAD DATA LOCAL INFILE 'x.csv' REPLACE INTO TABLE c CHARACTER SET utf8mb4 FIELDS TERMINATED BY ',' OPTIONALLY ENCLOSED BY '\"' LINES TERMINATED BY '\r\n' IGNORE 1 LINES (a, b, c)",
+		output: "load data local infile 'x.csv' replace into table c character set utf8mb4 fields terminated by ',' optionally enclosed by '\"' lines terminated by '\\r\\n' ignore 1 lines (a, b, c)",
+	}, {
+		input:  "load data local infile 'x.txt' into table c columns terminated by '\t' escaped by '\\\\' lines starting by 'xxx' terminated by '\n'",
+		output: "load data local infile 'x.txt' into table c lines starting by 'xxx'",
+	}, {
+		input:  "load data local infile 'x.txt' into table c fields escaped by '' ignore 2 rows (a, b) set c = a + b",
+		output: "load data local infile 'x.txt' into table c fields escaped by '' ignore 2 lines (a, b) set c = a + b",
+	}}
 
 	parser := NewTestParser()
 	for _, tcase := range validSQL {
-		_, err := parser.Parse(tcase)
-		require.NoError(t, err)
+		t.Run(tcase.input, func(t *testing.T) {
+			if tcase.output == "" {
+				tcase.output = tcase.input
+			}
+			tree, err := parser.Parse(tcase.input)
+			require.NoError(t, err)
+			assert.Equal(t, tcase.output, String(tree))
+		})
 	}
+
+	_, err := parser.Parse("load data infile 'x.txt' into table 'c'")
+	require.EqualError(t, err, "syntax error at position 40 near 'c'")
 }
 
 func TestCreateTable(t *testing.T) {
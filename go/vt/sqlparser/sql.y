@@ -81,6 +81,7 @@ func markBindVariable(yylex yyLexer, bvar string) {
   alterOption     AlterOption
 
   ins           *Insert
+  load          *Load
   colName       *ColName
   colNames      []*ColName
   indexHint    *IndexHint
@@ -278,7 +279,7 @@ func markBindVariable(yylex yyLexer, bvar string) {
 %token <str> DISTINCT AS EXISTS ASC DESC INTO DUPLICATE DEFAULT SET LOCK UNLOCK KEYS DO CALL
 %left <str> ALL ANY SOME
 %token <str> DISTINCTROW PARSER GENERATED ALWAYS
-%token <str> OUTFILE S3 DATA LOAD LINES TERMINATED ESCAPED ENCLOSED
+%token <str> OUTFILE S3 DATA LOAD LINES TERMINATED ESCAPED ENCLOSED INFILE
 %token <str> DUMPFILE CSV HEADER MANIFEST OVERWRITE STARTING OPTIONALLY
 %token <str> VALUES LAST_INSERT_ID
 %token <str> NEXT VALUE SHARE MODE
@@ -553,6 +554,10 @@ func markBindVariable(yylex yyLexer, bvar string) {
 %type <boolVal> boolean_value
 %type <comparisonExprOperator> compare any_all_compare
 %type <ins> insert_data
+%type <load> load_fields_opt load_field_list load_lines_opt load_line_list
+%type <str> load_duplicate_opt
+%type <integer> load_ignore_lines_opt
+%type <updateExprs> load_set_opt
 %type <expr> num_val
 %type <expr> function_call_keyword function_call_nonkeyword function_call_generic function_call_conflict
 %type <isExprOperator> is_suffix
@@ -1037,10 +1042,120 @@ do_statement:
   }
 
 load_statement:
-  LOAD DATA skip_to_end
+  LOAD DATA FROM skip_to_end
   {
     $$ = &Load{}
   }
+| LOAD DATA local_opt INFILE STRING load_duplicate_opt INTO TABLE table_name opt_partition_clause charset_opt load_fields_opt load_lines_opt load_ignore_lines_opt column_list_opt load_set_opt
+  {
+    $$ = &Load{
+      Local: $3,
+      Infile: $5,
+      Replace: $6 == "replace",
+      Ignore: $6 == "ignore",
+      Table: $9,
+      Partitions: $10,
+      Charset: $11,
+      FieldsTerminatedBy: $12.FieldsTerminatedBy,
+      FieldsEnclosedBy: $12.FieldsEnclosedBy,
+      FieldsOptionallyEnclosed: $12.FieldsOptionallyEnclosed,
+      FieldsEscapedBy: $12.FieldsEscapedBy,
+      LinesStartingBy: $13.LinesStartingBy,
+      LinesTerminatedBy: $13.LinesTerminatedBy,
+      IgnoreLines: $14,
+      Columns: $15,
+      SetExprs: $16,
+    }
+  }
+
+load_duplicate_opt:
+  {
+    $$ = ""
+  }
+| REPLACE
+  {
+    $$ = "replace"
+  }
+| IGNORE
+  {
+    $$ = "ignore"
+  }
+
+load_fields_opt:
+  {
+    $$ = &Load{FieldsTerminatedBy: LoadFieldsTerminatedByDefault, FieldsEscapedBy: LoadFieldsEscapedByDefault}
+  }
+| columns_or_fields load_field_list
+  {
+    $$ = $2
+  }
+
+load_field_list:
+  {
+    $$ = &Load{FieldsTerminatedBy: LoadFieldsTerminatedByDefault, FieldsEscapedBy: LoadFieldsEscapedByDefault}
+  }
+| load_field_list TERMINATED BY STRING
+  {
+    $1.FieldsTerminatedBy = $4
+    $$ = $1
+  }
+| load_field_list optionally_opt ENCLOSED BY STRING
+  {
+    $1.FieldsEnclosedBy = $5
+    $1.FieldsOptionallyEnclosed = $2 != ""
+    $$ = $1
+  }
+| load_field_list ESCAPED BY STRING
+  {
+    $1.FieldsEscapedBy = $4
+    $$ = $1
+  }
+
+load_lines_opt:
+  {
+    $$ = &Load{LinesTerminatedBy: LoadLinesTerminatedByDefault}
+  }
+| LINES load_line_list
+  {
+    $$ = $2
+  }
+
+load_line_list:
+  {
+    $$ = &Load{LinesTerminatedBy: LoadLinesTerminatedByDefault}
+  }
+| load_line_list STARTING BY STRING
+  {
+    $1.LinesStartingBy = $4
+    $$ = $1
+  }
+| load_line_list TERMINATED BY STRING
+  {
+    $1.LinesTerminatedBy = $4
+    $$ = $1
+  }
+
+load_ignore_lines_opt:
+  {
+    $$ = 0
+  }
+| IGNORE INTEGRAL LINES
+  {
+    $$ = convertStringToInt($2)
+  }
+| IGNORE INTEGRAL ROWS
+  {
+    $$ = convertStringToInt($2)
+  }
+
+load_set_opt:
+  {
+    $$ = nil
+  }
+| SET update_list
+  {
+    $$ = $2
+  }
 
 with_clause:
   WITH with_list
@@ -8663,6 +8778,7 @@ reserved_keyword:
 | IGNORE
 | IN
 | INDEX
+| INFILE
 | INNER
 | INOUT
 | INSERT
@@ -57,7 +57,8 @@ var (
 	off     = "0"
 	utf8mb4 = "'utf8mb4'"
 
-	ForeignKeyChecks = "foreign_key_checks"
+	ForeignKeyChecks  = "foreign_key_checks"
+	GroupConcatMaxLen = "group_concat_max_len"
 
 	Autocommit                  = SystemVariable{Name: "autocommit", IsBoolean: true, Default: on}
 	Charset                     = SystemVariable{Name: "charset", Default: utf8mb4, IdentifierAsString: true}
@@ -78,6 +79,9 @@ var (
 	DDLStrategy      = SystemVariable{Name: "ddl_strategy", IdentifierAsString: true}
 	MigrationContext = SystemVariable{Name: "migration_context", IdentifierAsString: true}
 
+	// Multi-tenant keyspaces
+	TenantID = SystemVariable{Name: "tenant_id", IdentifierAsString: true}
+
 	// Version
 	Version        = SystemVariable{Name: "version"}
 	VersionComment = SystemVariable{Name: "version_comment"}
@@ -109,6 +113,7 @@ var (
 		ReadAfterWriteTimeOut,
 		SessionTrackGTIDs,
 		QueryTimeout,
+		TenantID,
 	}
 
 	ReadOnly = []SystemVariable{
@@ -192,7 +197,7 @@ var (
 		{Name: "eq_range_index_dive_limit", SupportSetVar: true},
 		{Name: "explicit_defaults_for_timestamp"},
 		{Name: ForeignKeyChecks, IsBoolean: true, SupportSetVar: true},
-		{Name: "group_concat_max_len", SupportSetVar: true},
+		{Name: GroupConcatMaxLen, SupportSetVar: true},
 		{Name: "information_schema_stats_expiry"},
 		{Name: "innodb_lock_wait_timeout"},
 		{Name: "max_heap_table_size", SupportSetVar: true},
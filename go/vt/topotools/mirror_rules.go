@@ -52,10 +52,48 @@ func GetMirrorRules(ctx context.Context, ts *topo.Server) (map[string]map[string
 	return rules, nil
 }
 
+// GetMirrorRulesCompareMap returns a mapping of fromTable=>toTable=>true for
+// the mirror rules which compare the results of the mirror target with those
+// of the source.
+func GetMirrorRulesCompareMap(rules *vschemapb.MirrorRules) map[string]map[string]bool {
+	compare := make(map[string]map[string]bool)
+	if rules == nil {
+		return compare
+	}
+	for _, mr := range rules.Rules {
+		if !mr.Compare {
+			continue
+		}
+		if _, ok := compare[mr.FromTable]; !ok {
+			compare[mr.FromTable] = make(map[string]bool)
+		}
+		compare[mr.FromTable][mr.ToTable] = true
+	}
+	return compare
+}
+
+// GetMirrorRulesWithCompare fetches mirror rules from the topology server and
+// returns a mapping of fromTable=>toTable=>percent, as well as the mapping of
+// the rules which compare results.
+func GetMirrorRulesWithCompare(ctx context.Context, ts *topo.Server) (map[string]map[string]float32, map[string]map[string]bool, error) {
+	mrs, err := ts.GetMirrorRules(ctx)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return GetMirrorRulesMap(mrs), GetMirrorRulesCompareMap(mrs), nil
+}
+
 // SaveMirrorRules converts a mapping of fromTable=>[]toTables into a
 // vschemapb.MirrorRules protobuf message and saves it in the topology.
 func SaveMirrorRules(ctx context.Context, ts *topo.Server, rules map[string]map[string]float32) error {
-	log.V(2).Infof("Saving mirror rules %v\n", rules)
+	return SaveMirrorRulesWithCompare(ctx, ts, rules, nil)
+}
+
+// SaveMirrorRulesWithCompare is like SaveMirrorRules, but also enables result
+// comparison on the rules found in the compare mapping.
+func SaveMirrorRulesWithCompare(ctx context.Context, ts *topo.Server, rules map[string]map[string]float32, compare map[string]map[string]bool) error {
+	log.V(2).Infof("Saving mirror rules %v, comparing %v\n", rules, compare)
 
 	rrs := &vschemapb.MirrorRules{Rules: make([]*vschemapb.MirrorRule, 0)}
 	for fromTable, mrs := range rules {
@@ -64,6 +102,7 @@ func SaveMirrorRules(ctx context.Context, ts *topo.Server, rules map[string]map[
 				FromTable: fromTable,
 				Percent:   percent,
 				ToTable:   toTable,
+				Compare:   compare[fromTable][toTable],
 			})
 		}
 	}
@@ -51,6 +51,36 @@ func TestMirrorRulesRoundTrip(t *testing.T) {
 	assert.Equal(t, rules, roundtripRules)
 }
 
+func TestMirrorRulesWithCompareRoundTrip(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	ts := memorytopo.NewServer(ctx, "zone1")
+	defer ts.Close()
+
+	rules := map[string]map[string]float32{
+		"k1.t1@replica": {
+			"k2": 50.0,
+		},
+		"k1.t4": {
+			"k3": 75.0,
+		},
+	}
+	compare := map[string]map[string]bool{
+		"k1.t4": {
+			"k3": true,
+		},
+	}
+
+	err := SaveMirrorRulesWithCompare(ctx, ts, rules, compare)
+	require.NoError(t, err, "could not save mirror rules to topo %v", rules)
+
+	roundtripRules, roundtripCompare, err := GetMirrorRulesWithCompare(ctx, ts)
+	require.NoError(t, err, "could not fetch mirror rules from topo")
+
+	assert.Equal(t, rules, roundtripRules)
+	assert.Equal(t, compare, roundtripCompare)
+}
+
 func TestMirrorRulesErrors(t *testing.T) {
 	ctx, cancel := context.WithCancel(context.Background())
 	defer cancel()
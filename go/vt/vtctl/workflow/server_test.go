@@ -1856,8 +1856,9 @@ func TestMirrorTraffic(t *testing.T) {
 		targetKeyspace string
 		targetShards   []string
 
-		wantErr         string
-		wantMirrorRules map[string]map[string]float32
+		wantErr          string
+		wantMirrorRules  map[string]map[string]float32
+		wantCompareRules map[string]map[string]bool
 	}{
 		{
 			name: "no such keyspace",
@@ -2065,6 +2066,33 @@ func TestMirrorTraffic(t *testing.T) {
 				},
 			},
 		},
+		{
+			name: "ok with result comparison",
+			req: &vtctldatapb.WorkflowMirrorTrafficRequest{
+				Keyspace:    targetKs,
+				Workflow:    workflow,
+				TabletTypes: []topodatapb.TabletType{topodatapb.TabletType_PRIMARY},
+				Percent:     50.0,
+				Compare:     true,
+			},
+			routingRules: initialRoutingRules,
+			wantMirrorRules: map[string]map[string]float32{
+				fmt.Sprintf("%s.%s", sourceKs, table1): {
+					fmt.Sprintf("%s.%s", targetKs, table1): 50.0,
+				},
+				fmt.Sprintf("%s.%s", sourceKs, table2): {
+					fmt.Sprintf("%s.%s", targetKs, table2): 50.0,
+				},
+			},
+			wantCompareRules: map[string]map[string]bool{
+				fmt.Sprintf("%s.%s", sourceKs, table1): {
+					fmt.Sprintf("%s.%s", targetKs, table1): true,
+				},
+				fmt.Sprintf("%s.%s", sourceKs, table2): {
+					fmt.Sprintf("%s.%s", targetKs, table2): true,
+				},
+			},
+		},
 		{
 			name: "does not overwrite unrelated mirror rules",
 			mirrorRules: map[string]map[string]float32{
@@ -2190,13 +2218,18 @@ func TestMirrorTraffic(t *testing.T) {
 				require.NoError(t, err)
 				require.NotNil(t, got)
 			}
-			mr, err := topotools.GetMirrorRules(ctx, te.topoServ)
+			mr, compareRules, err := topotools.GetMirrorRulesWithCompare(ctx, te.topoServ)
 			require.NoError(t, err)
 			wantMirrorRules := tt.mirrorRules
 			if tt.wantMirrorRules != nil {
 				wantMirrorRules = tt.wantMirrorRules
 			}
 			require.Equal(t, wantMirrorRules, mr)
+			wantCompareRules := tt.wantCompareRules
+			if wantCompareRules == nil {
+				wantCompareRules = map[string]map[string]bool{}
+			}
+			require.Equal(t, wantCompareRules, compareRules)
 		})
 	}
 }
@@ -164,6 +164,6 @@ func (r *switcher) initializeTargetSequences(ctx context.Context, sequencesByBac
 	return r.ts.initializeTargetSequences(ctx, sequencesByBackingTable)
 }
 
-func (r *switcher) mirrorTableTraffic(ctx context.Context, types []topodatapb.TabletType, percent float32) error {
-	return r.ts.mirrorTableTraffic(ctx, types, percent)
+func (r *switcher) mirrorTableTraffic(ctx context.Context, types []topodatapb.TabletType, percent float32, compare bool) error {
+	return r.ts.mirrorTableTraffic(ctx, types, percent, compare)
 }
@@ -64,13 +64,16 @@ func (dr *switcherDryRun) deleteKeyspaceRoutingRules(ctx context.Context) error
 	return nil
 }
 
-func (dr *switcherDryRun) mirrorTableTraffic(ctx context.Context, types []topodatapb.TabletType, percent float32) error {
+func (dr *switcherDryRun) mirrorTableTraffic(ctx context.Context, types []topodatapb.TabletType, percent float32, compare bool) error {
 	var tabletTypes []string
 	for _, servedType := range types {
 		tabletTypes = append(tabletTypes, servedType.String())
 	}
 	dr.drLog.Logf("Mirroring %.2f percent of traffic from keyspace %s to keyspace %s for tablet types [%s]",
 		percent, dr.ts.SourceKeyspaceName(), dr.ts.TargetKeyspaceName(), strings.Join(tabletTypes, ","))
+	if compare && percent > 0 {
+		dr.drLog.Log("Results of mirrored queries will be compared with those of the source keyspace")
+	}
 
 	return nil
 }
@@ -1545,8 +1545,8 @@ func (ts *trafficSwitcher) IsMultiTenantMigration() bool {
 	return false
 }
 
-func (ts *trafficSwitcher) mirrorTableTraffic(ctx context.Context, types []topodatapb.TabletType, percent float32) error {
-	mrs, err := topotools.GetMirrorRules(ctx, ts.TopoServer())
+func (ts *trafficSwitcher) mirrorTableTraffic(ctx context.Context, types []topodatapb.TabletType, percent float32, compare bool) error {
+	mrs, compareRules, err := topotools.GetMirrorRulesWithCompare(ctx, ts.TopoServer())
 	if err != nil {
 		return err
 	}
@@ -1572,9 +1572,18 @@ func (ts *trafficSwitcher) mirrorTableTraffic(ctx context.Context, types []topod
 				// When percent is 0, remove mirror rule if it exists.
 				if _, ok := mrs[fromTable][toTable]; ok {
 					delete(mrs, fromTable)
+					delete(compareRules, fromTable)
 				}
 			} else {
 				mrs[fromTable][toTable] = percent
+				if compare {
+					if _, ok := compareRules[fromTable]; !ok {
+						compareRules[fromTable] = make(map[string]bool)
+					}
+					compareRules[fromTable][toTable] = true
+				} else {
+					delete(compareRules[fromTable], toTable)
+				}
 			}
 		}
 	}
@@ -1583,7 +1592,7 @@ func (ts *trafficSwitcher) mirrorTableTraffic(ctx context.Context, types []topod
 		return vterrors.Errorf(vtrpcpb.Code_ALREADY_EXISTS, "wrong number of pre-existing mirror rules")
 	}
 
-	if err := topotools.SaveMirrorRules(ctx, ts.TopoServer(), mrs); err != nil {
+	if err := topotools.SaveMirrorRulesWithCompare(ctx, ts.TopoServer(), mrs, compareRules); err != nil {
 		return err
 	}
 
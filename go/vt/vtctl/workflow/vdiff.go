@@ -362,6 +362,8 @@ func (s *Server) VDiffCreate(ctx context.Context, req *vtctldatapb.VDiffCreateRe
 			UpdateTableStats:      req.UpdateTableStats,
 			MaxDiffSeconds:        req.MaxDiffDuration.Seconds,
 			AutoStart:             &autoStart,
+			Checksum:              req.Checksum,
+			SamplePct:             req.SamplePct,
 		},
 		ReportOptions: &tabletmanagerdatapb.VDiffReportOptions{
 			OnlyPks:                 req.OnlyPKs,
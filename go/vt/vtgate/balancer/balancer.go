@@ -92,6 +92,10 @@ type TabletBalancer interface {
 	// for a given query to maintain the desired balanced allocation over multiple executions.
 	Pick(target *querypb.Target, tablets []*discovery.TabletHealth) *discovery.TabletHealth
 
+	// TrackQuery is called when a request is sent to a tablet. It returns a function
+	// that must be called with the outcome of the request once it is done.
+	TrackQuery(tablet *discovery.TabletHealth, streaming bool) func(err error)
+
 	// DebugHandler provides a summary of tablet balancer state
 	DebugHandler(w http.ResponseWriter, r *http.Request)
 }
@@ -188,6 +192,11 @@ func (b *tabletBalancer) Pick(target *querypb.Target, tablets []*discovery.Table
 	return tablets[0]
 }
 
+// TrackQuery is a no-op, as the allocation only depends on the topology.
+func (b *tabletBalancer) TrackQuery(*discovery.TabletHealth, bool) func(error) {
+	return func(error) {}
+}
+
 // To stick with integer arithmetic, use 1,000,000 as the full load
 const ALLOCATION = 1000000
 
@@ -0,0 +1,346 @@
+/*
+Copyright 2025 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package balancer
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"math/rand/v2"
+	"net/http"
+	"sort"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"vitess.io/vitess/go/vt/discovery"
+	querypb "vitess.io/vitess/go/vt/proto/query"
+	topodatapb "vitess.io/vitess/go/vt/proto/topodata"
+	vtrpcpb "vitess.io/vitess/go/vt/proto/vtrpc"
+	"vitess.io/vitess/go/vt/topo/topoproto"
+	"vitess.io/vitess/go/vt/vterrors"
+)
+
+/*
+
+The loadBalancer picks tablets based on the load this vtgate observes on them,
+rather than on the topology alone. For every tablet it tracks:
+
+* The number of requests currently in flight from this vtgate
+* An exponentially weighted moving average (EWMA) of the response times
+
+Both are updated by the gateway through TrackQuery, for every request sent to
+a tablet. The replication lag reported by the health stream is applied as a
+penalty on top of the observed load, so that lagging replicas get less traffic
+even before their response times degrade.
+
+The load of a tablet is scored as:
+
+  least-outstanding: (in flight + 1) * lag penalty
+  ewma, power-of-two: average response time * (in flight + 1) * lag penalty
+
+where the lag penalty is 1 + replication lag / lag penalty interval, i.e. a
+tablet lagging by one interval is considered twice as loaded.
+
+The least-outstanding and ewma modes send each request to the tablet with the
+lowest score, preferring the local cell when scores are equal. The
+power-of-two mode compares the scores of two tablets picked at random, which
+avoids sending bursts of requests to the same tablet before its score catches
+up.
+
+The load of the tablets that left the healthcheck, and have no request in
+flight, is periodically pruned, so that the tracked loads do not grow with
+every tablet ever seen by this vtgate.
+
+*/
+
+// Modes of the tablet balancer.
+const (
+	// ModeCell balances the query load across cells, based on the cells that
+	// contain vtgates and tablets.
+	ModeCell = "cell"
+	// ModeLeastOutstanding picks the tablet with the fewest requests in
+	// flight from this vtgate.
+	ModeLeastOutstanding = "least-outstanding"
+	// ModeEWMA picks the tablet with the lowest expected response time, based
+	// on the average of its observed response times and its requests in flight.
+	ModeEWMA = "ewma"
+	// ModePowerOfTwo picks two tablets at random and uses the one with the
+	// lowest expected response time, as computed by ModeEWMA.
+	ModePowerOfTwo = "power-of-two"
+)
+
+// Modes lists the supported balancer modes.
+var Modes = []string{ModeCell, ModeLeastOutstanding, ModeEWMA, ModePowerOfTwo}
+
+// ewmaWeight is the weight given to a new response time in the moving average.
+const ewmaWeight = 0.2
+
+// loadPruneInterval is how often the load of the tablets that left the
+// healthcheck is pruned.
+const loadPruneInterval = time.Minute
+
+type loadBalancer struct {
+	// The balancing mode, one of the load-aware Modes
+	mode string
+
+	// The local cell for the vtgate
+	localCell string
+
+	// The replication lag at which the load of a tablet is considered to
+	// have doubled. A zero value disables the replication lag penalty.
+	lagPenalty time.Duration
+
+	// mu protects the loads map
+	mu    sync.RWMutex
+	loads map[tabletKey]*tabletLoad
+}
+
+type tabletKey struct {
+	cell string
+	uid  uint32
+}
+
+// tabletLoad is the load this vtgate observes on one tablet.
+type tabletLoad struct {
+	alias    string
+	inFlight atomic.Int64
+
+	// mu protects the response time average
+	mu   sync.Mutex
+	ewma float64 // seconds, or zero if no response was observed yet
+}
+
+// NewLoadBalancer creates a balancer that picks tablets based on their
+// observed load, using one of the load-aware modes. The load of the tablets
+// that leave the given healthcheck is pruned until the context is done.
+func NewLoadBalancer(ctx context.Context, hc discovery.HealthCheck, mode string, localCell string, lagPenalty time.Duration) (TabletBalancer, error) {
+	switch mode {
+	case ModeLeastOutstanding, ModeEWMA, ModePowerOfTwo:
+	default:
+		return nil, vterrors.Errorf(vtrpcpb.Code_INVALID_ARGUMENT, "balancer mode %q is not load-aware", mode)
+	}
+	b := &loadBalancer{
+		mode:       mode,
+		localCell:  localCell,
+		lagPenalty: lagPenalty,
+		loads:      map[tabletKey]*tabletLoad{},
+	}
+	if hc != nil {
+		go b.pruneLoads(ctx, hc)
+	}
+	return b, nil
+}
+
+// pruneLoads prunes the load of the tablets that left the healthcheck every
+// loadPruneInterval, until the context is done.
+func (b *loadBalancer) pruneLoads(ctx context.Context, hc discovery.HealthCheck) {
+	ticker := time.NewTicker(loadPruneInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			b.prune(hc)
+		}
+	}
+}
+
+// prune forgets the load of the tablets that are no longer in the healthcheck.
+// The load of a tablet with requests in flight is kept, as they still count
+// towards its score if the tablet comes back.
+func (b *loadBalancer) prune(hc discovery.HealthCheck) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	for key, load := range b.loads {
+		if load.inFlight.Load() > 0 {
+			continue
+		}
+		if _, err := hc.GetTabletHealthByAlias(&topodatapb.TabletAlias{Cell: key.cell, Uid: key.uid}); err != nil {
+			delete(b.loads, key)
+		}
+	}
+}
+
+// Pick returns the tablet with the lowest load score, out of all tablets or
+// out of two random ones in power-of-two mode.
+func (b *loadBalancer) Pick(target *querypb.Target, tablets []*discovery.TabletHealth) *discovery.TabletHealth {
+	switch len(tablets) {
+	case 0:
+		return nil
+	case 1:
+		return tablets[0]
+	}
+
+	candidates := tablets
+	if b.mode == ModePowerOfTwo {
+		i := rand.IntN(len(tablets))
+		j := rand.IntN(len(tablets) - 1)
+		if j >= i {
+			j++
+		}
+		candidates = []*discovery.TabletHealth{tablets[i], tablets[j]}
+	}
+
+	scores := b.scores(candidates)
+
+	// Start at a random offset, so that ties are broken randomly.
+	offset := rand.IntN(len(candidates))
+	best := offset
+	for n := 1; n < len(candidates); n++ {
+		i := (offset + n) % len(candidates)
+		switch {
+		case scores[i] < scores[best]:
+			best = i
+		case scores[i] == scores[best] && candidates[i].Tablet.Alias.Cell == b.localCell && candidates[best].Tablet.Alias.Cell != b.localCell:
+			best = i
+		}
+	}
+	return candidates[best]
+}
+
+// scores returns the load score of each of the given tablets.
+func (b *loadBalancer) scores(tablets []*discovery.TabletHealth) []float64 {
+	scores := make([]float64, len(tablets))
+	latencies := make([]float64, len(tablets))
+
+	// Tablets without any observed response time are assumed to be as fast
+	// as the fastest known one, so that they get their share of traffic.
+	minLatency := 0.0
+	for i, th := range tablets {
+		load := b.load(th.Tablet.Alias)
+		scores[i] = float64(load.inFlight.Load() + 1)
+		if b.mode == ModeLeastOutstanding {
+			continue
+		}
+		latencies[i] = load.latency()
+		if latencies[i] > 0 && (minLatency == 0 || latencies[i] < minLatency) {
+			minLatency = latencies[i]
+		}
+	}
+
+	for i, th := range tablets {
+		if b.mode != ModeLeastOutstanding {
+			latency := latencies[i]
+			if latency == 0 {
+				latency = minLatency
+			}
+			if latency > 0 {
+				scores[i] *= latency
+			}
+		}
+		scores[i] *= b.lagPenaltyFactor(th)
+	}
+	return scores
+}
+
+// lagPenaltyFactor returns the factor by which the load of a tablet is
+// multiplied to account for its replication lag.
+func (b *loadBalancer) lagPenaltyFactor(th *discovery.TabletHealth) float64 {
+	if b.lagPenalty <= 0 || th.Stats == nil || th.Stats.ReplicationLagSeconds == 0 {
+		return 1
+	}
+	lag := time.Duration(th.Stats.ReplicationLagSeconds) * time.Second
+	return 1 + float64(lag)/float64(b.lagPenalty)
+}
+
+// TrackQuery counts the request as in flight until the returned function is
+// called, and records its response time unless it is streaming.
+func (b *loadBalancer) TrackQuery(th *discovery.TabletHealth, streaming bool) func(err error) {
+	load := b.load(th.Tablet.Alias)
+	load.inFlight.Add(1)
+	start := time.Now()
+	return func(err error) {
+		load.inFlight.Add(-1)
+		if !streaming {
+			load.observe(time.Since(start), err != nil)
+		}
+	}
+}
+
+// load returns the load tracked for the given tablet, creating it if needed.
+func (b *loadBalancer) load(alias *topodatapb.TabletAlias) *tabletLoad {
+	key := tabletKey{cell: alias.Cell, uid: alias.Uid}
+
+	b.mu.RLock()
+	load, ok := b.loads[key]
+	b.mu.RUnlock()
+	if ok {
+		return load
+	}
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if load, ok = b.loads[key]; !ok {
+		load = &tabletLoad{alias: topoproto.TabletAliasString(alias)}
+		b.loads[key] = load
+	}
+	return load
+}
+
+// latency returns the average response time of the tablet, in seconds.
+func (l *tabletLoad) latency() float64 {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return l.ewma
+}
+
+// observe adds a response time to the moving average. Failed requests count
+// as at least twice the current average, so that tablets which fail fast do
+// not attract more traffic.
+func (l *tabletLoad) observe(elapsed time.Duration, failed bool) {
+	sample := elapsed.Seconds()
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if failed && sample < 2*l.ewma {
+		sample = 2 * l.ewma
+	}
+	if l.ewma == 0 {
+		l.ewma = sample
+		return
+	}
+	l.ewma += ewmaWeight * (sample - l.ewma)
+}
+
+func (b *loadBalancer) DebugHandler(w http.ResponseWriter, _ *http.Request) {
+	type tabletState struct {
+		Tablet      string
+		InFlight    int64
+		LatencyEWMA string
+	}
+
+	b.mu.RLock()
+	tablets := make([]tabletState, 0, len(b.loads))
+	for _, load := range b.loads {
+		tablets = append(tablets, tabletState{
+			Tablet:      load.alias,
+			InFlight:    load.inFlight.Load(),
+			LatencyEWMA: time.Duration(load.latency() * float64(time.Second)).String(),
+		})
+	}
+	b.mu.RUnlock()
+	sort.Slice(tablets, func(i, j int) bool { return tablets[i].Tablet < tablets[j].Tablet })
+
+	w.Header().Set("Content-Type", "text/plain")
+	fmt.Fprintf(w, "Mode: %v\r\n", b.mode)
+	fmt.Fprintf(w, "Local Cell: %v\r\n", b.localCell)
+	fmt.Fprintf(w, "Replication Lag Penalty: %v\r\n", b.lagPenalty)
+	loads, _ := json.MarshalIndent(tablets, "", "  ")
+	fmt.Fprintf(w, "Tablets: %v\r\n", string(loads))
+}
@@ -0,0 +1,221 @@
+/*
+Copyright 2025 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package balancer
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"vitess.io/vitess/go/vt/discovery"
+	querypb "vitess.io/vitess/go/vt/proto/query"
+)
+
+func newTestLoadBalancer(t *testing.T, mode string, localCell string, lagPenalty time.Duration) *loadBalancer {
+	b, err := NewLoadBalancer(context.Background(), nil, mode, localCell, lagPenalty)
+	require.NoError(t, err)
+	return b.(*loadBalancer)
+}
+
+func TestNewLoadBalancerInvalidMode(t *testing.T) {
+	for _, mode := range []string{ModeCell, "", "random"} {
+		_, err := NewLoadBalancer(context.Background(), nil, mode, "a", 0)
+		assert.ErrorContains(t, err, "is not load-aware", mode)
+	}
+}
+
+func TestLoadBalancerPickSingle(t *testing.T) {
+	target := &querypb.Target{Keyspace: "k", Shard: "s"}
+	for _, mode := range Modes[1:] {
+		b := newTestLoadBalancer(t, mode, "a", 0)
+		assert.Nil(t, b.Pick(target, nil))
+
+		tablet := createTestTablet("b")
+		assert.Equal(t, tablet, b.Pick(target, []*discovery.TabletHealth{tablet}))
+	}
+}
+
+func TestLoadBalancerLeastOutstanding(t *testing.T) {
+	target := &querypb.Target{Keyspace: "k", Shard: "s"}
+	b := newTestLoadBalancer(t, ModeLeastOutstanding, "a", 0)
+
+	tablets := []*discovery.TabletHealth{createTestTablet("a"), createTestTablet("a"), createTestTablet("b")}
+	done0 := b.TrackQuery(tablets[0], false)
+	done1 := b.TrackQuery(tablets[1], false)
+	_ = b.TrackQuery(tablets[1], true)
+
+	for range 100 {
+		assert.Equal(t, tablets[2], b.Pick(target, tablets))
+	}
+
+	// Once the requests complete, the local cell is preferred again.
+	done0(nil)
+	done1(nil)
+	for range 100 {
+		assert.Equal(t, tablets[0], b.Pick(target, tablets))
+	}
+	assert.EqualValues(t, 1, b.load(tablets[1].Tablet.Alias).inFlight.Load())
+}
+
+func TestLoadBalancerEWMA(t *testing.T) {
+	target := &querypb.Target{Keyspace: "k", Shard: "s"}
+	b := newTestLoadBalancer(t, ModeEWMA, "a", 0)
+
+	tablets := []*discovery.TabletHealth{createTestTablet("a"), createTestTablet("b")}
+	b.load(tablets[0].Tablet.Alias).observe(100*time.Millisecond, false)
+	b.load(tablets[1].Tablet.Alias).observe(10*time.Millisecond, false)
+
+	for range 100 {
+		assert.Equal(t, tablets[1], b.Pick(target, tablets))
+	}
+
+	// With enough requests in flight, the faster tablet is considered more
+	// loaded than the slower one.
+	for range 10 {
+		_ = b.TrackQuery(tablets[1], false)
+	}
+	for range 100 {
+		assert.Equal(t, tablets[0], b.Pick(target, tablets))
+	}
+}
+
+func TestLoadBalancerEWMAUnsampled(t *testing.T) {
+	target := &querypb.Target{Keyspace: "k", Shard: "s"}
+	b := newTestLoadBalancer(t, ModeEWMA, "a", 0)
+
+	// A tablet without any samples is assumed to be as fast as the fastest
+	// one, so it gets picked over a slower tablet.
+	tablets := []*discovery.TabletHealth{createTestTablet("b"), createTestTablet("b"), createTestTablet("b")}
+	b.load(tablets[0].Tablet.Alias).observe(10*time.Millisecond, false)
+	b.load(tablets[1].Tablet.Alias).observe(50*time.Millisecond, false)
+
+	picks := map[*discovery.TabletHealth]int{}
+	for range 100 {
+		picks[b.Pick(target, tablets)]++
+	}
+	assert.Zero(t, picks[tablets[1]])
+	assert.NotZero(t, picks[tablets[0]])
+	assert.NotZero(t, picks[tablets[2]])
+}
+
+func TestLoadBalancerObserve(t *testing.T) {
+	var l tabletLoad
+	l.observe(100*time.Millisecond, false)
+	assert.InDelta(t, 0.1, l.latency(), 1e-9)
+
+	l.observe(200*time.Millisecond, false)
+	assert.InDelta(t, 0.12, l.latency(), 1e-9)
+
+	// A fast failure counts as twice the average.
+	l.observe(time.Millisecond, true)
+	assert.InDelta(t, 0.144, l.latency(), 1e-9)
+}
+
+func TestLoadBalancerTrackQuery(t *testing.T) {
+	b := newTestLoadBalancer(t, ModeEWMA, "a", 0)
+	tablet := createTestTablet("a")
+	load := b.load(tablet.Tablet.Alias)
+
+	done := b.TrackQuery(tablet, true)
+	assert.EqualValues(t, 1, load.inFlight.Load())
+	done(nil)
+	assert.EqualValues(t, 0, load.inFlight.Load())
+	assert.Zero(t, load.latency(), "streaming requests should not be sampled")
+
+	done = b.TrackQuery(tablet, false)
+	done(errors.New("failed"))
+	assert.EqualValues(t, 0, load.inFlight.Load())
+	assert.NotZero(t, load.latency())
+}
+
+func TestLoadBalancerPrune(t *testing.T) {
+	b := newTestLoadBalancer(t, ModeEWMA, "a", 0)
+	hc := discovery.NewFakeHealthCheck(nil)
+	tablets := []*discovery.TabletHealth{createTestTablet("a"), createTestTablet("a"), createTestTablet("b")}
+	for _, th := range tablets {
+		hc.AddTablet(th.Tablet)
+		b.load(th.Tablet.Alias).observe(10*time.Millisecond, false)
+	}
+
+	b.prune(hc)
+	assert.Len(t, b.loads, 3)
+
+	// The load of a removed tablet is kept while it has requests in flight.
+	hc.RemoveTablet(tablets[0].Tablet)
+	hc.RemoveTablet(tablets[2].Tablet)
+	done := b.TrackQuery(tablets[2], false)
+	b.prune(hc)
+	assert.Len(t, b.loads, 2)
+	assert.Contains(t, b.loads, tabletKey{cell: "a", uid: tablets[1].Tablet.Alias.Uid})
+	assert.Contains(t, b.loads, tabletKey{cell: "b", uid: tablets[2].Tablet.Alias.Uid})
+
+	done(nil)
+	b.prune(hc)
+	assert.Len(t, b.loads, 1)
+	assert.Contains(t, b.loads, tabletKey{cell: "a", uid: tablets[1].Tablet.Alias.Uid})
+}
+
+func TestLoadBalancerReplicationLag(t *testing.T) {
+	target := &querypb.Target{Keyspace: "k", Shard: "s"}
+	b := newTestLoadBalancer(t, ModeLeastOutstanding, "a", 10*time.Second)
+
+	tablets := []*discovery.TabletHealth{createTestTablet("a"), createTestTablet("a")}
+	tablets[0].Stats = &querypb.RealtimeStats{ReplicationLagSeconds: 10}
+	assert.Equal(t, 2.0, b.lagPenaltyFactor(tablets[0]))
+	assert.Equal(t, 1.0, b.lagPenaltyFactor(tablets[1]))
+
+	for range 100 {
+		assert.Equal(t, tablets[1], b.Pick(target, tablets))
+	}
+
+	// One request in flight on the tablet without lag makes both equivalent,
+	// two make the lagging tablet the least loaded one.
+	_ = b.TrackQuery(tablets[1], false)
+	_ = b.TrackQuery(tablets[1], false)
+	for range 100 {
+		assert.Equal(t, tablets[0], b.Pick(target, tablets))
+	}
+
+	// Without a penalty, the replication lag is ignored.
+	b = newTestLoadBalancer(t, ModeLeastOutstanding, "a", 0)
+	assert.Equal(t, 1.0, b.lagPenaltyFactor(tablets[0]))
+}
+
+func TestLoadBalancerPowerOfTwo(t *testing.T) {
+	target := &querypb.Target{Keyspace: "k", Shard: "s"}
+	b := newTestLoadBalancer(t, ModePowerOfTwo, "a", 0)
+
+	tablets := []*discovery.TabletHealth{createTestTablet("a"), createTestTablet("a"), createTestTablet("a")}
+	for _, th := range tablets {
+		b.load(th.Tablet.Alias).observe(10*time.Millisecond, false)
+	}
+	b.load(tablets[0].Tablet.Alias).observe(time.Second, false)
+
+	// The slowest tablet is only picked when compared with itself, which
+	// never happens, so it never gets picked. The others share the load.
+	picks := map[*discovery.TabletHealth]int{}
+	for range 300 {
+		picks[b.Pick(target, tablets)]++
+	}
+	assert.Zero(t, picks[tablets[0]])
+	assert.NotZero(t, picks[tablets[1]])
+	assert.NotZero(t, picks[tablets[2]])
+}
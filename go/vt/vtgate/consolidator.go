@@ -0,0 +1,183 @@
+/*
+Copyright 2025 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package vtgate
+
+import (
+	"context"
+	"sync"
+
+	"vitess.io/vitess/go/cache/theine"
+	"vitess.io/vitess/go/sqltypes"
+	"vitess.io/vitess/go/stats"
+	querypb "vitess.io/vitess/go/vt/proto/query"
+	topodatapb "vitess.io/vitess/go/vt/proto/topodata"
+	vtrpcpb "vitess.io/vitess/go/vt/proto/vtrpc"
+	"vitess.io/vitess/go/vt/vterrors"
+)
+
+/*
+
+The queryConsolidator shares the result of a read-only query between all the
+identical queries that arrive while it is in flight, like the consolidator of
+vttablet does for the queries it sends to MySQL. Consolidating in vtgate also
+saves the fan out of scatter queries: without it, a burst of identical scatter
+queries, e.g. after a cache flush in the application, multiplies the load on
+every shard.
+
+Queries are identical if they have the same plan key, which includes the
+target, and the same bind variables, tenant and caller. The first query
+executes the plan, and the queries that arrive while it runs wait for it and
+return its result.
+
+The result is not shared if it is larger than the maximum result size, so
+that large results are not held for, and shared between, an unbounded number
+of sessions, nor if the first query was canceled or timed out, since the
+timeout of a session is not part of the key. One of the waiting queries then
+executes the plan, and the others wait for it in turn, so that a result that
+cannot be shared does not release all of them against the shards at once.
+
+*/
+
+var (
+	consolidatorWaits            = stats.NewCounter("ConsolidatorWaits", "Queries that waited for the result of an identical query in flight")
+	consolidatorOversizedResults = stats.NewCounter("ConsolidatorOversizedResults", "Results too large to be shared by the query consolidator")
+)
+
+type queryConsolidator struct {
+	// mode is the default consolidator mode of the queries, which the
+	// CONSOLIDATOR directive of a query overrides, except for the queries
+	// to the primary, which the directive can only disable.
+	mode querypb.ExecuteOptions_Consolidator
+	// maxResultSize is the size, in bytes, above which a result is not shared
+	// with the waiting queries. Zero means no limit.
+	maxResultSize int64
+
+	// mu protects the queries map
+	mu      sync.Mutex
+	queries map[theine.HashKey256]*pendingQuery
+}
+
+// pendingQuery is a query in flight, and the result it shares once done.
+type pendingQuery struct {
+	done chan struct{}
+
+	// The fields below must only be read after done is closed.
+	result *sqltypes.Result
+	err    error
+	// shared is false if one of the waiting queries must execute the plan
+	shared bool
+}
+
+// consolidatorMode returns the default consolidator mode set by the flags.
+func consolidatorMode() querypb.ExecuteOptions_Consolidator {
+	switch {
+	case enableConsolidatorReplicas:
+		return querypb.ExecuteOptions_CONSOLIDATOR_ENABLED_REPLICAS
+	case enableConsolidator:
+		return querypb.ExecuteOptions_CONSOLIDATOR_ENABLED
+	}
+	return querypb.ExecuteOptions_CONSOLIDATOR_DISABLED
+}
+
+func newQueryConsolidator(mode querypb.ExecuteOptions_Consolidator, maxResultSize int64) *queryConsolidator {
+	return &queryConsolidator{
+		mode:          mode,
+		maxResultSize: maxResultSize,
+		queries:       map[theine.HashKey256]*pendingQuery{},
+	}
+}
+
+// enabled returns true if the queries with the given consolidator directive
+// are consolidated when they run on the given tablet type. The CONSOLIDATOR
+// directive is also sent to vttablet, so enabling the consolidation of the
+// reads of the primary in vtgate is left to the flags.
+func (qc *queryConsolidator) enabled(directive querypb.ExecuteOptions_Consolidator, tabletType topodatapb.TabletType) bool {
+	mode := qc.mode
+	if directive != querypb.ExecuteOptions_CONSOLIDATOR_UNSPECIFIED {
+		mode = directive
+	}
+	switch mode {
+	case querypb.ExecuteOptions_CONSOLIDATOR_ENABLED:
+		return tabletType != topodatapb.TabletType_PRIMARY || qc.mode == querypb.ExecuteOptions_CONSOLIDATOR_ENABLED
+	case querypb.ExecuteOptions_CONSOLIDATOR_ENABLED_REPLICAS:
+		return tabletType != topodatapb.TabletType_PRIMARY
+	}
+	return false
+}
+
+// execute waits for the result of the identical query in flight with the
+// given key, or executes the query and shares its result with the identical
+// queries that arrive in the meantime.
+func (qc *queryConsolidator) execute(ctx context.Context, key theine.HashKey256, exec func() (*sqltypes.Result, error)) (*sqltypes.Result, error) {
+	qc.mu.Lock()
+	if pq, ok := qc.queries[key]; ok {
+		qc.mu.Unlock()
+		return qc.wait(ctx, key, pq, exec)
+	}
+	pq := &pendingQuery{done: make(chan struct{})}
+	qc.queries[key] = pq
+	qc.mu.Unlock()
+
+	// The deferred function also releases the waiting queries if exec panics,
+	// in which case they execute the query themselves.
+	defer func() {
+		qc.mu.Lock()
+		delete(qc.queries, key)
+		qc.mu.Unlock()
+		close(pq.done)
+	}()
+
+	qr, err := exec()
+	switch {
+	case err != nil:
+		// An error caused by the context or the timeout of this query, or a
+		// kill, does not apply to the waiting queries.
+		switch vterrors.Code(err) {
+		case vtrpcpb.Code_DEADLINE_EXCEEDED, vtrpcpb.Code_CANCELED:
+		default:
+			pq.err = err
+			pq.shared = ctx.Err() == nil
+		}
+	case qc.maxResultSize == 0 || qr.CachedSize(true) <= qc.maxResultSize:
+		// The caller may modify its result once returned, the waiting
+		// queries get copies of this one.
+		pq.result = qr.ShallowCopy()
+		pq.shared = true
+	default:
+		consolidatorOversizedResults.Add(1)
+	}
+	return qr, err
+}
+
+// wait returns the result of the pending query once it is done. If that
+// result cannot be shared, the first waiting query to get there executes the
+// query for the others.
+func (qc *queryConsolidator) wait(ctx context.Context, key theine.HashKey256, pq *pendingQuery, exec func() (*sqltypes.Result, error)) (*sqltypes.Result, error) {
+	consolidatorWaits.Add(1)
+	select {
+	case <-pq.done:
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+	if !pq.shared {
+		return qc.execute(ctx, key, exec)
+	}
+	if pq.err != nil {
+		return nil, pq.err
+	}
+	return pq.result.ShallowCopy(), nil
+}
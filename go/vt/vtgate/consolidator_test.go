@@ -0,0 +1,320 @@
+/*
+Copyright 2025 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package vtgate
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"vitess.io/vitess/go/sqltypes"
+	"vitess.io/vitess/go/test/utils"
+	querypb "vitess.io/vitess/go/vt/proto/query"
+	topodatapb "vitess.io/vitess/go/vt/proto/topodata"
+	vtgatepb "vitess.io/vitess/go/vt/proto/vtgate"
+	vtrpcpb "vitess.io/vitess/go/vt/proto/vtrpc"
+	"vitess.io/vitess/go/vt/vterrors"
+	"vitess.io/vitess/go/vt/vtgate/engine"
+	econtext "vitess.io/vitess/go/vt/vtgate/executorcontext"
+)
+
+func TestQueryConsolidatorEnabled(t *testing.T) {
+	tcases := []struct {
+		mode      querypb.ExecuteOptions_Consolidator
+		directive querypb.ExecuteOptions_Consolidator
+		primary   bool
+		replica   bool
+	}{{
+		mode: querypb.ExecuteOptions_CONSOLIDATOR_DISABLED,
+	}, {
+		mode:    querypb.ExecuteOptions_CONSOLIDATOR_ENABLED,
+		primary: true,
+		replica: true,
+	}, {
+		mode:    querypb.ExecuteOptions_CONSOLIDATOR_ENABLED_REPLICAS,
+		replica: true,
+	}, {
+		mode:      querypb.ExecuteOptions_CONSOLIDATOR_ENABLED,
+		directive: querypb.ExecuteOptions_CONSOLIDATOR_DISABLED,
+	}, {
+		mode:      querypb.ExecuteOptions_CONSOLIDATOR_DISABLED,
+		directive: querypb.ExecuteOptions_CONSOLIDATOR_ENABLED,
+		replica:   true,
+	}, {
+		mode:      querypb.ExecuteOptions_CONSOLIDATOR_ENABLED_REPLICAS,
+		directive: querypb.ExecuteOptions_CONSOLIDATOR_ENABLED,
+		replica:   true,
+	}, {
+		mode:      querypb.ExecuteOptions_CONSOLIDATOR_ENABLED,
+		directive: querypb.ExecuteOptions_CONSOLIDATOR_ENABLED,
+		primary:   true,
+		replica:   true,
+	}, {
+		mode:      querypb.ExecuteOptions_CONSOLIDATOR_ENABLED,
+		directive: querypb.ExecuteOptions_CONSOLIDATOR_ENABLED_REPLICAS,
+		replica:   true,
+	}}
+	for _, tcase := range tcases {
+		t.Run(tcase.mode.String()+"/"+tcase.directive.String(), func(t *testing.T) {
+			qc := newQueryConsolidator(tcase.mode, 0)
+			assert.Equal(t, tcase.primary, qc.enabled(tcase.directive, topodatapb.TabletType_PRIMARY))
+			assert.Equal(t, tcase.replica, qc.enabled(tcase.directive, topodatapb.TabletType_REPLICA))
+			assert.Equal(t, tcase.replica, qc.enabled(tcase.directive, topodatapb.TabletType_RDONLY))
+		})
+	}
+}
+
+// consolidate starts the given number of identical queries in the
+// consolidator, and returns their results once the first one, which is
+// blocked until release is closed, is done.
+func consolidate(t *testing.T, ctx context.Context, qc *queryConsolidator, waiters int, exec func() (*sqltypes.Result, error)) ([]*sqltypes.Result, []error) {
+	t.Helper()
+	key := resultCacheKey(engine.PlanKey{Query: "select id from t1"}, "", "", nil)
+	results := make([]*sqltypes.Result, waiters+1)
+	errs := make([]error, waiters+1)
+
+	started := make(chan struct{})
+	release := make(chan struct{})
+	var wg sync.WaitGroup
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		results[0], errs[0] = qc.execute(ctx, key, func() (*sqltypes.Result, error) {
+			close(started)
+			<-release
+			return exec()
+		})
+	}()
+	<-started
+
+	waits := consolidatorWaits.Get()
+	for i := 1; i <= waiters; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			results[i], errs[i] = qc.execute(ctx, key, exec)
+		}()
+	}
+	// Release the first query once all the others are waiting for it.
+	require.Eventually(t, func() bool {
+		return consolidatorWaits.Get()-waits == int64(waiters)
+	}, 10*time.Second, time.Millisecond)
+	close(release)
+	wg.Wait()
+	return results, errs
+}
+
+func TestQueryConsolidatorExecute(t *testing.T) {
+	result := sqltypes.MakeTestResult(sqltypes.MakeTestFields("id", "int64"), "1", "2")
+
+	t.Run("shared result", func(t *testing.T) {
+		qc := newQueryConsolidator(querypb.ExecuteOptions_CONSOLIDATOR_ENABLED, 0)
+		var executions atomic.Int64
+		results, errs := consolidate(t, context.Background(), qc, 3, func() (*sqltypes.Result, error) {
+			executions.Add(1)
+			return result, nil
+		})
+		assert.EqualValues(t, 1, executions.Load())
+		for i := range results {
+			require.NoError(t, errs[i])
+			assert.Equal(t, result, results[i])
+		}
+		assert.Empty(t, qc.queries)
+	})
+
+	t.Run("shared error", func(t *testing.T) {
+		qc := newQueryConsolidator(querypb.ExecuteOptions_CONSOLIDATOR_ENABLED, 0)
+		var executions atomic.Int64
+		_, errs := consolidate(t, context.Background(), qc, 2, func() (*sqltypes.Result, error) {
+			executions.Add(1)
+			return nil, errors.New("shard error")
+		})
+		assert.EqualValues(t, 1, executions.Load())
+		for _, err := range errs {
+			assert.EqualError(t, err, "shard error")
+		}
+	})
+
+	t.Run("oversized result", func(t *testing.T) {
+		qc := newQueryConsolidator(querypb.ExecuteOptions_CONSOLIDATOR_ENABLED, 1)
+		var executions, running atomic.Int64
+		var concurrent atomic.Bool
+		oversized := consolidatorOversizedResults.Get()
+		results, errs := consolidate(t, context.Background(), qc, 3, func() (*sqltypes.Result, error) {
+			executions.Add(1)
+			if running.Add(1) > 1 {
+				concurrent.Store(true)
+			}
+			defer running.Add(-1)
+			time.Sleep(time.Millisecond)
+			return result, nil
+		})
+		// The waiting queries execute one at a time.
+		assert.EqualValues(t, 4, executions.Load())
+		assert.False(t, concurrent.Load())
+		assert.EqualValues(t, 4, consolidatorOversizedResults.Get()-oversized)
+		for i := range results {
+			require.NoError(t, errs[i])
+			assert.Equal(t, result, results[i])
+		}
+	})
+
+	t.Run("timed out first query", func(t *testing.T) {
+		qc := newQueryConsolidator(querypb.ExecuteOptions_CONSOLIDATOR_ENABLED, 0)
+		var executions atomic.Int64
+		results, errs := consolidate(t, context.Background(), qc, 2, func() (*sqltypes.Result, error) {
+			// The first query times out on the timeout of its own session.
+			if executions.Add(1) == 1 {
+				return nil, vterrors.Errorf(vtrpcpb.Code_DEADLINE_EXCEEDED, "query timed out")
+			}
+			return result, nil
+		})
+		// A waiting query executes the query again, and the other one waits
+		// for it, unless it only gets there once it is done.
+		assert.GreaterOrEqual(t, executions.Load(), int64(2))
+		assert.ErrorContains(t, errs[0], "query timed out")
+		for i := 1; i < len(results); i++ {
+			require.NoError(t, errs[i])
+			assert.Equal(t, result, results[i])
+		}
+		assert.Empty(t, qc.queries)
+	})
+
+	t.Run("canceled first query", func(t *testing.T) {
+		qc := newQueryConsolidator(querypb.ExecuteOptions_CONSOLIDATOR_ENABLED, 0)
+		key := resultCacheKey(engine.PlanKey{Query: "select id from t1"}, "", "", nil)
+
+		ctx, cancel := context.WithCancel(context.Background())
+		started := make(chan struct{})
+		done := make(chan error)
+		go func() {
+			_, err := qc.execute(ctx, key, func() (*sqltypes.Result, error) {
+				close(started)
+				<-ctx.Done()
+				return nil, ctx.Err()
+			})
+			done <- err
+		}()
+		<-started
+
+		waits := consolidatorWaits.Get()
+		waiter := make(chan *sqltypes.Result)
+		go func() {
+			qr, err := qc.execute(context.Background(), key, func() (*sqltypes.Result, error) {
+				return result, nil
+			})
+			assert.NoError(t, err)
+			waiter <- qr
+		}()
+		require.Eventually(t, func() bool {
+			return consolidatorWaits.Get()-waits == 1
+		}, 10*time.Second, time.Millisecond)
+
+		// The waiting query executes itself rather than failing.
+		cancel()
+		assert.ErrorIs(t, <-done, context.Canceled)
+		assert.Equal(t, result, <-waiter)
+	})
+
+	t.Run("canceled waiting query", func(t *testing.T) {
+		qc := newQueryConsolidator(querypb.ExecuteOptions_CONSOLIDATOR_ENABLED, 0)
+		key := resultCacheKey(engine.PlanKey{Query: "select id from t1"}, "", "", nil)
+
+		started := make(chan struct{})
+		release := make(chan struct{})
+		done := make(chan struct{})
+		go func() {
+			defer close(done)
+			_, _ = qc.execute(context.Background(), key, func() (*sqltypes.Result, error) {
+				close(started)
+				<-release
+				return result, nil
+			})
+		}()
+		<-started
+
+		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+		defer cancel()
+		_, err := qc.execute(ctx, key, func() (*sqltypes.Result, error) {
+			return result, nil
+		})
+		assert.ErrorIs(t, err, context.DeadlineExceeded)
+		close(release)
+		<-done
+	})
+}
+
+func TestExecutorConsolidate(t *testing.T) {
+	ctx := utils.LeakCheckContext(t)
+	executor, _, replica := createExecutorEnvWithPrimaryReplicaConn(t, ctx, 0)
+	executor.consolidator = newQueryConsolidator(querypb.ExecuteOptions_CONSOLIDATOR_ENABLED_REPLICAS, 0)
+
+	tcases := []struct {
+		query       string
+		consolidate bool
+	}{{
+		query:       "select id from music_user_map where id = 1",
+		consolidate: true,
+	}, {
+		query:       "select id from music_user_map union select id from music_user_map",
+		consolidate: true,
+	}, {
+		query: "select id from music_user_map where id = 1 for update",
+	}, {
+		query: "select get_lock('a', 1) from dual",
+	}, {
+		query: "select next value from user_seq",
+	}, {
+		query: "update music_user_map set id = 2 where id = 1",
+	}}
+	for _, tcase := range tcases {
+		t.Run(tcase.query, func(t *testing.T) {
+			session := econtext.NewSafeSession(&vtgatepb.Session{TargetString: KsTestUnsharded + "@replica"})
+			plan, _ := getPlanCached(t, ctx, executor, session, tcase.query, makeComments(""), nil, true)
+			assert.Equal(t, tcase.consolidate, plan.Consolidate)
+		})
+	}
+
+	shouldConsolidate := func(target, query string, inTransaction bool) bool {
+		t.Helper()
+		session := econtext.NewSafeSession(&vtgatepb.Session{TargetString: target, InTransaction: inTransaction})
+		vcursor, err := executor.newVCursor(session, makeComments(""), nil)
+		require.NoError(t, err)
+		plan, _ := getPlanCached(t, ctx, executor, session, query, makeComments(""), nil, true)
+		return executor.shouldConsolidate(session, plan, vcursor)
+	}
+	query := "select id from music_user_map where id = 1"
+	assert.True(t, shouldConsolidate(KsTestUnsharded+"@replica", query, false))
+	assert.False(t, shouldConsolidate(KsTestUnsharded+"@replica", query, true))
+	assert.False(t, shouldConsolidate(KsTestUnsharded, query, false))
+	assert.False(t, shouldConsolidate(KsTestUnsharded, "select /*vt+ CONSOLIDATOR=enabled */ id from music_user_map where id = 1", false))
+	assert.False(t, shouldConsolidate(KsTestUnsharded+"@replica", "select /*vt+ CONSOLIDATOR=disabled */ id from music_user_map where id = 1", false))
+
+	// consolidated queries return their results as usual
+	session := econtext.NewSafeSession(&vtgatepb.Session{TargetString: KsTestUnsharded + "@replica", Autocommit: true})
+	replica.SetResults([]*sqltypes.Result{sqltypes.MakeTestResult(sqltypes.MakeTestFields("id", "int64"), "1")})
+	qr, err := executor.Execute(ctx, nil, "TestExecutorConsolidate", session, query, nil, false)
+	require.NoError(t, err)
+	utils.MustMatch(t, sqltypes.MakeTestResult(sqltypes.MakeTestFields("id", "int64"), "1").Rows, qr.Rows)
+	assert.Empty(t, executor.consolidator.queries)
+}
@@ -18,14 +18,21 @@ package engine
 
 import (
 	"fmt"
+	"math"
+	"slices"
 	"strconv"
+	"strings"
+	"unicode/utf8"
 
 	"vitess.io/vitess/go/mysql/collations"
+	"vitess.io/vitess/go/mysql/json"
+	"vitess.io/vitess/go/mysql/sqlerror"
 	"vitess.io/vitess/go/slice"
 	"vitess.io/vitess/go/sqltypes"
 	binlogdatapb "vitess.io/vitess/go/vt/proto/binlogdata"
 	querypb "vitess.io/vitess/go/vt/proto/query"
 	"vitess.io/vitess/go/vt/sqlparser"
+	"vitess.io/vitess/go/vt/sysvars"
 	"vitess.io/vitess/go/vt/vterrors"
 	"vitess.io/vitess/go/vt/vtgate/engine/opcode"
 	"vitess.io/vitess/go/vt/vtgate/evalengine"
@@ -50,6 +57,19 @@ type AggregateParams struct {
 	// not what we use to aggregate at the engine primitive level.
 	OrigOpcode opcode.AggregateOpcode
 
+	// These are used only for group_concat, when the rows of the group are
+	// concatenated by the vtgate instead of by the shards.
+	// GroupConcatArgs holds the columns that are concatenated, and compared for DISTINCT.
+	// GroupConcatOrderBy holds the ordering of the values within the group.
+	GroupConcatArgs    evalengine.Comparison
+	GroupConcatOrderBy evalengine.Comparison
+
+	// These are used only for var_pop, when the variances of the shards are merged.
+	// They are the columns of the count and the sum of the values of the shards,
+	// or -1 when the rows hold the values themselves.
+	CountCol int
+	SumCol   int
+
 	CollationEnv *collations.Environment
 }
 
@@ -59,6 +79,8 @@ func NewAggregateParam(opcode opcode.AggregateOpcode, col int, alias string, col
 		Col:          col,
 		Alias:        alias,
 		WCol:         -1,
+		CountCol:     -1,
+		SumCol:       -1,
 		CollationEnv: collationEnv,
 	}
 	if opcode.NeedsComparableValues() {
@@ -79,6 +101,12 @@ func (ap *AggregateParams) String() string {
 	if sqltypes.IsText(ap.Type.Type()) && ap.CollationEnv.IsSupported(ap.Type.Collation()) {
 		keyCol += " COLLATE " + ap.CollationEnv.LookupName(ap.Type.Collation())
 	}
+	if len(ap.GroupConcatArgs) > 0 {
+		keyCol = ap.groupConcatString()
+	}
+	if ap.CountCol >= 0 {
+		keyCol = fmt.Sprintf("%s, %d, %d", keyCol, ap.CountCol, ap.SumCol)
+	}
 	dispOrigOp := ""
 	if ap.OrigOpcode != opcode.AggregateUnassigned && ap.OrigOpcode != ap.Opcode {
 		dispOrigOp = "_" + ap.OrigOpcode.String()
@@ -89,6 +117,31 @@ func (ap *AggregateParams) String() string {
 	return fmt.Sprintf("%s%s(%s)", ap.Opcode.String(), dispOrigOp, keyCol)
 }
 
+func (ap *AggregateParams) groupConcatString() string {
+	var buf strings.Builder
+	if gcFunc, ok := ap.Func.(*sqlparser.GroupConcatExpr); ok && gcFunc.Distinct {
+		buf.WriteString("distinct ")
+	}
+	for i, arg := range ap.GroupConcatArgs {
+		if i > 0 {
+			buf.WriteString(", ")
+		}
+		buf.WriteString(strconv.Itoa(arg.Col))
+		if arg.WeightStringCol != -1 && arg.WeightStringCol != arg.Col {
+			fmt.Fprintf(&buf, "|%d", arg.WeightStringCol)
+		}
+	}
+	for i, order := range ap.GroupConcatOrderBy {
+		if i == 0 {
+			buf.WriteString(" order by ")
+		} else {
+			buf.WriteString(", ")
+		}
+		buf.WriteString(order.String())
+	}
+	return buf.String()
+}
+
 func (ap *AggregateParams) typ(inputType querypb.Type) querypb.Type {
 	if ap.OrigOpcode != opcode.AggregateUnassigned {
 		return ap.OrigOpcode.SQLType(inputType)
@@ -98,7 +151,7 @@ func (ap *AggregateParams) typ(inputType querypb.Type) querypb.Type {
 
 type aggregator interface {
 	add(row []sqltypes.Value) error
-	finish() sqltypes.Value
+	finish() (sqltypes.Value, error)
 	reset()
 }
 
@@ -151,8 +204,8 @@ func (a *aggregatorCount) add(row []sqltypes.Value) error {
 	return nil
 }
 
-func (a *aggregatorCount) finish() sqltypes.Value {
-	return sqltypes.NewInt64(a.n)
+func (a *aggregatorCount) finish() (sqltypes.Value, error) {
+	return sqltypes.NewInt64(a.n), nil
 }
 
 func (a *aggregatorCount) reset() {
@@ -169,8 +222,8 @@ func (a *aggregatorCountStar) add(_ []sqltypes.Value) error {
 	return nil
 }
 
-func (a *aggregatorCountStar) finish() sqltypes.Value {
-	return sqltypes.NewInt64(a.n)
+func (a *aggregatorCountStar) finish() (sqltypes.Value, error) {
+	return sqltypes.NewInt64(a.n), nil
 }
 
 func (a *aggregatorCountStar) reset() {
@@ -198,8 +251,8 @@ func (a *aggregatorMax) add(row []sqltypes.Value) (err error) {
 	return a.minmax.Max(row[a.from])
 }
 
-func (a *aggregatorMinMax) finish() sqltypes.Value {
-	return a.minmax.Result()
+func (a *aggregatorMinMax) finish() (sqltypes.Value, error) {
+	return a.minmax.Result(), nil
 }
 
 func (a *aggregatorMinMax) reset() {
@@ -222,8 +275,8 @@ func (a *aggregatorSum) add(row []sqltypes.Value) error {
 	return a.sum.Add(row[a.from])
 }
 
-func (a *aggregatorSum) finish() sqltypes.Value {
-	return a.sum.Result()
+func (a *aggregatorSum) finish() (sqltypes.Value, error) {
+	return a.sum.Result(), nil
 }
 
 func (a *aggregatorSum) reset() {
@@ -245,8 +298,8 @@ func (a *aggregatorScalar) add(row []sqltypes.Value) error {
 	return nil
 }
 
-func (a *aggregatorScalar) finish() sqltypes.Value {
-	return a.current
+func (a *aggregatorScalar) finish() (sqltypes.Value, error) {
+	return a.current, nil
 }
 
 func (a *aggregatorScalar) reset() {
@@ -254,37 +307,274 @@ func (a *aggregatorScalar) reset() {
 	a.init = false
 }
 
+// aggregatorGroupConcat implements GROUP_CONCAT. When the shards have already concatenated
+// the values of the group, their results are concatenated again. Otherwise, the aggregator
+// receives the rows of the group and concatenates the arguments itself. If the values have
+// to be distinct or ordered, the rows are kept until the group is finished.
 type aggregatorGroupConcat struct {
 	from      int
+	args      evalengine.Comparison
+	orderBy   evalengine.Comparison
+	distinct  bool
 	type_     sqltypes.Type
 	separator []byte
+	maxLen    uint64
+	session   SessionActions
 
 	concat []byte
 	n      int
+	rows   []sqltypes.Row
+
+	// group is the number of the group being aggregated, used in the truncation warning
+	group int
 }
 
 func (a *aggregatorGroupConcat) add(row []sqltypes.Value) error {
-	if row[a.from].IsNull() {
+	if len(a.args) == 0 {
+		if row[a.from].IsNull() {
+			return nil
+		}
+		a.appendValues(row[a.from])
+		return nil
+	}
+
+	for _, arg := range a.args {
+		// like MySQL, we skip the rows where any of the arguments is NULL
+		if row[arg.Col].IsNull() {
+			return nil
+		}
+	}
+	if a.distinct || len(a.orderBy) > 0 {
+		a.rows = append(a.rows, row)
 		return nil
 	}
+	a.appendRow(row)
+	return nil
+}
+
+func (a *aggregatorGroupConcat) appendRow(row []sqltypes.Value) {
+	values := make([]sqltypes.Value, 0, len(a.args))
+	for _, arg := range a.args {
+		values = append(values, row[arg.Col])
+	}
+	a.appendValues(values...)
+}
+
+func (a *aggregatorGroupConcat) appendValues(values ...sqltypes.Value) {
+	if uint64(len(a.concat)) > a.maxLen {
+		// the result is going to be truncated anyway
+		return
+	}
 	if a.n > 0 {
 		a.concat = append(a.concat, a.separator...)
 	}
-	a.concat = append(a.concat, row[a.from].Raw()...)
+	for _, v := range values {
+		a.concat = append(a.concat, v.Raw()...)
+	}
 	a.n++
+}
+
+// concatRows concatenates the rows that were kept for the group, after removing
+// the duplicates and sorting them
+func (a *aggregatorGroupConcat) concatRows() (err error) {
+	defer evalengine.PanicHandler(&err)
+
+	rows := a.rows
+	if a.distinct {
+		slices.SortStableFunc(rows, a.args.Compare)
+		rows = slices.CompactFunc(rows, func(r1, r2 sqltypes.Row) bool {
+			return a.args.Compare(r1, r2) == 0
+		})
+	}
+	if len(a.orderBy) > 0 {
+		slices.SortStableFunc(rows, a.orderBy.Compare)
+	}
+	for _, row := range rows {
+		a.appendRow(row)
+	}
 	return nil
 }
 
-func (a *aggregatorGroupConcat) finish() sqltypes.Value {
+func (a *aggregatorGroupConcat) finish() (sqltypes.Value, error) {
+	a.group++
+	if err := a.concatRows(); err != nil {
+		return sqltypes.NULL, err
+	}
 	if a.n == 0 {
-		return sqltypes.NULL
+		return sqltypes.NULL, nil
+	}
+	concat := a.concat
+	if uint64(len(concat)) > a.maxLen {
+		concat = a.truncate(concat)
 	}
-	return sqltypes.MakeTrusted(a.type_, a.concat)
+	return sqltypes.MakeTrusted(a.type_, concat), nil
+}
+
+// truncate cuts the result at group_concat_max_len, and records the same warning as MySQL does
+func (a *aggregatorGroupConcat) truncate(concat []byte) []byte {
+	end := int(a.maxLen)
+	if sqltypes.IsText(a.type_) {
+		// we don't want to cut a multibyte character in half
+		for end > 0 && !utf8.RuneStart(concat[end]) {
+			end--
+		}
+	}
+	if a.session != nil {
+		a.session.RecordWarning(&querypb.QueryWarning{
+			Code:    uint32(sqlerror.ERCutValueGroupConcat),
+			Message: fmt.Sprintf("Row %d was cut by GROUP_CONCAT()", a.group),
+		})
+	}
+	return concat[:end]
 }
 
 func (a *aggregatorGroupConcat) reset() {
 	a.n = 0
 	a.concat = nil // not safe to reuse this byte slice as it's returned as MakeTrusted
+	a.rows = nil
+}
+
+// groupConcatMaxLen returns the maximum length of the result of a group_concat, which is
+// the value of group_concat_max_len set in the session. The global value of the MySQL
+// servers is unknown to vtgate, so the result is not truncated when the session doesn't set it.
+func groupConcatMaxLen(vcursor VCursor) uint64 {
+	maxLen := uint64(math.MaxUint64)
+	if vcursor == nil {
+		return maxLen
+	}
+	vcursor.Session().GetSystemVariables(func(k string, v string) {
+		if k != sysvars.GroupConcatMaxLen {
+			return
+		}
+		if val, err := strconv.ParseUint(strings.Trim(v, "'"), 10, 64); err == nil {
+			maxLen = val
+		}
+	})
+	return maxLen
+}
+
+type aggregatorBit struct {
+	from int
+	bit  evalengine.Bit
+}
+
+func (a *aggregatorBit) add(row []sqltypes.Value) error {
+	return a.bit.Add(row[a.from])
+}
+
+func (a *aggregatorBit) finish() (sqltypes.Value, error) {
+	return a.bit.Result(), nil
+}
+
+func (a *aggregatorBit) reset() {
+	a.bit.Reset()
+}
+
+// aggregatorVariance implements VAR_POP. Its input is either the values, when the
+// aggregation could not be pushed down, or the population variances of the shards,
+// with the count and the sum of their values, which are merged together.
+type aggregatorVariance struct {
+	from     int
+	countCol int
+	sumCol   int
+	variance evalengine.Variance
+}
+
+func (a *aggregatorVariance) add(row []sqltypes.Value) error {
+	if a.countCol < 0 {
+		return a.variance.Add(row[a.from])
+	}
+	return a.variance.Merge(row[a.countCol], row[a.sumCol], row[a.from])
+}
+
+func (a *aggregatorVariance) finish() (sqltypes.Value, error) {
+	return a.variance.Result(), nil
+}
+
+func (a *aggregatorVariance) reset() {
+	a.variance.Reset()
+}
+
+// aggregatorJSONArray implements JSON_ARRAYAGG. Its input values are JSON arrays:
+// either the partial aggregations of the shards, or the single values wrapped
+// in JSON_ARRAY when the aggregation could not be pushed down. The elements of
+// all the arrays are concatenated.
+type aggregatorJSONArray struct {
+	from  int
+	elems []*json.Value
+	init  bool
+}
+
+func (a *aggregatorJSONArray) add(row []sqltypes.Value) error {
+	// a shard without rows returns NULL
+	if row[a.from].IsNull() {
+		return nil
+	}
+	var p json.Parser
+	doc, err := p.ParseBytes(row[a.from].Raw())
+	if err != nil {
+		return err
+	}
+	elems, ok := doc.Array()
+	if !ok {
+		return vterrors.VT13001(fmt.Sprintf("expected a JSON array to aggregate, got: %s", doc.String()))
+	}
+	a.elems = append(a.elems, elems...)
+	a.init = true
+	return nil
+}
+
+func (a *aggregatorJSONArray) finish() (sqltypes.Value, error) {
+	if !a.init {
+		return sqltypes.NULL, nil
+	}
+	return sqltypes.MakeTrusted(sqltypes.TypeJSON, json.NewArray(a.elems).ToRawBytes()), nil
+}
+
+func (a *aggregatorJSONArray) reset() {
+	a.elems = nil
+	a.init = false
+}
+
+// aggregatorJSONObject implements JSON_OBJECTAGG. Like aggregatorJSONArray, its
+// input values are JSON objects that are merged together. When a key is found
+// more than once, the last value wins, which matches MySQL.
+type aggregatorJSONObject struct {
+	from int
+	obj  json.Object
+	init bool
+}
+
+func (a *aggregatorJSONObject) add(row []sqltypes.Value) error {
+	if row[a.from].IsNull() {
+		return nil
+	}
+	var p json.Parser
+	doc, err := p.ParseBytes(row[a.from].Raw())
+	if err != nil {
+		return err
+	}
+	obj, ok := doc.Object()
+	if !ok {
+		return vterrors.VT13001(fmt.Sprintf("expected a JSON object to aggregate, got: %s", doc.String()))
+	}
+	obj.Visit(func(key string, v *json.Value) {
+		a.obj.Set(key, v, json.Set)
+	})
+	a.init = true
+	return nil
+}
+
+func (a *aggregatorJSONObject) finish() (sqltypes.Value, error) {
+	if !a.init {
+		return sqltypes.NULL, nil
+	}
+	return sqltypes.MakeTrusted(sqltypes.TypeJSON, json.NewObject(a.obj).ToRawBytes()), nil
+}
+
+func (a *aggregatorJSONObject) reset() {
+	a.obj = json.Object{}
+	a.init = false
 }
 
 type aggregatorGtid struct {
@@ -301,9 +591,9 @@ func (a *aggregatorGtid) add(row []sqltypes.Value) error {
 	return nil
 }
 
-func (a *aggregatorGtid) finish() sqltypes.Value {
+func (a *aggregatorGtid) finish() (sqltypes.Value, error) {
 	gtid := binlogdatapb.VGtid{ShardGtids: a.shards}
-	return sqltypes.NewVarChar(gtid.String())
+	return sqltypes.NewVarChar(gtid.String()), nil
 }
 
 func (a *aggregatorGtid) reset() {
@@ -321,12 +611,16 @@ func (a aggregationState) add(row []sqltypes.Value) error {
 	return nil
 }
 
-func (a aggregationState) finish() (row []sqltypes.Value) {
-	row = make([]sqltypes.Value, 0, len(a))
+func (a aggregationState) finish() ([]sqltypes.Value, error) {
+	row := make([]sqltypes.Value, 0, len(a))
 	for _, st := range a {
-		row = append(row, st.finish())
+		v, err := st.finish()
+		if err != nil {
+			return nil, err
+		}
+		row = append(row, v)
 	}
-	return
+	return row, nil
 }
 
 func (a aggregationState) reset() {
@@ -354,7 +648,7 @@ func isComparable(typ sqltypes.Type) bool {
 	return false
 }
 
-func newAggregation(fields []*querypb.Field, aggregates []*AggregateParams) (aggregationState, []*querypb.Field, error) {
+func newAggregation(vcursor VCursor, fields []*querypb.Field, aggregates []*AggregateParams) (aggregationState, []*querypb.Field, error) {
 	fields = slice.Map(fields, func(from *querypb.Field) *querypb.Field { return from.CloneVT() })
 
 	agstate := make([]aggregator, len(fields))
@@ -362,104 +656,144 @@ func newAggregation(fields []*querypb.Field, aggregates []*AggregateParams) (agg
 		sourceType := fields[aggr.Col].Type
 		targetType := aggr.typ(sourceType)
 
-		var ag aggregator
-		var distinct = -1
+		ag, err := newAggregator(vcursor, aggr, sourceType, targetType)
+		if err != nil {
+			return nil, nil, err
+		}
 
-		if aggr.Opcode.IsDistinct() {
-			distinct = aggr.KeyCol
-			if aggr.WAssigned() && !isComparable(sourceType) {
-				distinct = aggr.WCol
-			}
+		agstate[aggr.Col] = ag
+		fields[aggr.Col].Type = targetType
+		if aggr.Alias != "" {
+			fields[aggr.Col].Name = aggr.Alias
 		}
+	}
 
-		if aggr.Opcode == opcode.AggregateMin || aggr.Opcode == opcode.AggregateMax {
-			if aggr.WAssigned() && !isComparable(sourceType) {
-				return nil, nil, vterrors.VT12001("min/max on types that are not comparable is not supported")
-			}
+	for i, a := range agstate {
+		if a == nil {
+			agstate[i] = &aggregatorScalar{from: i}
 		}
+	}
 
-		switch aggr.Opcode {
-		case opcode.AggregateCountStar:
-			ag = &aggregatorCountStar{}
-
-		case opcode.AggregateCount, opcode.AggregateCountDistinct:
-			ag = &aggregatorCount{
-				from: aggr.Col,
-				distinct: aggregatorDistinct{
-					column:       distinct,
-					coll:         aggr.Type.Collation(),
-					collationEnv: aggr.CollationEnv,
-					values:       aggr.Type.Values(),
-				},
-			}
+	return agstate, fields, nil
+}
 
-		case opcode.AggregateSum, opcode.AggregateSumDistinct:
-			var sum evalengine.Sum
-			switch aggr.OrigOpcode {
-			case opcode.AggregateCount, opcode.AggregateCountStar, opcode.AggregateCountDistinct:
-				sum = evalengine.NewSumOfCounts()
-			default:
-				sum = evalengine.NewAggregationSum(sourceType)
-			}
+// newAggregator creates the aggregator for a single aggregation function
+func newAggregator(vcursor VCursor, aggr *AggregateParams, sourceType, targetType sqltypes.Type) (aggregator, error) {
+	var ag aggregator
+	var distinct = -1
 
-			ag = &aggregatorSum{
-				from: aggr.Col,
-				sum:  sum,
-				distinct: aggregatorDistinct{
-					column:       distinct,
-					coll:         aggr.Type.Collation(),
-					collationEnv: aggr.CollationEnv,
-					values:       aggr.Type.Values(),
-				},
-			}
+	if aggr.Opcode.IsDistinct() {
+		distinct = aggr.KeyCol
+		if aggr.WAssigned() && !isComparable(sourceType) {
+			distinct = aggr.WCol
+		}
+	}
 
-		case opcode.AggregateMin:
-			ag = &aggregatorMin{
-				aggregatorMinMax{
-					from:   aggr.Col,
-					minmax: evalengine.NewAggregationMinMax(sourceType, aggr.CollationEnv, aggr.Type.Collation(), aggr.Type.Values()),
-				},
-			}
+	if aggr.Opcode == opcode.AggregateMin || aggr.Opcode == opcode.AggregateMax {
+		if aggr.WAssigned() && !isComparable(sourceType) {
+			return nil, vterrors.VT12001("min/max on types that are not comparable is not supported")
+		}
+	}
 
-		case opcode.AggregateMax:
-			ag = &aggregatorMax{
-				aggregatorMinMax{
-					from:   aggr.Col,
-					minmax: evalengine.NewAggregationMinMax(sourceType, aggr.CollationEnv, aggr.Type.Collation(), aggr.Type.Values()),
-				},
-			}
+	switch aggr.Opcode {
+	case opcode.AggregateCountStar:
+		ag = &aggregatorCountStar{}
+
+	case opcode.AggregateCount, opcode.AggregateCountDistinct:
+		ag = &aggregatorCount{
+			from: aggr.Col,
+			distinct: aggregatorDistinct{
+				column:       distinct,
+				coll:         aggr.Type.Collation(),
+				collationEnv: aggr.CollationEnv,
+				values:       aggr.Type.Values(),
+			},
+		}
 
-		case opcode.AggregateGtid:
-			ag = &aggregatorGtid{from: aggr.Col}
+	case opcode.AggregateSum, opcode.AggregateSumDistinct:
+		var sum evalengine.Sum
+		switch aggr.OrigOpcode {
+		case opcode.AggregateCount, opcode.AggregateCountStar, opcode.AggregateCountDistinct:
+			sum = evalengine.NewSumOfCounts()
+		default:
+			sum = evalengine.NewAggregationSum(sourceType)
+		}
 
-		case opcode.AggregateAnyValue:
-			ag = &aggregatorScalar{from: aggr.Col}
+		ag = &aggregatorSum{
+			from: aggr.Col,
+			sum:  sum,
+			distinct: aggregatorDistinct{
+				column:       distinct,
+				coll:         aggr.Type.Collation(),
+				collationEnv: aggr.CollationEnv,
+				values:       aggr.Type.Values(),
+			},
+		}
 
-		case opcode.AggregateGroupConcat:
-			gcFunc := aggr.Func.(*sqlparser.GroupConcatExpr)
-			separator := []byte(gcFunc.Separator)
-			ag = &aggregatorGroupConcat{
-				from:      aggr.Col,
-				type_:     targetType,
-				separator: separator,
-			}
+	case opcode.AggregateMin:
+		ag = &aggregatorMin{
+			aggregatorMinMax{
+				from:   aggr.Col,
+				minmax: evalengine.NewAggregationMinMax(sourceType, aggr.CollationEnv, aggr.Type.Collation(), aggr.Type.Values()),
+			},
+		}
 
-		default:
-			panic("BUG: unexpected Aggregation opcode")
+	case opcode.AggregateMax:
+		ag = &aggregatorMax{
+			aggregatorMinMax{
+				from:   aggr.Col,
+				minmax: evalengine.NewAggregationMinMax(sourceType, aggr.CollationEnv, aggr.Type.Collation(), aggr.Type.Values()),
+			},
 		}
 
-		agstate[aggr.Col] = ag
-		fields[aggr.Col].Type = targetType
-		if aggr.Alias != "" {
-			fields[aggr.Col].Name = aggr.Alias
+	case opcode.AggregateGtid:
+		ag = &aggregatorGtid{from: aggr.Col}
+
+	case opcode.AggregateAnyValue:
+		ag = &aggregatorScalar{from: aggr.Col}
+
+	case opcode.AggregateBitAnd:
+		ag = &aggregatorBit{from: aggr.Col, bit: evalengine.NewAggregationBitAnd()}
+
+	case opcode.AggregateBitOr:
+		ag = &aggregatorBit{from: aggr.Col, bit: evalengine.NewAggregationBitOr()}
+
+	case opcode.AggregateBitXor:
+		ag = &aggregatorBit{from: aggr.Col, bit: evalengine.NewAggregationBitXor()}
+
+	case opcode.AggregateVarPop:
+		ag = &aggregatorVariance{
+			from:     aggr.Col,
+			countCol: aggr.CountCol,
+			sumCol:   aggr.SumCol,
+			variance: evalengine.NewAggregationVariance(),
 		}
-	}
 
-	for i, a := range agstate {
-		if a == nil {
-			agstate[i] = &aggregatorScalar{from: i}
+	case opcode.AggregateJSONArrayAgg:
+		ag = &aggregatorJSONArray{from: aggr.Col}
+
+	case opcode.AggregateJSONObjectAgg:
+		ag = &aggregatorJSONObject{from: aggr.Col}
+
+	case opcode.AggregateGroupConcat:
+		gcFunc := aggr.Func.(*sqlparser.GroupConcatExpr)
+		separator := []byte(gcFunc.Separator)
+		gc := &aggregatorGroupConcat{
+			from:      aggr.Col,
+			args:      aggr.GroupConcatArgs,
+			orderBy:   aggr.GroupConcatOrderBy,
+			distinct:  gcFunc.Distinct && len(aggr.GroupConcatArgs) > 0,
+			type_:     targetType,
+			separator: separator,
+			maxLen:    groupConcatMaxLen(vcursor),
 		}
-	}
+		if vcursor != nil {
+			gc.session = vcursor.Session()
+		}
+		ag = gc
 
-	return agstate, fields, nil
+	default:
+		panic("BUG: unexpected Aggregation opcode")
+	}
+	return ag, nil
 }
@@ -29,7 +29,7 @@ func (cached *AggregateParams) CachedSize(alloc bool) int64 {
 	}
 	size := int64(0)
 	if alloc {
-		size += int64(112)
+		size += int64(176)
 	}
 	// field Type vitess.io/vitess/go/vt/vtgate/evalengine.Type
 	size += cached.Type.CachedSize(false)
@@ -41,6 +41,20 @@ func (cached *AggregateParams) CachedSize(alloc bool) int64 {
 	}
 	// field Original *vitess.io/vitess/go/vt/sqlparser.AliasedExpr
 	size += cached.Original.CachedSize(true)
+	// field GroupConcatArgs vitess.io/vitess/go/vt/vtgate/evalengine.Comparison
+	{
+		size += hack.RuntimeAllocSize(int64(cap(cached.GroupConcatArgs)) * int64(56))
+		for _, elem := range cached.GroupConcatArgs {
+			size += elem.CachedSize(false)
+		}
+	}
+	// field GroupConcatOrderBy vitess.io/vitess/go/vt/vtgate/evalengine.Comparison
+	{
+		size += hack.RuntimeAllocSize(int64(cap(cached.GroupConcatOrderBy)) * int64(56))
+		for _, elem := range cached.GroupConcatOrderBy {
+			size += elem.CachedSize(false)
+		}
+	}
 	// field CollationEnv *vitess.io/vitess/go/mysql/collations.Environment
 	size += cached.CollationEnv.CachedSize(true)
 	return size
@@ -114,6 +128,53 @@ func (cached *Condition) CachedSize(alloc bool) int64 {
 	size += hack.RuntimeAllocSize(int64(len(cached.B)))
 	return size
 }
+
+//go:nocheckptr
+func (cached *CorrelatedSubquery) CachedSize(alloc bool) int64 {
+	if cached == nil {
+		return int64(0)
+	}
+	size := int64(0)
+	if alloc {
+		size += int64(144)
+	}
+	// field SubqueryResult string
+	size += hack.RuntimeAllocSize(int64(len(cached.SubqueryResult)))
+	// field HasValues string
+	size += hack.RuntimeAllocSize(int64(len(cached.HasValues)))
+	// field Vars map[string]int
+	if cached.Vars != nil {
+		size += hack.RuntimeMapSize(cached.Vars)
+		for k := range cached.Vars {
+			size += hack.RuntimeAllocSize(int64(len(k)))
+		}
+	}
+	// field Predicate vitess.io/vitess/go/vt/vtgate/evalengine.Expr
+	if cc, ok := cached.Predicate.(cachedObject); ok {
+		size += cc.CachedSize(true)
+	}
+	// field ASTPredicate vitess.io/vitess/go/vt/sqlparser.Expr
+	if cc, ok := cached.ASTPredicate.(cachedObject); ok {
+		size += cc.CachedSize(true)
+	}
+	// field Value vitess.io/vitess/go/vt/vtgate/evalengine.Expr
+	if cc, ok := cached.Value.(cachedObject); ok {
+		size += cc.CachedSize(true)
+	}
+	// field ASTValue vitess.io/vitess/go/vt/sqlparser.Expr
+	if cc, ok := cached.ASTValue.(cachedObject); ok {
+		size += cc.CachedSize(true)
+	}
+	// field Outer vitess.io/vitess/go/vt/vtgate/engine.Primitive
+	if cc, ok := cached.Outer.(cachedObject); ok {
+		size += cc.CachedSize(true)
+	}
+	// field Subquery vitess.io/vitess/go/vt/vtgate/engine.Primitive
+	if cc, ok := cached.Subquery.(cachedObject); ok {
+		size += cc.CachedSize(true)
+	}
+	return size
+}
 func (cached *DBDDL) CachedSize(alloc bool) int64 {
 	if cached == nil {
 		return int64(0)
@@ -423,7 +484,7 @@ func (cached *HashJoin) CachedSize(alloc bool) int64 {
 	}
 	size := int64(0)
 	if alloc {
-		size += int64(128)
+		size += int64(208)
 	}
 	// field Left vitess.io/vitess/go/vt/vtgate/engine.Primitive
 	if cc, ok := cached.Left.(cachedObject); ok {
@@ -437,19 +498,34 @@ func (cached *HashJoin) CachedSize(alloc bool) int64 {
 	{
 		size += hack.RuntimeAllocSize(int64(cap(cached.Cols)) * int64(8))
 	}
+	// field LHSKeys []int
+	{
+		size += hack.RuntimeAllocSize(int64(cap(cached.LHSKeys)) * int64(8))
+	}
+	// field RHSKeys []int
+	{
+		size += hack.RuntimeAllocSize(int64(cap(cached.RHSKeys)) * int64(8))
+	}
 	// field ASTPred vitess.io/vitess/go/vt/sqlparser.Expr
 	if cc, ok := cached.ASTPred.(cachedObject); ok {
 		size += cc.CachedSize(true)
 	}
+	// field ComparisonTypes []vitess.io/vitess/go/vt/vtgate/evalengine.Type
+	{
+		size += hack.RuntimeAllocSize(int64(cap(cached.ComparisonTypes)) * int64(24))
+		for _, elem := range cached.ComparisonTypes {
+			size += elem.CachedSize(false)
+		}
+	}
 	// field CollationEnv *vitess.io/vitess/go/mysql/collations.Environment
 	size += cached.CollationEnv.CachedSize(true)
-	// field Values *vitess.io/vitess/go/vt/vtgate/evalengine.EnumSetValues
-	if cached.Values != nil {
-		size += int64(24)
-		size += hack.RuntimeAllocSize(int64(cap(*cached.Values)) * int64(16))
-		for _, elem := range *cached.Values {
-			size += hack.RuntimeAllocSize(int64(len(elem)))
-		}
+	// field Residual vitess.io/vitess/go/vt/vtgate/evalengine.Expr
+	if cc, ok := cached.Residual.(cachedObject); ok {
+		size += cc.CachedSize(true)
+	}
+	// field ResidualCols []int
+	{
+		size += hack.RuntimeAllocSize(int64(cap(cached.ResidualCols)) * int64(8))
 	}
 	return size
 }
@@ -540,7 +616,7 @@ func (cached *InsertSelect) CachedSize(alloc bool) int64 {
 	}
 	size := int64(0)
 	if alloc {
-		size += int64(192)
+		size += int64(224)
 	}
 	// field InsertCommon vitess.io/vitess/go/vt/vtgate/engine.InsertCommon
 	size += cached.InsertCommon.CachedSize(false)
@@ -557,6 +633,39 @@ func (cached *InsertSelect) CachedSize(alloc bool) int64 {
 			}
 		}
 	}
+	// field DeleteBeforeInsert vitess.io/vitess/go/vt/vtgate/engine.Primitive
+	if cc, ok := cached.DeleteBeforeInsert.(cachedObject); ok {
+		size += cc.CachedSize(true)
+	}
+	// field DeleteKeyOffsets [][]int
+	{
+		size += hack.RuntimeAllocSize(int64(cap(cached.DeleteKeyOffsets)) * int64(24))
+		for _, elem := range cached.DeleteKeyOffsets {
+			{
+				size += hack.RuntimeAllocSize(int64(cap(elem)) * int64(8))
+			}
+		}
+	}
+	return size
+}
+func (cached *JSONTable) CachedSize(alloc bool) int64 {
+	if cached == nil {
+		return int64(0)
+	}
+	size := int64(0)
+	if alloc {
+		size += int64(48)
+	}
+	// field Table *vitess.io/vitess/go/vt/vtgate/evalengine.JSONTable
+	size += cached.Table.CachedSize(true)
+	// field Cols []int
+	{
+		size += hack.RuntimeAllocSize(int64(cap(cached.Cols)) * int64(8))
+	}
+	// field Doc vitess.io/vitess/go/vt/sqlparser.Expr
+	if cc, ok := cached.Doc.(cachedObject); ok {
+		size += cc.CachedSize(true)
+	}
 	return size
 }
 
@@ -612,6 +721,35 @@ func (cached *Limit) CachedSize(alloc bool) int64 {
 	}
 	return size
 }
+func (cached *LoadData) CachedSize(alloc bool) int64 {
+	if cached == nil {
+		return int64(0)
+	}
+	size := int64(0)
+	if alloc {
+		size += int64(128)
+	}
+	// field FileName string
+	size += hack.RuntimeAllocSize(int64(len(cached.FileName)))
+	// field Columns []string
+	{
+		size += hack.RuntimeAllocSize(int64(cap(cached.Columns)) * int64(16))
+		for _, elem := range cached.Columns {
+			size += hack.RuntimeAllocSize(int64(len(elem)))
+		}
+	}
+	// field FieldsTerminatedBy string
+	size += hack.RuntimeAllocSize(int64(len(cached.FieldsTerminatedBy)))
+	// field FieldsEnclosedBy string
+	size += hack.RuntimeAllocSize(int64(len(cached.FieldsEnclosedBy)))
+	// field FieldsEscapedBy string
+	size += hack.RuntimeAllocSize(int64(len(cached.FieldsEscapedBy)))
+	// field LinesStartingBy string
+	size += hack.RuntimeAllocSize(int64(len(cached.LinesStartingBy)))
+	// field LinesTerminatedBy string
+	size += hack.RuntimeAllocSize(int64(len(cached.LinesTerminatedBy)))
+	return size
+}
 func (cached *Lock) CachedSize(alloc bool) int64 {
 	if cached == nil {
 		return int64(0)
@@ -653,6 +791,41 @@ func (cached *LockFunc) CachedSize(alloc bool) int64 {
 	}
 	return size
 }
+func (cached *LockTables) CachedSize(alloc bool) int64 {
+	if cached == nil {
+		return int64(0)
+	}
+	size := int64(0)
+	if alloc {
+		size += int64(48)
+	}
+	// field TargetDestination vitess.io/vitess/go/vt/key.ShardDestination
+	if cc, ok := cached.TargetDestination.(cachedObject); ok {
+		size += cc.CachedSize(true)
+	}
+	// field Queries []*vitess.io/vitess/go/vt/vtgate/engine.LockTablesQuery
+	{
+		size += hack.RuntimeAllocSize(int64(cap(cached.Queries)) * int64(8))
+		for _, elem := range cached.Queries {
+			size += elem.CachedSize(true)
+		}
+	}
+	return size
+}
+func (cached *LockTablesQuery) CachedSize(alloc bool) int64 {
+	if cached == nil {
+		return int64(0)
+	}
+	size := int64(0)
+	if alloc {
+		size += int64(24)
+	}
+	// field Keyspace *vitess.io/vitess/go/vt/vtgate/vindexes.Keyspace
+	size += cached.Keyspace.CachedSize(true)
+	// field Query string
+	size += hack.RuntimeAllocSize(int64(len(cached.Query)))
+	return size
+}
 func (cached *MStream) CachedSize(alloc bool) int64 {
 	if cached == nil {
 		return int64(0)
@@ -792,7 +965,7 @@ func (cached *Plan) CachedSize(alloc bool) int64 {
 	}
 	size := int64(0)
 	if alloc {
-		size += int64(224)
+		size += int64(256)
 	}
 	// field Original string
 	size += hack.RuntimeAllocSize(int64(len(cached.Original)))
@@ -1308,6 +1481,29 @@ func (cached *SysVarSetAware) CachedSize(alloc bool) int64 {
 	}
 	return size
 }
+func (cached *TenantIsolation) CachedSize(alloc bool) int64 {
+	if cached == nil {
+		return int64(0)
+	}
+	size := int64(0)
+	if alloc {
+		size += int64(48)
+	}
+	// field Values []vitess.io/vitess/go/vt/vtgate/evalengine.Expr
+	{
+		size += hack.RuntimeAllocSize(int64(cap(cached.Values)) * int64(16))
+		for _, elem := range cached.Values {
+			if cc, ok := elem.(cachedObject); ok {
+				size += cc.CachedSize(true)
+			}
+		}
+	}
+	// field Input vitess.io/vitess/go/vt/vtgate/engine.Primitive
+	if cc, ok := cached.Input.(cachedObject); ok {
+		size += cc.CachedSize(true)
+	}
+	return size
+}
 func (cached *ThrottleApp) CachedSize(alloc bool) int64 {
 	if cached == nil {
 		return int64(0)
@@ -1613,6 +1809,63 @@ func (cached *VitessMetadata) CachedSize(alloc bool) int64 {
 	size += hack.RuntimeAllocSize(int64(len(cached.Value)))
 	return size
 }
+func (cached *Window) CachedSize(alloc bool) int64 {
+	if cached == nil {
+		return int64(0)
+	}
+	size := int64(0)
+	if alloc {
+		size += int64(96)
+	}
+	// field PartitionBy []*vitess.io/vitess/go/vt/vtgate/engine.GroupByParams
+	{
+		size += hack.RuntimeAllocSize(int64(cap(cached.PartitionBy)) * int64(8))
+		for _, elem := range cached.PartitionBy {
+			size += elem.CachedSize(true)
+		}
+	}
+	// field OrderBy []*vitess.io/vitess/go/vt/vtgate/engine.GroupByParams
+	{
+		size += hack.RuntimeAllocSize(int64(cap(cached.OrderBy)) * int64(8))
+		for _, elem := range cached.OrderBy {
+			size += elem.CachedSize(true)
+		}
+	}
+	// field Funcs []*vitess.io/vitess/go/vt/vtgate/engine.WindowFuncParams
+	{
+		size += hack.RuntimeAllocSize(int64(cap(cached.Funcs)) * int64(8))
+		for _, elem := range cached.Funcs {
+			size += elem.CachedSize(true)
+		}
+	}
+	// field Input vitess.io/vitess/go/vt/vtgate/engine.Primitive
+	if cc, ok := cached.Input.(cachedObject); ok {
+		size += cc.CachedSize(true)
+	}
+	return size
+}
+func (cached *WindowFuncParams) CachedSize(alloc bool) int64 {
+	if cached == nil {
+		return int64(0)
+	}
+	size := int64(0)
+	if alloc {
+		size += int64(80)
+	}
+	// field N vitess.io/vitess/go/vt/vtgate/evalengine.Expr
+	if cc, ok := cached.N.(cachedObject); ok {
+		size += cc.CachedSize(true)
+	}
+	// field Aggregate *vitess.io/vitess/go/vt/vtgate/engine.AggregateParams
+	size += cached.Aggregate.CachedSize(true)
+	// field Alias string
+	size += hack.RuntimeAllocSize(int64(len(cached.Alias)))
+	// field Expr vitess.io/vitess/go/vt/sqlparser.Expr
+	if cc, ok := cached.Expr.(cachedObject); ok {
+		size += cc.CachedSize(true)
+	}
+	return size
+}
 func (cached *percentBasedMirror) CachedSize(alloc bool) int64 {
 	if cached == nil {
 		return int64(0)
@@ -0,0 +1,403 @@
+/*
+Copyright 2025 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package engine
+
+import (
+	"context"
+	"encoding/binary"
+	"fmt"
+	"slices"
+	"sync"
+
+	"golang.org/x/sync/errgroup"
+
+	"vitess.io/vitess/go/sqltypes"
+	querypb "vitess.io/vitess/go/vt/proto/query"
+	"vitess.io/vitess/go/vt/sqlparser"
+	"vitess.io/vitess/go/vt/vterrors"
+	"vitess.io/vitess/go/vt/vtgate/engine/opcode"
+	"vitess.io/vitess/go/vt/vtgate/evalengine"
+)
+
+var _ Primitive = (*CorrelatedSubquery)(nil)
+
+// CorrelatedSubquery executes a subquery that depends on the rows of the outer query.
+// For every row of the outer query, the columns listed in Vars are sent as bind variables
+// to the subquery, and its result is bound to SubqueryResult and HasValues, the same way
+// UncorrelatedSubquery does. The subquery is only executed once for rows that have the
+// same values for these columns.
+//
+// When Predicate is set, the subquery is used to filter the outer query: the predicate
+// is evaluated on every row of the outer query, and the rows for which it's not true are dropped.
+// Otherwise, the value of the subquery is added as the first column of the rows of the outer query:
+// the value of Value when it's set, like for IN subqueries, the value of HasValues for EXISTS
+// subqueries, and the one of SubqueryResult for the others.
+type CorrelatedSubquery struct {
+	Opcode opcode.PulloutOpcode
+
+	// SubqueryResult and HasValues are the bind variables holding the result of the subquery
+	SubqueryResult string
+	HasValues      string
+
+	// Vars are the columns of the outer query that are sent as bind variables to the subquery
+	Vars map[string]int
+
+	Predicate    evalengine.Expr
+	ASTPredicate sqlparser.Expr
+
+	// Value is the comparison with the result of the subquery that is evaluated
+	// on every row of the outer query, when the subquery is not used as a filter.
+	Value    evalengine.Expr
+	ASTValue sqlparser.Expr
+
+	Outer    Primitive
+	Subquery Primitive
+}
+
+// Inputs returns the input primitives for this primitive
+func (cs *CorrelatedSubquery) Inputs() ([]Primitive, []map[string]any) {
+	return []Primitive{cs.Outer, cs.Subquery}, []map[string]any{{
+		inputName: "Outer",
+	}, {
+		inputName: "SubQuery",
+	}}
+}
+
+// TryExecute satisfies the Primitive interface.
+func (cs *CorrelatedSubquery) TryExecute(ctx context.Context, vcursor VCursor, bindVars map[string]*querypb.BindVariable, wantfields bool) (*sqltypes.Result, error) {
+	outer, err := vcursor.ExecutePrimitive(ctx, cs.Outer, bindVars, wantfields)
+	if err != nil {
+		return nil, err
+	}
+	result := &sqltypes.Result{Fields: outer.Fields}
+	if wantfields && cs.Predicate == nil {
+		if result.Fields, err = cs.valueFields(ctx, vcursor, bindVars, outer.Fields); err != nil {
+			return nil, err
+		}
+	}
+	rows, err := cs.newApplier(ctx, vcursor, bindVars).apply(outer.Rows)
+	if err != nil {
+		return nil, err
+	}
+	result.Rows = rows
+	return result, nil
+}
+
+// TryStreamExecute performs a streaming exec.
+func (cs *CorrelatedSubquery) TryStreamExecute(ctx context.Context, vcursor VCursor, bindVars map[string]*querypb.BindVariable, wantfields bool, callback func(*sqltypes.Result) error) error {
+	var mu sync.Mutex
+	applier := cs.newApplier(ctx, vcursor, bindVars)
+	return vcursor.StreamExecutePrimitive(ctx, cs.Outer, bindVars, wantfields, func(outer *sqltypes.Result) error {
+		mu.Lock()
+		defer mu.Unlock()
+		result := &sqltypes.Result{Fields: outer.Fields}
+		if len(outer.Fields) > 0 && cs.Predicate == nil {
+			fields, err := cs.valueFields(ctx, vcursor, bindVars, outer.Fields)
+			if err != nil {
+				return err
+			}
+			result.Fields = fields
+		}
+		rows, err := applier.apply(outer.Rows)
+		if err != nil {
+			return err
+		}
+		result.Rows = rows
+		return callback(result)
+	})
+}
+
+// GetFields fetches the field info.
+func (cs *CorrelatedSubquery) GetFields(ctx context.Context, vcursor VCursor, bindVars map[string]*querypb.BindVariable) (*sqltypes.Result, error) {
+	outer, err := cs.Outer.GetFields(ctx, vcursor, bindVars)
+	if err != nil {
+		return nil, err
+	}
+	if cs.Predicate != nil {
+		return outer, nil
+	}
+	fields, err := cs.valueFields(ctx, vcursor, bindVars, outer.Fields)
+	if err != nil {
+		return nil, err
+	}
+	return &sqltypes.Result{Fields: fields}, nil
+}
+
+// valueFields returns the fields of the outer query, preceded by the field of the value of the subquery
+func (cs *CorrelatedSubquery) valueFields(ctx context.Context, vcursor VCursor, bindVars map[string]*querypb.BindVariable, outerFields []*querypb.Field) ([]*querypb.Field, error) {
+	if cs.ASTValue != nil {
+		return append([]*querypb.Field{{Name: sqlparser.String(cs.ASTValue), Type: sqltypes.Int64}}, outerFields...), nil
+	}
+	if cs.Opcode == opcode.PulloutExists {
+		return append([]*querypb.Field{{Name: cs.HasValues, Type: sqltypes.Int64}}, outerFields...), nil
+	}
+	joinVars := make(map[string]*querypb.BindVariable, len(cs.Vars))
+	for k := range cs.Vars {
+		joinVars[k] = sqltypes.NullBindVariable
+	}
+	sub, err := cs.Subquery.GetFields(ctx, vcursor, combineVars(bindVars, joinVars))
+	if err != nil {
+		return nil, err
+	}
+	if len(sub.Fields) == 0 {
+		return nil, vterrors.VT13001("the subquery does not return any column")
+	}
+	return append([]*querypb.Field{sub.Fields[0]}, outerFields...), nil
+}
+
+// NeedsTransaction implements the Primitive interface
+func (cs *CorrelatedSubquery) NeedsTransaction() bool {
+	return cs.Subquery.NeedsTransaction() || cs.Outer.NeedsTransaction()
+}
+
+func (cs *CorrelatedSubquery) description() PrimitiveDescription {
+	other := map[string]any{}
+	if len(cs.Vars) > 0 {
+		other["JoinVars"] = orderedStringIntMap(cs.Vars)
+	}
+	var pulloutVars []string
+	if cs.HasValues != "" {
+		pulloutVars = append(pulloutVars, cs.HasValues)
+	}
+	if cs.SubqueryResult != "" {
+		pulloutVars = append(pulloutVars, cs.SubqueryResult)
+	}
+	if len(pulloutVars) > 0 {
+		other["PulloutVars"] = pulloutVars
+	}
+	if cs.ASTPredicate != nil {
+		other["Predicate"] = sqlparser.String(cs.ASTPredicate)
+	}
+	if cs.ASTValue != nil {
+		other["Value"] = sqlparser.String(cs.ASTValue)
+	}
+	return PrimitiveDescription{
+		OperatorType: "CorrelatedSubquery",
+		Variant:      cs.Opcode.String(),
+		Other:        other,
+	}
+}
+
+// correlatedSubqueryBatchSize is the maximum number of distinct values of the join vars
+// for which the subquery is executed at once, concurrently when not in a transaction.
+const correlatedSubqueryBatchSize = 32
+
+// correlatedSubqueryApplier executes the subquery for the rows of the outer query.
+// The rows are handled in batches: the subquery is executed for the distinct values of
+// the join vars of a batch, and then the rows of the batch are evaluated with its results.
+// The applier remembers the results for the values it has already seen, so rows with the
+// same values don't execute the subquery again, as long as the remembered results don't
+// hold more rows than the max memory rows.
+type correlatedSubqueryApplier struct {
+	cs       *CorrelatedSubquery
+	ctx      context.Context
+	vcursor  VCursor
+	bindVars map[string]*querypb.BindVariable
+	// env evaluates the predicate, with the bind variables of the query
+	// and the ones holding the result of the subquery for the current row
+	env *evalengine.ExpressionEnv
+
+	names   []string
+	key     []byte
+	results map[string]map[string]*querypb.BindVariable
+	// resultRows is the number of rows of the subquery held by results
+	resultRows int
+}
+
+// subqueryExecution is the execution of the subquery for the values of the join vars of a row.
+type subqueryExecution struct {
+	key      string
+	joinVars map[string]*querypb.BindVariable
+
+	vars map[string]*querypb.BindVariable
+	rows int
+}
+
+func (cs *CorrelatedSubquery) newApplier(ctx context.Context, vcursor VCursor, bindVars map[string]*querypb.BindVariable) *correlatedSubqueryApplier {
+	names := make([]string, 0, len(cs.Vars))
+	for k := range cs.Vars {
+		names = append(names, k)
+	}
+	slices.Sort(names)
+	return &correlatedSubqueryApplier{
+		cs:       cs,
+		ctx:      ctx,
+		vcursor:  vcursor,
+		bindVars: bindVars,
+		env:      evalengine.NewExpressionEnv(ctx, combineVars(bindVars, nil), vcursor),
+		names:    names,
+		results:  map[string]map[string]*querypb.BindVariable{},
+	}
+}
+
+func (a *correlatedSubqueryApplier) apply(rows []sqltypes.Row) ([]sqltypes.Row, error) {
+	var out []sqltypes.Row
+	for len(rows) > 0 {
+		keys, batch, err := a.nextBatch(rows)
+		if err != nil {
+			return nil, err
+		}
+		for i, key := range keys {
+			if out, err = a.applyRow(out, rows[i], batch[key]); err != nil {
+				return nil, err
+			}
+		}
+		rows = rows[len(keys):]
+	}
+	return out, nil
+}
+
+// applyRow appends the row to out, with the value of the subquery, or if it matches the predicate.
+func (a *correlatedSubqueryApplier) applyRow(out []sqltypes.Row, row sqltypes.Row, vars map[string]*querypb.BindVariable) ([]sqltypes.Row, error) {
+	if a.cs.Value != nil {
+		res, err := a.evaluate(a.cs.Value, row, vars)
+		if err != nil {
+			return nil, err
+		}
+		return append(out, append(sqltypes.Row{res.Value(a.vcursor.ConnCollation())}, row...)), nil
+	}
+	if a.cs.Predicate == nil {
+		name := a.cs.SubqueryResult
+		if a.cs.Opcode == opcode.PulloutExists {
+			name = a.cs.HasValues
+		}
+		value := sqltypes.NULL
+		if bv := vars[name]; bv != nil {
+			var err error
+			if value, err = sqltypes.BindVariableToValue(bv); err != nil {
+				return nil, err
+			}
+		}
+		return append(out, append(sqltypes.Row{value}, row...)), nil
+	}
+
+	res, err := a.evaluate(a.cs.Predicate, row, vars)
+	if err != nil {
+		return nil, err
+	}
+	if res.ToBoolean() {
+		out = append(out, row)
+	}
+	return out, nil
+}
+
+// evaluate evaluates an expression on a row of the outer query, with the result of the subquery for this row
+func (a *correlatedSubqueryApplier) evaluate(expr evalengine.Expr, row sqltypes.Row, vars map[string]*querypb.BindVariable) (evalengine.EvalResult, error) {
+	for k, v := range vars {
+		a.env.BindVars[k] = v
+	}
+	a.env.Row = row
+	return a.env.Evaluate(expr)
+}
+
+// nextBatch returns the keys of the values of the join vars of the first rows, and the bind
+// variables holding the result of the subquery for these keys. The batch ends before the
+// row that would need more than correlatedSubqueryBatchSize executions of the subquery.
+func (a *correlatedSubqueryApplier) nextBatch(rows []sqltypes.Row) ([]string, map[string]map[string]*querypb.BindVariable, error) {
+	var keys []string
+	var executions []*subqueryExecution
+	batch := map[string]map[string]*querypb.BindVariable{}
+	for _, row := range rows {
+		key := a.rowKey(row)
+		if _, ok := batch[key]; !ok {
+			if vars, ok := a.results[key]; ok {
+				batch[key] = vars
+			} else {
+				if len(executions) == correlatedSubqueryBatchSize {
+					break
+				}
+				executions = append(executions, &subqueryExecution{key: key, joinVars: a.joinVars(row)})
+				batch[key] = nil
+			}
+		}
+		keys = append(keys, key)
+	}
+
+	if err := a.execute(executions); err != nil {
+		return nil, nil, err
+	}
+	for _, e := range executions {
+		batch[e.key] = e.vars
+		a.remember(e)
+	}
+	return keys, batch, nil
+}
+
+// rowKey returns the values of the join vars of the row, encoded as a string.
+func (a *correlatedSubqueryApplier) rowKey(row sqltypes.Row) string {
+	a.key = a.key[:0]
+	for _, name := range a.names {
+		v := row[a.cs.Vars[name]]
+		a.key = binary.AppendUvarint(a.key, uint64(v.Type()))
+		a.key = binary.AppendUvarint(a.key, uint64(len(v.Raw())))
+		a.key = append(a.key, v.Raw()...)
+	}
+	return string(a.key)
+}
+
+func (a *correlatedSubqueryApplier) joinVars(row sqltypes.Row) map[string]*querypb.BindVariable {
+	joinVars := make(map[string]*querypb.BindVariable, len(a.names))
+	for _, name := range a.names {
+		joinVars[name] = sqltypes.ValueBindVariable(row[a.cs.Vars[name]])
+	}
+	return joinVars
+}
+
+// execute executes the subquery for a batch of values of the join vars. The executions run
+// concurrently, unless the session is in a transaction, whose queries must run one at a time.
+func (a *correlatedSubqueryApplier) execute(executions []*subqueryExecution) error {
+	if len(executions) == 1 || a.vcursor.Session().InTransaction() {
+		for _, e := range executions {
+			if err := a.executeOne(a.ctx, e); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+	g, ctx := errgroup.WithContext(a.ctx)
+	for _, e := range executions {
+		g.Go(func() error {
+			return a.executeOne(ctx, e)
+		})
+	}
+	return g.Wait()
+}
+
+func (a *correlatedSubqueryApplier) executeOne(ctx context.Context, e *subqueryExecution) error {
+	result, err := a.vcursor.ExecutePrimitive(ctx, a.cs.Subquery, combineVars(a.bindVars, e.joinVars), false)
+	if err != nil {
+		return err
+	}
+	if a.vcursor.ExceedsMaxMemoryRows(len(result.Rows)) {
+		return fmt.Errorf("in-memory row count exceeded allowed limit of %d", a.vcursor.MaxMemoryRows())
+	}
+	e.vars = make(map[string]*querypb.BindVariable, 2)
+	e.rows = max(len(result.Rows), 1)
+	return bindSubqueryResult(a.cs.Opcode, result, a.cs.SubqueryResult, a.cs.HasValues, e.vars)
+}
+
+// remember keeps the result of an execution of the subquery for the rows that have the same
+// values for the join vars. The results remembered so far are forgotten if they would hold
+// more rows than the max memory rows.
+func (a *correlatedSubqueryApplier) remember(e *subqueryExecution) {
+	if a.resultRows+e.rows > a.vcursor.MaxMemoryRows() {
+		clear(a.results)
+		a.resultRows = 0
+	}
+	a.results[e.key] = e.vars
+	a.resultRows += e.rows
+}
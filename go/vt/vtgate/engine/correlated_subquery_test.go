@@ -0,0 +1,334 @@
+/*
+Copyright 2025 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package engine
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"vitess.io/vitess/go/sqltypes"
+	querypb "vitess.io/vitess/go/vt/proto/query"
+	"vitess.io/vitess/go/vt/sqlparser"
+	"vitess.io/vitess/go/vt/vtenv"
+	. "vitess.io/vitess/go/vt/vtgate/engine/opcode"
+	"vitess.io/vitess/go/vt/vtgate/evalengine"
+)
+
+func TestCorrelatedSubqueryFilter(t *testing.T) {
+	outerFields := sqltypes.MakeTestFields("id|col", "int64|int64")
+	outerResult := sqltypes.MakeTestResult(outerFields, "1|10", "2|20", "3|10", "4|30")
+	sqFields := sqltypes.MakeTestFields("x", "int64")
+	hasValues := sqlparser.NewArgument("__sq_has_values")
+
+	tests := []struct {
+		name      string
+		opcode    PulloutOpcode
+		predicate sqlparser.Expr
+		// sqResults are the results of the subquery for col=10, col=20 and col=30
+		sqResults [3][]string
+		expected  []string
+	}{{
+		name:   "value",
+		opcode: PulloutValue,
+		// col < (select ...)
+		predicate: &sqlparser.ComparisonExpr{
+			Operator: sqlparser.LessThanOp,
+			Left:     sqlparser.NewOffset(1, nil),
+			Right:    sqlparser.NewArgument("__sq1"),
+		},
+		sqResults: [3][]string{{"15"}, {"5"}, nil},
+		expected:  []string{"1|10", "3|10"},
+	}, {
+		name:   "in",
+		opcode: PulloutIn,
+		// id in (select ...)
+		predicate: sqlparser.AndExpressions(hasValues, &sqlparser.ComparisonExpr{
+			Operator: sqlparser.InOp,
+			Left:     sqlparser.NewOffset(0, nil),
+			Right:    sqlparser.ListArg("__sq1"),
+		}),
+		sqResults: [3][]string{{"1", "2"}, {"2"}, nil},
+		expected:  []string{"1|10", "2|20"},
+	}, {
+		name:   "not in",
+		opcode: PulloutNotIn,
+		// id not in (select ...)
+		predicate: &sqlparser.OrExpr{
+			Left: &sqlparser.NotExpr{Expr: hasValues},
+			Right: &sqlparser.ComparisonExpr{
+				Operator: sqlparser.NotInOp,
+				Left:     sqlparser.NewOffset(0, nil),
+				Right:    sqlparser.ListArg("__sq1"),
+			},
+		},
+		sqResults: [3][]string{{"1", "2"}, {"2"}, nil},
+		expected:  []string{"3|10", "4|30"},
+	}, {
+		name:      "not exists",
+		opcode:    PulloutExists,
+		predicate: &sqlparser.NotExpr{Expr: hasValues},
+		sqResults: [3][]string{{"1"}, nil, {"1"}},
+		expected:  []string{"2|20"},
+	}}
+
+	for _, tc := range tests {
+		predicate, err := evalengine.Translate(tc.predicate, &evalengine.Config{
+			ResolveColumn: evalengine.FieldResolver(outerFields).Column,
+			Environment:   vtenv.NewTestEnv(),
+		})
+		require.NoError(t, err)
+
+		ufp := &fakePrimitive{results: []*sqltypes.Result{outerResult}}
+		sfp := newFakeCorrelatedSubquery(map[string]*sqltypes.Result{
+			"10": sqltypes.MakeTestResult(sqFields, tc.sqResults[0]...),
+			"20": sqltypes.MakeTestResult(sqFields, tc.sqResults[1]...),
+			"30": sqltypes.MakeTestResult(sqFields, tc.sqResults[2]...),
+		})
+		cs := &CorrelatedSubquery{
+			Opcode:         tc.opcode,
+			SubqueryResult: "__sq1",
+			HasValues:      "__sq_has_values",
+			Vars:           map[string]int{"col": 1},
+			Predicate:      predicate,
+			ASTPredicate:   tc.predicate,
+			Outer:          ufp,
+			Subquery:       sfp,
+		}
+		expected := sqltypes.MakeTestResult(outerFields, tc.expected...)
+		// the subquery is executed once for every distinct value of col
+		expectedLog := []string{"10", "20", "30"}
+
+		t.Run(tc.name, func(t *testing.T) {
+			ufp.rewind()
+			sfp.rewind()
+			result, err := cs.TryExecute(context.Background(), &noopVCursor{}, map[string]*querypb.BindVariable{}, true)
+			require.NoError(t, err)
+			expectResult(t, result, expected)
+			assert.Equal(t, expectedLog, sfp.executed())
+		})
+		t.Run("Streaming "+tc.name, func(t *testing.T) {
+			ufp.rewind()
+			sfp.rewind()
+			result, err := wrapStreamExecute(cs, &noopVCursor{}, map[string]*querypb.BindVariable{}, true)
+			require.NoError(t, err)
+			expectResult(t, result, expected)
+			assert.Equal(t, expectedLog, sfp.executed())
+		})
+	}
+}
+
+func TestCorrelatedSubqueryValue(t *testing.T) {
+	outerFields := sqltypes.MakeTestFields("id|col", "int64|int64")
+	outerResult := sqltypes.MakeTestResult(outerFields, "1|10", "2|20", "3|10")
+	sqFields := sqltypes.MakeTestFields("x", "varchar")
+
+	// id in (select ...)
+	in := sqlparser.AndExpressions(sqlparser.NewArgument("__sq_has_values"), &sqlparser.ComparisonExpr{
+		Operator: sqlparser.InOp,
+		Left:     sqlparser.NewOffset(0, nil),
+		Right:    sqlparser.ListArg("__sq1"),
+	})
+
+	tests := []struct {
+		opcode    PulloutOpcode
+		value     sqlparser.Expr
+		sqResults map[string]*sqltypes.Result
+		expected  *sqltypes.Result
+	}{{
+		opcode: PulloutValue,
+		sqResults: map[string]*sqltypes.Result{
+			// the subquery is executed once with NULL join vars for its fields
+			"":   sqltypes.MakeTestResult(sqFields),
+			"10": sqltypes.MakeTestResult(sqFields, "a"),
+			"20": sqltypes.MakeTestResult(sqFields),
+		},
+		expected: sqltypes.MakeTestResult(
+			sqltypes.MakeTestFields("x|id|col", "varchar|int64|int64"),
+			"a|1|10", "null|2|20", "a|3|10",
+		),
+	}, {
+		opcode: PulloutExists,
+		sqResults: map[string]*sqltypes.Result{
+			"10": sqltypes.MakeTestResult(sqFields, "a"),
+			"20": sqltypes.MakeTestResult(sqFields),
+		},
+		expected: sqltypes.MakeTestResult(
+			sqltypes.MakeTestFields("__sq_has_values|id|col", "int64|int64|int64"),
+			"1|1|10", "0|2|20", "1|3|10",
+		),
+	}, {
+		opcode: PulloutIn,
+		value:  in,
+		sqResults: map[string]*sqltypes.Result{
+			"10": sqltypes.MakeTestResult(sqFields, "1", "2"),
+			"20": sqltypes.MakeTestResult(sqFields),
+		},
+		expected: sqltypes.MakeTestResult(
+			sqltypes.MakeTestFields(":__sq_has_values and :0 in ::__sq1|id|col", "int64|int64|int64"),
+			"1|1|10", "0|2|20", "0|3|10",
+		),
+	}}
+
+	for _, tc := range tests {
+		t.Run(tc.opcode.String(), func(t *testing.T) {
+			cs := &CorrelatedSubquery{
+				Opcode:         tc.opcode,
+				SubqueryResult: "__sq1",
+				HasValues:      "__sq_has_values",
+				Vars:           map[string]int{"col": 1},
+				Outer:          &fakePrimitive{results: []*sqltypes.Result{outerResult}},
+				Subquery:       newFakeCorrelatedSubquery(tc.sqResults),
+			}
+			if tc.value != nil {
+				var err error
+				cs.Value, err = evalengine.Translate(tc.value, &evalengine.Config{
+					ResolveColumn: evalengine.FieldResolver(outerFields).Column,
+					Environment:   vtenv.NewTestEnv(),
+				})
+				require.NoError(t, err)
+				cs.ASTValue = tc.value
+			}
+			result, err := cs.TryExecute(context.Background(), &noopVCursor{}, map[string]*querypb.BindVariable{}, true)
+			require.NoError(t, err)
+			expectResult(t, result, tc.expected)
+		})
+	}
+}
+
+func TestCorrelatedSubqueryTooManyRows(t *testing.T) {
+	sqFields := sqltypes.MakeTestFields("x", "int64")
+	cs := &CorrelatedSubquery{
+		Opcode:         PulloutValue,
+		SubqueryResult: "__sq1",
+		Vars:           map[string]int{"col": 0},
+		Outer: &fakePrimitive{results: []*sqltypes.Result{
+			sqltypes.MakeTestResult(sqltypes.MakeTestFields("col", "int64"), "1"),
+		}},
+		Subquery: &fakePrimitive{results: []*sqltypes.Result{
+			sqltypes.MakeTestResult(sqFields, "1", "2"),
+		}},
+	}
+	_, err := cs.TryExecute(context.Background(), &noopVCursor{}, map[string]*querypb.BindVariable{}, false)
+	require.EqualError(t, err, "subquery returned more than one row")
+}
+
+func TestCorrelatedSubqueryMemory(t *testing.T) {
+	fields := sqltypes.MakeTestFields("col", "int64")
+	// the values of col are seen twice, and the results of the subquery for
+	// all of them hold more rows than the max memory rows
+	var rows []string
+	for range 2 {
+		for i := range testMaxMemoryRows + 50 {
+			rows = append(rows, fmt.Sprint(i))
+		}
+	}
+	sqResults := map[string]*sqltypes.Result{}
+	for i := range testMaxMemoryRows + 50 {
+		sqResults[fmt.Sprint(i)] = sqltypes.MakeTestResult(fields, fmt.Sprint(i))
+	}
+
+	t.Run("forgotten results", func(t *testing.T) {
+		sq := newFakeCorrelatedSubquery(sqResults)
+		cs := &CorrelatedSubquery{
+			Opcode:         PulloutValue,
+			SubqueryResult: "__sq1",
+			Vars:           map[string]int{"col": 0},
+			Outer:          &fakePrimitive{results: []*sqltypes.Result{sqltypes.MakeTestResult(fields, rows...)}},
+			Subquery:       sq,
+		}
+		result, err := cs.TryExecute(context.Background(), &noopVCursor{}, map[string]*querypb.BindVariable{}, false)
+		require.NoError(t, err)
+		require.Len(t, result.Rows, len(rows))
+		for _, row := range result.Rows {
+			assert.Equal(t, row[1], row[0])
+		}
+		// the results for the first values were forgotten before they were seen again
+		assert.Len(t, sq.executed(), len(rows))
+	})
+
+	t.Run("too many rows", func(t *testing.T) {
+		cs := &CorrelatedSubquery{
+			Opcode:         PulloutIn,
+			SubqueryResult: "__sq1",
+			HasValues:      "__sq_has_values",
+			Vars:           map[string]int{"col": 0},
+			Outer:          &fakePrimitive{results: []*sqltypes.Result{sqltypes.MakeTestResult(fields, "1")}},
+			Subquery: newFakeCorrelatedSubquery(map[string]*sqltypes.Result{
+				"1": sqltypes.MakeTestResult(fields, rows...),
+			}),
+		}
+		_, err := cs.TryExecute(context.Background(), &noopVCursor{}, map[string]*querypb.BindVariable{}, false)
+		require.EqualError(t, err, fmt.Sprintf("in-memory row count exceeded allowed limit of %d", testMaxMemoryRows))
+	})
+}
+
+// fakeCorrelatedSubquery fakes a subquery whose result depends on the value of its col
+// bind variable, and that can be executed concurrently.
+type fakeCorrelatedSubquery struct {
+	fakePrimitive
+
+	mu      sync.Mutex
+	byValue map[string]*sqltypes.Result
+	values  []string
+}
+
+func newFakeCorrelatedSubquery(byValue map[string]*sqltypes.Result) *fakeCorrelatedSubquery {
+	return &fakeCorrelatedSubquery{byValue: byValue}
+}
+
+func (f *fakeCorrelatedSubquery) TryExecute(ctx context.Context, vcursor VCursor, bindVars map[string]*querypb.BindVariable, wantfields bool) (*sqltypes.Result, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	value := string(bindVars["col"].GetValue())
+	f.values = append(f.values, value)
+	result, ok := f.byValue[value]
+	if !ok {
+		return nil, fmt.Errorf("unexpected value of col: %q", value)
+	}
+	return result.Copy(), nil
+}
+
+func (f *fakeCorrelatedSubquery) GetFields(ctx context.Context, vcursor VCursor, bindVars map[string]*querypb.BindVariable) (*sqltypes.Result, error) {
+	return f.TryExecute(ctx, vcursor, bindVars, true)
+}
+
+func (f *fakeCorrelatedSubquery) rewind() {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.values = nil
+}
+
+// executed returns the values of col the subquery was executed with, excluding
+// the executions for its fields, in order.
+func (f *fakeCorrelatedSubquery) executed() []string {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	var values []string
+	for _, v := range f.values {
+		if v != "" {
+			values = append(values, v)
+		}
+	}
+	slices.Sort(values)
+	return values
+}
@@ -65,6 +65,10 @@ func (t *noopVCursor) GetExecutionMetrics() *Metrics {
 	panic("implement me")
 }
 
+func (t *noopVCursor) ReadLocalInfile(ctx context.Context, filename string, callback func(data []byte) error) error {
+	panic("implement me")
+}
+
 func (t *noopVCursor) SetExecQueryTimeout(timeout *int) {
 	panic("implement me")
 }
@@ -112,6 +116,18 @@ func (t *noopVCursor) ReleaseLock(context.Context) error {
 	panic("implement me")
 }
 
+func (t *noopVCursor) ReleaseLockTables(context.Context) error {
+	panic("implement me")
+}
+
+func (t *noopVCursor) InLockTables() bool {
+	panic("implement me")
+}
+
+func (t *noopVCursor) SetInLockTables() {
+	panic("implement me")
+}
+
 func (t *noopVCursor) GetWarmingReadsPercent() int {
 	panic("implement me")
 }
@@ -192,7 +208,6 @@ func (t *noopVCursor) HasSystemVariables() bool {
 }
 
 func (t *noopVCursor) GetSystemVariables(func(k string, v string)) {
-	panic("implement me")
 }
 
 func (t *noopVCursor) GetWarnings() []*querypb.QueryWarning {
@@ -227,6 +242,14 @@ func (t *noopVCursor) GetMigrationContext() string {
 	panic("implement me")
 }
 
+func (t *noopVCursor) SetTenantID(string) error {
+	panic("implement me")
+}
+
+func (t *noopVCursor) GetTenantID(context.Context) string {
+	panic("implement me")
+}
+
 func (t *noopVCursor) GetSessionUUID() string {
 	panic("implement me")
 }
@@ -365,6 +388,10 @@ func (t *noopVCursor) ExceedsMaxMemoryRows(numRows int) bool {
 	return !testIgnoreMaxMemoryRows && numRows > testMaxMemoryRows
 }
 
+func (t *noopVCursor) SpillBudget() *SpillBudget {
+	return nil
+}
+
 func (t *noopVCursor) GetKeyspace() string {
 	return "test_ks"
 }
@@ -422,6 +449,10 @@ func (t *noopVCursor) GetLogs() ([]ExecuteEntry, error) {
 func (t *noopVCursor) RecordMirrorStats(sourceExecTime, targetExecTime time.Duration, targetErr error) {
 }
 
+// RecordMirrorResultDiff implements VCursor.
+func (t *noopVCursor) RecordMirrorResultDiff(diff string) {
+}
+
 var (
 	_ VCursor        = (*loggingVCursor)(nil)
 	_ SessionActions = (*loggingVCursor)(nil)
@@ -457,6 +488,7 @@ type loggingVCursor struct {
 	dbDDLPlugin     string
 	ksAvailable     bool
 	inReservedConn  bool
+	inLockTables    bool
 	systemVariables map[string]string
 	disableSetVar   bool
 
@@ -465,6 +497,9 @@ type loggingVCursor struct {
 
 	shardSession []*srvtopo.ResolvedShard
 
+	// the chunks sent by the client for LOAD DATA LOCAL INFILE
+	localInfile []string
+
 	parser *sqlparser.Parser
 
 	onMirrorClonesFn       func(context.Context) VCursor
@@ -472,13 +507,32 @@ type loggingVCursor struct {
 	onStreamExecuteMultiFn func(context.Context, Primitive, string, []*srvtopo.ResolvedShard, []map[string]*querypb.BindVariable, bool, bool, func(*sqltypes.Result) error)
 	onRecordMirrorStatsFn  func(time.Duration, time.Duration, error)
 
+	tenantID             string
+	onRecordMirrorDiffFn func(string)
+
 	metrics *Metrics
+
+	spillBudget *SpillBudget
 }
 
 func (f *loggingVCursor) GetExecutionMetrics() *Metrics {
 	return f.metrics
 }
 
+func (f *loggingVCursor) SpillBudget() *SpillBudget {
+	return f.spillBudget
+}
+
+func (f *loggingVCursor) ReadLocalInfile(ctx context.Context, filename string, callback func(data []byte) error) error {
+	f.log = append(f.log, "ReadLocalInfile "+filename)
+	for _, chunk := range f.localInfile {
+		if err := callback([]byte(chunk)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
 func (f *loggingVCursor) HasCreatedTempTable() {
 	f.log = append(f.log, "temp table getting created")
 }
@@ -521,8 +575,10 @@ func (f *loggingVCursor) HasSystemVariables() bool {
 	return len(f.systemVariables) > 0
 }
 
-func (f *loggingVCursor) GetSystemVariables(func(k string, v string)) {
-	panic("implement me")
+func (f *loggingVCursor) GetSystemVariables(fn func(k string, v string)) {
+	for k, v := range f.systemVariables {
+		fn(k, v)
+	}
 }
 
 func (f *loggingVCursor) SetFoundRows(u uint64) {
@@ -559,6 +615,22 @@ func (f *loggingVCursor) InReservedConn() bool {
 	return f.inReservedConn
 }
 
+func (f *loggingVCursor) InLockTables() bool {
+	return f.inLockTables
+}
+
+func (f *loggingVCursor) SetInLockTables() {
+	f.log = append(f.log, "In Lock Tables")
+	f.inLockTables = true
+	f.inReservedConn = true
+}
+
+func (f *loggingVCursor) ReleaseLockTables(context.Context) error {
+	f.log = append(f.log, "Release Lock Tables")
+	f.inLockTables = false
+	return nil
+}
+
 func (f *loggingVCursor) ShardSession() []*srvtopo.ResolvedShard {
 	return f.shardSession
 }
@@ -571,6 +643,10 @@ func (f *loggingVCursor) Session() SessionActions {
 	return f
 }
 
+func (f *loggingVCursor) GetTenantID(context.Context) string {
+	return f.tenantID
+}
+
 func (f *loggingVCursor) SetTarget(target string) error {
 	f.log = append(f.log, fmt.Sprintf("Target set to %s", target))
 	return nil
@@ -925,6 +1001,12 @@ func (t *loggingVCursor) RecordMirrorStats(sourceExecTime, targetExecTime time.D
 	}
 }
 
+func (t *loggingVCursor) RecordMirrorResultDiff(diff string) {
+	if t.onRecordMirrorDiffFn != nil {
+		t.onRecordMirrorDiffFn(diff)
+	}
+}
+
 func expectResult(t *testing.T, result, want *sqltypes.Result) {
 	t.Helper()
 	fieldsResult := fmt.Sprintf("%v", result.Fields)
@@ -18,6 +18,8 @@ package engine
 
 import (
 	"context"
+	"encoding/binary"
+	"errors"
 	"fmt"
 	"strings"
 	"sync"
@@ -26,7 +28,9 @@ import (
 	"vitess.io/vitess/go/mysql/collations"
 	"vitess.io/vitess/go/sqltypes"
 	querypb "vitess.io/vitess/go/vt/proto/query"
+	vtrpcpb "vitess.io/vitess/go/vt/proto/vtrpc"
 	"vitess.io/vitess/go/vt/sqlparser"
+	"vitess.io/vitess/go/vt/vterrors"
 	"vitess.io/vitess/go/vt/vtgate/evalengine"
 	"vitess.io/vitess/go/vt/vthash"
 )
@@ -36,9 +40,9 @@ var _ Primitive = (*HashJoin)(nil)
 type (
 	// HashJoin specifies the parameters for a join primitive
 	// Hash joins work by fetch all the input from the LHS, and building a hash map, known as the probe table, for this input.
-	// The key to the map is the hashcode of the value for column that we are joining by.
+	// The key to the map is the hashcode of the values of the columns that we are joining by.
 	// Then the RHS is fetched, and we can check if the rows from the RHS matches any from the LHS.
-	// When they match by hash code, we double-check that we are not working with a false positive by comparing the values.
+	// The rows that match by hash code are then checked against the residual predicate, if there is one.
 	HashJoin struct {
 		Opcode JoinOpcode
 
@@ -55,33 +59,42 @@ type (
 		// the returned result will be {Left0, Left1, Right0, Right1}.
 		Cols []int
 
-		// The keys correspond to the column offset in the inputs where
-		// the join columns can be found
-		LHSKey, RHSKey int
+		// The keys correspond to the column offsets in the inputs where
+		// the join columns can be found. The values of all the keys of
+		// a row are hashed together.
+		LHSKeys, RHSKeys []int
 
 		// The join condition. Used for plan descriptions
 		ASTPred sqlparser.Expr
 
-		// collation and type are used to hash the incoming values correctly
-		Collation      collations.ID
-		ComparisonType querypb.Type
+		// ComparisonTypes are used to hash the incoming values of each key correctly
+		ComparisonTypes []evalengine.Type
 
 		CollationEnv *collations.Environment
 
-		// Values for enum and set types
-		Values *evalengine.EnumSetValues
+		// Residual is the part of the join condition that is not an equality
+		// between the keys. It is evaluated on the rows that match by hash code,
+		// built from the left and right columns listed in ResidualCols,
+		// which use the same encoding as Cols.
+		Residual     evalengine.Expr
+		ResidualCols []int
 	}
 
 	hashJoinProbeTable struct {
 		innerMap map[vthash.Hash]*probeTableEntry
 
-		coll           collations.ID
-		typ            querypb.Type
-		lhsKey, rhsKey int
-		cols           []int
-		hasher         vthash.Hasher
-		sqlmode        evalengine.SQLMode
-		values         *evalengine.EnumSetValues
+		lhsKeys, rhsKeys []int
+		types            []evalengine.Type
+		cols             []int
+		hasher           vthash.Hasher
+		sqlmode          evalengine.SQLMode
+		// seed is the seed of the hasher, which differs between the levels of
+		// partitions of the inputs that spilled to disk
+		seed uint64
+
+		residual     evalengine.Expr
+		residualCols []int
+		env          *evalengine.ExpressionEnv
 	}
 
 	probeTableEntry struct {
@@ -93,12 +106,19 @@ type (
 
 // TryExecute implements the Primitive interface
 func (hj *HashJoin) TryExecute(ctx context.Context, vcursor VCursor, bindVars map[string]*querypb.BindVariable, wantfields bool) (*sqltypes.Result, error) {
+	if vcursor.SpillBudget() != nil {
+		// stream the inputs, so that the probe table can be spilled to disk
+		return collectStream(func(callback func(*sqltypes.Result) error) error {
+			return hj.TryStreamExecute(ctx, vcursor, bindVars, wantfields, callback)
+		})
+	}
+
 	lresult, err := vcursor.ExecutePrimitive(ctx, hj.Left, bindVars, wantfields)
 	if err != nil {
 		return nil, err
 	}
 
-	pt := newHashJoinProbeTable(hj.Collation, hj.ComparisonType, hj.LHSKey, hj.RHSKey, hj.Cols, hj.Values)
+	pt := hj.newProbeTable(evalengine.NewExpressionEnv(ctx, bindVars, vcursor))
 	// build the probe table from the LHS result
 	for _, row := range lresult.Rows {
 		err := pt.addLeftRow(row)
@@ -134,7 +154,20 @@ func (hj *HashJoin) TryExecute(ctx context.Context, vcursor VCursor, bindVars ma
 // TryStreamExecute implements the Primitive interface
 func (hj *HashJoin) TryStreamExecute(ctx context.Context, vcursor VCursor, bindVars map[string]*querypb.BindVariable, wantfields bool, callback func(*sqltypes.Result) error) error {
 	// build the probe table from the LHS result
-	pt := newHashJoinProbeTable(hj.Collation, hj.ComparisonType, hj.LHSKey, hj.RHSKey, hj.Cols, hj.Values)
+	env := evalengine.NewExpressionEnv(ctx, bindVars, vcursor)
+	pt := hj.newProbeTable(env)
+	budget := vcursor.SpillBudget()
+	var partitions *hashJoinPartitions
+	var ptSize int64
+	defer func() {
+		if budget != nil {
+			budget.release(ptSize)
+		}
+		if partitions != nil {
+			partitions.close()
+		}
+	}()
+
 	var lfields []*querypb.Field
 	var mu sync.Mutex
 	err := vcursor.StreamExecutePrimitive(ctx, hj.Left, bindVars, wantfields, func(result *sqltypes.Result) error {
@@ -144,16 +177,42 @@ func (hj *HashJoin) TryStreamExecute(ctx context.Context, vcursor VCursor, bindV
 			lfields = result.Fields
 		}
 		for _, current := range result.Rows {
+			if partitions != nil {
+				if err := partitions.addLeftRow(current); err != nil {
+					return err
+				}
+				continue
+			}
 			err := pt.addLeftRow(current)
 			if err != nil {
 				return err
 			}
+			if budget == nil {
+				continue
+			}
+			n := rowSize(current)
+			ptSize += n
+			if budget.grow(n) {
+				continue
+			}
+			// the probe table doesn't fit in memory anymore, so we move it to disk,
+			// and we will join the LHS and the RHS one partition at a time
+			partitions = newHashJoinPartitions(hj.newProbeTable(env), budget.Dir)
+			if err := partitions.addProbeTable(pt); err != nil {
+				return err
+			}
+			budget.release(ptSize)
+			ptSize = 0
+			pt = nil
 		}
 		return nil
 	})
 	if err != nil {
 		return err
 	}
+	if partitions != nil {
+		return hj.streamPartitions(ctx, vcursor, bindVars, wantfields, lfields, env, partitions, budget, callback)
+	}
 
 	var sendFields atomic.Bool
 	sendFields.Store(wantfields)
@@ -201,6 +260,192 @@ func (hj *HashJoin) TryStreamExecute(ctx context.Context, vcursor VCursor, bindV
 	return nil
 }
 
+// streamPartitions is the grace hash join algorithm: the RHS rows are written to the
+// partition matching the hash of their join keys, like the LHS rows were, and the
+// partitions are joined one at a time. A probe table is built only for the rows of
+// the LHS partition that is being joined, so it uses about 1/hashJoinPartitionCount of
+// the memory of the probe table for the whole LHS.
+func (hj *HashJoin) streamPartitions(
+	ctx context.Context,
+	vcursor VCursor,
+	bindVars map[string]*querypb.BindVariable,
+	wantfields bool,
+	lfields []*querypb.Field,
+	env *evalengine.ExpressionEnv,
+	partitions *hashJoinPartitions,
+	budget *SpillBudget,
+	callback func(*sqltypes.Result) error,
+) error {
+	var rfields []*querypb.Field
+	var mu sync.Mutex
+	err := vcursor.StreamExecutePrimitive(ctx, hj.Right, bindVars, wantfields, func(result *sqltypes.Result) error {
+		mu.Lock()
+		defer mu.Unlock()
+		if len(rfields) == 0 && len(result.Fields) != 0 {
+			rfields = result.Fields
+		}
+		for _, current := range result.Rows {
+			if err := partitions.addRightRow(current); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+	budget.recordSpill("HashJoin", partitions.size())
+
+	if wantfields {
+		if len(rfields) == 0 {
+			rres, err := hj.Right.GetFields(ctx, vcursor, bindVars)
+			if err != nil {
+				return err
+			}
+			rfields = rres.Fields
+		}
+		if err := callback(&sqltypes.Result{Fields: joinFields(lfields, rfields, hj.Cols)}); err != nil {
+			return err
+		}
+	}
+
+	res := &sqltypes.Result{}
+	var resSize int64
+	emit := func(rows []sqltypes.Row, force bool) error {
+		for _, row := range rows {
+			res.Rows = append(res.Rows, row)
+			resSize += rowSize(row)
+		}
+		if len(res.Rows) == 0 || (!force && resSize < spillBatchSize) {
+			return nil
+		}
+		if err := callback(res); err != nil {
+			return err
+		}
+		res = &sqltypes.Result{}
+		resSize = 0
+		return nil
+	}
+
+	if err := hj.joinPartitions(env, partitions, budget, 0, emit); err != nil {
+		return err
+	}
+	return emit(nil, true)
+}
+
+// joinPartitions joins the partitions one at a time. The rows of an LHS partition
+// can be too many to fit in the spill memory budget, e.g. if they have only a few
+// distinct join keys: such a partition and its RHS partition are split again into
+// smaller partitions with another hash seed, up to hashJoinMaxPartitionDepth times.
+func (hj *HashJoin) joinPartitions(
+	env *evalengine.ExpressionEnv,
+	partitions *hashJoinPartitions,
+	budget *SpillBudget,
+	depth int,
+	emit func(rows []sqltypes.Row, force bool) error,
+) error {
+	for i := range partitions.left {
+		left, right := partitions.left[i], partitions.right[i]
+		if left == nil || (right == nil && hj.Opcode != LeftJoin) {
+			continue
+		}
+
+		joined, err := hj.joinPartition(env, left, right, budget, emit)
+		if err != nil {
+			return err
+		}
+		if joined {
+			continue
+		}
+		if depth == hashJoinMaxPartitionDepth {
+			return vterrors.Errorf(vtrpcpb.Code_RESOURCE_EXHAUSTED,
+				"hash join partition exceeds the spill memory budget of %d bytes after %d re-partitions: too many rows of the LHS have the same join key",
+				budget.Limit, depth)
+		}
+		if err := hj.repartition(env, left, right, budget, depth+1, emit); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// joinPartition joins an LHS partition with its RHS partition, which can be nil.
+// It returns false, without emitting any row, if the rows of the LHS partition
+// don't fit in the spill memory budget.
+func (hj *HashJoin) joinPartition(
+	env *evalengine.ExpressionEnv,
+	left, right *spillFile,
+	budget *SpillBudget,
+	emit func(rows []sqltypes.Row, force bool) error,
+) (bool, error) {
+	pt := hj.newProbeTable(env)
+	var ptSize int64
+	defer func() {
+		budget.release(ptSize)
+	}()
+
+	err := readSpillFile(left, func(row sqltypes.Row) error {
+		n := rowSize(row)
+		ptSize += n
+		if !budget.grow(n) {
+			return errHashJoinPartitionTooLarge
+		}
+		return pt.addLeftRow(row)
+	})
+	if err == errHashJoinPartitionTooLarge {
+		return false, nil
+	}
+	if err != nil {
+		return false, err
+	}
+
+	if right != nil {
+		err := readSpillFile(right, func(row sqltypes.Row) error {
+			matches, err := pt.get(row)
+			if err != nil {
+				return err
+			}
+			return emit(matches, false)
+		})
+		if err != nil {
+			return false, err
+		}
+	}
+	if hj.Opcode == LeftJoin {
+		if err := emit(pt.notFetched(), false); err != nil {
+			return false, err
+		}
+	}
+	return true, nil
+}
+
+// repartition splits an LHS partition and its RHS partition into smaller partitions,
+// using the depth of the new partitions as the hash seed, and joins them.
+func (hj *HashJoin) repartition(
+	env *evalengine.ExpressionEnv,
+	left, right *spillFile,
+	budget *SpillBudget,
+	depth int,
+	emit func(rows []sqltypes.Row, force bool) error,
+) error {
+	hasher := hj.newProbeTable(env)
+	hasher.seed = uint64(depth)
+	hasher.hasher.Init(hasher.seed)
+	partitions := newHashJoinPartitions(hasher, budget.Dir)
+	defer partitions.close()
+
+	if err := readSpillFile(left, partitions.addLeftRow); err != nil {
+		return err
+	}
+	if right != nil {
+		if err := readSpillFile(right, partitions.addRightRow); err != nil {
+			return err
+		}
+	}
+	budget.recordSpill("HashJoin", partitions.size())
+	return hj.joinPartitions(env, partitions, budget, depth, emit)
+}
+
 // GetFields implements the Primitive interface
 func (hj *HashJoin) GetFields(ctx context.Context, vcursor VCursor, bindVars map[string]*querypb.BindVariable) (*sqltypes.Result, error) {
 	joinVars := make(map[string]*querypb.BindVariable)
@@ -229,14 +474,23 @@ func (hj *HashJoin) Inputs() ([]Primitive, []map[string]any) {
 
 // description implements the Primitive interface
 func (hj *HashJoin) description() PrimitiveDescription {
+	var types, colls []string
+	for _, typ := range hj.ComparisonTypes {
+		types = append(types, typ.Type().String())
+		if coll := typ.Collation(); coll != collations.Unknown {
+			colls = append(colls, hj.CollationEnv.LookupName(coll))
+		}
+	}
 	other := map[string]any{
 		"JoinColumnIndexes": strings.Trim(strings.Join(strings.Fields(fmt.Sprint(hj.Cols)), ","), "[]"),
 		"Predicate":         sqlparser.String(hj.ASTPred),
-		"ComparisonType":    hj.ComparisonType.String(),
+		"ComparisonType":    strings.Join(types, ", "),
+	}
+	if len(colls) > 0 {
+		other["Collation"] = strings.Join(colls, ", ")
 	}
-	coll := hj.Collation
-	if coll != collations.Unknown {
-		other["Collation"] = hj.CollationEnv.LookupName(coll)
+	if hj.Residual != nil {
+		other["ResidualColumnIndexes"] = strings.Trim(strings.Join(strings.Fields(fmt.Sprint(hj.ResidualCols)), ","), "[]")
 	}
 	return PrimitiveDescription{
 		OperatorType: "Join",
@@ -245,21 +499,31 @@ func (hj *HashJoin) description() PrimitiveDescription {
 	}
 }
 
-func newHashJoinProbeTable(coll collations.ID, typ querypb.Type, lhsKey, rhsKey int, cols []int, values *evalengine.EnumSetValues) *hashJoinProbeTable {
+func (hj *HashJoin) newProbeTable(env *evalengine.ExpressionEnv) *hashJoinProbeTable {
 	return &hashJoinProbeTable{
-		innerMap: map[vthash.Hash]*probeTableEntry{},
-		coll:     coll,
-		typ:      typ,
-		lhsKey:   lhsKey,
-		rhsKey:   rhsKey,
-		cols:     cols,
-		hasher:   vthash.New(),
-		values:   values,
+		innerMap:     map[vthash.Hash]*probeTableEntry{},
+		lhsKeys:      hj.LHSKeys,
+		rhsKeys:      hj.RHSKeys,
+		types:        hj.ComparisonTypes,
+		cols:         hj.Cols,
+		hasher:       vthash.New(),
+		residual:     hj.Residual,
+		residualCols: hj.ResidualCols,
+		env:          env,
 	}
 }
 
+// partition returns the partition of a row, among n partitions
+func (pt *hashJoinProbeTable) partition(row sqltypes.Row, keys []int, n int) (int, error) {
+	hash, err := pt.hash(row, keys)
+	if err != nil {
+		return 0, err
+	}
+	return int(binary.LittleEndian.Uint64(hash[:8]) % uint64(n)), nil
+}
+
 func (pt *hashJoinProbeTable) addLeftRow(r sqltypes.Row) error {
-	hash, err := pt.hash(r[pt.lhsKey])
+	hash, err := pt.hash(r, pt.lhsKeys)
 	if err != nil {
 		return err
 	}
@@ -271,29 +535,53 @@ func (pt *hashJoinProbeTable) addLeftRow(r sqltypes.Row) error {
 	return nil
 }
 
-func (pt *hashJoinProbeTable) hash(val sqltypes.Value) (vthash.Hash, error) {
-	err := evalengine.NullsafeHashcode128(&pt.hasher, val, pt.coll, pt.typ, pt.sqlmode, pt.values)
-	if err != nil {
-		return vthash.Hash{}, err
+// hash hashes the values of the keys of a row together
+func (pt *hashJoinProbeTable) hash(row sqltypes.Row, keys []int) (vthash.Hash, error) {
+	for i, key := range keys {
+		typ := pt.types[i]
+		err := evalengine.NullsafeHashcode128(&pt.hasher, row[key], typ.Collation(), typ.Type(), pt.sqlmode, typ.Values())
+		if err != nil {
+			pt.hasher.Init(pt.seed)
+			return vthash.Hash{}, err
+		}
 	}
 
 	res := pt.hasher.Sum128()
-	pt.hasher.Reset()
+	pt.hasher.Init(pt.seed)
 	return res, nil
 }
 
+// hasNullKey returns true if one of the keys of the row is NULL. Such a row
+// can't be equal to any other row.
+func hasNullKey(row sqltypes.Row, keys []int) bool {
+	for _, key := range keys {
+		if row[key].IsNull() {
+			return true
+		}
+	}
+	return false
+}
+
 func (pt *hashJoinProbeTable) get(rrow sqltypes.Row) (result []sqltypes.Row, err error) {
-	val := rrow[pt.rhsKey]
-	if val.IsNull() {
+	if hasNullKey(rrow, pt.rhsKeys) {
 		return
 	}
 
-	hash, err := pt.hash(val)
+	hash, err := pt.hash(rrow, pt.rhsKeys)
 	if err != nil {
 		return nil, err
 	}
 
 	for e := pt.innerMap[hash]; e != nil; e = e.next {
+		if pt.residual != nil {
+			match, err := pt.matchesResidual(e.row, rrow)
+			if err != nil {
+				return nil, err
+			}
+			if !match {
+				continue
+			}
+		}
 		e.seen = true
 		result = append(result, joinRows(e.row, rrow, pt.cols))
 	}
@@ -301,6 +589,16 @@ func (pt *hashJoinProbeTable) get(rrow sqltypes.Row) (result []sqltypes.Row, err
 	return
 }
 
+// matchesResidual evaluates the residual predicate on a pair of rows that matched by hash code
+func (pt *hashJoinProbeTable) matchesResidual(lrow, rrow sqltypes.Row) (bool, error) {
+	pt.env.Row = joinRows(lrow, rrow, pt.residualCols)
+	res, err := pt.env.Evaluate(pt.residual)
+	if err != nil {
+		return false, err
+	}
+	return res.ToBoolean(), nil
+}
+
 func (pt *hashJoinProbeTable) notFetched() (rows []sqltypes.Row) {
 	for _, e := range pt.innerMap {
 		for ; e != nil; e = e.next {
@@ -311,3 +609,94 @@ func (pt *hashJoinProbeTable) notFetched() (rows []sqltypes.Row) {
 	}
 	return
 }
+
+// hashJoinPartitionCount is the number of partitions the inputs of a hash join
+// are split into when the probe table doesn't fit in the spill memory budget.
+const hashJoinPartitionCount = 32
+
+// hashJoinMaxPartitionDepth is the number of times a partition that doesn't fit in
+// the spill memory budget can be split again into smaller partitions.
+const hashJoinMaxPartitionDepth = 3
+
+// errHashJoinPartitionTooLarge is returned while reading an LHS partition that
+// doesn't fit in the spill memory budget.
+var errHashJoinPartitionTooLarge = errors.New("hash join partition too large")
+
+// hashJoinPartitions holds the rows of the inputs of a hash join in temporary
+// files, partitioned by the hash of their join keys. Rows that can match can
+// only be found in the same partition of the LHS and the RHS.
+type hashJoinPartitions struct {
+	// hasher is only used to compute the hash of the join keys
+	hasher      *hashJoinProbeTable
+	dir         string
+	left, right []*spillFile
+}
+
+func newHashJoinPartitions(hasher *hashJoinProbeTable, dir string) *hashJoinPartitions {
+	return &hashJoinPartitions{
+		hasher: hasher,
+		dir:    dir,
+		left:   make([]*spillFile, hashJoinPartitionCount),
+		right:  make([]*spillFile, hashJoinPartitionCount),
+	}
+}
+
+// addProbeTable moves the LHS rows of an in-memory probe table to the partitions.
+func (hp *hashJoinPartitions) addProbeTable(pt *hashJoinProbeTable) error {
+	for _, e := range pt.innerMap {
+		for ; e != nil; e = e.next {
+			if err := hp.addLeftRow(e.row); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+func (hp *hashJoinPartitions) addLeftRow(row sqltypes.Row) error {
+	return hp.add(hp.left, row, hp.hasher.lhsKeys)
+}
+
+func (hp *hashJoinPartitions) addRightRow(row sqltypes.Row) error {
+	if hasNullKey(row, hp.hasher.rhsKeys) {
+		// a NULL key never matches any row of the LHS
+		return nil
+	}
+	return hp.add(hp.right, row, hp.hasher.rhsKeys)
+}
+
+func (hp *hashJoinPartitions) add(files []*spillFile, row sqltypes.Row, keys []int) error {
+	i, err := hp.hasher.partition(row, keys, len(files))
+	if err != nil {
+		return err
+	}
+	if files[i] == nil {
+		files[i], err = newSpillFile(hp.dir)
+		if err != nil {
+			return err
+		}
+	}
+	return files[i].write(row)
+}
+
+// size returns the number of bytes written to the partitions
+func (hp *hashJoinPartitions) size() (size int64) {
+	for _, files := range [][]*spillFile{hp.left, hp.right} {
+		for _, f := range files {
+			if f != nil {
+				size += f.size
+			}
+		}
+	}
+	return
+}
+
+func (hp *hashJoinPartitions) close() {
+	for _, files := range [][]*spillFile{hp.left, hp.right} {
+		for _, f := range files {
+			if f != nil {
+				f.close()
+			}
+		}
+	}
+}
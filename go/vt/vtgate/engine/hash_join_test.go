@@ -25,6 +25,8 @@ import (
 	"vitess.io/vitess/go/mysql/collations"
 	"vitess.io/vitess/go/sqltypes"
 	querypb "vitess.io/vitess/go/vt/proto/query"
+	"vitess.io/vitess/go/vt/sqlparser"
+	"vitess.io/vitess/go/vt/vtenv"
 	"vitess.io/vitess/go/vt/vtgate/evalengine"
 )
 
@@ -136,13 +138,12 @@ func TestHashJoinVariations(t *testing.T) {
 		require.NoError(t, err)
 
 		jn := &HashJoin{
-			Opcode:         tc.typ,
-			Cols:           []int{-1, -2, 1, 2},
-			LHSKey:         tc.lhs,
-			RHSKey:         tc.rhs,
-			Collation:      typ.Collation(),
-			ComparisonType: typ.Type(),
-			CollationEnv:   collations.MySQL8(),
+			Opcode:          tc.typ,
+			Cols:            []int{-1, -2, 1, 2},
+			LHSKeys:         []int{tc.lhs},
+			RHSKeys:         []int{tc.rhs},
+			ComparisonTypes: []evalengine.Type{typ},
+			CollationEnv:    collations.MySQL8(),
 		}
 
 		t.Run(tc.name, func(t *testing.T) {
@@ -162,6 +163,75 @@ func TestHashJoinVariations(t *testing.T) {
 	}
 }
 
+func TestHashJoinCompositeKeysAndResidual(t *testing.T) {
+	// l.a = r.a and l.b = r.b and l.x < r.y
+	lfields := sqltypes.MakeTestFields("a|b|x", "int64|varchar|int64")
+	rfields := sqltypes.MakeTestFields("a|b|y", "int64|varchar|int64")
+	lhs := &fakePrimitive{
+		results: []*sqltypes.Result{
+			sqltypes.MakeTestResult(lfields, "1|a|1", "1|b|5", "2|a|3", "null|a|1"),
+		},
+	}
+	rhs := &fakePrimitive{
+		results: []*sqltypes.Result{
+			sqltypes.MakeTestResult(rfields, "1|a|2", "1|a|0", "1|b|4", "2|A|4", "null|a|9"),
+		},
+	}
+
+	residualFields := sqltypes.MakeTestFields("x|y", "int64|int64")
+	residual, err := evalengine.Translate(&sqlparser.ComparisonExpr{
+		Operator: sqlparser.LessThanOp,
+		Left:     sqlparser.NewColName("x"),
+		Right:    sqlparser.NewColName("y"),
+	}, &evalengine.Config{
+		ResolveColumn: evalengine.FieldResolver(residualFields).Column,
+		Environment:   vtenv.NewTestEnv(),
+	})
+	require.NoError(t, err)
+
+	fields := sqltypes.MakeTestFields("a|b|x|y", "int64|varchar|int64|int64")
+	for _, tc := range []struct {
+		typ      JoinOpcode
+		expected []string
+	}{{
+		typ:      InnerJoin,
+		expected: []string{"1|a|1|2", "2|a|3|4"},
+	}, {
+		// the LHS rows whose matches are all rejected by the residual are still returned
+		typ:      LeftJoin,
+		expected: []string{"1|a|1|2", "2|a|3|4", "1|b|5|null", "null|a|1|null"},
+	}} {
+		jn := &HashJoin{
+			Opcode:          tc.typ,
+			Left:            lhs,
+			Right:           rhs,
+			Cols:            []int{-1, -2, -3, 3},
+			LHSKeys:         []int{0, 1},
+			RHSKeys:         []int{0, 1},
+			ComparisonTypes: []evalengine.Type{typeForOffset(0), typeForOffset(1)},
+			CollationEnv:    collations.MySQL8(),
+			Residual:        residual,
+			ResidualCols:    []int{-3, 3},
+		}
+		expected := sqltypes.MakeTestResult(fields, tc.expected...)
+
+		t.Run(tc.typ.String(), func(t *testing.T) {
+			lhs.rewind()
+			rhs.rewind()
+			r, err := jn.TryExecute(context.Background(), &noopVCursor{}, map[string]*querypb.BindVariable{}, true)
+			require.NoError(t, err)
+			expectResultAnyOrder(t, r, expected)
+		})
+		t.Run("Streaming "+tc.typ.String(), func(t *testing.T) {
+			lhs.rewind()
+			rhs.rewind()
+			r, err := wrapStreamExecute(jn, &noopVCursor{}, map[string]*querypb.BindVariable{}, true)
+			require.NoError(t, err)
+			expectResultAnyOrder(t, r, expected)
+		})
+	}
+}
+
 func typeForOffset(i int) evalengine.Type {
 	switch i {
 	case 0:
@@ -47,9 +47,25 @@ type (
 		// VindexValueOffset stores the offset for each column in the ColumnVindex
 		// that will appear in the result set of the select query.
 		VindexValueOffset [][]int
+
+		// DeleteBeforeInsert, when set, deletes the existing rows that the selected rows clash with
+		// before they are inserted. It is used to run REPLACE INTO ... SELECT as a delete followed by an insert.
+		DeleteBeforeInsert Primitive
+
+		// DeleteKeyOffsets stores, for each key that DeleteBeforeInsert compares against, the offsets of
+		// the key columns in the result set of the select query. The key values of the selected rows are
+		// bound to the tuple bind variable named by DeleteKeyVarName.
+		DeleteKeyOffsets [][]int
 	}
 )
 
+// DeleteKeyVarName returns the name of the tuple bind variable holding the values of the key at the given
+// position for the rows deleted before insert. This method is used by the planner and engine,
+// to make sure they both produce the same names
+func DeleteKeyVarName(keyIdx int) string {
+	return fmt.Sprintf("__replace_key%d", keyIdx)
+}
+
 // newInsertSelect creates a new InsertSelect. Used in testing.
 func newInsertSelect(
 	ignore bool,
@@ -83,7 +99,13 @@ func newInsertSelect(
 }
 
 func (ins *InsertSelect) Inputs() ([]Primitive, []map[string]any) {
-	return []Primitive{ins.Input}, nil
+	if ins.DeleteBeforeInsert == nil {
+		return []Primitive{ins.Input}, nil
+	}
+	return []Primitive{ins.Input, ins.DeleteBeforeInsert}, []map[string]any{
+		{inputName: "Selection"},
+		{inputName: "DeleteBeforeInsert"},
+	}
 }
 
 // TryExecute performs a non-streaming exec.
@@ -153,7 +175,12 @@ func (ins *InsertSelect) execInsertUnsharded(ctx context.Context, vcursor VCurso
 
 func (ins *InsertSelect) insertIntoUnshardedTable(ctx context.Context, vcursor VCursor, bindVars map[string]*querypb.BindVariable, irr insertRowsResult) (*sqltypes.Result, error) {
 	query := ins.getInsertUnshardedQuery(irr.rows, bindVars)
-	return ins.executeUnshardedTableQuery(ctx, vcursor, ins, bindVars, query, irr.insertID)
+	qr, err := ins.executeUnshardedTableQuery(ctx, vcursor, ins, bindVars, query, irr.insertID)
+	if err != nil {
+		return nil, err
+	}
+	qr.RowsAffected += irr.rowsDeleted
+	return qr, nil
 }
 
 func (ins *InsertSelect) getInsertUnshardedQuery(rows []sqltypes.Row, bindVars map[string]*querypb.BindVariable) string {
@@ -186,6 +213,7 @@ func (ins *InsertSelect) insertIntoShardedTable(
 		return nil, err
 	}
 	qr.InsertID = uint64(irr.insertID)
+	qr.RowsAffected += irr.rowsDeleted
 	return qr, nil
 }
 
@@ -327,6 +355,13 @@ func (ins *InsertSelect) description() PrimitiveDescription {
 		}
 		other["VindexOffsetFromSelect"] = valuesOffsets
 	}
+	if len(ins.DeleteKeyOffsets) > 0 {
+		var offsets []string
+		for idx, offset := range ins.DeleteKeyOffsets {
+			offsets = append(offsets, fmt.Sprintf("%s:%v", DeleteKeyVarName(idx), offset))
+		}
+		other["DeleteKeyOffsets"] = offsets
+	}
 
 	return PrimitiveDescription{
 		OperatorType: "Insert",
@@ -360,8 +395,9 @@ func insertVarOffset(rowNum, colOffset int) string {
 }
 
 type insertRowsResult struct {
-	rows     []sqltypes.Row
-	insertID uint64
+	rows        []sqltypes.Row
+	insertID    uint64
+	rowsDeleted uint64
 }
 
 func (ins *InsertSelect) execSelect(
@@ -379,9 +415,15 @@ func (ins *InsertSelect) execSelect(
 		return insertRowsResult{}, err
 	}
 
+	rowsDeleted, err := ins.deleteBeforeInsert(ctx, vcursor, bindVars, res.Rows)
+	if err != nil {
+		return insertRowsResult{}, err
+	}
+
 	return insertRowsResult{
-		rows:     res.Rows,
-		insertID: uint64(insertID),
+		rows:        res.Rows,
+		insertID:    uint64(insertID),
+		rowsDeleted: rowsDeleted,
 	}, nil
 }
 
@@ -408,9 +450,41 @@ func (ins *InsertSelect) execSelectStreaming(
 			return err
 		}
 
+		rowsDeleted, err := ins.deleteBeforeInsert(ctx, vcursor, bindVars, result.Rows)
+		if err != nil {
+			return err
+		}
+
 		return callback(insertRowsResult{
-			rows:     result.Rows,
-			insertID: uint64(insertID),
+			rows:        result.Rows,
+			insertID:    uint64(insertID),
+			rowsDeleted: rowsDeleted,
 		})
 	})
 }
+
+// deleteBeforeInsert deletes the existing rows that the given rows clash with on any of the keys of the table.
+// It returns the number of rows deleted, which MySQL reports as affected by REPLACE along with the inserted rows.
+func (ins *InsertSelect) deleteBeforeInsert(
+	ctx context.Context,
+	vcursor VCursor,
+	bindVars map[string]*querypb.BindVariable,
+	rows []sqltypes.Row,
+) (uint64, error) {
+	if ins.DeleteBeforeInsert == nil {
+		return 0, nil
+	}
+	bvs := sqltypes.CopyBindVariables(bindVars)
+	for idx, offsets := range ins.DeleteKeyOffsets {
+		if len(offsets) == 1 {
+			bvs[DeleteKeyVarName(idx)] = getBVSingle(rows, offsets[0])
+		} else {
+			bvs[DeleteKeyVarName(idx)] = getBVMulti(rows, offsets)
+		}
+	}
+	qr, err := vcursor.ExecutePrimitive(ctx, ins.DeleteBeforeInsert, bvs, false)
+	if err != nil {
+		return 0, err
+	}
+	return qr.RowsAffected, nil
+}
@@ -1739,6 +1739,77 @@ func TestInsertSelectSimple(t *testing.T) {
 			` {_c1_0: type:VARCHAR value:"a" _c1_1: type:INT64 value:"3"} true false`})
 }
 
+func TestInsertSelectDeleteBeforeInsert(t *testing.T) {
+	invschema := &vschemapb.SrvVSchema{
+		Keyspaces: map[string]*vschemapb.Keyspace{
+			"sharded": {
+				Sharded: true,
+				Vindexes: map[string]*vschemapb.Vindex{
+					"hash": {Type: "hash"}},
+				Tables: map[string]*vschemapb.Table{
+					"t1": {
+						ColumnVindexes: []*vschemapb.ColumnVindex{{
+							Name:    "hash",
+							Columns: []string{"id"}}}}}}}}
+
+	vs := vindexes.BuildVSchema(invschema, sqlparser.NewTestParser())
+	ks := vs.Keyspaces["sharded"]
+
+	rb := &Route{
+		Query:      "dummy_select",
+		FieldQuery: "dummy_field_query",
+		RoutingParameters: &RoutingParameters{
+			Opcode:   Scatter,
+			Keyspace: ks.Keyspace}}
+	ins := newInsertSelect(false, ks.Keyspace, ks.Tables["t1"], "prefix ", nil, [][]int{{1}}, rb)
+	ins.DeleteBeforeInsert = &Delete{
+		DML: &DML{
+			RoutingParameters: &RoutingParameters{
+				Opcode:   Scatter,
+				Keyspace: ks.Keyspace,
+			},
+			Query:             "dummy_delete",
+			PreventAutoCommit: true,
+		},
+	}
+	// the rows are deleted by their id, and by their name and id together.
+	ins.DeleteKeyOffsets = [][]int{{1}, {0, 1}}
+
+	vc := newTestVCursor("-20", "20-")
+	vc.shardForKsid = []string{"20-", "-20"}
+	vc.results = []*sqltypes.Result{
+		sqltypes.MakeTestResult(
+			sqltypes.MakeTestFields(
+				"name|id",
+				"varchar|int64"),
+			"a|1",
+			"b|3"),
+		{RowsAffected: 1},
+		{RowsAffected: 2},
+	}
+
+	qr, err := ins.TryExecute(context.Background(), vc, map[string]*querypb.BindVariable{}, false)
+	require.NoError(t, err)
+	vc.ExpectLog(t, []string{
+		`ResolveDestinations sharded [] Destinations:DestinationAllShards()`,
+		`ExecuteMultiShard sharded.-20: dummy_select {} sharded.20-: dummy_select {} false false`,
+
+		// the rows clashing with the selected rows are deleted first
+		`ResolveDestinations sharded [] Destinations:DestinationAllShards()`,
+		`ExecuteMultiShard ` +
+			`sharded.-20: dummy_delete {__replace_key0: type:TUPLE values:{type:INT64 value:"1"} values:{type:INT64 value:"3"} ` +
+			`__replace_key1: type:TUPLE values:{type:TUPLE value:"\x950\x01a\x89\x02\x011"} values:{type:TUPLE value:"\x950\x01b\x89\x02\x013"}} ` +
+			`sharded.20-: dummy_delete {__replace_key0: type:TUPLE values:{type:INT64 value:"1"} values:{type:INT64 value:"3"} ` +
+			`__replace_key1: type:TUPLE values:{type:TUPLE value:"\x950\x01a\x89\x02\x011"} values:{type:TUPLE value:"\x950\x01b\x89\x02\x013"}} true false`,
+
+		`ResolveDestinations sharded [value:"0" value:"1"] Destinations:DestinationKeyspaceID(166b40b44aba4bd6),DestinationKeyspaceID(4eb190c9a2fa169c)`,
+		`ExecuteMultiShard ` +
+			`sharded.20-: prefix values (:_c0_0, :_c0_1) {_c0_0: type:VARCHAR value:"a" _c0_1: type:INT64 value:"1"} ` +
+			`sharded.-20: prefix values (:_c1_0, :_c1_1) {_c1_0: type:VARCHAR value:"b" _c1_1: type:INT64 value:"3"} true false`})
+	// REPLACE reports both the deleted and the inserted rows as affected.
+	require.EqualValues(t, 3, qr.RowsAffected)
+}
+
 func TestInsertSelectOwned(t *testing.T) {
 	invschema := &vschemapb.SrvVSchema{
 		Keyspaces: map[string]*vschemapb.Keyspace{
@@ -0,0 +1,99 @@
+/*
+Copyright 2025 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package engine
+
+import (
+	"context"
+
+	"vitess.io/vitess/go/sqltypes"
+	querypb "vitess.io/vitess/go/vt/proto/query"
+	"vitess.io/vitess/go/vt/sqlparser"
+	"vitess.io/vitess/go/vt/vtgate/evalengine"
+)
+
+var _ Primitive = (*JSONTable)(nil)
+
+// JSONTable generates the rows of a JSON_TABLE expression in vtgate.
+// It is used when the JSON document does not come from a single route, typically
+// on the RHS of a join that sends the document of every row of its LHS as a bind variable.
+type JSONTable struct {
+	noInputs
+	noTxNeeded
+
+	Table *evalengine.JSONTable
+	// Cols are the offsets of the columns of the JSON_TABLE returned by this primitive
+	Cols []int
+
+	// Doc is the JSON document of the JSON_TABLE expression, used to describe the primitive
+	Doc sqlparser.Expr
+}
+
+// TryExecute implements the Primitive interface
+func (jt *JSONTable) TryExecute(ctx context.Context, vcursor VCursor, bindVars map[string]*querypb.BindVariable, _ bool) (*sqltypes.Result, error) {
+	env := evalengine.NewExpressionEnv(ctx, bindVars, vcursor)
+	rows, err := jt.Table.Evaluate(env)
+	if err != nil {
+		return nil, err
+	}
+	result := &sqltypes.Result{Fields: jt.fields()}
+	for _, row := range rows {
+		out := make(sqltypes.Row, 0, len(jt.Cols))
+		for _, col := range jt.Cols {
+			out = append(out, row[col])
+		}
+		result.Rows = append(result.Rows, out)
+	}
+	return result, nil
+}
+
+// TryStreamExecute implements the Primitive interface
+func (jt *JSONTable) TryStreamExecute(ctx context.Context, vcursor VCursor, bindVars map[string]*querypb.BindVariable, wantfields bool, callback func(*sqltypes.Result) error) error {
+	result, err := jt.TryExecute(ctx, vcursor, bindVars, wantfields)
+	if err != nil {
+		return err
+	}
+	return callback(result)
+}
+
+// GetFields implements the Primitive interface
+func (jt *JSONTable) GetFields(context.Context, VCursor, map[string]*querypb.BindVariable) (*sqltypes.Result, error) {
+	return &sqltypes.Result{Fields: jt.fields()}, nil
+}
+
+func (jt *JSONTable) fields() []*querypb.Field {
+	all := jt.Table.Fields()
+	fields := make([]*querypb.Field, 0, len(jt.Cols))
+	for _, col := range jt.Cols {
+		fields = append(fields, all[col])
+	}
+	return fields
+}
+
+func (jt *JSONTable) description() PrimitiveDescription {
+	cols := make([]string, 0, len(jt.Cols))
+	for _, field := range jt.fields() {
+		cols = append(cols, field.Name)
+	}
+	return PrimitiveDescription{
+		OperatorType: "JSONTable",
+		Other: map[string]any{
+			"Alias":    jt.Table.Alias,
+			"Document": sqlparser.String(jt.Doc),
+			"Columns":  cols,
+		},
+	}
+}
@@ -0,0 +1,78 @@
+/*
+Copyright 2025 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package engine
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"vitess.io/vitess/go/mysql/collations"
+	"vitess.io/vitess/go/sqltypes"
+	querypb "vitess.io/vitess/go/vt/proto/query"
+	"vitess.io/vitess/go/vt/sqlparser"
+	"vitess.io/vitess/go/vt/vtenv"
+	"vitess.io/vitess/go/vt/vtgate/evalengine"
+)
+
+func TestJSONTable(t *testing.T) {
+	venv := vtenv.NewTestEnv()
+	stmt, err := venv.Parser().Parse("select * from json_table(:u_col, '$[*]' columns(id for ordinality, a int path '$.a', b varchar(10) path '$.b')) as jt")
+	require.NoError(t, err)
+	node := stmt.(*sqlparser.Select).From[0].(*sqlparser.JSONTableExpr)
+	table, err := evalengine.TranslateJSONTable(node, &evalengine.Config{
+		Collation:   venv.CollationEnv().DefaultConnectionCharset(),
+		Environment: venv,
+	})
+	require.NoError(t, err)
+
+	jt := &JSONTable{
+		Table: table,
+		Cols:  []int{2, 1},
+		Doc:   node.Expr,
+	}
+	bv := map[string]*querypb.BindVariable{
+		"u_col": sqltypes.StringBindVariable(`[{"a": 1, "b": "x"}, {"a": 2}]`),
+	}
+	want := sqltypes.MakeTestResult(
+		sqltypes.MakeTestFields("b|a", "varchar|int32"),
+		"x|1",
+		"null|2",
+	)
+	want.Fields[0].ColumnLength = 10
+	want.Fields[0].Charset = uint32(collations.MySQL8().DefaultConnectionCharset())
+	want.Fields[1].Charset = collations.CollationBinaryID
+
+	qr, err := jt.TryExecute(context.Background(), &noopVCursor{}, bv, true)
+	require.NoError(t, err)
+	expectResult(t, qr, want)
+
+	qr, err = wrapStreamExecute(jt, &noopVCursor{}, bv, true)
+	require.NoError(t, err)
+	expectResult(t, qr, want)
+
+	qr, err = jt.GetFields(context.Background(), &noopVCursor{}, bv)
+	require.NoError(t, err)
+	expectResult(t, qr, &sqltypes.Result{Fields: want.Fields})
+
+	// a NULL document produces no rows
+	bv["u_col"] = sqltypes.NullBindVariable
+	qr, err = jt.TryExecute(context.Background(), &noopVCursor{}, bv, true)
+	require.NoError(t, err)
+	expectResult(t, qr, &sqltypes.Result{Fields: want.Fields})
+}
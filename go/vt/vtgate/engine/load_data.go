@@ -0,0 +1,297 @@
+/*
+Copyright 2025 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package engine
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+
+	"vitess.io/vitess/go/sqltypes"
+	querypb "vitess.io/vitess/go/vt/proto/query"
+	"vitess.io/vitess/go/vt/sqlparser"
+	"vitess.io/vitess/go/vt/vterrors"
+)
+
+var _ Primitive = (*LoadData)(nil)
+
+// loadDataBatchSize is the number of rows LoadData hands to its caller at a time when streaming
+const loadDataBatchSize = 1000
+
+// LoadData reads the file of a LOAD DATA LOCAL INFILE statement from the client,
+// and returns the rows in it. All values are returned as VARCHAR, or NULL.
+// It is used as the input of an InsertSelect, that routes the rows to the right shards.
+type LoadData struct {
+	noInputs
+	noTxNeeded
+
+	FileName string
+	// Columns are the names of the columns in each row of the file
+	Columns []string
+
+	FieldsTerminatedBy string
+	// FieldsEnclosedBy and FieldsEscapedBy are either empty or a single character
+	FieldsEnclosedBy  string
+	FieldsEscapedBy   string
+	LinesStartingBy   string
+	LinesTerminatedBy string
+	IgnoreLines       int
+}
+
+// TryExecute implements the Primitive interface
+func (ld *LoadData) TryExecute(ctx context.Context, vcursor VCursor, _ map[string]*querypb.BindVariable, _ bool) (*sqltypes.Result, error) {
+	result := &sqltypes.Result{Fields: ld.fields()}
+	err := ld.readRows(ctx, vcursor, func(row sqltypes.Row) error {
+		result.Rows = append(result.Rows, row)
+		if vcursor.ExceedsMaxMemoryRows(len(result.Rows)) {
+			return fmt.Errorf("in-memory row count exceeded allowed limit of %d", vcursor.MaxMemoryRows())
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+// TryStreamExecute implements the Primitive interface
+func (ld *LoadData) TryStreamExecute(ctx context.Context, vcursor VCursor, _ map[string]*querypb.BindVariable, wantfields bool, callback func(*sqltypes.Result) error) error {
+	if wantfields {
+		if err := callback(&sqltypes.Result{Fields: ld.fields()}); err != nil {
+			return err
+		}
+	}
+	var rows []sqltypes.Row
+	err := ld.readRows(ctx, vcursor, func(row sqltypes.Row) error {
+		rows = append(rows, row)
+		if len(rows) < loadDataBatchSize {
+			return nil
+		}
+		err := callback(&sqltypes.Result{Rows: rows})
+		rows = nil
+		return err
+	})
+	if err != nil {
+		return err
+	}
+	if len(rows) == 0 {
+		return nil
+	}
+	return callback(&sqltypes.Result{Rows: rows})
+}
+
+// GetFields implements the Primitive interface
+func (ld *LoadData) GetFields(context.Context, VCursor, map[string]*querypb.BindVariable) (*sqltypes.Result, error) {
+	return &sqltypes.Result{Fields: ld.fields()}, nil
+}
+
+func (ld *LoadData) fields() []*querypb.Field {
+	fields := make([]*querypb.Field, 0, len(ld.Columns))
+	for _, col := range ld.Columns {
+		fields = append(fields, &querypb.Field{Name: col, Type: sqltypes.VarChar})
+	}
+	return fields
+}
+
+// readRows reads the file from the client, and calls the callback for every row in it.
+// The file arrives in chunks that don't follow the line boundaries,
+// so the incomplete row at the end of a chunk is kept until the next chunk arrives.
+func (ld *LoadData) readRows(ctx context.Context, vcursor VCursor, callback func(row sqltypes.Row) error) error {
+	var buf []byte
+	lines := 0
+	parse := func(atEOF bool) error {
+		for len(buf) > 0 {
+			row, n, ok := ld.parseRow(buf, atEOF)
+			if !ok {
+				break
+			}
+			buf = buf[n:]
+			lines++
+			if lines <= ld.IgnoreLines {
+				continue
+			}
+			if len(row) != len(ld.Columns) {
+				return vterrors.VT03006()
+			}
+			if err := callback(row); err != nil {
+				return err
+			}
+		}
+		if atEOF {
+			buf = nil
+		}
+		return nil
+	}
+
+	err := vcursor.ReadLocalInfile(ctx, ld.FileName, func(data []byte) error {
+		buf = append(buf, data...)
+		return parse(false)
+	})
+	if err != nil {
+		return err
+	}
+	return parse(true)
+}
+
+// parseRow parses the row at the start of data, and returns its values and the number of bytes used.
+// It returns false when data does not hold a complete row yet, or, at the end of the file, no row at all.
+func (ld *LoadData) parseRow(data []byte, atEOF bool) (sqltypes.Row, int, bool) {
+	pos := 0
+	if ld.LinesStartingBy != "" {
+		// everything up to the line prefix is skipped, including the lines without the prefix
+		idx := bytes.Index(data, []byte(ld.LinesStartingBy))
+		if idx < 0 {
+			return nil, 0, false
+		}
+		pos = idx + len(ld.LinesStartingBy)
+	}
+
+	var row sqltypes.Row
+	for {
+		value, next, ok := ld.parseField(data, pos, atEOF)
+		if !ok {
+			return nil, 0, false
+		}
+		row = append(row, value)
+		pos = next
+		switch {
+		case bytes.HasPrefix(data[pos:], []byte(ld.FieldsTerminatedBy)):
+			pos += len(ld.FieldsTerminatedBy)
+		case bytes.HasPrefix(data[pos:], []byte(ld.LinesTerminatedBy)):
+			return row, pos + len(ld.LinesTerminatedBy), true
+		default:
+			// the last line of the file does not need a terminator
+			return row, pos, true
+		}
+	}
+}
+
+// parseField parses the field starting at pos, and returns its value and the position of the terminator after it.
+func (ld *LoadData) parseField(data []byte, pos int, atEOF bool) (sqltypes.Value, int, bool) {
+	// we only look at the data when we can see a terminator after the current byte
+	lookahead := 2 + max(len(ld.FieldsTerminatedBy), len(ld.LinesTerminatedBy))
+	start := pos
+	quoted := ld.FieldsEnclosedBy != "" && pos < len(data) && data[pos] == ld.FieldsEnclosedBy[0]
+	if quoted {
+		pos++
+	}
+
+	var out []byte
+	for {
+		if !atEOF && len(data)-pos < lookahead {
+			return sqltypes.Value{}, 0, false
+		}
+		if pos >= len(data) {
+			break
+		}
+		c := data[pos]
+		if ld.FieldsEscapedBy != "" && c == ld.FieldsEscapedBy[0] {
+			if pos+1 < len(data) {
+				out = append(out, unescapeLoadData(data[pos+1]))
+				pos += 2
+			} else {
+				out = append(out, c)
+				pos++
+			}
+			continue
+		}
+		if quoted {
+			if c == ld.FieldsEnclosedBy[0] {
+				if pos+1 < len(data) && data[pos+1] == c {
+					// a doubled enclosing character stands for the character itself
+					out = append(out, c)
+					pos += 2
+					continue
+				}
+				if ld.terminatorAt(data, pos+1) {
+					pos++
+					break
+				}
+			}
+			out = append(out, c)
+			pos++
+			continue
+		}
+		if ld.terminatorAt(data, pos) {
+			break
+		}
+		out = append(out, c)
+		pos++
+	}
+
+	if !quoted {
+		raw := string(data[start:pos])
+		if (ld.FieldsEscapedBy != "" && raw == ld.FieldsEscapedBy+"N") || (ld.FieldsEscapedBy == "" && raw == "NULL") {
+			return sqltypes.NULL, pos, true
+		}
+	}
+	return sqltypes.MakeTrusted(sqltypes.VarChar, out), pos, true
+}
+
+func (ld *LoadData) terminatorAt(data []byte, pos int) bool {
+	return pos == len(data) ||
+		bytes.HasPrefix(data[pos:], []byte(ld.FieldsTerminatedBy)) ||
+		bytes.HasPrefix(data[pos:], []byte(ld.LinesTerminatedBy))
+}
+
+// unescapeLoadData returns the character an escape sequence in a LOAD DATA file stands for
+func unescapeLoadData(c byte) byte {
+	switch c {
+	case '0':
+		return 0
+	case 'b':
+		return '\b'
+	case 'n':
+		return '\n'
+	case 'r':
+		return '\r'
+	case 't':
+		return '\t'
+	case 'Z':
+		return 26
+	}
+	return c
+}
+
+func (ld *LoadData) description() PrimitiveDescription {
+	other := map[string]any{
+		"FileName": ld.FileName,
+		"Columns":  ld.Columns,
+	}
+	if ld.FieldsTerminatedBy != sqlparser.LoadFieldsTerminatedByDefault {
+		other["FieldsTerminatedBy"] = ld.FieldsTerminatedBy
+	}
+	if ld.FieldsEnclosedBy != "" {
+		other["FieldsEnclosedBy"] = ld.FieldsEnclosedBy
+	}
+	if ld.FieldsEscapedBy != sqlparser.LoadFieldsEscapedByDefault {
+		other["FieldsEscapedBy"] = ld.FieldsEscapedBy
+	}
+	if ld.LinesStartingBy != "" {
+		other["LinesStartingBy"] = ld.LinesStartingBy
+	}
+	if ld.LinesTerminatedBy != sqlparser.LoadLinesTerminatedByDefault {
+		other["LinesTerminatedBy"] = ld.LinesTerminatedBy
+	}
+	if ld.IgnoreLines > 0 {
+		other["IgnoreLines"] = ld.IgnoreLines
+	}
+	return PrimitiveDescription{
+		OperatorType: "LoadData",
+		Other:        other,
+	}
+}
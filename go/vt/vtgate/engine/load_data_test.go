@@ -0,0 +1,108 @@
+/*
+Copyright 2025 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package engine
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"vitess.io/vitess/go/sqltypes"
+	"vitess.io/vitess/go/vt/sqlparser"
+)
+
+func newTestLoadData(columns ...string) *LoadData {
+	return &LoadData{
+		FileName:           "data.txt",
+		Columns:            columns,
+		FieldsTerminatedBy: sqlparser.LoadFieldsTerminatedByDefault,
+		FieldsEscapedBy:    sqlparser.LoadFieldsEscapedByDefault,
+		LinesTerminatedBy:  sqlparser.LoadLinesTerminatedByDefault,
+	}
+}
+
+func TestLoadDataExecute(t *testing.T) {
+	ld := newTestLoadData("id", "name")
+	vc := &loggingVCursor{
+		// the chunks sent by the client don't follow the line boundaries
+		localInfile: []string{"1\ta\n2", "\tb\\tc\n3\t\\N", "\n4\t\n"},
+	}
+
+	result, err := ld.TryExecute(context.Background(), vc, nil, true)
+	require.NoError(t, err)
+	vc.ExpectLog(t, []string{"ReadLocalInfile data.txt"})
+	expectResult(t, result, sqltypes.MakeTestResult(
+		sqltypes.MakeTestFields("id|name", "varchar|varchar"),
+		"1|a",
+		"2|b\tc",
+		"3|null",
+		"4|",
+	))
+}
+
+func TestLoadDataOptions(t *testing.T) {
+	ld := newTestLoadData("id", "name")
+	ld.FieldsTerminatedBy = ","
+	ld.FieldsEnclosedBy = `"`
+	ld.LinesStartingBy = "xxx"
+	ld.LinesTerminatedBy = "\r\n"
+	ld.IgnoreLines = 1
+	vc := &loggingVCursor{
+		localInfile: []string{
+			"xxxid,name\r\n",
+			"xxx1,\"a,b\"\r\n",
+			"skipped\r\nxxx2,\"say \"\"hi\"\"\"\r\n",
+			"xxx3,\"NULL\"",
+		},
+	}
+
+	result, err := ld.TryExecute(context.Background(), vc, nil, true)
+	require.NoError(t, err)
+	require.Len(t, result.Rows, 3)
+	assert.Equal(t, "a,b", result.Rows[0][1].ToString())
+	assert.Equal(t, `say "hi"`, result.Rows[1][1].ToString())
+	// an enclosed NULL is a string, not a NULL value
+	assert.False(t, result.Rows[2][1].IsNull())
+	assert.Equal(t, "NULL", result.Rows[2][1].ToString())
+}
+
+func TestLoadDataStreamExecute(t *testing.T) {
+	ld := newTestLoadData("id")
+	vc := &loggingVCursor{localInfile: []string{"1\n2\n", "3"}}
+
+	var results []*sqltypes.Result
+	err := ld.TryStreamExecute(context.Background(), vc, nil, true, func(qr *sqltypes.Result) error {
+		results = append(results, qr)
+		return nil
+	})
+	require.NoError(t, err)
+	require.Len(t, results, 2)
+	assert.Equal(t, "id", results[0].Fields[0].Name)
+	want := sqltypes.MakeTestResult(sqltypes.MakeTestFields("id", "varchar"), "1", "2", "3")
+	want.Fields = nil
+	expectResult(t, results[1], want)
+}
+
+func TestLoadDataColumnCountMismatch(t *testing.T) {
+	ld := newTestLoadData("id", "name")
+	vc := &loggingVCursor{localInfile: []string{"1\ta\n2\n"}}
+
+	_, err := ld.TryExecute(context.Background(), vc, nil, true)
+	require.ErrorContains(t, err, "VT03006: column count does not match value count with the row")
+}
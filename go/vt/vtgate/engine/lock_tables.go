@@ -0,0 +1,119 @@
+/*
+Copyright 2025 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package engine
+
+import (
+	"context"
+
+	"vitess.io/vitess/go/sqltypes"
+	"vitess.io/vitess/go/vt/key"
+	querypb "vitess.io/vitess/go/vt/proto/query"
+	"vitess.io/vitess/go/vt/srvtopo"
+	"vitess.io/vitess/go/vt/vterrors"
+	"vitess.io/vitess/go/vt/vtgate/vindexes"
+)
+
+var _ Primitive = (*LockTables)(nil)
+
+// LockTables primitive takes the table locks of a LOCK TABLES statement.
+// A connection is reserved on every shard that holds one of the tables, and the locks
+// are taken on it. The session keeps the reserved connections until UNLOCK TABLES is executed,
+// or the session is closed.
+type LockTables struct {
+	noTxNeeded
+	noInputs
+
+	// TargetDestination specifies the shards of every keyspace to send the queries to.
+	TargetDestination key.ShardDestination
+
+	// Queries holds the LOCK TABLES statement to send to every keyspace.
+	// All the tables of a keyspace are locked by the same statement,
+	// because every LOCK TABLES releases the table locks already held by the connection.
+	Queries []*LockTablesQuery
+}
+
+// LockTablesQuery is the LOCK TABLES statement for the tables of a single keyspace
+type LockTablesQuery struct {
+	Keyspace *vindexes.Keyspace
+	Query    string
+}
+
+// TryExecute implements the Primitive interface
+func (l *LockTables) TryExecute(ctx context.Context, vcursor VCursor, bindVars map[string]*querypb.BindVariable, wantfields bool) (*sqltypes.Result, error) {
+	// same as MySQL, LOCK TABLES commits the open transaction
+	if err := vcursor.Session().Commit(ctx); err != nil {
+		return nil, err
+	}
+	if vcursor.Session().InLockTables() {
+		// the new table locks replace the ones held by the session
+		if _, err := execUnlockTables(ctx, vcursor, l); err != nil {
+			return nil, err
+		}
+	}
+
+	var rss []*srvtopo.ResolvedShard
+	var bqs []*querypb.BoundQuery
+	for _, q := range l.Queries {
+		shards, _, err := vcursor.ResolveDestinations(ctx, q.Keyspace.Name, nil, []key.ShardDestination{l.TargetDestination})
+		if err != nil {
+			return nil, err
+		}
+		for _, rs := range shards {
+			rss = append(rss, rs)
+			bqs = append(bqs, &querypb.BoundQuery{Sql: q.Query})
+		}
+	}
+
+	vcursor.Session().SetInLockTables()
+	qr, errs := vcursor.ExecuteMultiShard(ctx, l, rss, bqs, false, false, false)
+	if err := vterrors.Aggregate(errs); err != nil {
+		// MySQL does not hold any table lock after a failed LOCK TABLES
+		_, _ = execUnlockTables(ctx, vcursor, l)
+		_ = vcursor.ReleaseLockTables(ctx)
+		return nil, err
+	}
+	return qr, nil
+}
+
+// TryStreamExecute implements the Primitive interface
+func (l *LockTables) TryStreamExecute(ctx context.Context, vcursor VCursor, bindVars map[string]*querypb.BindVariable, wantfields bool, callback func(*sqltypes.Result) error) error {
+	qr, err := l.TryExecute(ctx, vcursor, bindVars, wantfields)
+	if err != nil {
+		return err
+	}
+	return callback(qr)
+}
+
+// GetFields implements the Primitive interface
+func (l *LockTables) GetFields(ctx context.Context, vcursor VCursor, bindVars map[string]*querypb.BindVariable) (*sqltypes.Result, error) {
+	return nil, vterrors.VT13001("GetFields should not be called for lock tables")
+}
+
+func (l *LockTables) description() PrimitiveDescription {
+	queries := map[string]string{}
+	for _, q := range l.Queries {
+		queries[q.Keyspace.Name] = q.Query
+	}
+	other := map[string]any{
+		"Queries": queries,
+	}
+	return PrimitiveDescription{
+		OperatorType:      "LockTables",
+		TargetDestination: l.TargetDestination,
+		Other:             other,
+	}
+}
@@ -0,0 +1,141 @@
+/*
+Copyright 2025 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package engine
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"vitess.io/vitess/go/sqltypes"
+	"vitess.io/vitess/go/vt/key"
+	querypb "vitess.io/vitess/go/vt/proto/query"
+	"vitess.io/vitess/go/vt/srvtopo"
+	"vitess.io/vitess/go/vt/vtgate/vindexes"
+)
+
+func TestLockTablesExecute(t *testing.T) {
+	lt := &LockTables{
+		TargetDestination: key.DestinationAllShards{},
+		Queries: []*LockTablesQuery{{
+			Keyspace: &vindexes.Keyspace{Name: "ks", Sharded: true},
+			Query:    "lock tables t1 read, t2 as a write",
+		}, {
+			Keyspace: &vindexes.Keyspace{Name: "uks"},
+			Query:    "lock tables t3 read",
+		}},
+	}
+	vc := &loggingVCursor{
+		ksShardMap: map[string][]string{
+			"ks":  {"-20", "20-"},
+			"uks": {"0"},
+		},
+		results: []*sqltypes.Result{{}},
+	}
+
+	_, err := lt.TryExecute(context.Background(), vc, map[string]*querypb.BindVariable{}, false)
+	require.NoError(t, err)
+	vc.ExpectLog(t, []string{
+		"commit",
+		"ResolveDestinations ks [] Destinations:DestinationAllShards()",
+		"ResolveDestinations uks [] Destinations:DestinationAllShards()",
+		"In Lock Tables",
+		"ExecuteMultiShard ks.-20: lock tables t1 read, t2 as a write {} ks.20-: lock tables t1 read, t2 as a write {} uks.0: lock tables t3 read {} false false",
+	})
+	assert.True(t, vc.InLockTables())
+	assert.True(t, vc.InReservedConn())
+}
+
+func TestLockTablesReplacesLocks(t *testing.T) {
+	lt := &LockTables{
+		TargetDestination: key.DestinationAllShards{},
+		Queries: []*LockTablesQuery{{
+			Keyspace: &vindexes.Keyspace{Name: "ks", Sharded: true},
+			Query:    "lock tables t1 read, t2 as a write",
+		}, {
+			Keyspace: &vindexes.Keyspace{Name: "uks"},
+			Query:    "lock tables t3 read",
+		}},
+	}
+	vc := &loggingVCursor{
+		ksShardMap: map[string][]string{
+			"ks":  {"-20", "20-"},
+			"uks": {"0"},
+		},
+		shardSession: []*srvtopo.ResolvedShard{{Target: &querypb.Target{Keyspace: "ks", Shard: "-20"}}},
+		inLockTables: true,
+		results:      []*sqltypes.Result{{}, {}},
+	}
+
+	_, err := lt.TryExecute(context.Background(), vc, map[string]*querypb.BindVariable{}, false)
+	require.NoError(t, err)
+	vc.ExpectLog(t, []string{
+		"commit",
+		"ExecuteMultiShard ks.-20: unlock tables {} true false",
+		"ResolveDestinations ks [] Destinations:DestinationAllShards()",
+		"ResolveDestinations uks [] Destinations:DestinationAllShards()",
+		"In Lock Tables",
+		"ExecuteMultiShard ks.-20: lock tables t1 read, t2 as a write {} ks.20-: lock tables t1 read, t2 as a write {} uks.0: lock tables t3 read {} false false",
+	})
+}
+
+func TestLockTablesError(t *testing.T) {
+	lt := &LockTables{
+		TargetDestination: key.DestinationAllShards{},
+		Queries: []*LockTablesQuery{{
+			Keyspace: &vindexes.Keyspace{Name: "ks", Sharded: true},
+			Query:    "lock tables t1 read, t2 as a write",
+		}, {
+			Keyspace: &vindexes.Keyspace{Name: "uks"},
+			Query:    "lock tables t3 read",
+		}},
+	}
+	vc := &loggingVCursor{
+		ksShardMap: map[string][]string{
+			"ks":  {"-20", "20-"},
+			"uks": {"0"},
+		},
+		resultErr: errors.New("lock wait timeout"),
+	}
+
+	_, err := lt.TryExecute(context.Background(), vc, map[string]*querypb.BindVariable{}, false)
+	require.EqualError(t, err, "lock wait timeout")
+	assert.False(t, vc.InLockTables())
+}
+
+func TestUnlockTablesExecute(t *testing.T) {
+	vc := &loggingVCursor{
+		shardSession: []*srvtopo.ResolvedShard{
+			{Target: &querypb.Target{Keyspace: "ks", Shard: "-20"}},
+			{Target: &querypb.Target{Keyspace: "ks", Shard: "20-"}},
+		},
+		inLockTables: true,
+		results:      []*sqltypes.Result{{}},
+	}
+
+	_, err := (&Unlock{}).TryExecute(context.Background(), vc, map[string]*querypb.BindVariable{}, false)
+	require.NoError(t, err)
+	vc.ExpectLog(t, []string{
+		"commit",
+		"ExecuteMultiShard ks.-20: unlock tables {} ks.20-: unlock tables {} true false",
+		"Release Lock Tables",
+	})
+	assert.False(t, vc.InLockTables())
+}
@@ -33,6 +33,8 @@ import (
 var _ Primitive = (*MemorySort)(nil)
 
 // MemorySort is a primitive that performs in-memory sorting.
+// When the query has a spill memory budget, the rows that exceed it are
+// sorted in runs that are written to disk, and merged at the end.
 type MemorySort struct {
 	UpperLimit evalengine.Expr
 	OrderBy    evalengine.Comparison
@@ -46,6 +48,13 @@ type MemorySort struct {
 
 // TryExecute satisfies the Primitive interface.
 func (ms *MemorySort) TryExecute(ctx context.Context, vcursor VCursor, bindVars map[string]*querypb.BindVariable, wantfields bool) (*sqltypes.Result, error) {
+	if vcursor.SpillBudget() != nil {
+		// stream the input, so that the rows can be spilled to disk while sorting them
+		return collectStream(func(callback func(*sqltypes.Result) error) error {
+			return ms.TryStreamExecute(ctx, vcursor, bindVars, wantfields, callback)
+		})
+	}
+
 	count, err := ms.fetchCount(ctx, vcursor, bindVars)
 	if err != nil {
 		return nil, err
@@ -78,10 +87,9 @@ func (ms *MemorySort) TryStreamExecute(ctx context.Context, vcursor VCursor, bin
 		return callback(qr.Truncate(ms.TruncateColumnCount))
 	}
 
-	sorter := &evalengine.Sorter{
-		Compare: ms.OrderBy,
-		Limit:   count,
-	}
+	budget := vcursor.SpillBudget()
+	sorter := newSpillSorter(ms.OrderBy, count, budget)
+	defer sorter.close()
 
 	var mu sync.Mutex
 	err = vcursor.StreamExecutePrimitive(ctx, ms.Input, bindVars, wantfields, func(qr *sqltypes.Result) error {
@@ -93,17 +101,22 @@ func (ms *MemorySort) TryStreamExecute(ctx context.Context, vcursor VCursor, bin
 			}
 		}
 		for _, row := range qr.Rows {
-			sorter.Push(row)
+			if err := sorter.push(row); err != nil {
+				return err
+			}
 		}
 		if vcursor.ExceedsMaxMemoryRows(sorter.Len()) {
-			return fmt.Errorf("in-memory row count exceeded allowed limit of %d", vcursor.MaxMemoryRows())
+			if budget == nil {
+				return fmt.Errorf("in-memory row count exceeded allowed limit of %d", vcursor.MaxMemoryRows())
+			}
+			return sorter.spill()
 		}
 		return nil
 	})
 	if err != nil {
 		return err
 	}
-	return cb(&sqltypes.Result{Rows: sorter.Sorted()})
+	return sorter.emit(cb)
 }
 
 // GetFields satisfies the Primitive interface.
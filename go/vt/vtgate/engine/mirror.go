@@ -18,10 +18,17 @@ package engine
 
 import (
 	"context"
+	"encoding/binary"
+	"fmt"
 	"math/rand/v2"
+	"slices"
+	"sync"
 	"time"
 
+	"github.com/cespare/xxhash/v2"
+
 	"vitess.io/vitess/go/sqltypes"
+	"vitess.io/vitess/go/stats"
 	querypb "vitess.io/vitess/go/vt/proto/query"
 	"vitess.io/vitess/go/vt/proto/vtrpc"
 	"vitess.io/vitess/go/vt/vterrors"
@@ -29,6 +36,19 @@ import (
 
 var errMirrorTargetQueryTookTooLong = vterrors.Errorf(vtrpc.Code_ABORTED, "Mirror target query took too long")
 
+var mirrorResultComparisons = stats.NewCountersWithSingleLabel(
+	"MirrorResultComparisons",
+	"Number of mirror target results compared with those of the source, by outcome",
+	"Outcome")
+
+// Outcomes of comparing the results of a mirror target with those of the source.
+const (
+	mirrorResultMatch             = "Match"
+	mirrorResultRowCountMismatch  = "RowCountMismatch"
+	mirrorResultFieldTypeMismatch = "FieldTypeMismatch"
+	mirrorResultRowMismatch       = "RowMismatch"
+)
+
 type (
 	// percentBasedMirror represents the instructions to execute an
 	// authoritative primitive and, based on whether a die-roll exceeds a
@@ -37,11 +57,27 @@ type (
 		percent   float32
 		primitive Primitive
 		target    Primitive
+
+		// compare is set if the results of the target should be compared
+		// with those of the primitive.
+		compare bool
 	}
 
 	mirrorResult struct {
 		execTime time.Duration
 		err      error
+		digest   *resultDigest
+	}
+
+	// resultDigest summarizes a result, so that the results of a mirror
+	// target can be compared with those of the source without holding on
+	// to the rows. The row hash is the sum of the hashes of all rows, which
+	// makes it insensitive to the order in which rows are returned.
+	resultDigest struct {
+		mu         sync.Mutex
+		fieldTypes []querypb.Type
+		rowCount   uint64
+		rowHash    uint64
 	}
 )
 
@@ -53,9 +89,10 @@ const (
 
 var _ Primitive = (*percentBasedMirror)(nil)
 
-// NewPercentBasedMirror creates a Mirror.
-func NewPercentBasedMirror(percentage float32, primitive Primitive, target Primitive) Primitive {
-	return &percentBasedMirror{percent: percentage, primitive: primitive, target: target}
+// NewPercentBasedMirror creates a Mirror. If compare is set, the results of
+// the target are compared with those of the primitive.
+func NewPercentBasedMirror(percentage float32, compare bool, primitive Primitive, target Primitive) Primitive {
+	return &percentBasedMirror{percent: percentage, primitive: primitive, target: target, compare: compare}
 }
 
 func (m *percentBasedMirror) GetFields(ctx context.Context, vcursor VCursor, bindVars map[string]*querypb.BindVariable) (*sqltypes.Result, error) {
@@ -78,16 +115,22 @@ func (m *percentBasedMirror) TryExecute(ctx context.Context, vcursor VCursor, bi
 	go func() {
 		mirrorVCursor := vcursor.CloneForMirroring(mirrorCtx)
 		targetStartTime := time.Now()
-		_, targetErr := mirrorVCursor.ExecutePrimitive(mirrorCtx, m.target, bindVars, wantfields)
-		mirrorCh <- mirrorResult{
+		tr, targetErr := mirrorVCursor.ExecutePrimitive(mirrorCtx, m.target, bindVars, wantfields)
+		res := mirrorResult{
 			execTime: time.Since(targetStartTime),
 			err:      targetErr,
 		}
+		if m.compare && targetErr == nil {
+			res.digest = &resultDigest{}
+			res.digest.add(tr)
+		}
+		mirrorCh <- res
 	}()
 
 	var (
 		sourceExecTime, targetExecTime time.Duration
 		targetErr                      error
+		targetDigest                   *resultDigest
 	)
 
 	sourceStartTime := time.Now()
@@ -100,6 +143,7 @@ func (m *percentBasedMirror) TryExecute(ctx context.Context, vcursor VCursor, bi
 		// Mirror target finished on time.
 		targetExecTime = r.execTime
 		targetErr = r.err
+		targetDigest = r.digest
 	case <-time.After(maxMirrorTargetLag):
 		// Mirror target took too long.
 		mirrorCtxCancel()
@@ -109,6 +153,12 @@ func (m *percentBasedMirror) TryExecute(ctx context.Context, vcursor VCursor, bi
 
 	vcursor.RecordMirrorStats(sourceExecTime, targetExecTime, targetErr)
 
+	if err == nil && targetDigest != nil {
+		sourceDigest := &resultDigest{}
+		sourceDigest.add(r)
+		compareMirrorResults(vcursor, sourceDigest, targetDigest)
+	}
+
 	return r, err
 }
 
@@ -123,23 +173,42 @@ func (m *percentBasedMirror) TryStreamExecute(ctx context.Context, vcursor VCurs
 
 	go func() {
 		mirrorVCursor := vcursor.CloneForMirroring(mirrorCtx)
+		var digest *resultDigest
+		if m.compare {
+			digest = &resultDigest{}
+		}
 		mirrorStartTime := time.Now()
-		targetErr := mirrorVCursor.StreamExecutePrimitive(mirrorCtx, m.target, bindVars, wantfields, func(_ *sqltypes.Result) error {
+		targetErr := mirrorVCursor.StreamExecutePrimitive(mirrorCtx, m.target, bindVars, wantfields, func(qr *sqltypes.Result) error {
+			digest.add(qr)
 			return nil
 		})
-		mirrorCh <- mirrorResult{
+		res := mirrorResult{
 			execTime: time.Since(mirrorStartTime),
 			err:      targetErr,
 		}
+		if targetErr == nil {
+			res.digest = digest
+		}
+		mirrorCh <- res
 	}()
 
 	var (
 		sourceExecTime, targetExecTime time.Duration
 		targetErr                      error
+		sourceDigest, targetDigest     *resultDigest
 	)
 
+	sourceCallback := callback
+	if m.compare {
+		sourceDigest = &resultDigest{}
+		sourceCallback = func(qr *sqltypes.Result) error {
+			sourceDigest.add(qr)
+			return callback(qr)
+		}
+	}
+
 	sourceStartTime := time.Now()
-	err := vcursor.StreamExecutePrimitive(ctx, m.primitive, bindVars, wantfields, callback)
+	err := vcursor.StreamExecutePrimitive(ctx, m.primitive, bindVars, wantfields, sourceCallback)
 	sourceExecTime = time.Since(sourceStartTime)
 
 	// Cancel the mirror context if it continues executing too long.
@@ -148,6 +217,7 @@ func (m *percentBasedMirror) TryStreamExecute(ctx context.Context, vcursor VCurs
 		// Mirror target finished on time.
 		targetExecTime = r.execTime
 		targetErr = r.err
+		targetDigest = r.digest
 	case <-time.After(maxMirrorTargetLag):
 		// Mirror target took too long.
 		mirrorCtxCancel()
@@ -157,6 +227,10 @@ func (m *percentBasedMirror) TryStreamExecute(ctx context.Context, vcursor VCurs
 
 	vcursor.RecordMirrorStats(sourceExecTime, targetExecTime, targetErr)
 
+	if err == nil && targetDigest != nil {
+		compareMirrorResults(vcursor, sourceDigest, targetDigest)
+	}
+
 	return err
 }
 
@@ -169,15 +243,87 @@ func (m *percentBasedMirror) Inputs() ([]Primitive, []map[string]any) {
 // description is the description, sans the inputs, of this Primitive.
 // to get the plan description with all children, use PrimitiveToPlanDescription()
 func (m *percentBasedMirror) description() PrimitiveDescription {
-	return PrimitiveDescription{
+	desc := PrimitiveDescription{
 		OperatorType: "Mirror",
 		Variant:      "PercentBased",
 		Other: map[string]any{
 			"Percent": m.percent,
 		},
 	}
+	if m.compare {
+		desc.Other["Compare"] = true
+	}
+	return desc
 }
 
 func (m *percentBasedMirror) percentAtLeastDieRoll() bool {
 	return m.percent >= (rand.Float32() * 100.0)
 }
+
+// compareMirrorResults compares the digests of the source and target results,
+// and records the outcome. Differences are also reported to the vcursor.
+func compareMirrorResults(vcursor VCursor, source, target *resultDigest) {
+	outcome, diff := source.compare(target)
+	mirrorResultComparisons.Add(outcome, 1)
+	if diff != "" {
+		vcursor.RecordMirrorResultDiff(diff)
+	}
+}
+
+// add adds the fields and rows of the given result to the digest. It is safe
+// to call add concurrently, as streaming callbacks may be.
+func (d *resultDigest) add(qr *sqltypes.Result) {
+	if d == nil || qr == nil {
+		return
+	}
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	if d.fieldTypes == nil && len(qr.Fields) > 0 {
+		d.fieldTypes = make([]querypb.Type, 0, len(qr.Fields))
+		for _, f := range qr.Fields {
+			d.fieldTypes = append(d.fieldTypes, f.Type)
+		}
+	}
+	for _, row := range qr.Rows {
+		d.rowCount++
+		d.rowHash += hashMirrorRow(row)
+	}
+}
+
+// compare returns the outcome of comparing the digest with the one of the
+// mirror target, and a description of the difference if they don't match.
+// Field types are only compared if both results included fields.
+func (d *resultDigest) compare(target *resultDigest) (outcome string, diff string) {
+	if d.rowCount != target.rowCount {
+		return mirrorResultRowCountMismatch, fmt.Sprintf("row count: source %d, target %d", d.rowCount, target.rowCount)
+	}
+	if d.fieldTypes != nil && target.fieldTypes != nil && !slices.Equal(d.fieldTypes, target.fieldTypes) {
+		return mirrorResultFieldTypeMismatch, fmt.Sprintf("field types: source %v, target %v", d.fieldTypes, target.fieldTypes)
+	}
+	if d.rowHash != target.rowHash {
+		return mirrorResultRowMismatch, fmt.Sprintf("row hash: source %016x, target %016x, over %d rows", d.rowHash, target.rowHash, d.rowCount)
+	}
+	return mirrorResultMatch, ""
+}
+
+// hashMirrorRow hashes the values of a row, so that NULL and empty values,
+// as well as values split differently across columns, hash differently.
+func hashMirrorRow(row sqltypes.Row) uint64 {
+	var (
+		h   xxhash.Digest
+		buf [9]byte
+	)
+	h.Reset()
+	for _, v := range row {
+		if v.IsNull() {
+			buf[0] = 0
+			_, _ = h.Write(buf[:1])
+			continue
+		}
+		buf[0] = 1
+		binary.LittleEndian.PutUint64(buf[1:], uint64(len(v.Raw())))
+		_, _ = h.Write(buf[:])
+		_, _ = h.Write(v.Raw())
+	}
+	return h.Sum64()
+}
@@ -59,7 +59,7 @@ func TestMirror(t *testing.T) {
 		evalengine.NewLiteralInt(1),
 	}
 
-	mirror := NewPercentBasedMirror(100, primitive, mirrorPrimitive1)
+	mirror := NewPercentBasedMirror(100, false, primitive, mirrorPrimitive1)
 
 	mirrorVC := &loggingVCursor{
 		shards: []string{"-20", "20-"},
@@ -547,3 +547,111 @@ func TestMirror(t *testing.T) {
 		require.ErrorContains(t, *targetErr.Load(), "Mirror target query took too long")
 	})
 }
+
+func TestMirrorCompare(t *testing.T) {
+	fields := sqltypes.MakeTestFields("id|bar", "int64|varchar")
+
+	tcases := []struct {
+		name    string
+		source  *sqltypes.Result
+		target  *sqltypes.Result
+		outcome string
+		diff    string
+	}{{
+		name:    "same rows in different order",
+		source:  sqltypes.MakeTestResult(fields, "1|a", "2|b", "3|c"),
+		target:  sqltypes.MakeTestResult(fields, "3|c", "1|a", "2|b"),
+		outcome: mirrorResultMatch,
+	}, {
+		name:    "missing row",
+		source:  sqltypes.MakeTestResult(fields, "1|a", "2|b"),
+		target:  sqltypes.MakeTestResult(fields, "1|a"),
+		outcome: mirrorResultRowCountMismatch,
+		diff:    "row count: source 2, target 1",
+	}, {
+		name:    "different field types",
+		source:  sqltypes.MakeTestResult(fields, "1|a"),
+		target:  sqltypes.MakeTestResult(sqltypes.MakeTestFields("id|bar", "int32|varchar"), "1|a"),
+		outcome: mirrorResultFieldTypeMismatch,
+		diff:    "field types: source [INT64 VARCHAR], target [INT32 VARCHAR]",
+	}, {
+		name:    "different values",
+		source:  sqltypes.MakeTestResult(fields, "1|a", "2|b"),
+		target:  sqltypes.MakeTestResult(fields, "1|a", "2|c"),
+		outcome: mirrorResultRowMismatch,
+		diff:    "row hash: source",
+	}, {
+		name:    "null and empty values",
+		source:  sqltypes.MakeTestResult(fields, "1|null"),
+		target:  sqltypes.MakeTestResult(fields, "1|"),
+		outcome: mirrorResultRowMismatch,
+		diff:    "row hash: source",
+	}, {
+		name:    "duplicated rows",
+		source:  sqltypes.MakeTestResult(fields, "1|a", "1|a"),
+		target:  sqltypes.MakeTestResult(fields, "2|b", "2|b"),
+		outcome: mirrorResultRowMismatch,
+		diff:    "row hash: source",
+	}}
+
+	for _, tcase := range tcases {
+		for _, streaming := range []bool{false, true} {
+			t.Run(fmt.Sprintf("%s streaming=%t", tcase.name, streaming), func(t *testing.T) {
+				source := &fakePrimitive{results: []*sqltypes.Result{tcase.source}, allResultsInOneCall: true}
+				target := &fakePrimitive{results: []*sqltypes.Result{tcase.target}, allResultsInOneCall: true}
+				mirror := NewPercentBasedMirror(100, true, source, target)
+
+				var diff string
+				mirrorVC := &loggingVCursor{}
+				vc := &loggingVCursor{
+					onMirrorClonesFn: func(ctx context.Context) VCursor {
+						return mirrorVC
+					},
+					onRecordMirrorDiffFn: func(d string) {
+						diff = d
+					},
+				}
+
+				before := mirrorResultComparisons.Counts()[tcase.outcome]
+				if streaming {
+					err := mirror.TryStreamExecute(context.Background(), vc, nil, true, func(*sqltypes.Result) error {
+						return nil
+					})
+					require.NoError(t, err)
+				} else {
+					res, err := mirror.TryExecute(context.Background(), vc, nil, true)
+					require.NoError(t, err)
+					require.Equal(t, tcase.source, res)
+				}
+
+				require.EqualValues(t, before+1, mirrorResultComparisons.Counts()[tcase.outcome])
+				if tcase.diff == "" {
+					require.Empty(t, diff)
+				} else {
+					require.Contains(t, diff, tcase.diff)
+				}
+			})
+		}
+	}
+
+	t.Run("target error is not compared", func(t *testing.T) {
+		source := &fakePrimitive{results: []*sqltypes.Result{sqltypes.MakeTestResult(fields, "1|a")}}
+		target := &fakePrimitive{sendErr: fmt.Errorf("target error")}
+		mirror := NewPercentBasedMirror(100, true, source, target)
+
+		mirrorVC := &loggingVCursor{}
+		vc := &loggingVCursor{
+			onMirrorClonesFn: func(ctx context.Context) VCursor {
+				return mirrorVC
+			},
+			onRecordMirrorDiffFn: func(d string) {
+				require.Fail(t, "unexpected diff", d)
+			},
+		}
+
+		before := mirrorResultComparisons.Counts()
+		_, err := mirror.TryExecute(context.Background(), vc, nil, true)
+		require.NoError(t, err)
+		require.Equal(t, before, mirrorResultComparisons.Counts())
+	})
+}
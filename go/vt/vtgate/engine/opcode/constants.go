@@ -77,7 +77,16 @@ const (
 	AggregateCountStar
 	AggregateGroupConcat
 	AggregateAvg
-	AggregateUDF  // This is an opcode used to represent UDFs
+	AggregateUDF // This is an opcode used to represent UDFs
+	AggregateBitAnd
+	AggregateBitOr
+	AggregateBitXor
+	AggregateStdPop
+	AggregateStdSamp
+	AggregateVarPop
+	AggregateVarSamp
+	AggregateJSONArrayAgg
+	AggregateJSONObjectAgg
 	_NumOfOpCodes // This line must be last of the opcodes!
 )
 
@@ -89,6 +98,19 @@ var SupportedAggregates = map[string]AggregateOpcode{
 	"min":   AggregateMin,
 	"max":   AggregateMax,
 	"avg":   AggregateAvg,
+
+	"bit_and":        AggregateBitAnd,
+	"bit_or":         AggregateBitOr,
+	"bit_xor":        AggregateBitXor,
+	"std":            AggregateStdPop,
+	"stddev":         AggregateStdPop,
+	"stddev_pop":     AggregateStdPop,
+	"stddev_samp":    AggregateStdSamp,
+	"variance":       AggregateVarPop,
+	"var_pop":        AggregateVarPop,
+	"var_samp":       AggregateVarSamp,
+	"json_arrayagg":  AggregateJSONArrayAgg,
+	"json_objectagg": AggregateJSONObjectAgg,
 	// These functions don't exist in mysql, but are used
 	// to display the plan.
 	"count_distinct": AggregateCountDistinct,
@@ -111,6 +133,15 @@ var AggregateName = map[AggregateOpcode]string{
 	AggregateGroupConcat:   "group_concat",
 	AggregateAnyValue:      "any_value",
 	AggregateAvg:           "avg",
+	AggregateBitAnd:        "bit_and",
+	AggregateBitOr:         "bit_or",
+	AggregateBitXor:        "bit_xor",
+	AggregateStdPop:        "stddev_pop",
+	AggregateStdSamp:       "stddev_samp",
+	AggregateVarPop:        "var_pop",
+	AggregateVarSamp:       "var_samp",
+	AggregateJSONArrayAgg:  "json_arrayagg",
+	AggregateJSONObjectAgg: "json_objectagg",
 }
 
 func (code AggregateOpcode) String() string {
@@ -154,6 +185,12 @@ func (code AggregateOpcode) SQLType(typ querypb.Type) querypb.Type {
 		return sqltypes.Int64
 	case AggregateGtid:
 		return sqltypes.VarChar
+	case AggregateBitAnd, AggregateBitOr, AggregateBitXor:
+		return sqltypes.Uint64
+	case AggregateStdPop, AggregateStdSamp, AggregateVarPop, AggregateVarSamp:
+		return sqltypes.Float64
+	case AggregateJSONArrayAgg, AggregateJSONObjectAgg:
+		return sqltypes.TypeJSON
 	case AggregateUDF:
 		return sqltypes.Unknown
 	default:
@@ -163,7 +200,7 @@ func (code AggregateOpcode) SQLType(typ querypb.Type) querypb.Type {
 
 func (code AggregateOpcode) Nullable() bool {
 	switch code {
-	case AggregateCount, AggregateCountStar:
+	case AggregateCount, AggregateCountStar, AggregateBitAnd, AggregateBitOr, AggregateBitXor:
 		return false
 	default:
 		return true
@@ -200,3 +237,64 @@ func (code AggregateOpcode) IsDistinct() bool {
 		return false
 	}
 }
+
+// WindowOpcode is the opcode for a window function evaluated by the Window primitive.
+type WindowOpcode int
+
+// These constants list the window functions that can be evaluated on the vtgate.
+const (
+	WindowUnassigned = WindowOpcode(iota)
+	WindowRowNumber
+	WindowRank
+	WindowDenseRank
+	WindowPercentRank
+	WindowCumeDist
+	WindowNtile
+	WindowLag
+	WindowLead
+	WindowFirstValue
+	WindowLastValue
+	WindowNthValue
+	WindowAggregate
+)
+
+var WindowName = map[WindowOpcode]string{
+	WindowRowNumber:   "row_number",
+	WindowRank:        "rank",
+	WindowDenseRank:   "dense_rank",
+	WindowPercentRank: "percent_rank",
+	WindowCumeDist:    "cume_dist",
+	WindowNtile:       "ntile",
+	WindowLag:         "lag",
+	WindowLead:        "lead",
+	WindowFirstValue:  "first_value",
+	WindowLastValue:   "last_value",
+	WindowNthValue:    "nth_value",
+	WindowAggregate:   "aggregate",
+}
+
+func (code WindowOpcode) String() string {
+	name := WindowName[code]
+	if name == "" {
+		name = "ERROR"
+	}
+	return name
+}
+
+// MarshalJSON serializes the WindowOpcode as a JSON string.
+// It's used for testing and diagnostics.
+func (code WindowOpcode) MarshalJSON() ([]byte, error) {
+	return ([]byte)(fmt.Sprintf("\"%s\"", code.String())), nil
+}
+
+// SQLType returns the type of the window function result, given the type of its argument
+func (code WindowOpcode) SQLType(typ querypb.Type) querypb.Type {
+	switch code {
+	case WindowRowNumber, WindowRank, WindowDenseRank, WindowNtile:
+		return sqltypes.Uint64
+	case WindowPercentRank, WindowCumeDist:
+		return sqltypes.Float64
+	default:
+		return typ
+	}
+}
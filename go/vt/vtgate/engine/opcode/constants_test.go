@@ -52,6 +52,12 @@ func TestType(t *testing.T) {
 		{AggregateCount, sqltypes.Int32, sqltypes.Int64},
 		{AggregateCountStar, sqltypes.Int64, sqltypes.Int64},
 		{AggregateGtid, sqltypes.VarChar, sqltypes.VarChar},
+		{AggregateBitAnd, sqltypes.Int32, sqltypes.Uint64},
+		{AggregateBitXor, sqltypes.VarChar, sqltypes.Uint64},
+		{AggregateStdPop, sqltypes.Int64, sqltypes.Float64},
+		{AggregateVarSamp, sqltypes.Decimal, sqltypes.Float64},
+		{AggregateJSONArrayAgg, sqltypes.Int64, sqltypes.TypeJSON},
+		{AggregateJSONObjectAgg, sqltypes.VarChar, sqltypes.TypeJSON},
 	}
 
 	for _, tc := range tt {
@@ -130,6 +136,10 @@ func TestAggregateOpcode_MarshalJSON(t *testing.T) {
 		{AggregateGroupConcat, "\"group_concat\""},
 		{AggregateAnyValue, "\"any_value\""},
 		{AggregateAvg, "\"avg\""},
+		{AggregateBitOr, "\"bit_or\""},
+		{AggregateStdSamp, "\"stddev_samp\""},
+		{AggregateVarPop, "\"var_pop\""},
+		{AggregateJSONArrayAgg, "\"json_arrayagg\""},
 		{999, "\"ERROR\""},
 	}
 
@@ -81,6 +81,13 @@ func (gbp GroupByParams) String() string {
 
 // TryExecute is a Primitive function.
 func (oa *OrderedAggregate) TryExecute(ctx context.Context, vcursor VCursor, bindVars map[string]*querypb.BindVariable, _ bool) (*sqltypes.Result, error) {
+	if vcursor.SpillBudget() != nil {
+		// stream the input instead of holding all of it in memory, since the
+		// rows of a group are all we need at once
+		return collectStream(func(callback func(*sqltypes.Result) error) error {
+			return oa.TryStreamExecute(ctx, vcursor, bindVars, true, callback)
+		})
+	}
 	qr, err := oa.execute(ctx, vcursor, bindVars)
 	if err != nil {
 		return nil, err
@@ -131,7 +138,7 @@ func (oa *OrderedAggregate) execute(ctx context.Context, vcursor VCursor, bindVa
 		return oa.executeGroupBy(result)
 	}
 
-	agg, fields, err := newAggregation(result.Fields, oa.Aggregates)
+	agg, fields, err := newAggregation(vcursor, result.Fields, oa.Aggregates)
 	if err != nil {
 		return nil, err
 	}
@@ -151,7 +158,11 @@ func (oa *OrderedAggregate) execute(ctx context.Context, vcursor VCursor, bindVa
 		}
 
 		if nextGroup {
-			out.Rows = append(out.Rows, agg.finish())
+			finished, err := agg.finish()
+			if err != nil {
+				return nil, err
+			}
+			out.Rows = append(out.Rows, finished)
 			agg.reset()
 		}
 
@@ -161,7 +172,11 @@ func (oa *OrderedAggregate) execute(ctx context.Context, vcursor VCursor, bindVa
 	}
 
 	if currentKey != nil {
-		out.Rows = append(out.Rows, agg.finish())
+		finished, err := agg.finish()
+		if err != nil {
+			return nil, err
+		}
+		out.Rows = append(out.Rows, finished)
 	}
 
 	return out, nil
@@ -236,7 +251,7 @@ func (oa *OrderedAggregate) TryStreamExecute(ctx context.Context, vcursor VCurso
 		var err error
 
 		if agg == nil && len(qr.Fields) != 0 {
-			agg, fields, err = newAggregation(qr.Fields, oa.Aggregates)
+			agg, fields, err = newAggregation(vcursor, qr.Fields, oa.Aggregates)
 			if err != nil {
 				return err
 			}
@@ -256,7 +271,11 @@ func (oa *OrderedAggregate) TryStreamExecute(ctx context.Context, vcursor VCurso
 
 			if nextGroup {
 				// this is a new grouping. let's yield the old one, and start a new
-				if err := cb(&sqltypes.Result{Rows: [][]sqltypes.Value{agg.finish()}}); err != nil {
+				finished, err := agg.finish()
+				if err != nil {
+					return err
+				}
+				if err := cb(&sqltypes.Result{Rows: [][]sqltypes.Value{finished}}); err != nil {
 					return err
 				}
 
@@ -277,7 +296,11 @@ func (oa *OrderedAggregate) TryStreamExecute(ctx context.Context, vcursor VCurso
 	}
 
 	if currentKey != nil {
-		if err := cb(&sqltypes.Result{Rows: [][]sqltypes.Value{agg.finish()}}); err != nil {
+		finished, err := agg.finish()
+		if err != nil {
+			return err
+		}
+		if err := cb(&sqltypes.Result{Rows: [][]sqltypes.Value{finished}}); err != nil {
 			return err
 		}
 	}
@@ -291,7 +314,7 @@ func (oa *OrderedAggregate) GetFields(ctx context.Context, vcursor VCursor, bind
 		return nil, err
 	}
 
-	_, fields, err := newAggregation(qr.Fields, oa.Aggregates)
+	_, fields, err := newAggregation(vcursor, qr.Fields, oa.Aggregates)
 	if err != nil {
 		return nil, err
 	}
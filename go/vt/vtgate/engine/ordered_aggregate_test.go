@@ -20,6 +20,7 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"math"
 	"testing"
 
 	"vitess.io/vitess/go/vt/sqlparser"
@@ -29,6 +30,7 @@ import (
 	"github.com/stretchr/testify/require"
 
 	"vitess.io/vitess/go/mysql/collations"
+	"vitess.io/vitess/go/mysql/sqlerror"
 	"vitess.io/vitess/go/sqltypes"
 	"vitess.io/vitess/go/test/utils"
 	binlogdatapb "vitess.io/vitess/go/vt/proto/binlogdata"
@@ -1169,3 +1171,104 @@ func TestGroupConcat(t *testing.T) {
 		})
 	}
 }
+
+// TestGroupConcatOnVtgate tests group_concat that concatenates the rows of the group on the vtgate,
+// when the values have to be distinct or ordered, or when there is more than one argument.
+func TestGroupConcatOnVtgate(t *testing.T) {
+	fields := sqltypes.MakeTestFields(
+		"c1|c2|c3",
+		"int64|varchar|int64",
+	)
+	outFields := sqltypes.MakeTestFields(
+		"c1|group_concat",
+		"int64|text",
+	)
+	c2 := evalengine.OrderByParams{Col: 1, WeightStringCol: -1, Type: evalengine.NewType(sqltypes.VarChar, collations.CollationUtf8mb4ID), CollationEnv: collations.MySQL8()}
+	c3 := evalengine.OrderByParams{Col: 2, WeightStringCol: -1, Type: evalengine.NewType(sqltypes.Int64, collations.CollationBinaryID), CollationEnv: collations.MySQL8()}
+	c3Desc := c3
+	c3Desc.Desc = true
+
+	input := sqltypes.MakeTestResult(fields,
+		"10|b|3", "10|a|1", "10|A|2", "10|b|4",
+		"20|null|1", "20|c|null", "20|d|2",
+		"30|null|1")
+
+	var tcases = []struct {
+		name     string
+		distinct bool
+		args     evalengine.Comparison
+		orderBy  evalengine.Comparison
+		sysVars  map[string]string
+		expected *sqltypes.Result
+		warning  string
+	}{{
+		name:    "order by",
+		args:    evalengine.Comparison{c2},
+		orderBy: evalengine.Comparison{c3Desc},
+		expected: sqltypes.MakeTestResult(outFields,
+			"10|b,b,A,a", "20|d,c", "30|null"),
+	}, {
+		name:     "distinct uses the collation",
+		distinct: true,
+		args:     evalengine.Comparison{c2},
+		expected: sqltypes.MakeTestResult(outFields,
+			"10|a,b", "20|c,d", "30|null"),
+	}, {
+		name:     "distinct with order by",
+		distinct: true,
+		args:     evalengine.Comparison{c2},
+		orderBy:  evalengine.Comparison{c3Desc},
+		expected: sqltypes.MakeTestResult(outFields,
+			"10|b,a", "20|d,c", "30|null"),
+	}, {
+		name:    "multiple arguments skip the rows with a NULL",
+		args:    evalengine.Comparison{c2, c3},
+		orderBy: evalengine.Comparison{c3},
+		expected: sqltypes.MakeTestResult(outFields,
+			"10|a1,A2,b3,b4", "20|d2", "30|null"),
+	}, {
+		name:    "truncated at group_concat_max_len",
+		args:    evalengine.Comparison{c2, c3},
+		sysVars: map[string]string{"group_concat_max_len": "5"},
+		expected: sqltypes.MakeTestResult(outFields,
+			"10|b3,a1", "20|d2", "30|null"),
+		warning: "Row 1 was cut by GROUP_CONCAT()",
+	}}
+
+	for _, tcase := range tcases {
+		t.Run(tcase.name, func(t *testing.T) {
+			fp := &fakePrimitive{results: []*sqltypes.Result{input}}
+			agp := NewAggregateParam(AggregateGroupConcat, 1, "group_concat", collations.MySQL8())
+			agp.Func = &sqlparser.GroupConcatExpr{Separator: ",", Distinct: tcase.distinct}
+			agp.GroupConcatArgs = tcase.args
+			agp.GroupConcatOrderBy = tcase.orderBy
+			oa := &OrderedAggregate{
+				Aggregates:          []*AggregateParams{agp},
+				GroupByKeys:         []*GroupByParams{{KeyCol: 0}},
+				TruncateColumnCount: 2,
+				Input:               fp,
+			}
+			vc := &loggingVCursor{systemVariables: tcase.sysVars}
+			qr, err := oa.TryExecute(context.Background(), vc, nil, false)
+			require.NoError(t, err)
+			utils.MustMatch(t, tcase.expected, qr)
+
+			var warnings []string
+			for _, warning := range vc.warnings {
+				assert.EqualValues(t, sqlerror.ERCutValueGroupConcat, warning.Code)
+				warnings = append(warnings, warning.Message)
+			}
+			if tcase.warning == "" {
+				assert.Empty(t, warnings)
+			} else {
+				assert.Equal(t, []string{tcase.warning}, warnings)
+			}
+		})
+	}
+}
+
+func TestGroupConcatMaxLen(t *testing.T) {
+	// the result is not truncated unless the session sets group_concat_max_len
+	assert.EqualValues(t, uint64(math.MaxUint64), groupConcatMaxLen(&loggingVCursor{}))
+	assert.EqualValues(t, 2048, groupConcatMaxLen(&loggingVCursor{systemVariables: map[string]string{"group_concat_max_len": "2048"}}))
+}
@@ -51,12 +51,16 @@ type (
 		ParamsCount  uint16                  // ParamsCount is the total number of bind parameters (?) in the query.
 		Optimized    atomic.Bool             // Prepared queries need to be optimized before the first execution
 
+		ResultCacheTTL time.Duration // ResultCacheTTL is how long the results of the plan are cached by vtgate, zero if they are not cached.
+		Consolidate    bool          // Consolidate is true if identical executions of the plan in flight can share their result.
+
 		ExecCount    uint64 // ExecCount is how many times this plan has been executed.
 		ExecTime     uint64 // ExecTime is the total accumulated execution time in nanoseconds.
 		ShardQueries uint64 // ShardQueries is the total count of shard-level queries performed.
 		RowsReturned uint64 // RowsReturned is the total number of rows returned to clients.
 		RowsAffected uint64 // RowsAffected is the total number of rows affected by DML operations.
 		Errors       uint64 // Errors is the total count of errors encountered during execution.
+		SpillBytes   uint64 // SpillBytes is the total number of bytes of rows spilled to disk by the primitives.
 	}
 
 	// PlanKey identifies a plan uniquely based on keyspace, destination, query,
@@ -133,7 +137,7 @@ func getPlanType(p Primitive) PlanType {
 		return PlanPassthrough
 	case *Send:
 		return getPlanTypeFromTarget(prim)
-	case *TransactionStatus:
+	case *TransactionStatus, *LockTables:
 		return PlanMultiShard
 	case *Limit:
 		return getPlanType(prim.Input)
@@ -302,7 +306,10 @@ func (p *Plan) MarshalJSON() ([]byte, error) {
 		RowsAffected uint64                `json:",omitempty"`
 		RowsReturned uint64                `json:",omitempty"`
 		Errors       uint64                `json:",omitempty"`
+		SpillBytes   uint64                `json:",omitempty"`
 		TablesUsed   []string              `json:",omitempty"`
+
+		ResultCacheTTL time.Duration `json:",omitempty"`
 	}{
 		Type:         p.Type.String(),
 		QueryType:    p.QueryType.String(),
@@ -314,7 +321,10 @@ func (p *Plan) MarshalJSON() ([]byte, error) {
 		RowsAffected: atomic.LoadUint64(&p.RowsAffected),
 		RowsReturned: atomic.LoadUint64(&p.RowsReturned),
 		Errors:       atomic.LoadUint64(&p.Errors),
+		SpillBytes:   atomic.LoadUint64(&p.SpillBytes),
 		TablesUsed:   p.TablesUsed,
+
+		ResultCacheTTL: p.ResultCacheTTL,
 	}
 
 	b := new(bytes.Buffer)
@@ -338,6 +348,11 @@ func (p *Plan) AddStats(execCount uint64, execTime time.Duration, shardQueries,
 	atomic.AddUint64(&p.Errors, errors)
 }
 
+// AddSpillBytes adds to the number of bytes of rows that the plan spilled to disk
+func (p *Plan) AddSpillBytes(bytes uint64) {
+	atomic.AddUint64(&p.SpillBytes, bytes)
+}
+
 // Stats returns a copy of the plan execution statistics
 func (p *Plan) Stats() (execCount uint64, execTime time.Duration, shardQueries, rowsAffected, rowsReturned, errors uint64) {
 	execCount = atomic.LoadUint64(&p.ExecCount)
@@ -56,6 +56,10 @@ type (
 		// if the max memory rows override directive is set to true
 		ExceedsMaxMemoryRows(numRows int) bool
 
+		// SpillBudget returns the memory budget of the query for the primitives
+		// that can spill rows to disk. Returns nil if spilling is disabled.
+		SpillBudget() *SpillBudget
+
 		Execute(ctx context.Context, method string, query string, bindVars map[string]*querypb.BindVariable, rollbackOnError bool, co vtgatepb.CommitOrder) (*sqltypes.Result, error)
 		AutocommitApproval() bool
 
@@ -123,6 +127,10 @@ type (
 		// ReleaseLock releases all the held advisory locks.
 		ReleaseLock(ctx context.Context) error
 
+		// ReleaseLockTables clears the table locks of the session, and releases the reserved connections
+		// if they were only needed for the table locks.
+		ReleaseLockTables(ctx context.Context) error
+
 		// GetWarmingReadsPercent gets the percentage of queries to clone to replicas for bufferpool warming
 		GetWarmingReadsPercent() int
 
@@ -148,9 +156,17 @@ type (
 		// RecordMirrorStats is used to record stats about a mirror query.
 		RecordMirrorStats(time.Duration, time.Duration, error)
 
+		// RecordMirrorResultDiff is used to record how the results of a
+		// mirror target differ from those of the source.
+		RecordMirrorResultDiff(diff string)
+
 		SetLastInsertID(uint64)
 
 		GetExecutionMetrics() *Metrics
+
+		// ReadLocalInfile reads the given file from the client, for LOAD DATA LOCAL INFILE.
+		// The callback is called with the chunks of the file, in order.
+		ReadLocalInfile(ctx context.Context, filename string, callback func(data []byte) error) error
 	}
 
 	// SessionActions gives primitives ability to interact with the session state
@@ -193,6 +209,13 @@ type (
 		SetMigrationContext(string)
 		GetMigrationContext() string
 
+		// SetTenantID sets the tenant that the queries of the session are
+		// restricted to in multi-tenant keyspaces.
+		SetTenantID(string) error
+		// GetTenantID returns the tenant that the queries of the session are
+		// restricted to in multi-tenant keyspaces, if any.
+		GetTenantID(ctx context.Context) string
+
 		GetSessionUUID() string
 
 		SetSessionEnableSystemSettings(context.Context, bool) error
@@ -217,6 +240,11 @@ type (
 		// RemoveAdvisoryLock removes advisory lock from the session
 		RemoveAdvisoryLock(name string)
 
+		// InLockTables returns true if the session holds table locks taken by LOCK TABLES
+		InLockTables() bool
+		// SetInLockTables marks the session as holding table locks, on reserved connections
+		SetInLockTables()
+
 		// VExplainLogging enables logging of all interactions to the tablets so
 		// VEXPLAIN QUERIES/ALL can report what's being done
 		VExplainLogging()
@@ -48,7 +48,7 @@ func (sa *ScalarAggregate) GetFields(ctx context.Context, vcursor VCursor, bindV
 		return nil, err
 	}
 
-	_, fields, err := newAggregation(qr.Fields, sa.Aggregates)
+	_, fields, err := newAggregation(vcursor, qr.Fields, sa.Aggregates)
 	if err != nil {
 		return nil, err
 	}
@@ -64,12 +64,18 @@ func (sa *ScalarAggregate) NeedsTransaction() bool {
 
 // TryExecute implements the Primitive interface
 func (sa *ScalarAggregate) TryExecute(ctx context.Context, vcursor VCursor, bindVars map[string]*querypb.BindVariable, wantfields bool) (*sqltypes.Result, error) {
+	if vcursor.SpillBudget() != nil {
+		// stream the input instead of holding all of it in memory
+		return collectStream(func(callback func(*sqltypes.Result) error) error {
+			return sa.TryStreamExecute(ctx, vcursor, bindVars, true, callback)
+		})
+	}
 	result, err := vcursor.ExecutePrimitive(ctx, sa.Input, bindVars, true)
 	if err != nil {
 		return nil, err
 	}
 
-	agg, fields, err := newAggregation(result.Fields, sa.Aggregates)
+	agg, fields, err := newAggregation(vcursor, result.Fields, sa.Aggregates)
 	if err != nil {
 		return nil, err
 	}
@@ -80,9 +86,13 @@ func (sa *ScalarAggregate) TryExecute(ctx context.Context, vcursor VCursor, bind
 		}
 	}
 
+	finished, err := agg.finish()
+	if err != nil {
+		return nil, err
+	}
 	out := &sqltypes.Result{
 		Fields: fields,
-		Rows:   [][]sqltypes.Value{agg.finish()},
+		Rows:   [][]sqltypes.Value{finished},
 	}
 	return out.Truncate(sa.TruncateColumnCount), nil
 }
@@ -107,7 +117,7 @@ func (sa *ScalarAggregate) TryStreamExecute(ctx context.Context, vcursor VCursor
 
 		if agg == nil && len(result.Fields) != 0 {
 			var err error
-			agg, fields, err = newAggregation(result.Fields, sa.Aggregates)
+			agg, fields, err = newAggregation(vcursor, result.Fields, sa.Aggregates)
 			if err != nil {
 				return err
 			}
@@ -130,7 +140,11 @@ func (sa *ScalarAggregate) TryStreamExecute(ctx context.Context, vcursor VCursor
 		return err
 	}
 
-	return cb(&sqltypes.Result{Rows: [][]sqltypes.Value{agg.finish()}})
+	finished, err := agg.finish()
+	if err != nil {
+		return err
+	}
+	return cb(&sqltypes.Result{Rows: [][]sqltypes.Value{finished}})
 }
 
 // Inputs implements the Primitive interface
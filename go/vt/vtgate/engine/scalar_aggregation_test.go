@@ -66,6 +66,18 @@ func TestEmptyRows(outer *testing.T) {
 		opcode:      AggregateMin,
 		expectedVal: "null",
 		expectedTyp: "int64",
+	}, {
+		opcode:      AggregateBitAnd,
+		expectedVal: "18446744073709551615",
+		expectedTyp: "uint64",
+	}, {
+		opcode:      AggregateBitXor,
+		expectedVal: "0",
+		expectedTyp: "uint64",
+	}, {
+		opcode:      AggregateJSONArrayAgg,
+		expectedVal: "null",
+		expectedTyp: "json",
 	}}
 
 	for _, test := range testCases {
@@ -342,6 +354,65 @@ func TestScalarDistinctPushedDown(t *testing.T) {
 	require.Equal(t, `[[INT64(27) DECIMAL(1430)]]`, fmt.Sprintf("%v", results.Rows))
 }
 
+// TestScalarBitAndJSONAggregations tests merging the partial bit and JSON aggregations of the shards.
+func TestScalarBitAndJSONAggregations(t *testing.T) {
+	fp := &fakePrimitive{results: []*sqltypes.Result{sqltypes.MakeTestResult(
+		sqltypes.MakeTestFields(
+			"bit_and(a)|bit_or(a)|bit_xor(a)|json_arrayagg(b)|json_objectagg(a, b)",
+			"uint64|uint64|uint64|json|json",
+		),
+		`7|7|7|[1, "x"]|{"a": 1, "b": 2}`,
+		`18446744073709551615|0|0|null|null`,
+		`12|12|12|[null]|{"b": 3, "c": [4]}`,
+	)}}
+
+	oa := &ScalarAggregate{
+		Aggregates: []*AggregateParams{
+			NewAggregateParam(AggregateBitAnd, 0, "", collations.MySQL8()),
+			NewAggregateParam(AggregateBitOr, 1, "", collations.MySQL8()),
+			NewAggregateParam(AggregateBitXor, 2, "", collations.MySQL8()),
+			NewAggregateParam(AggregateJSONArrayAgg, 3, "", collations.MySQL8()),
+			NewAggregateParam(AggregateJSONObjectAgg, 4, "", collations.MySQL8()),
+		},
+		Input: fp,
+	}
+	qr, err := oa.TryExecute(context.Background(), &noopVCursor{}, nil, false)
+	require.NoError(t, err)
+	require.Equal(t, `[[UINT64(4) UINT64(15) UINT64(11) JSON("[1, \"x\", null]") JSON("{\"a\": 1, \"b\": 3, \"c\": [4]}")]]`, fmt.Sprintf("%v", qr.Rows))
+}
+
+// TestScalarVariance tests merging the population variances of the shards, and
+// calculating the variance of the values when the aggregation is not pushed down.
+func TestScalarVariance(t *testing.T) {
+	fp := &fakePrimitive{results: []*sqltypes.Result{sqltypes.MakeTestResult(
+		sqltypes.MakeTestFields("var_pop(a)|count(a)|sum(a)", "float64|int64|decimal"),
+		"0.25|2|2000000003",
+		"null|0|null",
+		"0.25|2|2000000007",
+	)}}
+	variance := NewAggregateParam(AggregateVarPop, 0, "", collations.MySQL8())
+	variance.CountCol, variance.SumCol = 1, 2
+	oa := &ScalarAggregate{
+		Aggregates: []*AggregateParams{variance},
+		Input:      fp,
+	}
+	qr, err := oa.TryExecute(context.Background(), &noopVCursor{}, nil, false)
+	require.NoError(t, err)
+	require.Equal(t, `[[FLOAT64(1.25) INT64(2) DECIMAL(2000000003)]]`, fmt.Sprintf("%v", qr.Rows))
+
+	fp = &fakePrimitive{results: []*sqltypes.Result{sqltypes.MakeTestResult(
+		sqltypes.MakeTestFields("a", "int64"),
+		"1000000001", "null", "1000000002", "1000000003", "1000000004",
+	)}}
+	oa = &ScalarAggregate{
+		Aggregates: []*AggregateParams{NewAggregateParam(AggregateVarPop, 0, "", collations.MySQL8())},
+		Input:      fp,
+	}
+	qr, err = oa.TryExecute(context.Background(), &noopVCursor{}, nil, false)
+	require.NoError(t, err)
+	require.Equal(t, `[[FLOAT64(1.25)]]`, fmt.Sprintf("%v", qr.Rows))
+}
+
 // TestScalarGroupConcat tests group_concat with partial aggregation on engine.
 func TestScalarGroupConcat(t *testing.T) {
 	fields := sqltypes.MakeTestFields(
@@ -470,6 +470,16 @@ func (svss *SysVarSetAware) Execute(ctx context.Context, vcursor VCursor, env *e
 			return vterrors.NewErrorf(vtrpcpb.Code_INVALID_ARGUMENT, vterrors.WrongValueForVar, "invalid migration_context: %s", str)
 		}
 		vcursor.Session().SetMigrationContext(str)
+	case sysvars.TenantID.Name:
+		value, err := env.Evaluate(svss.Expr)
+		if err != nil {
+			return err
+		}
+		v := value.Value(vcursor.ConnCollation())
+		if !v.IsIntegral() && !v.IsText() && !v.IsBinary() {
+			return vterrors.NewErrorf(vtrpcpb.Code_INVALID_ARGUMENT, vterrors.WrongTypeForVar, "incorrect argument type to variable '%s': %s", svss.Name, v.Type().String())
+		}
+		return vcursor.Session().SetTenantID(v.ToString())
 	case sysvars.QueryTimeout.Name:
 		queryTimeout, err := svss.evalAsInt64(env, vcursor)
 		if err != nil {
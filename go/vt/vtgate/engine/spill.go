@@ -0,0 +1,388 @@
+/*
+Copyright 2025 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package engine
+
+import (
+	"bufio"
+	"encoding/binary"
+	"errors"
+	"io"
+	"os"
+	"sync"
+	"sync/atomic"
+
+	"vitess.io/vitess/go/sqltypes"
+	"vitess.io/vitess/go/stats"
+	querypb "vitess.io/vitess/go/vt/proto/query"
+	"vitess.io/vitess/go/vt/vterrors"
+	"vitess.io/vitess/go/vt/vtgate/evalengine"
+)
+
+// spillBatchSize is the number of bytes of rows that are sent to the callback
+// at once, when the rows are read back from the temporary files.
+const spillBatchSize = 1 << 20
+
+var (
+	// spillSortRunSize is the number of bytes of rows that a sorter always holds in
+	// memory before it writes them to a sorted run, even when the budget is exhausted,
+	// so that a sorter doesn't write a run for every row once the other primitives of
+	// the query used all the budget.
+	spillSortRunSize int64 = 1 << 20
+
+	// spillMergeFanIn is the maximum number of sorted runs that are merged at once.
+	// When a sorter wrote more runs, they are first merged into longer runs.
+	spillMergeFanIn = 64
+)
+
+var spilledBytes = stats.NewCountersWithSingleLabel(
+	"SpilledBytes",
+	"Number of bytes of rows written to temporary files by the vtgate primitives that exceeded the spill memory budget",
+	"Operator")
+
+// SpillBudget is the memory budget of a query for the primitives that can
+// spill their rows to temporary files when they hold too many of them in
+// memory: the sorts, the hash joins and the aggregations. The budget is
+// shared by all the primitives of the query.
+type SpillBudget struct {
+	// Limit is the number of bytes of rows that the primitives of the query
+	// can hold in memory before they spill them to disk.
+	Limit int64
+	// Dir is the directory of the temporary files. The default directory for
+	// temporary files is used when it's empty.
+	Dir string
+	// OnSpill is called with the number of bytes written to the temporary files.
+	OnSpill func(bytes int64)
+
+	used atomic.Int64
+}
+
+// grow accounts for n more bytes held in memory, and returns false when
+// the budget is exceeded.
+func (b *SpillBudget) grow(n int64) bool {
+	return b.used.Add(n) <= b.Limit
+}
+
+// release gives back n bytes to the budget.
+func (b *SpillBudget) release(n int64) {
+	b.used.Add(-n)
+}
+
+func (b *SpillBudget) recordSpill(operator string, n int64) {
+	spilledBytes.Add(operator, n)
+	if b.OnSpill != nil {
+		b.OnSpill(n)
+	}
+}
+
+// rowSize estimates the number of bytes of memory used by a row.
+func rowSize(row sqltypes.Row) int64 {
+	var size int64
+	for i := range row {
+		size += row[i].CachedSize(true)
+	}
+	return size
+}
+
+// collectStream gathers all the results streamed by a primitive into a single result.
+// The primitives that can spill use it to implement TryExecute with their streaming
+// code path, so that they never need to hold all of their input in memory.
+func collectStream(stream func(callback func(*sqltypes.Result) error) error) (*sqltypes.Result, error) {
+	var mu sync.Mutex
+	result := &sqltypes.Result{}
+	err := stream(func(qr *sqltypes.Result) error {
+		mu.Lock()
+		defer mu.Unlock()
+		result.AppendResult(qr)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+// spillFile is a temporary file that rows are written to, and then read back from.
+// Every row is encoded as its number of values, followed by the type, the length
+// and the bytes of every value.
+type spillFile struct {
+	file *os.File
+	w    *bufio.Writer
+	r    *bufio.Reader
+	buf  []byte
+
+	// size is the number of bytes written to the file
+	size int64
+}
+
+func newSpillFile(dir string) (*spillFile, error) {
+	file, err := os.CreateTemp(dir, "vtgate-spill-")
+	if err != nil {
+		return nil, vterrors.Wrap(err, "failed to create a temporary file to spill rows")
+	}
+	return &spillFile{
+		file: file,
+		w:    bufio.NewWriter(file),
+	}, nil
+}
+
+func (sf *spillFile) write(row sqltypes.Row) error {
+	sf.buf = binary.AppendUvarint(sf.buf[:0], uint64(len(row)))
+	for _, v := range row {
+		raw := v.Raw()
+		sf.buf = binary.AppendUvarint(sf.buf, uint64(v.Type()))
+		sf.buf = binary.AppendUvarint(sf.buf, uint64(len(raw)))
+		sf.buf = append(sf.buf, raw...)
+	}
+	n, err := sf.w.Write(sf.buf)
+	sf.size += int64(n)
+	return err
+}
+
+// rewind flushes the written rows, and prepares the file for reading them back.
+func (sf *spillFile) rewind() error {
+	if err := sf.w.Flush(); err != nil {
+		return err
+	}
+	if _, err := sf.file.Seek(0, io.SeekStart); err != nil {
+		return err
+	}
+	sf.r = bufio.NewReader(sf.file)
+	return nil
+}
+
+// read returns the next row of the file, or io.EOF when all the rows have been read.
+func (sf *spillFile) read() (sqltypes.Row, error) {
+	cols, err := binary.ReadUvarint(sf.r)
+	if err != nil {
+		return nil, err
+	}
+	row := make(sqltypes.Row, cols)
+	for i := range row {
+		typ, err := binary.ReadUvarint(sf.r)
+		if err != nil {
+			return nil, unexpectedEOF(err)
+		}
+		length, err := binary.ReadUvarint(sf.r)
+		if err != nil {
+			return nil, unexpectedEOF(err)
+		}
+		raw := make([]byte, length)
+		if _, err := io.ReadFull(sf.r, raw); err != nil {
+			return nil, unexpectedEOF(err)
+		}
+		row[i] = sqltypes.MakeTrusted(querypb.Type(typ), raw)
+	}
+	return row, nil
+}
+
+func unexpectedEOF(err error) error {
+	if errors.Is(err, io.EOF) {
+		return io.ErrUnexpectedEOF
+	}
+	return err
+}
+
+// close closes and removes the file.
+func (sf *spillFile) close() {
+	_ = sf.file.Close()
+	_ = os.Remove(sf.file.Name())
+}
+
+// spillSorter sorts rows like evalengine.Sorter does, but when the rows don't fit
+// in the memory budget anymore, it sorts them and writes them to a temporary file
+// as a sorted run. The runs are merged when the sorted rows are read.
+type spillSorter struct {
+	compare evalengine.Comparison
+	limit   int
+	budget  *SpillBudget
+
+	sorter *evalengine.Sorter
+	// size is the number of bytes of the budget used by the rows of the sorter
+	size int64
+	runs []*spillFile
+}
+
+func newSpillSorter(compare evalengine.Comparison, limit int, budget *SpillBudget) *spillSorter {
+	return &spillSorter{
+		compare: compare,
+		limit:   limit,
+		budget:  budget,
+		sorter:  &evalengine.Sorter{Compare: compare, Limit: limit},
+	}
+}
+
+// Len returns the number of rows held in memory.
+func (s *spillSorter) Len() int {
+	return s.sorter.Len()
+}
+
+func (s *spillSorter) push(row sqltypes.Row) error {
+	before := s.sorter.Len()
+	s.sorter.Push(row)
+	if s.budget == nil || s.sorter.Len() == before {
+		// once the sorter holds `limit` rows, pushing a row replaces another one
+		return nil
+	}
+	n := rowSize(row)
+	s.size += n
+	if s.budget.grow(n) || s.size < spillSortRunSize {
+		return nil
+	}
+	return s.spill()
+}
+
+// spill writes the rows held in memory to a new sorted run.
+func (s *spillSorter) spill() error {
+	if s.sorter.Len() == 0 {
+		return nil
+	}
+	run, err := newSpillFile(s.budget.Dir)
+	if err != nil {
+		return err
+	}
+	s.runs = append(s.runs, run)
+	for _, row := range s.sorter.Sorted() {
+		if err := run.write(row); err != nil {
+			return err
+		}
+	}
+	s.budget.recordSpill("Sort", run.size)
+	s.budget.release(s.size)
+	s.size = 0
+	s.sorter = &evalengine.Sorter{Compare: s.compare, Limit: s.limit}
+	return nil
+}
+
+// emit sends the sorted rows to the callback.
+func (s *spillSorter) emit(callback func(*sqltypes.Result) error) error {
+	if len(s.runs) == 0 {
+		return callback(&sqltypes.Result{Rows: s.sorter.Sorted()})
+	}
+	if err := s.spill(); err != nil {
+		return err
+	}
+	if err := s.compact(); err != nil {
+		return err
+	}
+
+	var rows []sqltypes.Row
+	var size int64
+	err := s.merge(s.runs, func(row sqltypes.Row) error {
+		rows = append(rows, row)
+		size += rowSize(row)
+		if size < spillBatchSize {
+			return nil
+		}
+		err := callback(&sqltypes.Result{Rows: rows})
+		rows, size = nil, 0
+		return err
+	})
+	if err != nil || len(rows) == 0 {
+		return err
+	}
+	return callback(&sqltypes.Result{Rows: rows})
+}
+
+// compact merges the runs spillMergeFanIn at a time into longer runs, until there
+// are few enough of them to be merged at once.
+func (s *spillSorter) compact() error {
+	for len(s.runs) > spillMergeFanIn {
+		run, err := newSpillFile(s.budget.Dir)
+		if err != nil {
+			return err
+		}
+		merged := s.runs[:spillMergeFanIn]
+		s.runs = append(s.runs[spillMergeFanIn:], run)
+		err = s.merge(merged, run.write)
+		for _, m := range merged {
+			m.close()
+		}
+		if err != nil {
+			return err
+		}
+		s.budget.recordSpill("Sort", run.size)
+	}
+	return nil
+}
+
+// merge reads the sorted runs, and calls f for their rows in order, up to the
+// limit of the sorter.
+func (s *spillSorter) merge(runs []*spillFile, f func(sqltypes.Row) error) error {
+	merger := &evalengine.Merger{Compare: s.compare}
+	for source, run := range runs {
+		if err := run.rewind(); err != nil {
+			return err
+		}
+		row, err := run.read()
+		if err == io.EOF {
+			continue
+		}
+		if err != nil {
+			return err
+		}
+		merger.Push(row, source)
+	}
+	merger.Init()
+
+	for emitted := 0; merger.Len() > 0 && emitted < s.limit; emitted++ {
+		row, source := merger.Pop()
+		if err := f(row); err != nil {
+			return err
+		}
+
+		next, err := runs[source].read()
+		if err == io.EOF {
+			continue
+		}
+		if err != nil {
+			return err
+		}
+		merger.Push(next, source)
+	}
+	return nil
+}
+
+// close gives back the memory used by the sorter to the budget, and removes the runs.
+func (s *spillSorter) close() {
+	if s.budget != nil {
+		s.budget.release(s.size)
+		s.size = 0
+	}
+	for _, run := range s.runs {
+		run.close()
+	}
+	s.runs = nil
+}
+
+// readSpillFile calls f for every row of the file.
+func readSpillFile(sf *spillFile, f func(sqltypes.Row) error) error {
+	if err := sf.rewind(); err != nil {
+		return err
+	}
+	for {
+		row, err := sf.read()
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		if err := f(row); err != nil {
+			return err
+		}
+	}
+}
@@ -0,0 +1,297 @@
+/*
+Copyright 2025 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package engine
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"math"
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"vitess.io/vitess/go/mysql/collations"
+	"vitess.io/vitess/go/sqltypes"
+	"vitess.io/vitess/go/test/utils"
+	querypb "vitess.io/vitess/go/vt/proto/query"
+	. "vitess.io/vitess/go/vt/vtgate/engine/opcode"
+	"vitess.io/vitess/go/vt/vtgate/evalengine"
+)
+
+// newTestSpillBudget returns a budget so small that every primitive spills
+// all of its rows, and a function returning the number of bytes spilled.
+func newTestSpillBudget(t *testing.T) (*SpillBudget, func() int64) {
+	runSize := spillSortRunSize
+	spillSortRunSize = 0
+	t.Cleanup(func() {
+		spillSortRunSize = runSize
+	})
+
+	var spilled int64
+	budget := &SpillBudget{
+		Limit: 1,
+		Dir:   t.TempDir(),
+		OnSpill: func(bytes int64) {
+			spilled += bytes
+		},
+	}
+	t.Cleanup(func() {
+		// all the temporary files must have been removed
+		entries, err := os.ReadDir(budget.Dir)
+		require.NoError(t, err)
+		assert.Empty(t, entries)
+		assert.Zero(t, budget.used.Load())
+	})
+	return budget, func() int64 { return spilled }
+}
+
+func TestSpillFile(t *testing.T) {
+	rows := []sqltypes.Row{
+		{sqltypes.NewInt64(1), sqltypes.NewVarChar("a"), sqltypes.NULL},
+		{sqltypes.NewInt64(-2), sqltypes.NewVarChar(""), sqltypes.NewFloat64(1.5)},
+		{},
+		{sqltypes.MakeTrusted(sqltypes.Decimal, []byte("12.34")), sqltypes.NewVarBinary("\x00\xff")},
+	}
+
+	sf, err := newSpillFile(t.TempDir())
+	require.NoError(t, err)
+	defer sf.close()
+	for _, row := range rows {
+		require.NoError(t, sf.write(row))
+	}
+	require.NoError(t, sf.rewind())
+
+	for _, want := range rows {
+		row, err := sf.read()
+		require.NoError(t, err)
+		utils.MustMatch(t, want, row)
+	}
+	_, err = sf.read()
+	require.Equal(t, io.EOF, err)
+}
+
+func TestMemorySortSpill(t *testing.T) {
+	fields := sqltypes.MakeTestFields(
+		"c1|c2",
+		"varbinary|decimal",
+	)
+	fp := &fakePrimitive{
+		allResultsInOneCall: true,
+		results: []*sqltypes.Result{
+			sqltypes.MakeTestResult(fields, "a|1", "g|2", "a|1"),
+			sqltypes.MakeTestResult(fields, "c|4", "c|3"),
+		},
+	}
+	ms := &MemorySort{
+		OrderBy: []evalengine.OrderByParams{{
+			WeightStringCol: -1,
+			Col:             1,
+		}},
+		Input: fp,
+	}
+
+	budget, spilled := newTestSpillBudget(t)
+	vc := &loggingVCursor{spillBudget: budget}
+
+	result, err := wrapStreamExecute(ms, vc, nil, true)
+	require.NoError(t, err)
+	utils.MustMatch(t, sqltypes.MakeTestResult(fields, "a|1", "a|1", "g|2", "c|3", "c|4"), result)
+	assert.NotZero(t, spilled())
+
+	fp.rewind()
+	ms.UpperLimit = evalengine.NewBindVar("__upper_limit", evalengine.NewType(sqltypes.Int64, collations.CollationBinaryID))
+	bv := map[string]*querypb.BindVariable{"__upper_limit": sqltypes.Int64BindVariable(3)}
+	result, err = ms.TryExecute(context.Background(), vc, bv, true)
+	require.NoError(t, err)
+	utils.MustMatch(t, sqltypes.MakeTestResult(fields, "a|1", "a|1", "g|2"), result)
+}
+
+func TestSpillSorterRuns(t *testing.T) {
+	budget, spilled := newTestSpillBudget(t)
+	row := func(i int) sqltypes.Row {
+		return sqltypes.Row{sqltypes.NewInt64(int64(i))}
+	}
+	// every run holds 3 rows, and they are merged 2 at a time
+	spillSortRunSize = 3 * rowSize(row(0))
+	fanIn := spillMergeFanIn
+	spillMergeFanIn = 2
+	defer func() {
+		spillMergeFanIn = fanIn
+	}()
+
+	sorter := newSpillSorter(evalengine.Comparison{{Col: 0, WeightStringCol: -1}}, math.MaxInt, budget)
+	defer sorter.close()
+	for i := range 20 {
+		require.NoError(t, sorter.push(row((i*7)%20)))
+	}
+	assert.Len(t, sorter.runs, 6)
+	assert.Equal(t, 2, sorter.Len())
+
+	var got []sqltypes.Row
+	err := sorter.emit(func(qr *sqltypes.Result) error {
+		got = append(got, qr.Rows...)
+		return nil
+	})
+	require.NoError(t, err)
+	assert.LessOrEqual(t, len(sorter.runs), 2)
+	var want []sqltypes.Row
+	for i := range 20 {
+		want = append(want, row(i))
+	}
+	utils.MustMatch(t, want, got)
+	assert.NotZero(t, spilled())
+}
+
+func TestHashJoinSpill(t *testing.T) {
+	lfields := sqltypes.MakeTestFields("col1|col2", "int64|varchar")
+	lhs := &fakePrimitive{
+		allResultsInOneCall: true,
+		results: []*sqltypes.Result{
+			sqltypes.MakeTestResult(lfields, "1|a", "2|b"),
+			{Rows: sqltypes.MakeTestResult(lfields, "3|c", "null|d", "1|e").Rows},
+		},
+	}
+	rhs := &fakePrimitive{
+		results: []*sqltypes.Result{
+			sqltypes.MakeTestResult(sqltypes.MakeTestFields("col3", "int64"), "1", "3", "null", "4"),
+		},
+	}
+	fields := sqltypes.MakeTestFields("col1|col2|col3", "int64|varchar|int64")
+
+	for _, tc := range []struct {
+		typ      JoinOpcode
+		expected []string
+	}{{
+		typ:      InnerJoin,
+		expected: []string{"1|a|1", "1|e|1", "3|c|3"},
+	}, {
+		typ:      LeftJoin,
+		expected: []string{"1|a|1", "1|e|1", "3|c|3", "2|b|null", "null|d|null"},
+	}} {
+		t.Run(tc.typ.String(), func(t *testing.T) {
+			jn := &HashJoin{
+				Opcode:          tc.typ,
+				Left:            lhs,
+				Right:           rhs,
+				Cols:            []int{-1, -2, 1},
+				LHSKeys:         []int{0},
+				RHSKeys:         []int{0},
+				ComparisonTypes: []evalengine.Type{evalengine.NewType(sqltypes.Int64, collations.CollationBinaryID)},
+				CollationEnv:    collations.MySQL8(),
+			}
+			budget, spilled := newTestSpillBudget(t)
+			// the rows of a partition fit in the budget, but not all the rows of the LHS
+			budget.Limit = 2 * rowSize(lhs.results[0].Rows[0])
+			vc := &loggingVCursor{spillBudget: budget}
+			expected := sqltypes.MakeTestResult(fields, tc.expected...)
+
+			lhs.rewind()
+			rhs.rewind()
+			result, err := wrapStreamExecute(jn, vc, nil, true)
+			require.NoError(t, err)
+			expectResultAnyOrder(t, result, expected)
+			assert.NotZero(t, spilled())
+
+			lhs.rewind()
+			rhs.rewind()
+			result, err = jn.TryExecute(context.Background(), vc, nil, true)
+			require.NoError(t, err)
+			expectResultAnyOrder(t, result, expected)
+		})
+	}
+}
+
+func TestHashJoinSpillRepartition(t *testing.T) {
+	lfields := sqltypes.MakeTestFields("col1", "int64")
+	rfields := sqltypes.MakeTestFields("col2", "int64")
+	newJoin := func(lhs, rhs *sqltypes.Result) *HashJoin {
+		return &HashJoin{
+			Opcode:          InnerJoin,
+			Left:            &fakePrimitive{results: []*sqltypes.Result{lhs}},
+			Right:           &fakePrimitive{results: []*sqltypes.Result{rhs}},
+			Cols:            []int{-1, 1},
+			LHSKeys:         []int{0},
+			RHSKeys:         []int{0},
+			ComparisonTypes: []evalengine.Type{evalengine.NewType(sqltypes.Int64, collations.CollationBinaryID)},
+			CollationEnv:    collations.MySQL8(),
+		}
+	}
+
+	t.Run("distinct keys", func(t *testing.T) {
+		// the partitions hold more rows than the budget, so they are split again
+		var lrows, rrows, expected []string
+		for i := range 100 {
+			lrows = append(lrows, fmt.Sprint(i))
+			if i%2 == 0 {
+				rrows = append(rrows, fmt.Sprint(i))
+				expected = append(expected, fmt.Sprintf("%d|%d", i, i))
+			}
+		}
+		lhs := sqltypes.MakeTestResult(lfields, lrows...)
+		budget, _ := newTestSpillBudget(t)
+		budget.Limit = 4 * rowSize(lhs.Rows[0])
+		var spills int
+		budget.OnSpill = func(int64) {
+			spills++
+		}
+
+		jn := newJoin(lhs, sqltypes.MakeTestResult(rfields, rrows...))
+		result, err := wrapStreamExecute(jn, &loggingVCursor{spillBudget: budget}, nil, true)
+		require.NoError(t, err)
+		expectResultAnyOrder(t, result, sqltypes.MakeTestResult(sqltypes.MakeTestFields("col1|col2", "int64|int64"), expected...))
+		assert.Greater(t, spills, 1)
+	})
+
+	t.Run("same key", func(t *testing.T) {
+		// the rows with the same join key can't be split into smaller partitions
+		lhs := sqltypes.MakeTestResult(lfields, "1", "1", "1", "1", "1", "1", "1", "1")
+		budget, _ := newTestSpillBudget(t)
+		budget.Limit = 4 * rowSize(lhs.Rows[0])
+
+		jn := newJoin(lhs, sqltypes.MakeTestResult(rfields, "1"))
+		_, err := wrapStreamExecute(jn, &loggingVCursor{spillBudget: budget}, nil, true)
+		require.ErrorContains(t, err, "hash join partition exceeds the spill memory budget")
+	})
+}
+
+func TestOrderedAggregateSpill(t *testing.T) {
+	fields := sqltypes.MakeTestFields("col|sum(x)", "varbinary|decimal")
+	fp := &fakePrimitive{
+		allResultsInOneCall: true,
+		results: []*sqltypes.Result{
+			sqltypes.MakeTestResult(fields, "a|1", "a|2"),
+			sqltypes.MakeTestResult(fields, "b|1", "c|2", "c|3"),
+		},
+	}
+	oa := &OrderedAggregate{
+		Aggregates:  []*AggregateParams{NewAggregateParam(AggregateSum, 1, "", collations.MySQL8())},
+		GroupByKeys: []*GroupByParams{{KeyCol: 0}},
+		Input: &MemorySort{
+			OrderBy: []evalengine.OrderByParams{{WeightStringCol: -1, Col: 0}},
+			Input:   fp,
+		},
+	}
+
+	budget, spilled := newTestSpillBudget(t)
+	result, err := oa.TryExecute(context.Background(), &loggingVCursor{spillBudget: budget}, nil, true)
+	require.NoError(t, err)
+	assert.Equal(t, `[[VARBINARY("a") DECIMAL(3)] [VARBINARY("b") DECIMAL(1)] [VARBINARY("c") DECIMAL(5)]]`, fmt.Sprintf("%v", result.Rows))
+	assert.NotZero(t, spilled())
+}
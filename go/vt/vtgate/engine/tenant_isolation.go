@@ -0,0 +1,153 @@
+/*
+Copyright 2025 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package engine
+
+import (
+	"context"
+	"maps"
+	"strconv"
+
+	"vitess.io/vitess/go/sqltypes"
+	querypb "vitess.io/vitess/go/vt/proto/query"
+	vtrpcpb "vitess.io/vitess/go/vt/proto/vtrpc"
+	"vitess.io/vitess/go/vt/sqlparser"
+	"vitess.io/vitess/go/vt/vterrors"
+	"vitess.io/vitess/go/vt/vtgate/evalengine"
+)
+
+var _ Primitive = (*TenantIsolation)(nil)
+
+// TenantIsolation restricts its input to the tenant of the session. The
+// planner adds a predicate on the tenant id column of every multi-tenant
+// table to the input, comparing it to the TenantIDName bind variable, which
+// this primitive sets before executing the input.
+type TenantIsolation struct {
+	// Type is the type of the tenant id column, either INT64 or VARCHAR.
+	Type querypb.Type
+
+	// Values are the tenant ids written by an INSERT. They must all be the
+	// tenant of the session.
+	Values []evalengine.Expr
+
+	Input Primitive
+}
+
+// TryExecute implements the Primitive interface
+func (t *TenantIsolation) TryExecute(ctx context.Context, vcursor VCursor, bindVars map[string]*querypb.BindVariable, wantfields bool) (*sqltypes.Result, error) {
+	bindVars, err := t.bindTenant(ctx, vcursor, bindVars)
+	if err != nil {
+		return nil, err
+	}
+	return vcursor.ExecutePrimitive(ctx, t.Input, bindVars, wantfields)
+}
+
+// TryStreamExecute implements the Primitive interface
+func (t *TenantIsolation) TryStreamExecute(ctx context.Context, vcursor VCursor, bindVars map[string]*querypb.BindVariable, wantfields bool, callback func(*sqltypes.Result) error) error {
+	bindVars, err := t.bindTenant(ctx, vcursor, bindVars)
+	if err != nil {
+		return err
+	}
+	return vcursor.StreamExecutePrimitive(ctx, t.Input, bindVars, wantfields, callback)
+}
+
+// GetFields implements the Primitive interface
+func (t *TenantIsolation) GetFields(ctx context.Context, vcursor VCursor, bindVars map[string]*querypb.BindVariable) (*sqltypes.Result, error) {
+	bindVars, err := t.bindTenant(ctx, vcursor, bindVars)
+	if err != nil {
+		return nil, err
+	}
+	return t.Input.GetFields(ctx, vcursor, bindVars)
+}
+
+// NeedsTransaction implements the Primitive interface
+func (t *TenantIsolation) NeedsTransaction() bool {
+	return t.Input.NeedsTransaction()
+}
+
+// Inputs implements the Primitive interface
+func (t *TenantIsolation) Inputs() ([]Primitive, []map[string]any) {
+	return []Primitive{t.Input}, nil
+}
+
+// description implements the Primitive interface
+func (t *TenantIsolation) description() PrimitiveDescription {
+	other := map[string]any{
+		"TenantIdType": t.Type.String(),
+	}
+	if len(t.Values) > 0 {
+		values := make([]string, 0, len(t.Values))
+		for _, v := range t.Values {
+			values = append(values, sqlparser.String(v))
+		}
+		other["Values"] = values
+	}
+	return PrimitiveDescription{
+		OperatorType: "TenantIsolation",
+		Other:        other,
+	}
+}
+
+// bindTenant returns a copy of the bind variables with the tenant of the
+// session, after checking that the written tenant ids belong to it.
+func (t *TenantIsolation) bindTenant(ctx context.Context, vcursor VCursor, bindVars map[string]*querypb.BindVariable) (map[string]*querypb.BindVariable, error) {
+	tenantID := vcursor.Session().GetTenantID(ctx)
+	if tenantID == "" {
+		return nil, vterrors.Errorf(vtrpcpb.Code_PERMISSION_DENIED, "tenant id is not set for the session, it is required to query multi-tenant tables")
+	}
+
+	var tenant sqltypes.Value
+	switch t.Type {
+	case querypb.Type_INT64:
+		id, err := strconv.ParseInt(tenantID, 10, 64)
+		if err != nil {
+			return nil, vterrors.Errorf(vtrpcpb.Code_INVALID_ARGUMENT, "tenant id is not a valid int: %s", tenantID)
+		}
+		tenant = sqltypes.NewInt64(id)
+	default:
+		tenant = sqltypes.NewVarChar(tenantID)
+	}
+
+	if len(t.Values) > 0 {
+		env := evalengine.NewExpressionEnv(ctx, bindVars, vcursor)
+		for _, expr := range t.Values {
+			res, err := env.Evaluate(expr)
+			if err != nil {
+				return nil, err
+			}
+			value := res.Value(vcursor.ConnCollation())
+			if !t.sameTenant(value, tenant) {
+				return nil, vterrors.Errorf(vtrpcpb.Code_PERMISSION_DENIED, "cannot write rows of tenant %s, the session is restricted to tenant %s", value.ToString(), tenantID)
+			}
+		}
+	}
+
+	out := make(map[string]*querypb.BindVariable, len(bindVars)+1)
+	maps.Copy(out, bindVars)
+	out[sqlparser.TenantIDName] = sqltypes.ValueBindVariable(tenant)
+	return out, nil
+}
+
+func (t *TenantIsolation) sameTenant(value, tenant sqltypes.Value) bool {
+	if value.IsNull() {
+		return false
+	}
+	if t.Type == querypb.Type_INT64 {
+		id, err := value.ToInt64()
+		return err == nil && strconv.FormatInt(id, 10) == tenant.ToString()
+	}
+	return value.ToString() == tenant.ToString()
+}
@@ -0,0 +1,105 @@
+/*
+Copyright 2025 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package engine
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"vitess.io/vitess/go/sqltypes"
+	querypb "vitess.io/vitess/go/vt/proto/query"
+	"vitess.io/vitess/go/vt/vtgate/evalengine"
+)
+
+func TestTenantIsolationBindsTenant(t *testing.T) {
+	result := sqltypes.MakeTestResult(sqltypes.MakeTestFields("id", "int64"), "1")
+
+	tcases := []struct {
+		name     string
+		colType  querypb.Type
+		tenantID string
+		log      []string
+		err      string
+	}{{
+		name:     "int tenant",
+		colType:  querypb.Type_INT64,
+		tenantID: "42",
+		log:      []string{`Execute __vttenant_id: type:INT64 value:"42" a: type:INT64 value:"1" true`},
+	}, {
+		name:     "varchar tenant",
+		colType:  querypb.Type_VARCHAR,
+		tenantID: "acme",
+		log:      []string{`Execute __vttenant_id: type:VARCHAR value:"acme" a: type:INT64 value:"1" true`},
+	}, {
+		name:    "tenant not set",
+		colType: querypb.Type_INT64,
+		err:     "tenant id is not set for the session",
+	}, {
+		name:     "invalid int tenant",
+		colType:  querypb.Type_INT64,
+		tenantID: "acme",
+		err:      "tenant id is not a valid int: acme",
+	}}
+	for _, tc := range tcases {
+		t.Run(tc.name, func(t *testing.T) {
+			input := &fakePrimitive{results: []*sqltypes.Result{result}}
+			ti := &TenantIsolation{Type: tc.colType, Input: input}
+			vc := &loggingVCursor{tenantID: tc.tenantID}
+			bv := map[string]*querypb.BindVariable{"a": sqltypes.Int64BindVariable(1)}
+
+			qr, err := ti.TryExecute(context.Background(), vc, bv, true)
+			if tc.err != "" {
+				require.ErrorContains(t, err, tc.err)
+				input.ExpectLog(t, nil)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, result, qr)
+			input.ExpectLog(t, tc.log)
+			assert.Len(t, bv, 1, "the bind variables of the caller must not be changed")
+		})
+	}
+}
+
+func TestTenantIsolationChecksValues(t *testing.T) {
+	ti := &TenantIsolation{
+		Type: querypb.Type_INT64,
+		Values: []evalengine.Expr{
+			evalengine.NewLiteralInt(42),
+			evalengine.NewBindVar("v1", evalengine.NewType(sqltypes.Int64, 0)),
+		},
+		Input: &fakePrimitive{results: []*sqltypes.Result{{RowsAffected: 2}, {RowsAffected: 2}}},
+	}
+	vc := &loggingVCursor{tenantID: "42"}
+
+	_, err := ti.TryExecute(context.Background(), vc, map[string]*querypb.BindVariable{"v1": sqltypes.Int64BindVariable(42)}, false)
+	require.NoError(t, err)
+
+	_, err = ti.TryExecute(context.Background(), vc, map[string]*querypb.BindVariable{"v1": sqltypes.Int64BindVariable(7)}, false)
+	require.ErrorContains(t, err, "cannot write rows of tenant 7, the session is restricted to tenant 42")
+
+	_, err = ti.TryExecute(context.Background(), vc, map[string]*querypb.BindVariable{"v1": sqltypes.NullBindVariable}, false)
+	require.ErrorContains(t, err, "cannot write rows of tenant")
+
+	err = ti.TryStreamExecute(context.Background(), vc, map[string]*querypb.BindVariable{"v1": sqltypes.Int64BindVariable(7)}, false, func(*sqltypes.Result) error {
+		return nil
+	})
+	require.ErrorContains(t, err, "cannot write rows of tenant 7")
+}
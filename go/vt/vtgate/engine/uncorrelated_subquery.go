@@ -111,27 +111,35 @@ func (ps *UncorrelatedSubquery) execSubquery(ctx context.Context, vcursor VCurso
 	for k, v := range bindVars {
 		combinedVars[k] = v
 	}
-	switch ps.Opcode {
+	if err := bindSubqueryResult(ps.Opcode, result, ps.SubqueryResult, ps.HasValues, combinedVars); err != nil {
+		return nil, err
+	}
+	return combinedVars, nil
+}
+
+// bindSubqueryResult adds the bind variables holding the result of a subquery to bindVars
+func bindSubqueryResult(op opcode.PulloutOpcode, result *sqltypes.Result, subqueryResult, hasValues string, bindVars map[string]*querypb.BindVariable) error {
+	switch op {
 	case opcode.PulloutValue:
 		switch len(result.Rows) {
 		case 0:
-			combinedVars[ps.SubqueryResult] = sqltypes.NullBindVariable
+			bindVars[subqueryResult] = sqltypes.NullBindVariable
 		case 1:
-			combinedVars[ps.SubqueryResult] = sqltypes.ValueBindVariable(result.Rows[0][0])
+			bindVars[subqueryResult] = sqltypes.ValueBindVariable(result.Rows[0][0])
 		default:
-			return nil, errSqRow
+			return errSqRow
 		}
 	case opcode.PulloutIn, opcode.PulloutNotIn:
 		switch len(result.Rows) {
 		case 0:
-			combinedVars[ps.HasValues] = sqltypes.Int64BindVariable(0)
+			bindVars[hasValues] = sqltypes.Int64BindVariable(0)
 			// Add a bogus value. It will not be checked.
-			combinedVars[ps.SubqueryResult] = &querypb.BindVariable{
+			bindVars[subqueryResult] = &querypb.BindVariable{
 				Type:   querypb.Type_TUPLE,
 				Values: []*querypb.Value{sqltypes.ValueToProto(sqltypes.NewInt64(0))},
 			}
 		default:
-			combinedVars[ps.HasValues] = sqltypes.Int64BindVariable(1)
+			bindVars[hasValues] = sqltypes.Int64BindVariable(1)
 			values := &querypb.BindVariable{
 				Type:   querypb.Type_TUPLE,
 				Values: make([]*querypb.Value, len(result.Rows)),
@@ -139,17 +147,17 @@ func (ps *UncorrelatedSubquery) execSubquery(ctx context.Context, vcursor VCurso
 			for i, v := range result.Rows {
 				values.Values[i] = sqltypes.ValueToProto(v[0])
 			}
-			combinedVars[ps.SubqueryResult] = values
+			bindVars[subqueryResult] = values
 		}
 	case opcode.PulloutExists:
 		switch len(result.Rows) {
 		case 0:
-			combinedVars[ps.HasValues] = sqltypes.Int64BindVariable(0)
+			bindVars[hasValues] = sqltypes.Int64BindVariable(0)
 		default:
-			combinedVars[ps.HasValues] = sqltypes.Int64BindVariable(1)
+			bindVars[hasValues] = sqltypes.Int64BindVariable(1)
 		}
 	}
-	return combinedVars, nil
+	return nil
 }
 
 func (ps *UncorrelatedSubquery) description() PrimitiveDescription {
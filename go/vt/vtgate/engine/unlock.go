@@ -39,6 +39,21 @@ func (u *Unlock) GetFields(ctx context.Context, vcursor VCursor, bindVars map[st
 }
 
 func (u *Unlock) TryExecute(ctx context.Context, vcursor VCursor, bindVars map[string]*querypb.BindVariable, wantfields bool) (*sqltypes.Result, error) {
+	// same as MySQL, UNLOCK TABLES commits the open transaction if the session holds table locks
+	if vcursor.Session().InLockTables() {
+		if err := vcursor.Session().Commit(ctx); err != nil {
+			return nil, err
+		}
+	}
+	qr, err := execUnlockTables(ctx, vcursor, u)
+	if err != nil {
+		return nil, err
+	}
+	return qr, vcursor.ReleaseLockTables(ctx)
+}
+
+// execUnlockTables sends unlock tables to all the connections in the session.
+func execUnlockTables(ctx context.Context, vcursor VCursor, primitive Primitive) (*sqltypes.Result, error) {
 	rss := vcursor.Session().ShardSession()
 
 	if len(rss) == 0 {
@@ -48,7 +63,7 @@ func (u *Unlock) TryExecute(ctx context.Context, vcursor VCursor, bindVars map[s
 	for i := 0; i < len(rss); i++ {
 		bqs[i] = &querypb.BoundQuery{Sql: unlockTables}
 	}
-	qr, errs := vcursor.ExecuteMultiShard(ctx, u, rss, bqs, true, false, false)
+	qr, errs := vcursor.ExecuteMultiShard(ctx, primitive, rss, bqs, true, false, false)
 	return qr, vterrors.Aggregate(errs)
 }
 
@@ -0,0 +1,481 @@
+/*
+Copyright 2025 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package engine
+
+import (
+	"context"
+	"fmt"
+	"sync"
+
+	"vitess.io/vitess/go/sqltypes"
+	querypb "vitess.io/vitess/go/vt/proto/query"
+	vtrpcpb "vitess.io/vitess/go/vt/proto/vtrpc"
+	"vitess.io/vitess/go/vt/sqlparser"
+	"vitess.io/vitess/go/vt/vterrors"
+	"vitess.io/vitess/go/vt/vtgate/engine/opcode"
+	"vitess.io/vitess/go/vt/vtgate/evalengine"
+)
+
+var _ Primitive = (*Window)(nil)
+
+// Window is a primitive that evaluates window functions on the vtgate.
+// It expects the underlying primitive to feed rows sorted by the
+// PartitionBy keys followed by the OrderBy keys, which is normally
+// achieved by a merge sort over a scatter route.
+// For every input row, one column per window function is added in
+// front of the input columns. All window functions share the same
+// window specification and use the default frame:
+// the whole partition without ORDER BY, and all rows up to and
+// including the peers of the current row with ORDER BY.
+type Window struct {
+	// PartitionBy specifies the columns that define the window partitions.
+	PartitionBy []*GroupByParams
+
+	// OrderBy specifies the columns used to find the peers of a row inside a partition.
+	OrderBy []*GroupByParams
+
+	// Funcs contains the window functions to evaluate.
+	Funcs []*WindowFuncParams
+
+	// Input is the primitive that will feed into this Primitive.
+	Input Primitive
+}
+
+// WindowFuncParams specify the parameters for each window function.
+type WindowFuncParams struct {
+	Opcode opcode.WindowOpcode
+
+	// Col is the input column holding the argument of the function, or -1 if the function has no argument.
+	Col int
+
+	// N is the offset used by LAG/LEAD, the number of buckets for NTILE and the row number for NTH_VALUE.
+	// It is a literal or a bind variable, evaluated once per execution.
+	N evalengine.Expr
+
+	// DefaultCol is the input column holding the default value for LAG/LEAD, or -1 if there is none.
+	DefaultCol int
+
+	// Aggregate is used when Opcode is WindowAggregate.
+	Aggregate *AggregateParams
+
+	Alias string
+	Expr  sqlparser.Expr
+}
+
+// String returns a string. Used for plan descriptions
+func (wf *WindowFuncParams) String() string {
+	var out string
+	switch wf.Opcode {
+	case opcode.WindowAggregate:
+		out = wf.Aggregate.String()
+	case opcode.WindowRowNumber, opcode.WindowRank, opcode.WindowDenseRank, opcode.WindowPercentRank, opcode.WindowCumeDist:
+		out = wf.Opcode.String() + "()"
+	case opcode.WindowNtile:
+		out = fmt.Sprintf("%s(%s)", wf.Opcode.String(), sqlparser.String(wf.N))
+	case opcode.WindowLag, opcode.WindowLead:
+		if wf.DefaultCol >= 0 {
+			out = fmt.Sprintf("%s(%d, %s, %d)", wf.Opcode.String(), wf.Col, sqlparser.String(wf.N), wf.DefaultCol)
+		} else {
+			out = fmt.Sprintf("%s(%d, %s)", wf.Opcode.String(), wf.Col, sqlparser.String(wf.N))
+		}
+	case opcode.WindowNthValue:
+		out = fmt.Sprintf("%s(%d, %s)", wf.Opcode.String(), wf.Col, sqlparser.String(wf.N))
+	default:
+		out = fmt.Sprintf("%s(%d)", wf.Opcode.String(), wf.Col)
+	}
+	if wf.Alias != "" && wf.Opcode != opcode.WindowAggregate {
+		out += " AS " + wf.Alias
+	}
+	return out
+}
+
+// TryExecute is a Primitive function.
+func (w *Window) TryExecute(ctx context.Context, vcursor VCursor, bindVars map[string]*querypb.BindVariable, _ bool) (*sqltypes.Result, error) {
+	args, err := w.evalArgs(ctx, vcursor, bindVars)
+	if err != nil {
+		return nil, err
+	}
+	result, err := vcursor.ExecutePrimitive(ctx, w.Input, bindVars, true)
+	if err != nil {
+		return nil, err
+	}
+
+	fields, err := w.fields(result.Fields)
+	if err != nil {
+		return nil, err
+	}
+	out := &sqltypes.Result{
+		Fields: fields,
+		Rows:   make([]sqltypes.Row, 0, len(result.Rows)),
+	}
+
+	start := 0
+	for i := 1; i <= len(result.Rows); i++ {
+		if i < len(result.Rows) {
+			same, err := sameKeys(w.PartitionBy, result.Rows[start], result.Rows[i])
+			if err != nil {
+				return nil, err
+			}
+			if same {
+				continue
+			}
+		}
+		rows, err := w.evalPartition(result.Fields, result.Rows[start:i], args)
+		if err != nil {
+			return nil, err
+		}
+		out.Rows = append(out.Rows, rows...)
+		start = i
+	}
+	return out, nil
+}
+
+// TryStreamExecute is a Primitive function.
+func (w *Window) TryStreamExecute(ctx context.Context, vcursor VCursor, bindVars map[string]*querypb.BindVariable, _ bool, callback func(*sqltypes.Result) error) error {
+	args, err := w.evalArgs(ctx, vcursor, bindVars)
+	if err != nil {
+		return err
+	}
+
+	var mu sync.Mutex
+	var inputFields []*querypb.Field
+	var partition []sqltypes.Row
+
+	flush := func() error {
+		if len(partition) == 0 {
+			return nil
+		}
+		rows, err := w.evalPartition(inputFields, partition, args)
+		if err != nil {
+			return err
+		}
+		partition = nil
+		return callback(&sqltypes.Result{Rows: rows})
+	}
+
+	err = vcursor.StreamExecutePrimitive(ctx, w.Input, bindVars, true, func(qr *sqltypes.Result) error {
+		mu.Lock()
+		defer mu.Unlock()
+		if inputFields == nil && len(qr.Fields) > 0 {
+			inputFields = qr.Fields
+			fields, err := w.fields(inputFields)
+			if err != nil {
+				return err
+			}
+			if err := callback(&sqltypes.Result{Fields: fields}); err != nil {
+				return err
+			}
+		}
+		for _, row := range qr.Rows {
+			if len(partition) > 0 {
+				same, err := sameKeys(w.PartitionBy, partition[0], row)
+				if err != nil {
+					return err
+				}
+				if !same {
+					if err := flush(); err != nil {
+						return err
+					}
+				}
+			}
+			partition = append(partition, row)
+		}
+		if vcursor.ExceedsMaxMemoryRows(len(partition)) {
+			return fmt.Errorf("in-memory row count exceeded allowed limit of %d", vcursor.MaxMemoryRows())
+		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+	return flush()
+}
+
+// GetFields is a Primitive function.
+func (w *Window) GetFields(ctx context.Context, vcursor VCursor, bindVars map[string]*querypb.BindVariable) (*sqltypes.Result, error) {
+	qr, err := w.Input.GetFields(ctx, vcursor, bindVars)
+	if err != nil {
+		return nil, err
+	}
+	fields, err := w.fields(qr.Fields)
+	if err != nil {
+		return nil, err
+	}
+	return &sqltypes.Result{Fields: fields}, nil
+}
+
+// Inputs returns the Primitive input for this window
+func (w *Window) Inputs() ([]Primitive, []map[string]any) {
+	return []Primitive{w.Input}, nil
+}
+
+// NeedsTransaction implements the Primitive interface
+func (w *Window) NeedsTransaction() bool {
+	return w.Input.NeedsTransaction()
+}
+
+func (w *Window) fields(input []*querypb.Field) ([]*querypb.Field, error) {
+	if input == nil {
+		return nil, nil
+	}
+	out := make([]*querypb.Field, 0, len(w.Funcs)+len(input))
+	for _, wf := range w.Funcs {
+		var field *querypb.Field
+		if wf.Col >= 0 {
+			field = input[wf.Col].CloneVT()
+		} else {
+			field = &querypb.Field{}
+		}
+		if wf.Opcode == opcode.WindowAggregate {
+			field.Type = wf.Aggregate.typ(field.Type)
+		} else {
+			field.Type = wf.Opcode.SQLType(field.Type)
+		}
+		field.Name = wf.Alias
+		out = append(out, field)
+	}
+	return append(out, input...), nil
+}
+
+// evalArgs evaluates the N argument of every window function, which is a bind
+// variable when the query was normalized or prepared.
+func (w *Window) evalArgs(ctx context.Context, vcursor VCursor, bindVars map[string]*querypb.BindVariable) ([]int, error) {
+	env := evalengine.NewExpressionEnv(ctx, bindVars, vcursor)
+	args := make([]int, len(w.Funcs))
+	for idx, wf := range w.Funcs {
+		if wf.N == nil {
+			continue
+		}
+		evalResult, err := env.Evaluate(wf.N)
+		if err != nil {
+			return nil, err
+		}
+		value := evalResult.Value(vcursor.ConnCollation())
+		// LAG and LEAD accept an offset of zero, which returns the current row.
+		minimum := 1
+		if wf.Opcode == opcode.WindowLag || wf.Opcode == opcode.WindowLead {
+			minimum = 0
+		}
+		n, err := value.ToInt()
+		if !value.IsIntegral() || err != nil || n < minimum {
+			return nil, vterrors.NewErrorf(vtrpcpb.Code_INVALID_ARGUMENT, vterrors.WrongArguments, "Incorrect arguments to %s", wf.Opcode.String())
+		}
+		args[idx] = n
+	}
+	return args, nil
+}
+
+// evalPartition calculates the window functions for all the rows of a single partition,
+// with the N arguments of the functions returned by evalArgs
+func (w *Window) evalPartition(fields []*querypb.Field, rows []sqltypes.Row, args []int) ([]sqltypes.Row, error) {
+	// peerEnd[i] is the index of the last peer of row i
+	peerEnd := make([]int, len(rows))
+	// peerStart[i] is the index of the first peer of row i
+	peerStart := make([]int, len(rows))
+	// peerGroup[i] is the number of the peer group row i belongs to, starting at 1
+	peerGroup := make([]int, len(rows))
+
+	start, group := 0, 1
+	for i := 1; i <= len(rows); i++ {
+		if i < len(rows) {
+			same, err := sameKeys(w.OrderBy, rows[start], rows[i])
+			if err != nil {
+				return nil, err
+			}
+			if same {
+				continue
+			}
+		}
+		for j := start; j < i; j++ {
+			peerStart[j] = start
+			peerEnd[j] = i - 1
+			peerGroup[j] = group
+		}
+		start = i
+		group++
+	}
+
+	out := make([]sqltypes.Row, len(rows))
+	for i, row := range rows {
+		outRow := make(sqltypes.Row, len(w.Funcs), len(w.Funcs)+len(row))
+		out[i] = append(outRow, row...)
+	}
+
+	n := len(rows)
+	for idx, wf := range w.Funcs {
+		switch wf.Opcode {
+		case opcode.WindowRowNumber:
+			for i := range rows {
+				out[i][idx] = sqltypes.NewUint64(uint64(i + 1))
+			}
+		case opcode.WindowRank:
+			for i := range rows {
+				out[i][idx] = sqltypes.NewUint64(uint64(peerStart[i] + 1))
+			}
+		case opcode.WindowDenseRank:
+			for i := range rows {
+				out[i][idx] = sqltypes.NewUint64(uint64(peerGroup[i]))
+			}
+		case opcode.WindowPercentRank:
+			for i := range rows {
+				var pr float64
+				if n > 1 {
+					pr = float64(peerStart[i]) / float64(n-1)
+				}
+				out[i][idx] = sqltypes.NewFloat64(pr)
+			}
+		case opcode.WindowCumeDist:
+			for i := range rows {
+				out[i][idx] = sqltypes.NewFloat64(float64(peerEnd[i]+1) / float64(n))
+			}
+		case opcode.WindowNtile:
+			for i := range rows {
+				out[i][idx] = sqltypes.NewUint64(uint64(ntileBucket(i, n, args[idx])))
+			}
+		case opcode.WindowLag, opcode.WindowLead:
+			for i, row := range rows {
+				from := i - args[idx]
+				if wf.Opcode == opcode.WindowLead {
+					from = i + args[idx]
+				}
+				switch {
+				case from >= 0 && from < n:
+					out[i][idx] = rows[from][wf.Col]
+				case wf.DefaultCol >= 0:
+					out[i][idx] = row[wf.DefaultCol]
+				default:
+					out[i][idx] = sqltypes.NULL
+				}
+			}
+		case opcode.WindowFirstValue:
+			for i := range rows {
+				out[i][idx] = rows[0][wf.Col]
+			}
+		case opcode.WindowLastValue:
+			for i := range rows {
+				out[i][idx] = rows[peerEnd[i]][wf.Col]
+			}
+		case opcode.WindowNthValue:
+			for i := range rows {
+				if nth := args[idx]; nth-1 <= peerEnd[i] {
+					out[i][idx] = rows[nth-1][wf.Col]
+				} else {
+					out[i][idx] = sqltypes.NULL
+				}
+			}
+		case opcode.WindowAggregate:
+			if err := w.evalAggregate(fields, rows, peerEnd, out, idx, wf); err != nil {
+				return nil, err
+			}
+		default:
+			return nil, fmt.Errorf("BUG: unexpected window function opcode: %s", wf.Opcode.String())
+		}
+	}
+	return out, nil
+}
+
+// evalAggregate calculates a running aggregation over the peer groups of the partition
+func (w *Window) evalAggregate(fields []*querypb.Field, rows []sqltypes.Row, peerEnd []int, out []sqltypes.Row, idx int, wf *WindowFuncParams) error {
+	aggr := wf.Aggregate
+	var sourceType sqltypes.Type
+	if aggr.Col >= 0 && aggr.Col < len(fields) {
+		sourceType = fields[aggr.Col].Type
+	}
+	ag, err := newAggregator(nil, aggr, sourceType, aggr.typ(sourceType))
+	if err != nil {
+		return err
+	}
+
+	for i := 0; i < len(rows); {
+		end := peerEnd[i]
+		for j := i; j <= end; j++ {
+			if err := ag.add(rows[j]); err != nil {
+				return err
+			}
+		}
+		result, err := ag.finish()
+		if err != nil {
+			return err
+		}
+		for j := i; j <= end; j++ {
+			out[j][idx] = result
+		}
+		i = end + 1
+	}
+	return nil
+}
+
+// ntileBucket returns the bucket number for row i out of n rows divided into buckets.
+// Like MySQL, when the rows can't be divided evenly, the first buckets get one extra row.
+func ntileBucket(i, n, buckets int) int {
+	if buckets <= 0 {
+		return 0
+	}
+	size, extra := n/buckets, n%buckets
+	large := extra * (size + 1)
+	if i < large {
+		return i/(size+1) + 1
+	}
+	return extra + (i-large)/size + 1
+}
+
+// sameKeys returns true if the two rows have equal values in all the key columns
+func sameKeys(keys []*GroupByParams, a, b sqltypes.Row) (bool, error) {
+	for _, key := range keys {
+		v1 := a[key.KeyCol]
+		v2 := b[key.KeyCol]
+		if v1.TinyWeightCmp(v2) != 0 {
+			return false, nil
+		}
+
+		cmp, err := evalengine.NullsafeCompare(v1, v2, key.CollationEnv, key.Type.Collation(), key.Type.Values())
+		if err != nil {
+			_, isCollationErr := err.(evalengine.UnsupportedCollationError)
+			if !isCollationErr || key.WeightStringCol == -1 {
+				return false, err
+			}
+			cmp, err = evalengine.NullsafeCompare(a[key.WeightStringCol], b[key.WeightStringCol], key.CollationEnv, key.Type.Collation(), key.Type.Values())
+			if err != nil {
+				return false, err
+			}
+		}
+		if cmp != 0 {
+			return false, nil
+		}
+	}
+	return true, nil
+}
+
+func windowFuncParamsToString(i any) string {
+	return i.(*WindowFuncParams).String()
+}
+
+func (w *Window) description() PrimitiveDescription {
+	other := map[string]any{
+		"Functions": GenericJoin(w.Funcs, windowFuncParamsToString),
+	}
+	if len(w.PartitionBy) > 0 {
+		other["PartitionBy"] = GenericJoin(w.PartitionBy, groupByParamsToString)
+	}
+	if len(w.OrderBy) > 0 {
+		other["OrderBy"] = GenericJoin(w.OrderBy, groupByParamsToString)
+	}
+	return PrimitiveDescription{
+		OperatorType: "Window",
+		Other:        other,
+	}
+}
@@ -0,0 +1,180 @@
+/*
+Copyright 2025 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package engine
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"vitess.io/vitess/go/mysql/collations"
+	"vitess.io/vitess/go/sqltypes"
+	"vitess.io/vitess/go/test/utils"
+	querypb "vitess.io/vitess/go/vt/proto/query"
+	. "vitess.io/vitess/go/vt/vtgate/engine/opcode"
+	"vitess.io/vitess/go/vt/vtgate/evalengine"
+)
+
+func newTestWindow(input Primitive) *Window {
+	env := collations.MySQL8()
+	return &Window{
+		PartitionBy: []*GroupByParams{{KeyCol: 0, WeightStringCol: -1, CollationEnv: env}},
+		OrderBy:     []*GroupByParams{{KeyCol: 1, WeightStringCol: -1, CollationEnv: env}},
+		Funcs: []*WindowFuncParams{
+			{Opcode: WindowRowNumber, Col: -1, DefaultCol: -1, Alias: "rn"},
+			{Opcode: WindowRank, Col: -1, DefaultCol: -1, Alias: "rnk"},
+			{Opcode: WindowDenseRank, Col: -1, DefaultCol: -1, Alias: "drnk"},
+			{Opcode: WindowAggregate, Col: 2, DefaultCol: -1, Alias: "total", Aggregate: NewAggregateParam(AggregateSum, 2, "total", env)},
+			{Opcode: WindowLag, Col: 2, N: evalengine.NewLiteralInt(1), DefaultCol: -1, Alias: "prev"},
+		},
+		Input: input,
+	}
+}
+
+func TestWindowExecute(t *testing.T) {
+	fields := sqltypes.MakeTestFields(
+		"k|v|x",
+		"varbinary|int64|int64",
+	)
+	fp := &fakePrimitive{
+		results: []*sqltypes.Result{sqltypes.MakeTestResult(
+			fields,
+			"a|1|10",
+			"a|1|20",
+			"a|2|30",
+			"b|5|40",
+		)},
+	}
+
+	w := newTestWindow(fp)
+	result, err := w.TryExecute(context.Background(), &noopVCursor{}, nil, true)
+	require.NoError(t, err)
+
+	wantResult := sqltypes.MakeTestResult(
+		sqltypes.MakeTestFields(
+			"rn|rnk|drnk|total|prev|k|v|x",
+			"uint64|uint64|uint64|decimal|int64|varbinary|int64|int64",
+		),
+		"1|1|1|30|null|a|1|10",
+		"2|1|1|30|10|a|1|20",
+		"3|3|2|60|20|a|2|30",
+		"1|1|1|40|null|b|5|40",
+	)
+	utils.MustMatch(t, wantResult, result)
+}
+
+func TestWindowStreamExecute(t *testing.T) {
+	fields := sqltypes.MakeTestFields(
+		"k|v|x",
+		"varbinary|int64|int64",
+	)
+	fp := &fakePrimitive{
+		allResultsInOneCall: true,
+		results: sqltypes.MakeTestStreamingResults(
+			fields,
+			"a|1|10",
+			"a|1|20",
+			"---",
+			"a|2|30",
+			"b|5|40",
+		),
+	}
+
+	w := newTestWindow(fp)
+	var results []*sqltypes.Result
+	err := w.TryStreamExecute(context.Background(), &noopVCursor{}, nil, true, func(qr *sqltypes.Result) error {
+		results = append(results, qr)
+		return nil
+	})
+	require.NoError(t, err)
+
+	wantResults := sqltypes.MakeTestStreamingResults(
+		sqltypes.MakeTestFields(
+			"rn|rnk|drnk|total|prev|k|v|x",
+			"uint64|uint64|uint64|decimal|int64|varbinary|int64|int64",
+		),
+		"1|1|1|30|null|a|1|10",
+		"2|1|1|30|10|a|1|20",
+		"3|3|2|60|20|a|2|30",
+		"---",
+		"1|1|1|40|null|b|5|40",
+	)
+	utils.MustMatch(t, wantResults, results)
+}
+
+func TestWindowBindVarArgs(t *testing.T) {
+	fields := sqltypes.MakeTestFields(
+		"k|v|x",
+		"varbinary|int64|int64",
+	)
+	input := sqltypes.MakeTestResult(
+		fields,
+		"a|1|10",
+		"a|2|20",
+		"a|3|30",
+	)
+	intType := evalengine.NewType(sqltypes.Int64, collations.CollationBinaryID)
+	w := &Window{
+		PartitionBy: []*GroupByParams{{KeyCol: 0, WeightStringCol: -1, CollationEnv: collations.MySQL8()}},
+		OrderBy:     []*GroupByParams{{KeyCol: 1, WeightStringCol: -1, CollationEnv: collations.MySQL8()}},
+		Funcs: []*WindowFuncParams{
+			{Opcode: WindowNtile, Col: -1, N: evalengine.NewBindVar("buckets", intType), DefaultCol: -1, Alias: "bucket"},
+			{Opcode: WindowLead, Col: 2, N: evalengine.NewBindVar("offset", intType), DefaultCol: -1, Alias: "next"},
+			{Opcode: WindowNthValue, Col: 2, N: evalengine.NewBindVar("nth", intType), DefaultCol: -1, Alias: "nth"},
+		},
+		Input: &fakePrimitive{results: []*sqltypes.Result{input}},
+	}
+
+	bindVars := map[string]*querypb.BindVariable{
+		"buckets": sqltypes.Int64BindVariable(2),
+		"offset":  sqltypes.Int64BindVariable(2),
+		"nth":     sqltypes.Int64BindVariable(2),
+	}
+	result, err := w.TryExecute(context.Background(), &noopVCursor{}, bindVars, true)
+	require.NoError(t, err)
+
+	wantResult := sqltypes.MakeTestResult(
+		sqltypes.MakeTestFields(
+			"bucket|next|nth|k|v|x",
+			"uint64|int64|int64|varbinary|int64|int64",
+		),
+		"1|30|null|a|1|10",
+		"1|null|20|a|2|20",
+		"2|null|20|a|3|30",
+	)
+	utils.MustMatch(t, wantResult, result)
+
+	// NTILE needs at least one bucket.
+	bindVars["buckets"] = sqltypes.Int64BindVariable(0)
+	_, err = w.TryExecute(context.Background(), &noopVCursor{}, bindVars, true)
+	require.EqualError(t, err, "Incorrect arguments to ntile")
+}
+
+func TestNtileBucket(t *testing.T) {
+	var buckets []int
+	for i := 0; i < 5; i++ {
+		buckets = append(buckets, ntileBucket(i, 5, 2))
+	}
+	require.Equal(t, []int{1, 1, 1, 2, 2}, buckets)
+
+	buckets = nil
+	for i := 0; i < 2; i++ {
+		buckets = append(buckets, ntileBucket(i, 2, 5))
+	}
+	require.Equal(t, []int{1, 2}, buckets)
+}
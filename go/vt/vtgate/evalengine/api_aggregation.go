@@ -17,6 +17,7 @@ limitations under the License.
 package evalengine
 
 import (
+	"math"
 	"strconv"
 
 	"vitess.io/vitess/go/mysql/collations"
@@ -24,6 +25,8 @@ import (
 	"vitess.io/vitess/go/mysql/fastparse"
 	"vitess.io/vitess/go/mysql/format"
 	"vitess.io/vitess/go/sqltypes"
+	vtrpcpb "vitess.io/vitess/go/vt/proto/vtrpc"
+	"vitess.io/vitess/go/vt/vterrors"
 )
 
 // Sum implements a SUM() aggregation
@@ -499,3 +502,169 @@ func NewAggregationMinMax(typ sqltypes.Type, collationEnv *collations.Environmen
 		return &aggregationMinMax{collation: collation, collationEnv: collationEnv, values: values}
 	}
 }
+
+// Bit implements a BIT_AND(), BIT_OR() or BIT_XOR() aggregation
+type Bit interface {
+	Add(value sqltypes.Value) error
+	Result() sqltypes.Value
+	Reset()
+}
+
+// aggregationBit implements the bitwise aggregations. Matching MySQL's behavior,
+// all the values are converted to 64-bit unsigned integers, NULL values are ignored,
+// and the result is always an UINT64, even if no values have been aggregated.
+// Since the bitwise operations are associative, the same aggregation can be
+// used to combine the partial results of different shards.
+type aggregationBit struct {
+	current uint64
+	initial uint64
+	op      func(a, b uint64) uint64
+}
+
+func (a *aggregationBit) Add(value sqltypes.Value) error {
+	if value.IsNull() {
+		return nil
+	}
+	n, err := valueToBitwiseUint64(value)
+	if err != nil {
+		return err
+	}
+	a.current = a.op(a.current, n)
+	return nil
+}
+
+func (a *aggregationBit) Result() sqltypes.Value {
+	return sqltypes.NewUint64(a.current)
+}
+
+func (a *aggregationBit) Reset() {
+	a.current = a.initial
+}
+
+func valueToBitwiseUint64(value sqltypes.Value) (uint64, error) {
+	switch tt := value.Type(); {
+	case sqltypes.IsUnsigned(tt):
+		return value.ToUint64()
+	case sqltypes.IsSigned(tt):
+		n, err := value.ToInt64()
+		return uint64(n), err
+	case tt == sqltypes.Bit:
+		var n uint64
+		for _, b := range value.Raw() {
+			n = n<<8 | uint64(b)
+		}
+		return n, nil
+	case tt == sqltypes.Geometry || tt == sqltypes.Vector:
+		return 0, vterrors.Errorf(vtrpcpb.Code_INVALID_ARGUMENT, "Incorrect arguments to bitwise aggregation: %s", tt.String())
+	}
+	e, err := valueToEval(value, collations.TypedCollation{}, nil)
+	if err != nil {
+		return 0, err
+	}
+	return uint64(evalToInt64(e).i), nil
+}
+
+func NewAggregationBitAnd() Bit {
+	return &aggregationBit{
+		current: math.MaxUint64,
+		initial: math.MaxUint64,
+		op:      func(a, b uint64) uint64 { return a & b },
+	}
+}
+
+func NewAggregationBitOr() Bit {
+	return &aggregationBit{op: func(a, b uint64) uint64 { return a | b }}
+}
+
+func NewAggregationBitXor() Bit {
+	return &aggregationBit{op: func(a, b uint64) uint64 { return a ^ b }}
+}
+
+// Variance implements the aggregation of the population variance, from which
+// VAR_POP(), VAR_SAMP(), STDDEV_POP() and STDDEV_SAMP() are calculated
+type Variance interface {
+	// Add adds a single value to the aggregation.
+	Add(value sqltypes.Value) error
+	// Merge adds the partial aggregation of a shard: the count, the sum and
+	// the population variance of its values.
+	Merge(count, sum, variance sqltypes.Value) error
+	Result() sqltypes.Value
+	Reset()
+}
+
+// aggregationVariance keeps the count, the mean and the sum of the squared
+// differences to the mean of the values. The partial aggregations are merged
+// with the parallel algorithm of Chan, Golub and LeVeque, which does not lose
+// precision when the values are large or far from zero, unlike a difference
+// between the sum of squares and the squared sum.
+type aggregationVariance struct {
+	count float64
+	mean  float64
+	m2    float64
+}
+
+func (a *aggregationVariance) Add(value sqltypes.Value) error {
+	if value.IsNull() {
+		return nil
+	}
+	f, err := valueToFloat64(value)
+	if err != nil {
+		return err
+	}
+	a.merge(1, f, 0)
+	return nil
+}
+
+func (a *aggregationVariance) Merge(count, sum, variance sqltypes.Value) error {
+	// a shard without values returns NULL for the variance
+	if count.IsNull() || sum.IsNull() || variance.IsNull() {
+		return nil
+	}
+	n, err := valueToFloat64(count)
+	if err != nil || n == 0 {
+		return err
+	}
+	s, err := valueToFloat64(sum)
+	if err != nil {
+		return err
+	}
+	v, err := valueToFloat64(variance)
+	if err != nil {
+		return err
+	}
+	a.merge(n, s/n, v*n)
+	return nil
+}
+
+func (a *aggregationVariance) merge(count, mean, m2 float64) {
+	total := a.count + count
+	delta := mean - a.mean
+	a.mean += delta * count / total
+	a.m2 += m2 + delta*delta*a.count*count/total
+	a.count = total
+}
+
+// Result returns the population variance, or NULL if no values have been aggregated.
+func (a *aggregationVariance) Result() sqltypes.Value {
+	if a.count == 0 {
+		return sqltypes.NULL
+	}
+	return sqltypes.NewFloat64(a.m2 / a.count)
+}
+
+func (a *aggregationVariance) Reset() {
+	*a = aggregationVariance{}
+}
+
+func valueToFloat64(value sqltypes.Value) (float64, error) {
+	e, err := valueToEval(value, collations.TypedCollation{}, nil)
+	if err != nil {
+		return 0, err
+	}
+	f, _ := evalToFloat(e)
+	return f.f, nil
+}
+
+func NewAggregationVariance() Variance {
+	return &aggregationVariance{}
+}
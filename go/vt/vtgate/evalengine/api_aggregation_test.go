@@ -17,6 +17,7 @@ limitations under the License.
 package evalengine
 
 import (
+	"math"
 	"strconv"
 	"testing"
 
@@ -170,3 +171,138 @@ func TestMinMax(t *testing.T) {
 		})
 	}
 }
+
+func TestBitAggregations(t *testing.T) {
+	tcases := []struct {
+		values       []sqltypes.Value
+		and, or, xor uint64
+	}{
+		{
+			values: []sqltypes.Value{},
+			and:    math.MaxUint64,
+		},
+		{
+			values: []sqltypes.Value{NULL, NULL},
+			and:    math.MaxUint64,
+		},
+		{
+			values: []sqltypes.Value{NewInt64(6), NULL, NewInt64(3)},
+			and:    2,
+			or:     7,
+			xor:    5,
+		},
+		{
+			values: []sqltypes.Value{NewInt64(-1), sqltypes.NewUint64(12)},
+			and:    12,
+			or:     math.MaxUint64,
+			xor:    math.MaxUint64 ^ 12,
+		},
+		{
+			values: []sqltypes.Value{sqltypes.NewVarChar("5"), sqltypes.NewFloat64(1.6), sqltypes.MakeTrusted(sqltypes.Bit, []byte{0x01, 0x00})},
+			and:    0,
+			or:     0x107,
+			xor:    0x107,
+		},
+	}
+	for i, tcase := range tcases {
+		t.Run(strconv.Itoa(i), func(t *testing.T) {
+			for _, agg := range []struct {
+				bit  Bit
+				want uint64
+			}{
+				{NewAggregationBitAnd(), tcase.and},
+				{NewAggregationBitOr(), tcase.or},
+				{NewAggregationBitXor(), tcase.xor},
+			} {
+				for _, v := range tcase.values {
+					require.NoError(t, agg.bit.Add(v))
+				}
+				utils.MustMatch(t, sqltypes.NewUint64(agg.want), agg.bit.Result())
+
+				agg.bit.Reset()
+				require.NoError(t, agg.bit.Add(NewInt64(1)))
+				utils.MustMatch(t, sqltypes.NewUint64(1), agg.bit.Result())
+			}
+		})
+	}
+}
+
+func TestVariance(t *testing.T) {
+	tcases := []struct {
+		name string
+		// values are the values of every shard
+		values [][]sqltypes.Value
+		want   float64
+		null   bool
+	}{{
+		name:   "no values",
+		values: [][]sqltypes.Value{{}, {NULL}},
+		null:   true,
+	}, {
+		name:   "small values",
+		values: [][]sqltypes.Value{{NewInt64(1), NULL, NewInt64(2)}, {NewInt64(3), NewInt64(4)}},
+		want:   1.25,
+	}, {
+		// the sum of the squares overflows a BIGINT
+		name:   "large values",
+		values: [][]sqltypes.Value{{NewInt64(-9_000_000_000_000_000_000)}, {NewInt64(9_000_000_000_000_000_000), sqltypes.NewUint64(9_000_000_000_000_000_000)}},
+		want:   7.2e37,
+	}, {
+		// the difference between the sum of the squares and the squared sum loses all the precision
+		name:   "high mean",
+		values: [][]sqltypes.Value{{NewInt64(1_000_000_001), NewInt64(1_000_000_002)}, {NewInt64(1_000_000_003), sqltypes.NewVarChar("1000000004")}},
+		want:   1.25,
+	}, {
+		name:   "high mean decimals",
+		values: [][]sqltypes.Value{{sqltypes.NewDecimal("123456789012.5"), sqltypes.NewDecimal("123456789013.5")}, {sqltypes.NewDecimal("123456789013.5")}},
+		want:   2.0 / 9,
+	}}
+	for _, tcase := range tcases {
+		t.Run(tcase.name, func(t *testing.T) {
+			check := func(variance Variance) {
+				t.Helper()
+				result := variance.Result()
+				if tcase.null {
+					require.True(t, result.IsNull(), result)
+					return
+				}
+				f, err := result.ToFloat64()
+				require.NoError(t, err)
+				require.InEpsilon(t, tcase.want, f, 1e-9)
+			}
+
+			// all the values are aggregated on the vtgate
+			raw := NewAggregationVariance()
+			for _, values := range tcase.values {
+				for _, v := range values {
+					require.NoError(t, raw.Add(v))
+				}
+			}
+			check(raw)
+
+			// every shard returns its count, sum and population variance, which are merged
+			merged := NewAggregationVariance()
+			for _, values := range tcase.values {
+				shard := NewAggregationVariance()
+				count, sum := 0, 0.0
+				for _, v := range values {
+					require.NoError(t, shard.Add(v))
+					if !v.IsNull() {
+						f, err := valueToFloat64(v)
+						require.NoError(t, err)
+						count, sum = count+1, sum+f
+					}
+				}
+				sumValue := sqltypes.NewFloat64(sum)
+				if count == 0 {
+					sumValue = NULL
+				}
+				require.NoError(t, merged.Merge(sqltypes.NewInt64(int64(count)), sumValue, shard.Result()))
+			}
+			check(merged)
+
+			merged.Reset()
+			require.True(t, merged.Result().IsNull())
+		})
+	}
+}
@@ -0,0 +1,370 @@
+/*
+Copyright 2025 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package evalengine
+
+import (
+	"slices"
+	"unicode/utf8"
+
+	"vitess.io/vitess/go/mysql/collations"
+	"vitess.io/vitess/go/mysql/json"
+	"vitess.io/vitess/go/sqltypes"
+	querypb "vitess.io/vitess/go/vt/proto/query"
+	vtrpcpb "vitess.io/vitess/go/vt/proto/vtrpc"
+	"vitess.io/vitess/go/vt/sqlparser"
+	"vitess.io/vitess/go/vt/vterrors"
+)
+
+type (
+	// JSONTable generates the rows of a JSON_TABLE expression: a row is produced for every value
+	// matched by Path in the JSON document, with the values described by the column definitions.
+	JSONTable struct {
+		Alias   string
+		Doc     Expr
+		Path    Expr
+		Columns []*JSONTableColumn
+
+		width int
+	}
+
+	// JSONTableColumn is a column definition of a JSON_TABLE expression. It is either a
+	// FOR ORDINALITY column, a PATH column, or a NESTED PATH with its own column definitions.
+	JSONTableColumn struct {
+		Name       string
+		Ordinality bool
+		Type       Type
+		Exists     bool
+		Path       Expr
+		OnEmpty    *JSONTableOnResponse
+		OnError    *JSONTableOnResponse
+		Nested     []*JSONTableColumn
+
+		// offset is the position of the column in the generated rows
+		offset int
+	}
+
+	// JSONTableOnResponse is the ON EMPTY or ON ERROR clause of a JSON_TABLE column.
+	// When Error is false and Default is nil, the column is NULL.
+	JSONTableOnResponse struct {
+		Error   bool
+		Default Expr
+	}
+
+	jsonTableGenerator struct {
+		jt    *JSONTable
+		env   *ExpressionEnv
+		paths map[*JSONTableColumn]*json.Path
+	}
+)
+
+// TypeForColumnType returns the type of the values stored in a column declared with the given type
+func TypeForColumnType(ct *sqlparser.ColumnType, collationEnv *collations.Environment) Type {
+	typ := ct.SQLType()
+	collation := collations.CollationForType(typ, collationEnv.DefaultConnectionCharset())
+	if ct.Options != nil && ct.Options.Collate != "" && sqltypes.IsText(typ) {
+		if id := collationEnv.LookupByName(ct.Options.Collate); id != collations.Unknown {
+			collation = id
+		}
+	}
+	var size, scale int32
+	if ct.Length != nil {
+		size = int32(*ct.Length)
+	}
+	if ct.Scale != nil {
+		scale = int32(*ct.Scale)
+	}
+	return NewTypeEx(typ, collation, true, size, scale, nil)
+}
+
+// JSONTableOrdinalityType is the type of the FOR ORDINALITY columns of a JSON_TABLE expression
+var JSONTableOrdinalityType = NewType(sqltypes.Uint32, collations.CollationBinaryID)
+
+// TranslateJSONTable translates the document, the paths and the default values of a JSON_TABLE
+// expression so it can be evaluated in the vtgate
+func TranslateJSONTable(node *sqlparser.JSONTableExpr, cfg *Config) (*JSONTable, error) {
+	jt := &JSONTable{Alias: node.Alias.String()}
+	var err error
+	if jt.Doc, err = Translate(node.Expr, cfg); err != nil {
+		return nil, err
+	}
+	if jt.Path, err = Translate(node.Filter, cfg); err != nil {
+		return nil, err
+	}
+	if jt.Columns, err = jt.translateColumns(node.Columns, cfg); err != nil {
+		return nil, err
+	}
+	return jt, nil
+}
+
+func (jt *JSONTable) translateColumns(defs []*sqlparser.JtColumnDefinition, cfg *Config) ([]*JSONTableColumn, error) {
+	var cols []*JSONTableColumn
+	for _, def := range defs {
+		col := &JSONTableColumn{}
+		var err error
+		switch {
+		case def.JtOrdinal != nil:
+			col.Name = def.JtOrdinal.Name.String()
+			col.Ordinality = true
+			col.Type = JSONTableOrdinalityType
+		case def.JtPath != nil:
+			col.Name = def.JtPath.Name.String()
+			col.Type = TypeForColumnType(def.JtPath.Type, cfg.Environment.CollationEnv())
+			col.Exists = def.JtPath.JtColExists
+			if col.Path, err = Translate(def.JtPath.Path, cfg); err != nil {
+				return nil, err
+			}
+			if col.OnEmpty, err = translateJSONTableOnResponse(def.JtPath.EmptyOnResponse, cfg); err != nil {
+				return nil, err
+			}
+			if col.OnError, err = translateJSONTableOnResponse(def.JtPath.ErrorOnResponse, cfg); err != nil {
+				return nil, err
+			}
+		case def.JtNestedPath != nil:
+			if col.Path, err = Translate(def.JtNestedPath.Path, cfg); err != nil {
+				return nil, err
+			}
+			if col.Nested, err = jt.translateColumns(def.JtNestedPath.Columns, cfg); err != nil {
+				return nil, err
+			}
+			cols = append(cols, col)
+			continue
+		}
+		col.offset = jt.width
+		jt.width++
+		cols = append(cols, col)
+	}
+	return cols, nil
+}
+
+func translateJSONTableOnResponse(resp *sqlparser.JtOnResponse, cfg *Config) (*JSONTableOnResponse, error) {
+	if resp == nil {
+		return nil, nil
+	}
+	switch resp.ResponseType {
+	case sqlparser.ErrorJSONType:
+		return &JSONTableOnResponse{Error: true}, nil
+	case sqlparser.DefaultJSONType:
+		def, err := Translate(resp.Expr, cfg)
+		if err != nil {
+			return nil, err
+		}
+		return &JSONTableOnResponse{Default: def}, nil
+	default:
+		return nil, nil
+	}
+}
+
+// Evaluate generates the rows of the JSON_TABLE expression for the document it evaluates in the given environment
+func (jt *JSONTable) Evaluate(env *ExpressionEnv) ([]sqltypes.Row, error) {
+	res, err := env.Evaluate(jt.Doc)
+	if err != nil {
+		return nil, err
+	}
+	if res.v == nil {
+		return nil, nil
+	}
+	doc, err := intoJSON("json_table", res.v)
+	if err != nil {
+		return nil, err
+	}
+	path, err := evalJSONTablePath(env, jt.Path)
+	if err != nil {
+		return nil, err
+	}
+
+	g := &jsonTableGenerator{jt: jt, env: env, paths: map[*JSONTableColumn]*json.Path{}}
+	var rows []sqltypes.Row
+	for i, value := range matchJSONPath(path, doc) {
+		r, err := g.rows(jt.Columns, make(sqltypes.Row, jt.width), value, i+1)
+		if err != nil {
+			return nil, err
+		}
+		rows = append(rows, r...)
+	}
+	return rows, nil
+}
+
+// Fields returns the fields of the rows generated by the JSON_TABLE expression
+func (jt *JSONTable) Fields() []*querypb.Field {
+	fields := make([]*querypb.Field, jt.width)
+	var visit func([]*JSONTableColumn)
+	visit = func(cols []*JSONTableColumn) {
+		for _, col := range cols {
+			if col.Nested != nil {
+				visit(col.Nested)
+				continue
+			}
+			fields[col.offset] = col.Type.ToField(col.Name)
+		}
+	}
+	visit(jt.Columns)
+	return fields
+}
+
+func evalJSONTablePath(env *ExpressionEnv, expr Expr) (*json.Path, error) {
+	res, err := env.Evaluate(expr)
+	if err != nil {
+		return nil, err
+	}
+	return intoJSONPath(res.v)
+}
+
+func matchJSONPath(path *json.Path, doc *json.Value) (matches []*json.Value) {
+	path.Match(doc, true, func(value *json.Value) {
+		matches = append(matches, value)
+	})
+	return matches
+}
+
+// rows generates the rows for a value matched by the path of the given column definitions.
+// Every nested path generates its own rows, where the columns of its sibling nested paths are NULL.
+// When none of the nested paths matches a value, a single row is generated for the value.
+func (g *jsonTableGenerator) rows(cols []*JSONTableColumn, parent sqltypes.Row, value *json.Value, ordinal int) ([]sqltypes.Row, error) {
+	row := slices.Clone(parent)
+	var nested []*JSONTableColumn
+	for _, col := range cols {
+		switch {
+		case col.Nested != nil:
+			nested = append(nested, col)
+		case col.Ordinality:
+			row[col.offset] = sqltypes.NewUint32(uint32(ordinal))
+		default:
+			v, err := g.value(col, value)
+			if err != nil {
+				return nil, err
+			}
+			row[col.offset] = v
+		}
+	}
+
+	var rows []sqltypes.Row
+	for _, col := range nested {
+		path, err := g.path(col)
+		if err != nil {
+			return nil, err
+		}
+		for i, match := range matchJSONPath(path, value) {
+			r, err := g.rows(col.Nested, row, match, i+1)
+			if err != nil {
+				return nil, err
+			}
+			rows = append(rows, r...)
+		}
+	}
+	if len(rows) == 0 {
+		rows = append(rows, row)
+	}
+	return rows, nil
+}
+
+func (g *jsonTableGenerator) path(col *JSONTableColumn) (*json.Path, error) {
+	if path, ok := g.paths[col]; ok {
+		return path, nil
+	}
+	path, err := evalJSONTablePath(g.env, col.Path)
+	if err != nil {
+		return nil, err
+	}
+	g.paths[col] = path
+	return path, nil
+}
+
+func (g *jsonTableGenerator) value(col *JSONTableColumn, value *json.Value) (sqltypes.Value, error) {
+	path, err := g.path(col)
+	if err != nil {
+		return sqltypes.NULL, err
+	}
+	matches := matchJSONPath(path, value)
+	if col.Exists {
+		e, err := evalCoerce(newEvalBool(len(matches) > 0), col.Type.Type(), col.Type.Size(), col.Type.Scale(), col.Type.Collation(), g.env.now, g.env.sqlmode.AllowZeroDate())
+		if err != nil {
+			return sqltypes.NULL, err
+		}
+		return evalToSQLValueWithType(e, col.Type), nil
+	}
+
+	switch len(matches) {
+	case 0:
+		return g.onResponse(col, col.OnEmpty, vterrors.Errorf(vtrpcpb.Code_INVALID_ARGUMENT, "Missing value for JSON_TABLE column '%s'", col.Name))
+	case 1:
+		v, err := g.convert(col, matches[0])
+		if err != nil {
+			return g.onResponse(col, col.OnError, err)
+		}
+		return v, nil
+	default:
+		return g.onResponse(col, col.OnError, g.scalarError(col))
+	}
+}
+
+func (g *jsonTableGenerator) onResponse(col *JSONTableColumn, resp *JSONTableOnResponse, err error) (sqltypes.Value, error) {
+	switch {
+	case resp == nil:
+		return sqltypes.NULL, nil
+	case resp.Error:
+		return sqltypes.NULL, err
+	case resp.Default != nil:
+		res, err := g.env.Evaluate(resp.Default)
+		if err != nil {
+			return sqltypes.NULL, err
+		}
+		def, err := intoJSON("json_table", res.v)
+		if err != nil {
+			return sqltypes.NULL, err
+		}
+		return g.convert(col, def)
+	default:
+		return sqltypes.NULL, nil
+	}
+}
+
+// convert converts a JSON value to the type of the column, the same way MySQL stores it in a JSON_TABLE column
+func (g *jsonTableGenerator) convert(col *JSONTableColumn, value *json.Value) (sqltypes.Value, error) {
+	typ := col.Type.Type()
+	if typ == sqltypes.TypeJSON {
+		return sqltypes.MakeTrusted(sqltypes.TypeJSON, value.ToRawBytes()), nil
+	}
+
+	var raw []byte
+	switch value.Type() {
+	case json.TypeNull:
+		return sqltypes.NULL, nil
+	case json.TypeObject, json.TypeArray:
+		return sqltypes.NULL, g.scalarError(col)
+	case json.TypeNumber, json.TypeBoolean:
+		raw = value.ToRawBytes()
+	default:
+		raw = value.ToUnencodedBytes()
+	}
+
+	if sqltypes.IsText(typ) || sqltypes.IsBinary(typ) {
+		if size := int(col.Type.Size()); size > 0 && utf8.RuneCount(raw) > size {
+			return sqltypes.NULL, vterrors.NewErrorf(vtrpcpb.Code_INVALID_ARGUMENT, vterrors.DataOutOfRange, "Data too long for column '%s' at row 1", col.Name)
+		}
+		return sqltypes.MakeTrusted(typ, raw), nil
+	}
+
+	e, err := evalCoerce(value, typ, col.Type.Size(), col.Type.Scale(), col.Type.Collation(), g.env.now, g.env.sqlmode.AllowZeroDate())
+	if err != nil {
+		return sqltypes.NULL, err
+	}
+	return evalToSQLValueWithType(e, col.Type), nil
+}
+
+func (g *jsonTableGenerator) scalarError(col *JSONTableColumn) error {
+	return vterrors.Errorf(vtrpcpb.Code_INVALID_ARGUMENT, "Can't store an array or an object in the scalar column '%s' of JSON_TABLE '%s'.", col.Name, g.jt.Alias)
+}
@@ -0,0 +1,98 @@
+/*
+Copyright 2025 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package evalengine
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"vitess.io/vitess/go/sqltypes"
+	querypb "vitess.io/vitess/go/vt/proto/query"
+	"vitess.io/vitess/go/vt/sqlparser"
+	"vitess.io/vitess/go/vt/vtenv"
+)
+
+func TestJSONTable(t *testing.T) {
+	tcases := []struct {
+		jsonTable string
+		bv        map[string]*querypb.BindVariable
+		fields    string
+		rows      string
+		err       string
+	}{{
+		jsonTable: `json_table('[{"a": 1, "b": "x"}, {"a": 2}]', '$[*]' columns(a int path '$.a', b varchar(10) path '$.b'))`,
+		fields:    "[a:INT32 b:VARCHAR]",
+		rows:      `[[INT32(1) VARCHAR("x")] [INT32(2) NULL]]`,
+	}, {
+		jsonTable: `json_table(:doc, '$[*]' columns(id for ordinality, a int path '$.a' default '7' on empty))`,
+		bv:        map[string]*querypb.BindVariable{"doc": sqltypes.StringBindVariable(`[{"a": 1}, {}]`)},
+		fields:    "[id:UINT32 a:INT32]",
+		rows:      `[[UINT32(1) INT32(1)] [UINT32(2) INT32(7)]]`,
+	}, {
+		jsonTable: `json_table('{"a": 1, "b": [10, 20]}', '$' columns(a int path '$.a', nested path '$.b[*]' columns(b int path '$')))`,
+		fields:    "[a:INT32 b:INT32]",
+		rows:      `[[INT32(1) INT32(10)] [INT32(1) INT32(20)]]`,
+	}, {
+		jsonTable: `json_table('[{"a": [1]}]', '$[*]' columns(a int exists path '$.a', b int path '$.b'))`,
+		fields:    "[a:INT32 b:INT32]",
+		rows:      `[[INT32(1) NULL]]`,
+	}, {
+		jsonTable: `json_table(null, '$[*]' columns(a int path '$.a'))`,
+		fields:    "[a:INT32]",
+		rows:      `[]`,
+	}, {
+		jsonTable: `json_table('[{"a": [1, 2]}]', '$[*]' columns(a int path '$.a'))`,
+		fields:    "[a:INT32]",
+		rows:      `[[NULL]]`,
+	}, {
+		jsonTable: `json_table('[{"a": [1, 2]}]', '$[*]' columns(a int path '$.a' error on error))`,
+		err:       "Can't store an array or an object in the scalar column 'a' of JSON_TABLE 'jt'.",
+	}}
+
+	venv := vtenv.NewTestEnv()
+	for _, tcase := range tcases {
+		t.Run(tcase.jsonTable, func(t *testing.T) {
+			stmt, err := venv.Parser().Parse("select * from " + tcase.jsonTable + " as jt")
+			require.NoError(t, err)
+			node := stmt.(*sqlparser.Select).From[0].(*sqlparser.JSONTableExpr)
+
+			jt, err := TranslateJSONTable(node, &Config{
+				Collation:   venv.CollationEnv().DefaultConnectionCharset(),
+				Environment: venv,
+			})
+			require.NoError(t, err)
+
+			rows, err := jt.Evaluate(NewExpressionEnv(context.Background(), tcase.bv, NewEmptyVCursor(venv, nil)))
+			if tcase.err != "" {
+				require.ErrorContains(t, err, tcase.err)
+				return
+			}
+			require.NoError(t, err)
+
+			var fields []string
+			for _, field := range jt.Fields() {
+				fields = append(fields, field.Name+":"+field.Type.String())
+			}
+			assert.Equal(t, tcase.fields, fmt.Sprintf("%v", fields))
+			assert.Equal(t, tcase.rows, fmt.Sprintf("%v", rows))
+		})
+	}
+}
@@ -323,6 +323,76 @@ func (cached *IsExpr) CachedSize(alloc bool) int64 {
 	size += cached.UnaryExpr.CachedSize(false)
 	return size
 }
+func (cached *JSONTable) CachedSize(alloc bool) int64 {
+	if cached == nil {
+		return int64(0)
+	}
+	size := int64(0)
+	if alloc {
+		size += int64(80)
+	}
+	// field Alias string
+	size += hack.RuntimeAllocSize(int64(len(cached.Alias)))
+	// field Doc vitess.io/vitess/go/vt/vtgate/evalengine.Expr
+	if cc, ok := cached.Doc.(cachedObject); ok {
+		size += cc.CachedSize(true)
+	}
+	// field Path vitess.io/vitess/go/vt/vtgate/evalengine.Expr
+	if cc, ok := cached.Path.(cachedObject); ok {
+		size += cc.CachedSize(true)
+	}
+	// field Columns []*vitess.io/vitess/go/vt/vtgate/evalengine.JSONTableColumn
+	{
+		size += hack.RuntimeAllocSize(int64(cap(cached.Columns)) * int64(8))
+		for _, elem := range cached.Columns {
+			size += elem.CachedSize(true)
+		}
+	}
+	return size
+}
+func (cached *JSONTableColumn) CachedSize(alloc bool) int64 {
+	if cached == nil {
+		return int64(0)
+	}
+	size := int64(0)
+	if alloc {
+		size += int64(128)
+	}
+	// field Name string
+	size += hack.RuntimeAllocSize(int64(len(cached.Name)))
+	// field Type vitess.io/vitess/go/vt/vtgate/evalengine.Type
+	size += cached.Type.CachedSize(false)
+	// field Path vitess.io/vitess/go/vt/vtgate/evalengine.Expr
+	if cc, ok := cached.Path.(cachedObject); ok {
+		size += cc.CachedSize(true)
+	}
+	// field OnEmpty *vitess.io/vitess/go/vt/vtgate/evalengine.JSONTableOnResponse
+	size += cached.OnEmpty.CachedSize(true)
+	// field OnError *vitess.io/vitess/go/vt/vtgate/evalengine.JSONTableOnResponse
+	size += cached.OnError.CachedSize(true)
+	// field Nested []*vitess.io/vitess/go/vt/vtgate/evalengine.JSONTableColumn
+	{
+		size += hack.RuntimeAllocSize(int64(cap(cached.Nested)) * int64(8))
+		for _, elem := range cached.Nested {
+			size += elem.CachedSize(true)
+		}
+	}
+	return size
+}
+func (cached *JSONTableOnResponse) CachedSize(alloc bool) int64 {
+	if cached == nil {
+		return int64(0)
+	}
+	size := int64(0)
+	if alloc {
+		size += int64(24)
+	}
+	// field Default vitess.io/vitess/go/vt/vtgate/evalengine.Expr
+	if cc, ok := cached.Default.(cachedObject); ok {
+		size += cc.CachedSize(true)
+	}
+	return size
+}
 func (cached *LikeExpr) CachedSize(alloc bool) int64 {
 	if cached == nil {
 		return int64(0)
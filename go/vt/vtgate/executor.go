@@ -134,6 +134,13 @@ type (
 		plans *PlanCache
 		epoch atomic.Uint32
 
+		// resultCache caches the results of the read-only queries that opt in,
+		// it is nil when the result cache is disabled.
+		resultCache *resultCache
+		// consolidator shares the results of identical read-only queries in
+		// flight.
+		consolidator *queryConsolidator
+
 		vm            *VSchemaManager
 		schemaTracker SchemaInfo
 
@@ -195,6 +202,10 @@ func NewExecutor(
 		warmingReadsChannel: make(chan bool, warmingReadsConcurrency),
 		ddlConfig:           ddlConfig,
 	}
+	if resultCacheMemory > 0 {
+		e.resultCache = newResultCache(resultCacheMemory)
+	}
+	e.consolidator = newQueryConsolidator(consolidatorMode(), consolidatorMaxResultSize)
 	// setting the vcursor config.
 	e.initVConfig(warnOnShardedOnly, pv)
 	e.metrics = &Metrics{
@@ -230,6 +241,20 @@ func NewExecutor(
 		stats.NewCounterFunc("QueryPlanCacheMisses", "Query plan cache misses", func() int64 {
 			return e.plans.Metrics.Misses()
 		})
+		if e.resultCache != nil {
+			stats.NewGaugeFunc("ResultCacheLength", "Result cache length", func() int64 {
+				return int64(e.resultCache.store.Len())
+			})
+			stats.NewGaugeFunc("ResultCacheSize", "Result cache size", func() int64 {
+				return int64(e.resultCache.store.UsedCapacity())
+			})
+			stats.NewGaugeFunc("ResultCacheCapacity", "Result cache capacity", func() int64 {
+				return int64(e.resultCache.store.MaxCapacity())
+			})
+			stats.NewCounterFunc("ResultCacheEvictions", "Result cache evictions", func() int64 {
+				return e.resultCache.store.Metrics.Evicted()
+			})
+		}
 		servenv.HTTPHandle(pathQueryPlans, e)
 		servenv.HTTPHandle(pathScatterStats, e)
 		servenv.HTTPHandle(pathVSchema, e)
@@ -522,6 +547,8 @@ func (e *Executor) addNeededBindVars(vcursor *econtext.VCursorImpl, bindVarNeeds
 			bindVars[key] = sqltypes.StringBindVariable(session.DDLStrategy)
 		case sysvars.MigrationContext.Name:
 			bindVars[key] = sqltypes.StringBindVariable(session.MigrationContext)
+		case sysvars.TenantID.Name:
+			bindVars[key] = sqltypes.StringBindVariable(session.GetTenantID())
 		case sysvars.SessionUUID.Name:
 			bindVars[key] = sqltypes.StringBindVariable(session.SessionUUID)
 		case sysvars.SessionEnableSystemSettings.Name:
@@ -1350,6 +1377,8 @@ func (e *Executor) buildStatement(
 	plan.ParamsCount = paramsCount
 	plan.Warnings = vcursor.GetAndEmptyWarnings()
 	plan.QueryHints = qh
+	plan.ResultCacheTTL = resultCacheTTL(stmt, qh, plan, e.VSchema())
+	plan.Consolidate = plan.QueryType == sqlparser.StmtSelect && isCacheableSelect(stmt)
 
 	err = e.checkThatPlanIsValid(stmt, plan)
 	return plan, err
@@ -1532,8 +1561,10 @@ func (e *Executor) initVConfig(warnOnShardedOnly bool, pv plancontext.PlannerVer
 		DefaultTabletType: defaultTabletType,
 		PlannerVersion:    pv,
 
-		QueryTimeout:  queryTimeout,
-		MaxMemoryRows: maxMemoryRows,
+		QueryTimeout:      queryTimeout,
+		MaxMemoryRows:     maxMemoryRows,
+		SpillMemoryBudget: spillMemoryBudget,
+		SpillDir:          spillDir,
 
 		SetVarEnabled:      sysVarSetEnabled,
 		EnableViews:        enableViews,
@@ -1546,6 +1577,8 @@ func (e *Executor) initVConfig(warnOnShardedOnly bool, pv plancontext.PlannerVer
 		WarmingReadsPercent: e.config.WarmingReadsPercent,
 		WarmingReadsTimeout: warmingReadsQueryTimeout,
 		WarmingReadsChannel: e.warmingReadsChannel,
+
+		TenantIDSource: tenantIDSource,
 	}
 }
 
@@ -1770,6 +1803,11 @@ func (e *Executor) ReleaseLock(ctx context.Context, session *econtext.SafeSessio
 	return e.txConn.ReleaseLock(ctx, session)
 }
 
+// ReleaseLockTables implements the IExecutor interface
+func (e *Executor) ReleaseLockTables(ctx context.Context, session *econtext.SafeSession) error {
+	return e.txConn.ReleaseLockTables(ctx, session)
+}
+
 // PlanPrepareStmt implements the IExecutor interface
 func (e *Executor) PlanPrepareStmt(ctx context.Context, safeSession *econtext.SafeSession, query string) (*engine.Plan, error) {
 	// creating this log stats to not interfere with the original log stats.
@@ -1786,6 +1824,9 @@ func (e *Executor) Close() {
 	}
 	topo.Close()
 	e.plans.Close()
+	if e.resultCache != nil {
+		e.resultCache.close()
+	}
 }
 
 func (e *Executor) Environment() *vtenv.Environment {
@@ -1973,6 +1973,64 @@ func TestSelectScatterOrderBy(t *testing.T) {
 	utils.MustMatch(t, wantResult, gotResult)
 }
 
+// TestSelectScatterWindowFunction runs a window function on the vtgate over the rows
+// of 8 shards, with its offset given as a bind variable like a prepared statement does.
+func TestSelectScatterWindowFunction(t *testing.T) {
+	ctx := utils.LeakCheckContext(t)
+
+	// Special setup: Don't use createExecutorEnv.
+	cell := "aa"
+	hc := discovery.NewFakeHealthCheck(nil)
+	u := createSandbox(KsTestUnsharded)
+	s := createSandbox(KsTestSharded)
+	s.VSchema = executorVSchema
+	u.VSchema = unshardedVSchema
+	serv := newSandboxForCells(ctx, []string{cell})
+	resolver := newTestResolver(ctx, hc, serv, cell)
+	shards := []string{"-20", "20-40", "40-60", "60-80", "80-a0", "a0-c0", "c0-e0", "e0-"}
+	for i, shard := range shards {
+		sbc := hc.AddTestTablet(cell, shard, 1, "TestExecutor", shard, topodatapb.TabletType_PRIMARY, true, 1, nil)
+		sbc.SetResults([]*sqltypes.Result{{
+			Fields: []*querypb.Field{
+				{Name: "col1", Type: sqltypes.Int32, Charset: collations.CollationBinaryID, Flags: uint32(querypb.MySqlFlag_NUM_FLAG)},
+				{Name: "weight_string(col1)", Type: sqltypes.VarBinary, Charset: collations.CollationBinaryID, Flags: uint32(querypb.MySqlFlag_BINARY_FLAG)},
+			},
+			Rows: [][]sqltypes.Value{{
+				sqltypes.NewInt32(int32(i)),
+				sqltypes.NULL,
+			}},
+		}})
+	}
+	queryLogger := streamlog.New[*logstats.LogStats]("VTGate", queryLogBufferSize)
+	executor := NewExecutor(ctx, vtenv.NewTestEnv(), serv, cell, resolver, createExecutorConfigWithNormalizer(), false, DefaultPlanCache(), nil, querypb.ExecuteOptions_Gen4, NewDynamicViperConfig())
+	executor.SetQueryLogger(queryLogger)
+	defer executor.Close()
+
+	query := "select col1, lag(col1, :off) over (order by col1) from user"
+	session := &vtgatepb.Session{
+		TargetString: "@primary",
+	}
+	gotResult, err := executorExec(ctx, executor, session, query, map[string]*querypb.BindVariable{
+		"off": sqltypes.Int64BindVariable(2),
+	})
+	require.NoError(t, err)
+
+	wantResult := sqltypes.MakeTestResult(
+		sqltypes.MakeTestFields("col1|lag(col1, :off) over ( order by col1 asc)", "int32|int32"),
+		"0|null",
+		"1|null",
+		"2|0",
+		"3|1",
+		"4|2",
+		"5|3",
+		"6|4",
+		"7|5",
+	)
+	require.Len(t, gotResult.Fields, 2)
+	assert.Equal(t, wantResult.Fields[1].Name, gotResult.Fields[1].Name)
+	utils.MustMatch(t, wantResult.Rows, gotResult.Rows)
+}
+
 // TestSelectScatterOrderByVarChar will run an ORDER BY query that will scatter out to 8 shards and return the 8 rows (one per shard) sorted.
 func TestSelectScatterOrderByVarChar(t *testing.T) {
 	ctx := utils.LeakCheckContext(t)
@@ -3268,14 +3326,14 @@ func TestOnlyOptimizedPlan(t *testing.T) {
 	logChan := executor.queryLogger.Subscribe("Test")
 	defer executor.queryLogger.Unsubscribe(logChan)
 
-	sql := "select col, trim((select user_name from user where id = ?)) val from user where id = ? group by col order by val"
+	sql := "select col, sum(col) over (order by col rows between 1 preceding and current row) from user where col in (select col from user where id = ?) and id = ?"
 	session := econtext.NewAutocommitSession(&vtgatepb.Session{TargetString: "@primary"})
 	bv := map[string]*querypb.BindVariable{
 		"v1": sqltypes.Int64BindVariable(1),
 		"v2": sqltypes.Int64BindVariable(2),
 	}
 	_, err := executor.Execute(ctx, nil, "TestExecute", session, sql, bv, true)
-	require.ErrorContains(t, err, "VT12001: unsupported: subquery with aggregation in order by")
+	require.ErrorContains(t, err, "VT12001: unsupported: OVER CLAUSE with sharded keyspace")
 	testQueryLog(t, executor, logChan, "TestExecute", "", sql, 0)
 
 	bv = map[string]*querypb.BindVariable{
@@ -3287,14 +3345,14 @@ func TestOnlyOptimizedPlan(t *testing.T) {
 	testQueryLog(t, executor, logChan, "TestExecute", "SELECT", sql, 1)
 	sp := assertOptimizedPlanCondition(t, executor, sql, engine.Condition{A: "v1", B: "v2"})
 	require.NotNil(t, sp)
-	require.ErrorContains(t, sp.BaselineErr, "VT12001: unsupported: subquery with aggregation in order by")
+	require.ErrorContains(t, sp.BaselineErr, "VT12001: unsupported: OVER CLAUSE with sharded keyspace")
 }
 
 // TestPrepareWithUnsupportedQuery tests that the fields returned by the query on unsupported query.
 func TestPrepareWithUnsupportedQuery(t *testing.T) {
 	executor, _, _, _, ctx := createExecutorEnvWithConfig(t, createExecutorConfigWithNormalizer())
 
-	sql := "select a, b, c, row_number() over (partition by x) from user where c1 = ? and c2 = ?"
+	sql := "select a, b, c, row_number() over w from user where c1 = ? and c2 = ? window w as (partition by x)"
 	session := econtext.NewAutocommitSession(&vtgatepb.Session{})
 	fields, paramsCount, err := executorPrepare(ctx, executor, session.Session, sql)
 	require.NoError(t, err)
@@ -3303,7 +3361,7 @@ func TestPrepareWithUnsupportedQuery(t *testing.T) {
 		{Name: "a", Type: querypb.Type_NULL_TYPE},
 		{Name: "b", Type: querypb.Type_NULL_TYPE},
 		{Name: "c", Type: querypb.Type_NULL_TYPE},
-		{Name: "row_number() over ( partition by x)", Type: querypb.Type_NULL_TYPE},
+		{Name: "row_number() over w", Type: querypb.Type_NULL_TYPE},
 	}
 	require.Equal(t, wantFields, fields)
 
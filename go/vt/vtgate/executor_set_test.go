@@ -153,6 +153,15 @@ func TestExecutorSet(t *testing.T) {
 	}, {
 		in:  "set workload = 1",
 		err: "incorrect argument type to variable 'workload': INT64",
+	}, {
+		in:  "set @@tenant_id = 42",
+		out: &vtgatepb.Session{Autocommit: true, TenantId: "42"},
+	}, {
+		in:  "set tenant_id = 'acme'",
+		out: &vtgatepb.Session{Autocommit: true, TenantId: "acme"},
+	}, {
+		in:  "set tenant_id = 1.5",
+		err: "incorrect argument type to variable 'tenant_id': DECIMAL",
 	}, {
 		in:  "set tx_isolation = 'read-committed'",
 		out: &vtgatepb.Session{Autocommit: true},
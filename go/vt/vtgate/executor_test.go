@@ -334,6 +334,34 @@ func TestExecutorTransactionsAutoCommit(t *testing.T) {
 	assert.EqualValues(t, "suuid", logStats.SessionUUID, "logstats: expected non-empty SessionUUID")
 }
 
+func TestExecutorTransactionsInLockTables(t *testing.T) {
+	executor, sbc1, _, sbclookup, ctx := createExecutorEnv(t)
+
+	session := econtext.NewSafeSession(&vtgatepb.Session{TargetString: "@primary", Autocommit: true})
+	_, err := executorExecSession(ctx, executor, session, "lock tables user write", nil)
+	require.NoError(t, err)
+	require.True(t, session.InLockTables())
+
+	// MySQL would release the table locks of the reserved connections.
+	_, err = executorExecSession(ctx, executor, session, "begin", nil)
+	require.ErrorContains(t, err, "transaction while the session holds table locks")
+	assert.False(t, session.InTransaction())
+
+	// The insert of the lookup vindex row needs a transaction.
+	sbc1.Queries = nil
+	_, err = executorExecSession(ctx, executor, session, "insert into user(id, v, name) values (1, 2, 'myname')", nil)
+	require.ErrorContains(t, err, "transaction while the session holds table locks")
+	assert.Empty(t, sbc1.Queries)
+	assert.Empty(t, sbclookup.Queries)
+	assert.True(t, session.InLockTables())
+
+	_, err = executorExecSession(ctx, executor, session, "unlock tables", nil)
+	require.NoError(t, err)
+	_, err = executorExecSession(ctx, executor, session, "begin", nil)
+	require.NoError(t, err)
+	assert.True(t, session.InTransaction())
+}
+
 func TestExecutorTransactionsAutoCommitStreaming(t *testing.T) {
 	executor, _, _, sbclookup, ctx := createExecutorEnv(t)
 
@@ -179,6 +179,7 @@ func (session *SafeSession) Reset() {
 	session.ShardSessions = nil
 	session.PreSessions = nil
 	session.PostSessions = nil
+	session.resetLockTablesLocked()
 }
 
 // ResetAll resets the shard sessions and lock session.
@@ -191,6 +192,7 @@ func (session *SafeSession) ResetAll() {
 	session.PostSessions = nil
 	session.LockSession = nil
 	session.AdvisoryLock = nil
+	session.resetLockTablesLocked()
 }
 
 func (session *SafeSession) resetCommonLocked() {
@@ -795,6 +797,41 @@ func (session *SafeSession) ResetLock() {
 	session.AdvisoryLock = nil
 }
 
+// SetInLockTables marks the session as holding table locks on its reserved connections.
+// If the session was not using reserved connections yet, they are only reserved for the table locks,
+// and are released again by UNLOCK TABLES.
+func (session *SafeSession) SetInLockTables() {
+	session.mu.Lock()
+	defer session.mu.Unlock()
+	if !session.Session.InLockTables && !session.Session.InReservedConn {
+		session.LockTablesReservedConn = true
+	}
+	session.Session.InLockTables = true
+	session.Session.InReservedConn = true
+}
+
+// InLockTables returns whether the session holds table locks.
+func (session *SafeSession) InLockTables() bool {
+	session.mu.Lock()
+	defer session.mu.Unlock()
+	return session.Session.InLockTables
+}
+
+// ResetLockTables clears the table locks of the session.
+// It returns true if the reserved connections were only needed for the table locks.
+func (session *SafeSession) ResetLockTables() bool {
+	session.mu.Lock()
+	defer session.mu.Unlock()
+	reservedConn := session.LockTablesReservedConn
+	session.resetLockTablesLocked()
+	return reservedConn
+}
+
+func (session *SafeSession) resetLockTablesLocked() {
+	session.Session.InLockTables = false
+	session.LockTablesReservedConn = false
+}
+
 // ResetShard reset the shard session for the provided tablet alias.
 func (session *SafeSession) ResetShard(tabletAlias *topodatapb.TabletAlias) error {
 	session.mu.Lock()
@@ -855,6 +892,20 @@ func (session *SafeSession) GetMigrationContext() string {
 	return session.MigrationContext
 }
 
+// SetTenantID sets the tenant_id setting.
+func (session *SafeSession) SetTenantID(tenantID string) {
+	session.mu.Lock()
+	defer session.mu.Unlock()
+	session.TenantId = tenantID
+}
+
+// GetTenantID returns the tenant_id value.
+func (session *SafeSession) GetTenantID() string {
+	session.mu.Lock()
+	defer session.mu.Unlock()
+	return session.TenantId
+}
+
 // GetSessionUUID returns the SessionUUID value.
 func (session *SafeSession) GetSessionUUID() string {
 	session.mu.Lock()
@@ -36,6 +36,7 @@ import (
 	"vitess.io/vitess/go/vt/callerid"
 	"vitess.io/vitess/go/vt/discovery"
 	"vitess.io/vitess/go/vt/key"
+	"vitess.io/vitess/go/vt/logutil"
 	binlogdatapb "vitess.io/vitess/go/vt/proto/binlogdata"
 	querypb "vitess.io/vitess/go/vt/proto/query"
 	topodatapb "vitess.io/vitess/go/vt/proto/topodata"
@@ -69,6 +70,18 @@ var (
 
 var ErrNoKeyspace = vterrors.VT09005()
 
+const (
+	// TenantIDFromSession takes the tenant id from the @@tenant_id session variable.
+	TenantIDFromSession = "session"
+	// TenantIDFromCaller takes the tenant id from the username of the
+	// authenticated caller.
+	TenantIDFromCaller = "caller"
+)
+
+// logMirrorResultDiff logs a sample of the mirror queries whose results
+// differ from those of the source.
+var logMirrorResultDiff = logutil.NewThrottledLogger("MirrorResultDiff", 5*time.Second)
+
 type (
 	ResultsObserver interface {
 		Observe(*sqltypes.Result)
@@ -88,9 +101,19 @@ type (
 		WarnShardedOnly    bool
 		PlannerVersion     plancontext.PlannerVersion
 
+		// SpillMemoryBudget is the number of bytes of rows that the primitives of a
+		// query can hold in memory before spilling them to disk. 0 disables spilling.
+		SpillMemoryBudget int64
+		SpillDir          string
+
 		WarmingReadsPercent int
 		WarmingReadsTimeout time.Duration
 		WarmingReadsChannel chan bool
+
+		// TenantIDSource is where the tenant of a session comes from, one of
+		// TenantIDFromSession or TenantIDFromCaller. When empty, the queries to
+		// multi-tenant keyspaces are not restricted to a tenant.
+		TenantIDSource string
 	}
 
 	// vcursor_impl needs these facilities to be able to be able to execute queries for vindexes
@@ -103,6 +126,7 @@ type (
 		ExecuteMessageStream(ctx context.Context, rss []*srvtopo.ResolvedShard, name string, callback func(*sqltypes.Result) error) error
 		ExecuteVStream(ctx context.Context, rss []*srvtopo.ResolvedShard, filter *binlogdatapb.Filter, gtid string, callback func(evs []*binlogdatapb.VEvent) error) error
 		ReleaseLock(ctx context.Context, session *SafeSession) error
+		ReleaseLockTables(ctx context.Context, session *SafeSession) error
 
 		ShowVitessReplicationStatus(ctx context.Context, filter *sqlparser.ShowFilter) (*sqltypes.Result, error)
 		ShowShards(ctx context.Context, filter *sqlparser.ShowFilter, destTabletType topodatapb.TabletType) (*sqltypes.Result, error)
@@ -169,6 +193,7 @@ type (
 		// A nil value represents that no foreign_key_checks value was provided.
 		fkChecksState       *bool
 		ignoreMaxMemoryRows bool
+		spillBudget         *engine.SpillBudget
 		vschema             *vindexes.VSchema
 		vm                  VSchemaOperator
 		semTable            *semantics.SemTable
@@ -232,6 +257,7 @@ func NewVCursorImpl(
 		executor:       executor,
 		logStats:       logStats,
 		metrics:        metrics,
+		spillBudget:    newSpillBudget(cfg, logStats),
 
 		resolver:   resolver,
 		vschema:    vschema,
@@ -241,6 +267,22 @@ func NewVCursorImpl(
 	}, nil
 }
 
+// newSpillBudget returns the spill memory budget of a query, or nil when spilling is disabled.
+func newSpillBudget(cfg VCursorConfig, logStats *logstats.LogStats) *engine.SpillBudget {
+	if cfg.SpillMemoryBudget <= 0 {
+		return nil
+	}
+	return &engine.SpillBudget{
+		Limit: cfg.SpillMemoryBudget,
+		Dir:   cfg.SpillDir,
+		OnSpill: func(bytes int64) {
+			if logStats != nil {
+				atomic.AddUint64(&logStats.SpillBytes, uint64(bytes))
+			}
+		},
+	}
+}
+
 func (vc *VCursorImpl) GetSafeSession() *SafeSession {
 	return vc.SafeSession
 }
@@ -266,6 +308,7 @@ func (vc *VCursorImpl) CloneForMirroring(ctx context.Context) engine.VCursor {
 	immediateCallerId := callerid.ImmediateCallerIDFromContext(ctx)
 
 	clonedCtx := callerid.NewContext(ctx, callerId, immediateCallerId)
+	logStats := &logstats.LogStats{Ctx: clonedCtx}
 
 	v := &VCursorImpl{
 		config:         vc.config,
@@ -277,10 +320,11 @@ func (vc *VCursorImpl) CloneForMirroring(ctx context.Context) engine.VCursor {
 		executor:       vc.executor,
 		resolver:       vc.resolver,
 		topoServer:     vc.topoServer,
-		logStats:       &logstats.LogStats{Ctx: clonedCtx},
+		logStats:       logStats,
 		metrics:        vc.metrics,
 
 		ignoreMaxMemoryRows: vc.ignoreMaxMemoryRows,
+		spillBudget:         newSpillBudget(vc.config, logStats),
 		vschema:             vc.vschema,
 		vm:                  vc.vm,
 		semTable:            vc.semTable,
@@ -299,6 +343,7 @@ func (vc *VCursorImpl) CloneForReplicaWarming(ctx context.Context) engine.VCurso
 
 	timedCtx, _ := context.WithTimeout(context.Background(), vc.config.WarmingReadsTimeout) // nolint
 	clonedCtx := callerid.NewContext(timedCtx, callerId, immediateCallerId)
+	logStats := &logstats.LogStats{Ctx: clonedCtx}
 
 	v := &VCursorImpl{
 		config:         vc.config,
@@ -310,10 +355,11 @@ func (vc *VCursorImpl) CloneForReplicaWarming(ctx context.Context) engine.VCurso
 		executor:       vc.executor,
 		resolver:       vc.resolver,
 		topoServer:     vc.topoServer,
-		logStats:       &logstats.LogStats{Ctx: clonedCtx},
+		logStats:       logStats,
 		metrics:        vc.metrics,
 
 		ignoreMaxMemoryRows: vc.ignoreMaxMemoryRows,
+		spillBudget:         newSpillBudget(vc.config, logStats),
 		vschema:             vc.vschema,
 		vm:                  vc.vm,
 		semTable:            vc.semTable,
@@ -338,6 +384,7 @@ func (vc *VCursorImpl) cloneWithAutocommitSession() *VCursorImpl {
 		executor:       vc.executor,
 		logStats:       vc.logStats,
 		metrics:        vc.metrics,
+		spillBudget:    vc.spillBudget,
 
 		resolver:   vc.resolver,
 		vschema:    vc.vschema,
@@ -352,6 +399,27 @@ func (vc *VCursorImpl) GetExecutionMetrics() *engine.Metrics {
 	return vc.metrics.GetExecutionMetrics()
 }
 
+// ReadLocalInfile implements the VCursor interface
+func (vc *VCursorImpl) ReadLocalInfile(ctx context.Context, filename string, callback func(data []byte) error) error {
+	reader, ok := ctx.Value(localInfileReaderKey).(LocalInfileReader)
+	if !ok {
+		return vterrors.VT12001("LOAD DATA LOCAL INFILE outside of the MySQL protocol")
+	}
+	return reader(filename, callback)
+}
+
+// LocalInfileReader reads a file from the client of the current connection.
+type LocalInfileReader func(filename string, callback func(data []byte) error) error
+
+type localInfileKey int
+
+const localInfileReaderKey localInfileKey = 0
+
+// WithLocalInfileReader returns a context that lets LOAD DATA LOCAL INFILE read files with the given reader
+func WithLocalInfileReader(ctx context.Context, reader LocalInfileReader) context.Context {
+	return context.WithValue(ctx, localInfileReaderKey, reader)
+}
+
 // HasSystemVariables returns whether the session has set system variables or not
 func (vc *VCursorImpl) HasSystemVariables() bool {
 	return vc.SafeSession.HasSystemVariables()
@@ -400,6 +468,11 @@ func (vc *VCursorImpl) ExceedsMaxMemoryRows(numRows int) bool {
 	return !vc.ignoreMaxMemoryRows && numRows > vc.config.MaxMemoryRows
 }
 
+// SpillBudget implements the VCursor interface.
+func (vc *VCursorImpl) SpillBudget() *engine.SpillBudget {
+	return vc.spillBudget
+}
+
 // SetIgnoreMaxMemoryRows sets the ignoreMaxMemoryRows value.
 func (vc *VCursorImpl) SetIgnoreMaxMemoryRows(ignoreMaxMemoryRows bool) {
 	vc.ignoreMaxMemoryRows = ignoreMaxMemoryRows
@@ -1274,6 +1347,23 @@ func (vc *VCursorImpl) GetMigrationContext() string {
 	return vc.SafeSession.GetMigrationContext()
 }
 
+// SetTenantID implements the SessionActions interface
+func (vc *VCursorImpl) SetTenantID(tenantID string) error {
+	if vc.config.TenantIDSource == TenantIDFromCaller {
+		return vterrors.Errorf(vtrpcpb.Code_PERMISSION_DENIED, "tenant id is taken from the caller identity and cannot be set for the session")
+	}
+	vc.SafeSession.SetTenantID(tenantID)
+	return nil
+}
+
+// GetTenantID implements the SessionActions interface
+func (vc *VCursorImpl) GetTenantID(ctx context.Context) string {
+	if vc.config.TenantIDSource == TenantIDFromCaller {
+		return callerid.GetUsername(callerid.ImmediateCallerIDFromContext(ctx))
+	}
+	return vc.SafeSession.GetTenantID()
+}
+
 // GetSessionUUID implements the SessionActions interface
 func (vc *VCursorImpl) GetSessionUUID() string {
 	return vc.SafeSession.GetSessionUUID()
@@ -1338,6 +1428,16 @@ func (vc *VCursorImpl) RemoveAdvisoryLock(name string) {
 	vc.SafeSession.RemoveAdvisoryLock(name)
 }
 
+// InLockTables implements the SessionActions interface
+func (vc *VCursorImpl) InLockTables() bool {
+	return vc.SafeSession.InLockTables()
+}
+
+// SetInLockTables implements the SessionActions interface
+func (vc *VCursorImpl) SetInLockTables() {
+	vc.SafeSession.SetInLockTables()
+}
+
 func (vc *VCursorImpl) SetCommitOrder(co vtgatepb.CommitOrder) {
 	vc.SafeSession.SetCommitOrder(co)
 }
@@ -1581,6 +1681,11 @@ func (vc *VCursorImpl) ReleaseLock(ctx context.Context) error {
 	return vc.executor.ReleaseLock(ctx, vc.SafeSession)
 }
 
+// ReleaseLockTables implements the VCursor interface
+func (vc *VCursorImpl) ReleaseLockTables(ctx context.Context) error {
+	return vc.executor.ReleaseLockTables(ctx, vc.SafeSession)
+}
+
 func (vc *VCursorImpl) VExplainLogging() {
 	vc.SafeSession.EnableLogging(vc.Environment().Parser())
 }
@@ -1597,6 +1702,23 @@ func (vc *VCursorImpl) IsViewsEnabled() bool {
 	return vc.config.EnableViews
 }
 
+func (vc *VCursorImpl) IsTenantIsolationEnabled() bool {
+	return vc.config.TenantIDSource != ""
+}
+
+// ShardCount returns the number of shards of the keyspace, or zero if they
+// cannot be resolved.
+func (vc *VCursorImpl) ShardCount(ctx context.Context, keyspace string) int {
+	if vc.resolver == nil {
+		return 0
+	}
+	rss, _, err := vc.resolver.ResolveDestinations(ctx, keyspace, vc.tabletType, nil, []key.ShardDestination{key.DestinationAllShards{}})
+	if err != nil {
+		return 0
+	}
+	return len(rss)
+}
+
 func (vc *VCursorImpl) GetUDV(name string) *querypb.BindVariable {
 	return vc.SafeSession.GetUDV(name)
 }
@@ -1642,6 +1764,13 @@ func (vc *VCursorImpl) RecordMirrorStats(sourceExecTime, targetExecTime time.Dur
 	vc.logStats.MirrorTargetError = targetErr
 }
 
+// RecordMirrorResultDiff is used to record how the results of a mirror query
+// differ from those of the source. Differences are logged at a throttled rate.
+func (vc *VCursorImpl) RecordMirrorResultDiff(diff string) {
+	vc.logStats.MirrorTargetResultDiff = diff
+	logMirrorResultDiff.Warningf("mirror target results differ from source: %s, query: %s", diff, vc.Environment().Parser().TruncateForLog(vc.logStats.SQL))
+}
+
 func (vc *VCursorImpl) GetMarginComments() sqlparser.MarginComments {
 	return vc.marginComments
 }
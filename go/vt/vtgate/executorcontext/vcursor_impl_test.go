@@ -29,6 +29,7 @@ import (
 	"vitess.io/vitess/go/streamlog"
 
 	"vitess.io/vitess/go/sqltypes"
+	"vitess.io/vitess/go/vt/callerid"
 	binlogdatapb "vitess.io/vitess/go/vt/proto/binlogdata"
 	"vitess.io/vitess/go/vt/topo"
 	"vitess.io/vitess/go/vt/vtenv"
@@ -326,6 +327,45 @@ func TestRecordMirrorStats(t *testing.T) {
 	require.ErrorContains(t, logStats.MirrorTargetError, "test error")
 }
 
+func TestRecordMirrorResultDiff(t *testing.T) {
+	safeSession := NewSafeSession(nil)
+	logStats := logstats.NewLogStats(context.Background(), t.Name(), "select 1", "", nil, streamlog.NewQueryLogConfigForTest())
+	vc, err := NewVCursorImpl(safeSession, sqlparser.MarginComments{}, fakeExecutor{}, logStats, nil, &vindexes.VSchema{}, nil, nil, fakeObserver{}, VCursorConfig{}, nil)
+	require.NoError(t, err)
+
+	require.Empty(t, logStats.MirrorTargetResultDiff)
+
+	vc.RecordMirrorResultDiff("row count: source 2, target 1")
+
+	require.Equal(t, "row count: source 2, target 1", logStats.MirrorTargetResultDiff)
+}
+
+func TestTenantID(t *testing.T) {
+	ctx := callerid.NewContext(context.Background(), nil, callerid.NewImmediateCallerID("acme"))
+
+	safeSession := NewSafeSession(nil)
+	vc, err := NewVCursorImpl(safeSession, sqlparser.MarginComments{}, nil, nil, nil, &vindexes.VSchema{}, nil, nil, fakeObserver{}, VCursorConfig{
+		TenantIDSource: TenantIDFromSession,
+	}, nil)
+	require.NoError(t, err)
+	require.True(t, vc.IsTenantIsolationEnabled())
+
+	require.NoError(t, vc.SetTenantID("42"))
+	require.Equal(t, "42", vc.GetTenantID(ctx))
+	require.Equal(t, "42", safeSession.TenantId)
+
+	// The identity of the caller cannot be overridden by the session.
+	safeSession = NewSafeSession(nil)
+	vc, err = NewVCursorImpl(safeSession, sqlparser.MarginComments{}, nil, nil, nil, &vindexes.VSchema{}, nil, nil, fakeObserver{}, VCursorConfig{
+		TenantIDSource: TenantIDFromCaller,
+	}, nil)
+	require.NoError(t, err)
+
+	require.ErrorContains(t, vc.SetTenantID("42"), "tenant id is taken from the caller identity")
+	require.Equal(t, "acme", vc.GetTenantID(ctx))
+	require.Empty(t, safeSession.TenantId)
+}
+
 type fakeExecutor struct{}
 
 func (f fakeExecutor) Execute(ctx context.Context, mysqlCtx vtgateservice.MySQLConnection, method string, session *SafeSession, s string, vars map[string]*querypb.BindVariable, prepared bool) (*sqltypes.Result, error) {
@@ -368,6 +408,11 @@ func (f fakeExecutor) ReleaseLock(ctx context.Context, session *SafeSession) err
 	panic("implement me")
 }
 
+func (f fakeExecutor) ReleaseLockTables(ctx context.Context, session *SafeSession) error {
+	// TODO implement me
+	panic("implement me")
+}
+
 func (f fakeExecutor) ShowVitessReplicationStatus(ctx context.Context, filter *sqlparser.ShowFilter) (*sqltypes.Result, error) {
 	// TODO implement me
 	panic("implement me")
@@ -44,6 +44,7 @@ type LogStats struct {
 	StartTime               time.Time
 	EndTime                 time.Time
 	ShardQueries            uint64
+	SpillBytes              uint64 // SpillBytes is the number of bytes of rows written to temporary files
 	RowsAffected            uint64
 	RowsReturned            uint64
 	PlanTime                time.Duration
@@ -57,6 +58,7 @@ type LogStats struct {
 	MirrorSourceExecuteTime time.Duration
 	MirrorTargetExecuteTime time.Duration
 	MirrorTargetError       error
+	MirrorTargetResultDiff  string // MirrorTargetResultDiff describes how the mirror target results differ from the source
 }
 
 // NewLogStats constructs a new LogStats with supplied Method and ctx
@@ -196,6 +198,8 @@ func (stats *LogStats) Logf(w io.Writer, params url.Values) error {
 	log.Duration(stats.MirrorTargetExecuteTime)
 	log.Key("MirrorTargetError")
 	log.String(stats.MirrorTargetErrorStr())
+	log.Key("MirrorTargetResultDiff")
+	log.String(stats.MirrorTargetResultDiff)
 
 	return log.Flush(w)
 }
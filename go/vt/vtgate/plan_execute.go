@@ -20,11 +20,15 @@ import (
 	"context"
 	"fmt"
 	"strings"
+	"sync/atomic"
 	"time"
 
+	"vitess.io/vitess/go/cache/theine"
 	"vitess.io/vitess/go/sqltypes"
+	"vitess.io/vitess/go/vt/callerid"
 	"vitess.io/vitess/go/vt/log"
 	querypb "vitess.io/vitess/go/vt/proto/query"
+	topodatapb "vitess.io/vitess/go/vt/proto/topodata"
 	vtrpcpb "vitess.io/vitess/go/vt/proto/vtrpc"
 	"vitess.io/vitess/go/vt/sqlparser"
 	"vitess.io/vitess/go/vt/vterrors"
@@ -329,7 +333,29 @@ func (e *Executor) executePlan(
 ) (*sqltypes.Result, error) {
 
 	// 4: Execute!
-	qr, err := vcursor.ExecutePrimitive(ctx, plan.Instructions, bindVars, true)
+	exec := func() (*sqltypes.Result, error) {
+		return vcursor.ExecutePrimitive(ctx, plan.Instructions, bindVars, true)
+	}
+	consolidate := e.shouldConsolidate(safeSession, plan, vcursor)
+	useResultCache := e.useResultCache(safeSession, plan, vcursor)
+	var key theine.HashKey256
+	if consolidate || useResultCache {
+		key = e.resultKey(ctx, plan, vcursor, bindVars)
+	}
+	if consolidate {
+		execPrimitive := exec
+		exec = func() (*sqltypes.Result, error) {
+			return e.consolidator.execute(ctx, key, execPrimitive)
+		}
+	}
+
+	var qr *sqltypes.Result
+	var err error
+	if useResultCache {
+		qr, err = e.resultCache.execute(key, plan, exec)
+	} else {
+		qr, err = exec()
+	}
 
 	// 5: Log and add statistics
 	e.setLogStats(logStats, plan, vcursor, execStart, err, qr)
@@ -341,6 +367,45 @@ func (e *Executor) executePlan(
 	return qr, nil
 }
 
+// useResultCache returns true if the result of the plan can be served from,
+// and stored in, the result cache: the plan has a result cache TTL and runs
+// on a replica or rdonly tablet, outside of any transaction or reserved
+// connection.
+func (e *Executor) useResultCache(safeSession *econtext.SafeSession, plan *engine.Plan, vcursor *econtext.VCursorImpl) bool {
+	if e.resultCache == nil || plan.ResultCacheTTL <= 0 {
+		return false
+	}
+	switch vcursor.TabletType() {
+	case topodatapb.TabletType_REPLICA, topodatapb.TabletType_RDONLY:
+	default:
+		return false
+	}
+	return !safeSession.InTransaction() && !safeSession.InReservedConn()
+}
+
+// shouldConsolidate returns true if the plan can share its result with the
+// identical queries in flight: the consolidator is enabled for the query and
+// its target, and the plan is a read-only select outside of any transaction
+// or reserved connection.
+func (e *Executor) shouldConsolidate(safeSession *econtext.SafeSession, plan *engine.Plan, vcursor *econtext.VCursorImpl) bool {
+	if !plan.Consolidate || !e.consolidator.enabled(plan.QueryHints.Consolidator, vcursor.TabletType()) {
+		return false
+	}
+	return !safeSession.InTransaction() && !safeSession.InReservedConn()
+}
+
+// resultKey returns the key of the result of the plan, in the result cache
+// and the consolidator.
+func (e *Executor) resultKey(ctx context.Context, plan *engine.Plan, vcursor *econtext.VCursorImpl, bindVars map[string]*querypb.BindVariable) theine.HashKey256 {
+	var setVarComment string
+	if e.vConfig.SetVarEnabled {
+		setVarComment = vcursor.PrepareSetVarComment()
+	}
+	planKey := buildPlanKey(ctx, vcursor, plan.Original, setVarComment)
+	caller := callerid.GetUsername(callerid.ImmediateCallerIDFromContext(ctx))
+	return resultCacheKey(planKey, vcursor.GetTenantID(ctx), caller, bindVars)
+}
+
 // rollbackExecIfNeeded rollbacks the partial execution if earlier it was detected that it needs partial query execution to be rolled back.
 func (e *Executor) rollbackExecIfNeeded(ctx context.Context, safeSession *econtext.SafeSession, bindVars map[string]*querypb.BindVariable, logStats *logstats.LogStats, err error) error {
 	if !safeSession.InTransaction() {
@@ -413,6 +478,9 @@ func (e *Executor) setLogStats(logStats *logstats.LogStats, plan *engine.Plan, v
 	logStats.TabletType = vcursor.TabletType().String()
 	errCount := e.logExecutionEnd(logStats, execStart, plan, vcursor, err, qr)
 	plan.AddStats(1, time.Since(logStats.StartTime), logStats.ShardQueries, logStats.RowsAffected, logStats.RowsReturned, errCount)
+	if spilled := atomic.LoadUint64(&logStats.SpillBytes); spilled > 0 {
+		plan.AddSpillBytes(spilled)
+	}
 }
 
 func (e *Executor) logExecutionEnd(logStats *logstats.LogStats, execStart time.Time, plan *engine.Plan, vcursor *econtext.VCursorImpl, err error, qr *sqltypes.Result) uint64 {
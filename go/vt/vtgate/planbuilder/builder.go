@@ -49,7 +49,7 @@ type (
 		tables    []string
 	}
 
-	stmtPlanner func(sqlparser.Statement, *sqlparser.ReservedVars, plancontext.VSchema) (*planResult, error)
+	stmtPlanner func(context.Context, sqlparser.Statement, *sqlparser.ReservedVars, plancontext.VSchema) (*planResult, error)
 )
 
 func newPlanResult(prim engine.Primitive, tablesUsed ...string) *planResult {
@@ -147,11 +147,11 @@ func getPlannerFromQueryHint(stmt sqlparser.Statement) (plancontext.PlannerVersi
 	return plancontext.PlannerNameToVersion(val)
 }
 
-func buildRoutePlan(stmt sqlparser.Statement, reservedVars *sqlparser.ReservedVars, vschema plancontext.VSchema, f func(statement sqlparser.Statement, reservedVars *sqlparser.ReservedVars, schema plancontext.VSchema) (*planResult, error)) (*planResult, error) {
+func buildRoutePlan(ctx context.Context, stmt sqlparser.Statement, reservedVars *sqlparser.ReservedVars, vschema plancontext.VSchema, f stmtPlanner) (*planResult, error) {
 	if vschema.ShardDestination() != nil {
 		return buildPlanForBypass(stmt, reservedVars, vschema)
 	}
-	return f(stmt, reservedVars, vschema)
+	return f(ctx, stmt, reservedVars, vschema)
 }
 
 func createInstructionFor(ctx context.Context, query string, stmt sqlparser.Statement, reservedVars *sqlparser.ReservedVars, vschema plancontext.VSchema, cfg dynamicconfig.DDL) (*planResult, error) {
@@ -161,13 +161,13 @@ func createInstructionFor(ctx context.Context, query string, stmt sqlparser.Stat
 		if err != nil {
 			return nil, err
 		}
-		return buildRoutePlan(stmt, reservedVars, vschema, configuredPlanner)
+		return buildTenantIsolatedPlan(ctx, stmt, reservedVars, vschema, configuredPlanner)
 	case *sqlparser.Union:
 		configuredPlanner, err := getConfiguredPlanner(vschema, stmt, query)
 		if err != nil {
 			return nil, err
 		}
-		return buildRoutePlan(stmt, reservedVars, vschema, configuredPlanner)
+		return buildTenantIsolatedPlan(ctx, stmt, reservedVars, vschema, configuredPlanner)
 	case sqlparser.DDLStatement:
 		return buildGeneralDDLPlan(ctx, query, stmt, reservedVars, vschema, cfg)
 	case *sqlparser.AlterMigration:
@@ -187,19 +187,22 @@ func createInstructionFor(ctx context.Context, query string, stmt sqlparser.Stat
 	case *sqlparser.ExplainTab:
 		return explainTabPlan(stmt, vschema)
 	case *sqlparser.ExplainStmt:
-		return buildRoutePlan(stmt, reservedVars, vschema, buildExplainStmtPlan)
+		return buildRoutePlan(ctx, stmt, reservedVars, vschema, buildExplainStmtPlan)
 	case *sqlparser.VExplainStmt:
 		return buildVExplainPlan(ctx, stmt, reservedVars, vschema, cfg)
 	case *sqlparser.OtherAdmin:
 		return buildOtherReadAndAdmin(query, vschema)
 	case *sqlparser.Analyze:
-		return buildRoutePlan(stmt, reservedVars, vschema, buildAnalyzePlan)
+		return buildRoutePlan(ctx, stmt, reservedVars, vschema, buildAnalyzePlan)
 	case *sqlparser.Set:
 		return buildSetPlan(stmt, vschema)
 	case *sqlparser.Load:
-		return buildLoadPlan(query, vschema)
+		if err := checkTenantIsolatedLoad(stmt, vschema); err != nil {
+			return nil, err
+		}
+		return buildLoadPlan(query, stmt, vschema)
 	case sqlparser.DBDDLStatement:
-		return buildRoutePlan(stmt, reservedVars, vschema, buildDBDDLPlan)
+		return buildRoutePlan(ctx, stmt, reservedVars, vschema, buildDBDDLPlan)
 	case *sqlparser.Begin, *sqlparser.Commit, *sqlparser.Rollback,
 		*sqlparser.Savepoint, *sqlparser.SRollback, *sqlparser.Release,
 		*sqlparser.Kill:
@@ -208,9 +211,9 @@ func createInstructionFor(ctx context.Context, query string, stmt sqlparser.Stat
 	case *sqlparser.Show:
 		return buildShowPlan(query, stmt, reservedVars, vschema)
 	case *sqlparser.LockTables:
-		return buildRoutePlan(stmt, reservedVars, vschema, buildLockPlan)
+		return buildRoutePlan(ctx, stmt, reservedVars, vschema, buildLockPlan)
 	case *sqlparser.UnlockTables:
-		return buildRoutePlan(stmt, reservedVars, vschema, buildUnlockPlan)
+		return buildRoutePlan(ctx, stmt, reservedVars, vschema, buildUnlockPlan)
 	case *sqlparser.Flush:
 		return buildFlushPlan(stmt, vschema)
 	case *sqlparser.CallProc:
@@ -234,7 +237,7 @@ func createInstructionFor(ctx context.Context, query string, stmt sqlparser.Stat
 	return nil, vterrors.VT13001(fmt.Sprintf("unexpected statement type: %T", stmt))
 }
 
-func buildAnalyzePlan(stmt sqlparser.Statement, _ *sqlparser.ReservedVars, vschema plancontext.VSchema) (*planResult, error) {
+func buildAnalyzePlan(_ context.Context, stmt sqlparser.Statement, _ *sqlparser.ReservedVars, vschema plancontext.VSchema) (*planResult, error) {
 	analyzeStmt := stmt.(*sqlparser.Analyze)
 
 	var ks *vindexes.Keyspace
@@ -271,7 +274,7 @@ func buildAnalyzePlan(stmt sqlparser.Statement, _ *sqlparser.ReservedVars, vsche
 	return newPlanResult(prim, sqlparser.String(analyzeStmt.Table)), nil
 }
 
-func buildDBDDLPlan(stmt sqlparser.Statement, _ *sqlparser.ReservedVars, vschema plancontext.VSchema) (*planResult, error) {
+func buildDBDDLPlan(_ context.Context, stmt sqlparser.Statement, _ *sqlparser.ReservedVars, vschema plancontext.VSchema) (*planResult, error) {
 	dbDDLstmt := stmt.(sqlparser.DBDDLStatement)
 	ksName := dbDDLstmt.GetDatabaseName()
 	if ksName == "" {
@@ -309,29 +312,6 @@ func buildDBDDLPlan(stmt sqlparser.Statement, _ *sqlparser.ReservedVars, vschema
 	return nil, vterrors.VT13001(fmt.Sprintf("database DDL not recognized: %s", sqlparser.String(dbDDLstmt)))
 }
 
-func buildLoadPlan(query string, vschema plancontext.VSchema) (*planResult, error) {
-	keyspace, err := vschema.SelectedKeyspace()
-	if err != nil {
-		return nil, err
-	}
-
-	destination := vschema.ShardDestination()
-	if destination == nil {
-		if err := vschema.ErrorIfShardedF(keyspace, "LOAD", "LOAD is not supported on sharded keyspace"); err != nil {
-			return nil, err
-		}
-		destination = key.DestinationAnyShard{}
-	}
-
-	return newPlanResult(&engine.Send{
-		Keyspace:          keyspace,
-		TargetDestination: destination,
-		Query:             query,
-		IsDML:             true,
-		SingleShardOnly:   true,
-	}), nil
-}
-
 func buildVSchemaDDLPlan(stmt *sqlparser.AlterVschema, vschema plancontext.VSchema) (*planResult, error) {
 	_, keyspace, _, err := vschema.TargetDestination(stmt.Table.Qualifier.String())
 	if err != nil {
@@ -228,7 +228,7 @@ func buildCreateViewCommon(
 	ddl sqlparser.DDLStatement,
 ) (key.ShardDestination, *vindexes.Keyspace, error) {
 	if vschema.IsViewsEnabled() {
-		return createViewEnabled(vschema, reservedVars, sel, ddl)
+		return createViewEnabled(ctx, vschema, reservedVars, sel, ddl)
 	}
 
 	// For Create View, we require that the keyspace exist and the select query can be satisfied within the keyspace itself
@@ -281,7 +281,7 @@ func buildCreateViewCommon(
 	return destination, keyspace, nil
 }
 
-func createViewEnabled(vschema plancontext.VSchema, reservedVars *sqlparser.ReservedVars, ddlSelect sqlparser.TableStatement, ddl sqlparser.DDLStatement) (key.ShardDestination, *vindexes.Keyspace, error) {
+func createViewEnabled(ctx context.Context, vschema plancontext.VSchema, reservedVars *sqlparser.ReservedVars, ddlSelect sqlparser.TableStatement, ddl sqlparser.DDLStatement) (key.ShardDestination, *vindexes.Keyspace, error) {
 	// For Create View, we require that the keyspace exist and the select query can be satisfied within the keyspace itself
 	// We should remove the keyspace name from the table name, as the database name in MySQL might be different than the keyspace name
 	destination, keyspace, err := findTableDestinationAndKeyspace(vschema, ddl)
@@ -297,7 +297,7 @@ func createViewEnabled(vschema plancontext.VSchema, reservedVars *sqlparser.Rese
 		return nil
 	})
 
-	pCtx, err := plancontext.CreatePlanningContext(ddlSelect, reservedVars, vschema, Gen4)
+	pCtx, err := plancontext.CreatePlanningContext(ctx, ddlSelect, reservedVars, vschema, Gen4)
 	if err != nil {
 		return nil, nil, err
 	}
@@ -17,6 +17,8 @@ limitations under the License.
 package planbuilder
 
 import (
+	"context"
+
 	querypb "vitess.io/vitess/go/vt/proto/query"
 	"vitess.io/vitess/go/vt/sqlparser"
 	"vitess.io/vitess/go/vt/vterrors"
@@ -27,15 +29,12 @@ import (
 )
 
 func gen4DeleteStmtPlanner(
+	ctx context.Context,
 	version querypb.ExecuteOptions_PlannerVersion,
 	deleteStmt *sqlparser.Delete,
 	reservedVars *sqlparser.ReservedVars,
 	vschema plancontext.VSchema,
 ) (*planResult, error) {
-	if deleteStmt.With != nil {
-		return nil, vterrors.VT12001("WITH expression in DELETE statement")
-	}
-
 	var err error
 	if len(deleteStmt.TableExprs) == 1 && len(deleteStmt.Targets) == 1 {
 		deleteStmt, err = rewriteSingleTbl(deleteStmt)
@@ -44,24 +43,24 @@ func gen4DeleteStmtPlanner(
 		}
 	}
 
-	ctx, err := plancontext.CreatePlanningContext(deleteStmt, reservedVars, vschema, version)
+	pCtx, err := plancontext.CreatePlanningContext(ctx, deleteStmt, reservedVars, vschema, version)
 	if err != nil {
 		return nil, err
 	}
 
-	err = queryRewrite(ctx, deleteStmt)
+	err = queryRewrite(pCtx, deleteStmt)
 	if err != nil {
 		return nil, err
 	}
 
 	// Remove all the foreign keys that don't require any handling.
-	err = ctx.SemTable.RemoveNonRequiredForeignKeys(ctx.VerifyAllFKs, vindexes.DeleteAction)
+	err = pCtx.SemTable.RemoveNonRequiredForeignKeys(pCtx.VerifyAllFKs, vindexes.DeleteAction)
 	if err != nil {
 		return nil, err
 	}
 
-	if ks, tables := ctx.SemTable.SingleUnshardedKeyspace(); ks != nil {
-		if !ctx.SemTable.ForeignKeysPresent() {
+	if ks, tables := pCtx.SemTable.SingleUnshardedKeyspace(); ks != nil {
+		if !pCtx.SemTable.ForeignKeysPresent() {
 			plan := deleteUnshardedShortcut(deleteStmt, ks, tables)
 			return newPlanResult(plan, operators.QualifiedTables(ks, tables)...), nil
 		}
@@ -69,16 +68,16 @@ func gen4DeleteStmtPlanner(
 
 	// error out here if delete query cannot bypass the planner and
 	// planner cannot plan such query due to different reason like missing full information, etc.
-	if ctx.SemTable.NotUnshardedErr != nil {
-		return nil, ctx.SemTable.NotUnshardedErr
+	if pCtx.SemTable.NotUnshardedErr != nil {
+		return nil, pCtx.SemTable.NotUnshardedErr
 	}
 
-	op, err := operators.PlanQuery(ctx, deleteStmt)
+	op, err := operators.PlanQuery(pCtx, deleteStmt)
 	if err != nil {
 		return nil, err
 	}
 
-	plan, err := transformToPrimitive(ctx, op)
+	plan, err := transformToPrimitive(pCtx, op)
 	if err != nil {
 		return nil, err
 	}
@@ -17,6 +17,8 @@ limitations under the License.
 package planbuilder
 
 import (
+	"context"
+
 	querypb "vitess.io/vitess/go/vt/proto/query"
 	"vitess.io/vitess/go/vt/sqlparser"
 	"vitess.io/vitess/go/vt/vterrors"
@@ -27,13 +29,13 @@ import (
 	"vitess.io/vitess/go/vt/vtgate/vindexes"
 )
 
-func gen4InsertStmtPlanner(version querypb.ExecuteOptions_PlannerVersion, insStmt *sqlparser.Insert, reservedVars *sqlparser.ReservedVars, vschema plancontext.VSchema) (*planResult, error) {
-	ctx, err := plancontext.CreatePlanningContext(insStmt, reservedVars, vschema, version)
+func gen4InsertStmtPlanner(ctx context.Context, version querypb.ExecuteOptions_PlannerVersion, insStmt *sqlparser.Insert, reservedVars *sqlparser.ReservedVars, vschema plancontext.VSchema) (*planResult, error) {
+	pCtx, err := plancontext.CreatePlanningContext(ctx, insStmt, reservedVars, vschema, version)
 	if err != nil {
 		return nil, err
 	}
 
-	err = queryRewrite(ctx, insStmt)
+	err = queryRewrite(pCtx, insStmt)
 	if err != nil {
 		return nil, err
 	}
@@ -43,21 +45,21 @@ func gen4InsertStmtPlanner(version querypb.ExecuteOptions_PlannerVersion, insStm
 
 	// Check single unsharded. Even if the table is for single unsharded but sequence table is used.
 	// We cannot shortcut here as sequence column needs additional planning.
-	ks, tables := ctx.SemTable.SingleUnshardedKeyspace()
+	ks, tables := pCtx.SemTable.SingleUnshardedKeyspace()
 	// Remove all the foreign keys that don't require any handling.
-	err = ctx.SemTable.RemoveNonRequiredForeignKeys(ctx.VerifyAllFKs, vindexes.UpdateAction)
+	err = pCtx.SemTable.RemoveNonRequiredForeignKeys(pCtx.VerifyAllFKs, vindexes.UpdateAction)
 	if err != nil {
 		return nil, err
 	}
 	if ks != nil {
-		if tables[0].AutoIncrement == nil && !ctx.SemTable.ForeignKeysPresent() {
-			plan := insertUnshardedShortcut(ctx, insStmt, ks, tables)
+		if tables[0].AutoIncrement == nil && !pCtx.SemTable.ForeignKeysPresent() {
+			plan := insertUnshardedShortcut(pCtx, insStmt, ks, tables)
 			setCommentDirectivesOnPlan(plan, insStmt)
 			return newPlanResult(plan, operators.QualifiedTables(ks, tables)...), nil
 		}
 	}
 
-	tblInfo, err := ctx.SemTable.TableInfoFor(ctx.SemTable.TableSetFor(insStmt.Table))
+	tblInfo, err := pCtx.SemTable.TableInfoFor(pCtx.SemTable.TableSetFor(insStmt.Table))
 	if err != nil {
 		return nil, err
 	}
@@ -66,16 +68,16 @@ func gen4InsertStmtPlanner(version querypb.ExecuteOptions_PlannerVersion, insStm
 		return nil, vterrors.VT09014()
 	}
 
-	if err = errOutIfPlanCannotBeConstructed(ctx, tblInfo.GetVindexTable()); err != nil {
+	if err = errOutIfPlanCannotBeConstructed(pCtx, tblInfo.GetVindexTable()); err != nil {
 		return nil, err
 	}
 
-	op, err := operators.PlanQuery(ctx, insStmt)
+	op, err := operators.PlanQuery(pCtx, insStmt)
 	if err != nil {
 		return nil, err
 	}
 
-	plan, err := transformToPrimitive(ctx, op)
+	plan, err := transformToPrimitive(pCtx, op)
 	if err != nil {
 		return nil, err
 	}
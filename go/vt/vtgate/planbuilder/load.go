@@ -0,0 +1,177 @@
+/*
+Copyright 2025 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package planbuilder
+
+import (
+	"slices"
+
+	"vitess.io/vitess/go/vt/key"
+	"vitess.io/vitess/go/vt/sqlparser"
+	"vitess.io/vitess/go/vt/vterrors"
+	"vitess.io/vitess/go/vt/vtgate/engine"
+	"vitess.io/vitess/go/vt/vtgate/planbuilder/operators"
+	"vitess.io/vitess/go/vt/vtgate/planbuilder/plancontext"
+	"vitess.io/vitess/go/vt/vtgate/vindexes"
+)
+
+func buildLoadPlan(query string, stmt *sqlparser.Load, vschema plancontext.VSchema) (*planResult, error) {
+	if stmt.Local {
+		return buildLoadLocalPlan(stmt, vschema)
+	}
+
+	// the file lives on the MySQL server, so the statement can only be sent as is
+	keyspace, err := vschema.SelectedKeyspace()
+	if err != nil {
+		return nil, err
+	}
+
+	destination := vschema.ShardDestination()
+	if destination == nil {
+		if err := vschema.ErrorIfShardedF(keyspace, "LOAD", "LOAD is not supported on sharded keyspace"); err != nil {
+			return nil, err
+		}
+		destination = key.DestinationAnyShard{}
+	}
+
+	return newPlanResult(&engine.Send{
+		Keyspace:          keyspace,
+		TargetDestination: destination,
+		Query:             query,
+		IsDML:             true,
+		SingleShardOnly:   true,
+	}), nil
+}
+
+// buildLoadLocalPlan plans a LOAD DATA LOCAL INFILE. vtgate reads the file from the client,
+// and inserts the rows the same way as an INSERT ... SELECT: the rows are routed to the shards
+// using the vindexes of the table, and the lookup vindexes are kept up to date.
+func buildLoadLocalPlan(stmt *sqlparser.Load, vschema plancontext.VSchema) (*planResult, error) {
+	vTbl, _, _, dest, err := vschema.FindTable(stmt.Table)
+	if err != nil {
+		return nil, err
+	}
+	if dest != nil {
+		return nil, vterrors.VT09017("LOAD DATA with a target destination is not allowed")
+	}
+	if err := checkLoadLocal(stmt); err != nil {
+		return nil, err
+	}
+
+	columns := stmt.Columns
+	if len(columns) == 0 {
+		if !vTbl.ColumnListAuthoritative {
+			return nil, vterrors.VT09004()
+		}
+		for _, col := range vTbl.Columns {
+			columns = append(columns, col.Name)
+		}
+	}
+
+	loadData := &engine.LoadData{
+		FileName:           stmt.Infile,
+		FieldsTerminatedBy: stmt.FieldsTerminatedBy,
+		FieldsEnclosedBy:   stmt.FieldsEnclosedBy,
+		FieldsEscapedBy:    stmt.FieldsEscapedBy,
+		LinesStartingBy:    stmt.LinesStartingBy,
+		LinesTerminatedBy:  stmt.LinesTerminatedBy,
+		IgnoreLines:        stmt.IgnoreLines,
+	}
+	for _, col := range columns {
+		loadData.Columns = append(loadData.Columns, col.String())
+	}
+
+	ins := &sqlparser.Insert{
+		// with LOCAL, MySQL turns duplicate key errors into warnings, since it can't stop the client from sending the file
+		Ignore: true,
+		Table: &sqlparser.AliasedTableExpr{
+			Expr:       sqlparser.NewTableName(vTbl.Name.String()),
+			Partitions: stmt.Partitions,
+		},
+		Columns: sqlparser.Clone(columns),
+	}
+
+	eins := &engine.InsertSelect{
+		InsertCommon: engine.InsertCommon{
+			Keyspace:  vTbl.Keyspace,
+			TableName: vTbl.Name.String(),
+			Ignore:    true,
+		},
+		Input: loadData,
+	}
+
+	if vTbl.AutoIncrement != nil {
+		offset := slices.IndexFunc(ins.Columns, vTbl.AutoIncrement.Column.Equal)
+		if offset == -1 {
+			// the generated values are added at the end of every row
+			offset = len(ins.Columns)
+			ins.Columns = append(ins.Columns, vTbl.AutoIncrement.Column)
+		}
+		eins.Generate = autoIncGenerate(&operators.Generate{
+			Keyspace:  vTbl.AutoIncrement.Sequence.Keyspace,
+			TableName: sqlparser.TableName{Name: vTbl.AutoIncrement.Sequence.Name},
+			Offset:    offset,
+		})
+	}
+
+	if vTbl.Keyspace.Sharded {
+		eins.ColVindexes, eins.VindexValueOffset, err = loadVindexOffsets(vTbl, ins.Columns)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	eins.Prefix, _, eins.Suffix = generateInsertShardedQuery(ins)
+	return newPlanResult(eins, singleTable(vTbl.Keyspace.Name, vTbl.Name.String())), nil
+}
+
+func checkLoadLocal(stmt *sqlparser.Load) error {
+	switch {
+	case stmt.Replace:
+		return vterrors.VT12001("REPLACE in LOAD DATA LOCAL INFILE")
+	case len(stmt.SetExprs) > 0:
+		return vterrors.VT12001("SET in LOAD DATA LOCAL INFILE")
+	case stmt.FieldsTerminatedBy == "" || stmt.LinesTerminatedBy == "":
+		return vterrors.VT12001("LOAD DATA LOCAL INFILE with empty field or line terminators")
+	case len(stmt.FieldsEnclosedBy) > 1 || len(stmt.FieldsEscapedBy) > 1:
+		return vterrors.VT12001("LOAD DATA LOCAL INFILE with ENCLOSED BY or ESCAPED BY longer than one character")
+	}
+	return nil
+}
+
+// loadVindexOffsets returns the vindexes that have to be computed for every row,
+// and the offsets of the vindex columns in the rows.
+func loadVindexOffsets(vTbl *vindexes.BaseTable, columns sqlparser.Columns) ([]*vindexes.ColumnVindex, [][]int, error) {
+	var colVindexes []*vindexes.ColumnVindex
+	var offsets [][]int
+	for _, colVindex := range vTbl.ColumnVindexes {
+		if colVindex.IsPartialVindex() {
+			continue
+		}
+		var vindexOffsets []int
+		for _, col := range colVindex.Columns {
+			offset := slices.IndexFunc(columns, col.Equal)
+			// sharding column values must be in the file
+			if offset == -1 && len(colVindexes) == 0 {
+				return nil, nil, vterrors.VT09003(col)
+			}
+			vindexOffsets = append(vindexOffsets, offset)
+		}
+		colVindexes = append(colVindexes, colVindex)
+		offsets = append(offsets, vindexOffsets)
+	}
+	return colVindexes, offsets, nil
+}
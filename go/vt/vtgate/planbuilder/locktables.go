@@ -17,21 +17,68 @@ limitations under the License.
 package planbuilder
 
 import (
-	"vitess.io/vitess/go/sqltypes"
-	"vitess.io/vitess/go/vt/log"
-	querypb "vitess.io/vitess/go/vt/proto/query"
+	"context"
+	"slices"
+
+	"vitess.io/vitess/go/vt/key"
 	"vitess.io/vitess/go/vt/sqlparser"
+	"vitess.io/vitess/go/vt/vterrors"
 	"vitess.io/vitess/go/vt/vtgate/engine"
 	"vitess.io/vitess/go/vt/vtgate/planbuilder/plancontext"
 )
 
 // buildLockPlan plans lock tables statement.
-func buildLockPlan(stmt sqlparser.Statement, _ *sqlparser.ReservedVars, _ plancontext.VSchema) (*planResult, error) {
-	log.Warningf("Lock Tables statement is ignored: %v", stmt)
-	return newPlanResult(engine.NewRowsPrimitive(make([][]sqltypes.Value, 0), make([]*querypb.Field, 0))), nil
+// The tables are locked on every shard of their keyspace, with a single LOCK TABLES statement per keyspace.
+func buildLockPlan(_ context.Context, stmt sqlparser.Statement, _ *sqlparser.ReservedVars, vschema plancontext.VSchema) (*planResult, error) {
+	lock := stmt.(*sqlparser.LockTables)
+
+	lt := &engine.LockTables{TargetDestination: vschema.ShardDestination()}
+	if lt.TargetDestination == nil {
+		lt.TargetDestination = key.DestinationAllShards{}
+	}
+
+	var tables []string
+	queries := map[string]*sqlparser.LockTables{}
+	for _, tbl := range lock.Tables {
+		ate, ok := tbl.Table.(*sqlparser.AliasedTableExpr)
+		if !ok {
+			return nil, vterrors.VT13001("unexpected table expression in LOCK TABLES: " + sqlparser.String(tbl.Table))
+		}
+		tableName, ok := ate.Expr.(sqlparser.TableName)
+		if !ok {
+			return nil, vterrors.VT13001("unexpected table expression in LOCK TABLES: " + sqlparser.String(tbl.Table))
+		}
+		vTbl, _, _, _, err := vschema.FindTable(tableName)
+		if err != nil {
+			return nil, err
+		}
+
+		ksName := vTbl.Keyspace.Name
+		query, exists := queries[ksName]
+		if !exists {
+			query = &sqlparser.LockTables{}
+			queries[ksName] = query
+			lt.Queries = append(lt.Queries, &engine.LockTablesQuery{Keyspace: vTbl.Keyspace})
+		}
+		// the keyspace qualifier is not sent to the shards
+		query.Tables = append(query.Tables, &sqlparser.TableAndLockType{
+			Table: &sqlparser.AliasedTableExpr{
+				Expr: sqlparser.NewTableName(vTbl.Name.String()),
+				As:   ate.As,
+			},
+			Lock: tbl.Lock,
+		})
+		tables = append(tables, singleTable(ksName, vTbl.Name.String()))
+	}
+
+	for _, q := range lt.Queries {
+		q.Query = sqlparser.String(queries[q.Keyspace.Name])
+	}
+	slices.Sort(tables)
+	return newPlanResult(lt, slices.Compact(tables)...), nil
 }
 
 // buildUnlockPlan plans lock tables statement.
-func buildUnlockPlan(stmt sqlparser.Statement, _ *sqlparser.ReservedVars, _ plancontext.VSchema) (*planResult, error) {
+func buildUnlockPlan(_ context.Context, stmt sqlparser.Statement, _ *sqlparser.ReservedVars, _ plancontext.VSchema) (*planResult, error) {
 	return newPlanResult(&engine.Unlock{}), nil
 }
@@ -59,6 +59,8 @@ func recursiveTransform(ctx *plancontext.PlanningContext, op operators.Operator)
 		return transformUnionPlan(ctx, op)
 	case *operators.Vindex:
 		return transformVindexPlan(ctx, op)
+	case *operators.JSONTable:
+		return transformJSONTable(ctx, op)
 	case *operators.SubQuery:
 		return transformSubQuery(ctx, op)
 	case *operators.Filter:
@@ -75,6 +77,8 @@ func recursiveTransform(ctx *plancontext.PlanningContext, op operators.Operator)
 		return transformAggregator(ctx, op)
 	case *operators.Distinct:
 		return transformDistinct(ctx, op)
+	case *operators.Window:
+		return transformWindow(ctx, op)
 	case *operators.FkCascade:
 		return transformFkCascade(ctx, op)
 	case *operators.FkVerify:
@@ -111,7 +115,7 @@ func transformPercentBasedMirror(ctx *plancontext.PlanningContext, op *operators
 		return primitive, nil
 	}
 
-	return engine.NewPercentBasedMirror(op.Percent, primitive, target), nil
+	return engine.NewPercentBasedMirror(op.Percent, op.Compare, primitive, target), nil
 }
 
 func transformDMLWithInput(ctx *plancontext.PlanningContext, op *operators.DMLWithInput) (engine.Primitive, error) {
@@ -169,9 +173,12 @@ func transformSequential(ctx *plancontext.PlanningContext, op *operators.Sequent
 		if err != nil {
 			return nil, err
 		}
-		ins, ok := prim.(*engine.Insert)
-		if ok {
-			ins.PreventAutoCommit = true
+		switch prim := prim.(type) {
+		case *engine.Insert:
+			prim.PreventAutoCommit = true
+		case *engine.Delete:
+			// the delete of a REPLACE INTO is followed by the insert, and both have to be committed together
+			prim.PreventAutoCommit = true
 		}
 
 		prims = append(prims, prim)
@@ -217,6 +224,18 @@ func transformInsertionSelection(ctx *plancontext.PlanningContext, op *operators
 	}
 
 	eins.Input = selectionPlan
+
+	if op.DeleteBeforeInsert != nil {
+		deletePlan, err := transformToPrimitive(ctx, op.DeleteBeforeInsert)
+		if err != nil {
+			return nil, err
+		}
+		if del, ok := deletePlan.(*engine.Delete); ok {
+			del.PreventAutoCommit = true
+		}
+		eins.DeleteBeforeInsert = deletePlan
+		eins.DeleteKeyOffsets = op.DeleteKeyOffsets
+	}
 	return eins, nil
 }
 
@@ -286,6 +305,37 @@ func transformSubQuery(ctx *plancontext.PlanningContext, op *operators.SubQuery)
 		}, nil
 	}
 
+	if op.RowFilter != nil || op.IsArgument {
+		cs := &engine.CorrelatedSubquery{
+			Opcode:         op.FilterType,
+			SubqueryResult: op.SubqueryValueName,
+			HasValues:      op.HasValuesName,
+			Vars:           op.Vars,
+			Outer:          outer,
+			Subquery:       inner,
+		}
+		cfg := &evalengine.Config{
+			ResolveType: ctx.TypeForExpr,
+			Collation:   ctx.SemTable.Collation,
+			Environment: ctx.VSchema.Environment(),
+		}
+		if op.RowFilter != nil {
+			cs.Predicate, err = evalengine.Translate(op.RowFilter, cfg)
+			if err != nil {
+				return nil, err
+			}
+			cs.ASTPredicate = op.RowFilter
+		}
+		if op.RowValue != nil {
+			cs.Value, err = evalengine.Translate(op.RowValue, cfg)
+			if err != nil {
+				return nil, err
+			}
+			cs.ASTValue = op.RowValue
+		}
+		return cs, nil
+	}
+
 	return &engine.SemiJoin{
 		Left:  outer,
 		Right: inner,
@@ -353,6 +403,12 @@ func transformAggregator(ctx *plancontext.PlanningContext, op *operators.Aggrega
 		aggrParam.OrigOpcode = aggr.OriginalOpCode
 		aggrParam.WCol = aggr.WSOffset
 		aggrParam.Type = aggr.GetTypeCollation(ctx)
+		aggrParam.GroupConcatArgs = groupConcatComparison(ctx, aggr.GroupConcatArgs)
+		aggrParam.GroupConcatOrderBy = groupConcatComparison(ctx, aggr.GroupConcatOrderBy)
+		if aggr.OpCode == opcode.AggregateVarPop && op.Pushed {
+			// the source returns the variances of the shards instead of the values
+			aggrParam.CountCol, aggrParam.SumCol = aggr.CountOffset, aggr.SumOffset
+		}
 		aggregates = append(aggregates, aggrParam)
 	}
 
@@ -383,6 +439,21 @@ func transformAggregator(ctx *plancontext.PlanningContext, op *operators.Aggrega
 	}, nil
 }
 
+func groupConcatComparison(ctx *plancontext.PlanningContext, columns []operators.GroupConcatColumn) evalengine.Comparison {
+	var cmp evalengine.Comparison
+	for _, col := range columns {
+		typ, _ := ctx.TypeForExpr(col.Expr)
+		cmp = append(cmp, evalengine.OrderByParams{
+			Col:             col.ColOffset,
+			WeightStringCol: col.WSOffset,
+			Desc:            col.Desc,
+			Type:            typ,
+			CollationEnv:    ctx.VSchema.Environment().CollationEnv(),
+		})
+	}
+	return cmp
+}
+
 func transformDistinct(ctx *plancontext.PlanningContext, op *operators.Distinct) (engine.Primitive, error) {
 	src, err := transformToPrimitive(ctx, op.Source)
 	if err != nil {
@@ -396,6 +467,137 @@ func transformDistinct(ctx *plancontext.PlanningContext, op *operators.Distinct)
 	}, nil
 }
 
+func transformWindow(ctx *plancontext.PlanningContext, op *operators.Window) (engine.Primitive, error) {
+	src, err := transformToPrimitive(ctx, op.Source)
+	if err != nil {
+		return nil, err
+	}
+
+	collationEnv := ctx.VSchema.Environment().CollationEnv()
+	windowKeys := func(keys []operators.WindowKey) []*engine.GroupByParams {
+		return slice.Map(keys, func(key operators.WindowKey) *engine.GroupByParams {
+			typ, _ := ctx.TypeForExpr(key.Inner)
+			return &engine.GroupByParams{
+				KeyCol:          key.Offset,
+				WeightStringCol: key.WSOffset,
+				Expr:            key.Inner,
+				Type:            typ,
+				CollationEnv:    collationEnv,
+			}
+		})
+	}
+
+	var funcs []*engine.WindowFuncParams
+	for _, f := range op.Funcs {
+		wf, err := createWindowFuncParams(ctx, f)
+		if err != nil {
+			return nil, err
+		}
+		funcs = append(funcs, wf)
+	}
+
+	return &engine.Window{
+		PartitionBy: windowKeys(op.PartitionBy),
+		OrderBy:     windowKeys(op.OrderBy),
+		Funcs:       funcs,
+		Input:       src,
+	}, nil
+}
+
+func createWindowFuncParams(ctx *plancontext.PlanningContext, f *operators.WindowFunc) (*engine.WindowFuncParams, error) {
+	wf := &engine.WindowFuncParams{
+		Col:        f.ArgOffset,
+		DefaultCol: f.DefaultOffset,
+		Alias:      f.Original.ColumnName(),
+		Expr:       f.Func,
+	}
+
+	var err error
+	switch fnc := f.Func.(type) {
+	case *sqlparser.ArgumentLessWindowExpr:
+		switch fnc.Type {
+		case sqlparser.RowNumberExprType:
+			wf.Opcode = opcode.WindowRowNumber
+		case sqlparser.RankExprType:
+			wf.Opcode = opcode.WindowRank
+		case sqlparser.DenseRankExprType:
+			wf.Opcode = opcode.WindowDenseRank
+		case sqlparser.PercentRankExprType:
+			wf.Opcode = opcode.WindowPercentRank
+		case sqlparser.CumeDistExprType:
+			wf.Opcode = opcode.WindowCumeDist
+		}
+	case *sqlparser.NtileExpr:
+		wf.Opcode = opcode.WindowNtile
+		wf.N, err = windowFuncArg(ctx, fnc.N, 0)
+	case *sqlparser.LagLeadExpr:
+		wf.Opcode = opcode.WindowLag
+		if fnc.Type == sqlparser.LeadExprType {
+			wf.Opcode = opcode.WindowLead
+		}
+		if fnc.NullTreatmentClause != nil && fnc.NullTreatmentClause.Type == sqlparser.IgnoreNullsType {
+			return nil, vterrors.VT12001("IGNORE NULLS in window functions")
+		}
+		wf.N, err = windowFuncArg(ctx, fnc.N, 1)
+	case *sqlparser.FirstOrLastValueExpr:
+		wf.Opcode = opcode.WindowFirstValue
+		if fnc.Type == sqlparser.LastValueExprType {
+			wf.Opcode = opcode.WindowLastValue
+		}
+		if fnc.NullTreatmentClause != nil && fnc.NullTreatmentClause.Type == sqlparser.IgnoreNullsType {
+			return nil, vterrors.VT12001("IGNORE NULLS in window functions")
+		}
+	case *sqlparser.NTHValueExpr:
+		wf.Opcode = opcode.WindowNthValue
+		if fnc.FromFirstLastClause != nil && fnc.FromFirstLastClause.Type == sqlparser.FromLastType {
+			return nil, vterrors.VT12001("FROM LAST in window functions")
+		}
+		if fnc.NullTreatmentClause != nil && fnc.NullTreatmentClause.Type == sqlparser.IgnoreNullsType {
+			return nil, vterrors.VT12001("IGNORE NULLS in window functions")
+		}
+		wf.N, err = windowFuncArg(ctx, fnc.N, 0)
+	case sqlparser.AggrFunc:
+		code := opcode.SupportedAggregates[fnc.AggrName()]
+		switch code {
+		case opcode.AggregateCount, opcode.AggregateCountStar, opcode.AggregateSum, opcode.AggregateMin, opcode.AggregateMax:
+		default:
+			return nil, vterrors.VT12001(fmt.Sprintf("in scatter query: window function '%s'", sqlparser.String(fnc)))
+		}
+		wf.Opcode = opcode.WindowAggregate
+		wf.Aggregate = engine.NewAggregateParam(code, f.ArgOffset, wf.Alias, ctx.VSchema.Environment().CollationEnv())
+		wf.Aggregate.Func = fnc
+		wf.Aggregate.Original = f.Original
+		if arg := fnc.GetArg(); arg != nil {
+			wf.Aggregate.Type, _ = ctx.TypeForExpr(arg)
+		}
+	}
+	if err != nil {
+		return nil, err
+	}
+	if wf.Opcode == opcode.WindowUnassigned {
+		return nil, vterrors.VT12001(fmt.Sprintf("in scatter query: window function '%s'", sqlparser.String(f.Func)))
+	}
+	return wf, nil
+}
+
+// windowFuncArg returns the integer argument of a window function. It is a literal,
+// or a bind variable once the query is normalized or prepared, that is evaluated
+// when the window function is executed.
+func windowFuncArg(ctx *plancontext.PlanningContext, expr sqlparser.Expr, def int64) (evalengine.Expr, error) {
+	if expr == nil {
+		return evalengine.NewLiteralInt(def), nil
+	}
+	lit, isLit := expr.(*sqlparser.Literal)
+	_, isArg := expr.(*sqlparser.Argument)
+	if !isArg && (!isLit || lit.Type != sqlparser.IntVal) {
+		return nil, vterrors.VT12001(fmt.Sprintf("in scatter query: non-literal window function argument '%s'", sqlparser.String(expr)))
+	}
+	return evalengine.Translate(expr, &evalengine.Config{
+		Collation:   ctx.VSchema.ConnCollation(),
+		Environment: ctx.VSchema.Environment(),
+	})
+}
+
 func transformOrdering(ctx *plancontext.PlanningContext, op *operators.Ordering) (engine.Primitive, error) {
 	plan, err := transformToPrimitive(ctx, op.Source)
 	if err != nil {
@@ -947,24 +1149,31 @@ func transformHashJoin(ctx *plancontext.PlanningContext, op *operators.HashJoin)
 		return nil, err
 	}
 
-	if len(op.LHSKeys) != 1 {
-		return nil, vterrors.VT12001("hash joins must have exactly one join predicate")
-	}
-
 	joinOp := engine.InnerJoin
 	if op.LeftJoin {
 		joinOp = engine.LeftJoin
 	}
 
 	var missingTypes []string
+	var comparisonTypes []evalengine.Type
+	for _, cmp := range op.JoinComparisons {
+		ltyp, lfound := ctx.TypeForExpr(cmp.LHS)
+		if !lfound {
+			missingTypes = append(missingTypes, sqlparser.String(cmp.LHS))
+		}
+		rtyp, rfound := ctx.TypeForExpr(cmp.RHS)
+		if !rfound {
+			missingTypes = append(missingTypes, sqlparser.String(cmp.RHS))
+		}
+		if !lfound || !rfound {
+			continue
+		}
 
-	ltyp, found := ctx.TypeForExpr(op.JoinComparisons[0].LHS)
-	if !found {
-		missingTypes = append(missingTypes, sqlparser.String(op.JoinComparisons[0].LHS))
-	}
-	rtyp, found := ctx.TypeForExpr(op.JoinComparisons[0].RHS)
-	if !found {
-		missingTypes = append(missingTypes, sqlparser.String(op.JoinComparisons[0].RHS))
+		comparisonType, err := evalengine.CoerceTypes(ltyp, rtyp, ctx.VSchema.Environment().CollationEnv())
+		if err != nil {
+			return nil, err
+		}
+		comparisonTypes = append(comparisonTypes, comparisonType)
 	}
 
 	if len(missingTypes) > 0 {
@@ -972,23 +1181,30 @@ func transformHashJoin(ctx *plancontext.PlanningContext, op *operators.HashJoin)
 			fmt.Sprintf("missing type information for [%s]", strings.Join(missingTypes, ", ")))
 	}
 
-	comparisonType, err := evalengine.CoerceTypes(ltyp, rtyp, ctx.VSchema.Environment().CollationEnv())
-	if err != nil {
-		return nil, err
+	var residual evalengine.Expr
+	if op.ResidualWithOffsets != nil {
+		residual, err = evalengine.Translate(op.ResidualWithOffsets, &evalengine.Config{
+			ResolveType: ctx.TypeForExpr,
+			Collation:   ctx.SemTable.Collation,
+			Environment: ctx.VSchema.Environment(),
+		})
+		if err != nil {
+			return nil, err
+		}
 	}
 
 	return &engine.HashJoin{
-		Left:           lhs,
-		Right:          rhs,
-		Opcode:         joinOp,
-		Cols:           op.ColumnOffsets,
-		LHSKey:         op.LHSKeys[0],
-		RHSKey:         op.RHSKeys[0],
-		ASTPred:        op.JoinPredicate(),
-		Collation:      comparisonType.Collation(),
-		ComparisonType: comparisonType.Type(),
-		CollationEnv:   ctx.VSchema.Environment().CollationEnv(),
-		Values:         comparisonType.Values(),
+		Left:            lhs,
+		Right:           rhs,
+		Opcode:          joinOp,
+		Cols:            op.ColumnOffsets,
+		LHSKeys:         op.LHSKeys,
+		RHSKeys:         op.RHSKeys,
+		ASTPred:         op.JoinPredicate(),
+		ComparisonTypes: comparisonTypes,
+		CollationEnv:    ctx.VSchema.Environment().CollationEnv(),
+		Residual:        residual,
+		ResidualCols:    op.ResidualColumns,
 	}, nil
 }
 
@@ -1024,6 +1240,39 @@ func transformVindexPlan(ctx *plancontext.PlanningContext, op *operators.Vindex)
 	return prim, nil
 }
 
+func transformJSONTable(ctx *plancontext.PlanningContext, op *operators.JSONTable) (engine.Primitive, error) {
+	node := *op.AST
+	node.Expr = op.CurrentDoc()
+	jt, err := evalengine.TranslateJSONTable(&node, &evalengine.Config{
+		Collation:   ctx.SemTable.Collation,
+		ResolveType: ctx.TypeForExpr,
+		Environment: ctx.VSchema.Environment(),
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	prim := &engine.JSONTable{
+		Table: jt,
+		Doc:   node.Expr,
+	}
+	fields := jt.Fields()
+	for _, col := range op.Columns {
+		offset := -1
+		for i, field := range fields {
+			if col.Name.EqualString(field.Name) {
+				offset = i
+				break
+			}
+		}
+		if offset < 0 {
+			return nil, vterrors.VT13001(fmt.Sprintf("column %s not found in JSON_TABLE %s", col.Name.String(), jt.Alias))
+		}
+		prim.Cols = append(prim.Cols, offset)
+	}
+	return prim, nil
+}
+
 func transformRecurseCTE(ctx *plancontext.PlanningContext, op *operators.RecurseCTE) (engine.Primitive, error) {
 	seed, err := transformToPrimitive(ctx, op.Seed())
 	if err != nil {
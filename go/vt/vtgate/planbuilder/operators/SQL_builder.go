@@ -23,7 +23,6 @@ import (
 
 	"vitess.io/vitess/go/vt/vtgate/planbuilder/operators/predicates"
 
-	"vitess.io/vitess/go/slice"
 	"vitess.io/vitess/go/vt/sqlparser"
 	"vitess.io/vitess/go/vt/vterrors"
 	"vitess.io/vitess/go/vt/vtgate/planbuilder/plancontext"
@@ -376,18 +375,28 @@ func (ts *tableSorter) Len() int {
 
 // Less implements the Sort interface
 func (ts *tableSorter) Less(i, j int) bool {
-	lhs := ts.sel.From[i]
-	rhs := ts.sel.From[j]
-	left, ok := lhs.(*sqlparser.AliasedTableExpr)
+	left, ok := ts.offset(ts.sel.From[i])
 	if !ok {
 		return i < j
 	}
-	right, ok := rhs.(*sqlparser.AliasedTableExpr)
+	right, ok := ts.offset(ts.sel.From[j])
 	if !ok {
 		return i < j
 	}
 
-	return ts.tbl.TableSetFor(left).TableOffset() < ts.tbl.TableSetFor(right).TableOffset()
+	return left < right
+}
+
+// offset returns the offset of the table in the semantic table. JSON_TABLE expressions
+// and lateral derived tables always come after the tables they depend on
+func (ts *tableSorter) offset(expr sqlparser.TableExpr) (int, bool) {
+	switch expr := expr.(type) {
+	case *sqlparser.AliasedTableExpr:
+		return ts.tbl.TableSetFor(expr).TableOffset(), true
+	case *sqlparser.JSONTableExpr:
+		return ts.tbl.TableSetForJSONTable(expr).TableOffset(), true
+	}
+	return 0, false
 }
 
 // Swap implements the Sort interface
@@ -476,6 +485,8 @@ func buildQuery(op Operator, qb *queryBuilder) {
 		buildDML(op, qb)
 	case *RecurseCTE:
 		buildRecursiveCTE(op, qb)
+	case *JSONTable:
+		buildJSONTable(op, qb)
 	default:
 		panic(vterrors.VT13001(fmt.Sprintf("unknown operator to convert to SQL: %T", op)))
 	}
@@ -615,12 +626,17 @@ func buildProjection(op *Projection, qb *queryBuilder) {
 }
 
 func buildApplyJoin(op *ApplyJoin, qb *queryBuilder) {
-	preds := slice.Map(op.JoinPredicates.columns, func(jc applyJoinColumn) sqlparser.Expr {
+	var preds []sqlparser.Expr
+	for _, jc := range op.JoinPredicates.columns {
+		if jc.Lateral {
+			// lateral predicates are already part of the derived table or JSON_TABLE on the RHS
+			continue
+		}
 		if jc.JoinPredicateID != nil {
 			qb.ctx.PredTracker.Skip(*jc.JoinPredicateID)
 		}
-		return jc.Original
-	})
+		preds = append(preds, jc.Original)
+	}
 	pred := sqlparser.AndExpressions(preds...)
 
 	buildQuery(op.LHS, qb)
@@ -707,14 +723,52 @@ func buildDerivedSelect(op *Horizon, qb *queryBuilder, sel *sqlparser.Select) {
 	sel.Having = mergeHaving(sel.Having, opQuery.Having)
 	sel.SelectExprs = opQuery.SelectExprs
 	sel.Distinct = opQuery.Distinct
-	qb.addTableExpr(op.Alias, op.Alias, TableID(op), &sqlparser.DerivedTable{
-		Select: sel,
+	tableID, lateral := TableID(op), isLateral(qb.ctx, op.Lateral)
+	if lateral {
+		// lateral derived tables have to be sorted after the tables they depend on
+		tableID = *op.TableId
+	}
+	qb.addTableExpr(op.Alias, op.Alias, tableID, &sqlparser.DerivedTable{
+		Lateral: lateral,
+		Select:  sel,
 	}, nil, op.ColumnAliases)
 	for _, col := range op.Columns {
 		qb.addProjection(&sqlparser.AliasedExpr{Expr: col})
 	}
 }
 
+// isLateral returns true if the lateral predicates are evaluated using the columns of the
+// tables preceding the derived table, and not arguments
+func isLateral(ctx *plancontext.PlanningContext, lateral []applyJoinColumn) bool {
+	for _, col := range lateral {
+		current, err := ctx.PredTracker.Get(*col.JoinPredicateID)
+		if err != nil {
+			panic(err)
+		}
+		if current == col.Original {
+			return true
+		}
+	}
+	return false
+}
+
+func buildJSONTable(op *JSONTable, qb *queryBuilder) {
+	if qb.stmt == nil {
+		qb.stmt = &sqlparser.Select{}
+	}
+	jt := &sqlparser.JSONTableExpr{
+		Expr:    op.CurrentDoc(),
+		Alias:   op.AST.Alias,
+		Filter:  op.AST.Filter,
+		Columns: op.AST.Columns,
+	}
+	qb.ctx.SemTable.ReplaceJSONTableFor(op.ID, jt)
+	qb.stmt.(FromStatement).SetFrom(append(qb.stmt.(FromStatement).GetFrom(), jt))
+	for _, col := range op.Columns {
+		qb.addProjection(&sqlparser.AliasedExpr{Expr: col})
+	}
+}
+
 func buildHorizon(op *Horizon, qb *queryBuilder) {
 	buildQuery(op.Source, qb)
 	stripDownQuery(op.Query, qb.asSelectStatement())
@@ -50,9 +50,16 @@ func tryPushAggregator(ctx *plancontext.PlanningContext, aggregator *Aggregator)
 	}
 
 	// if we have not yet been able to push this aggregation down,
-	// we need to turn AVG into SUM/COUNT to support this over a sharded keyspace
-	if needAvgBreaking(aggregator.Aggregations) {
-		return splitAvgAggregations(ctx, aggregator)
+	// we need to turn AVG and the statistical aggregations into SUM/COUNT to support this over a sharded keyspace
+	if needAggrSplitting(aggregator.Aggregations) {
+		return splitAggregations(ctx, aggregator)
+	}
+
+	if slices.ContainsFunc(aggregator.Aggregations, Aggr.needsAllRows) {
+		// the values of the group have to be deduplicated or sorted across all shards,
+		// so we keep the whole aggregation on the vtgate and only fetch the rows
+		setDistinctExprForVtgate(ctx, aggregator)
+		return aggregator, NoRewrite
 	}
 
 	switch src := aggregator.Source.(type) {
@@ -206,6 +213,24 @@ func pushAggregations(ctx *plancontext.PlanningContext, aggregator *Aggregator,
 	}
 }
 
+// setDistinctExprForVtgate stores the expression of the distinct aggregations that are evaluated on the vtgate,
+// so the rows are ordered by it. The distinct group_concat aggregations don't need it, they remove the duplicates themselves.
+func setDistinctExprForVtgate(ctx *plancontext.PlanningContext, aggregator *Aggregator) {
+	for _, aggr := range aggregator.Aggregations {
+		if !aggr.Distinct || aggr.OpCode == opcode.AggregateGroupConcat {
+			continue
+		}
+		args := aggr.Func.GetArgs()
+		if len(args) != 1 {
+			errDistinctAggrWithMultiExpr(aggr.Func)
+		}
+		if aggregator.DistinctExpr != nil && !ctx.SemTable.EqualsExpr(aggregator.DistinctExpr, args[0]) {
+			panic(vterrors.VT12001(fmt.Sprintf("only one DISTINCT aggregation is allowed in a SELECT: %s", sqlparser.String(aggr.Original))))
+		}
+		aggregator.DistinctExpr = args[0]
+	}
+}
+
 func checkIfWeCanPush(ctx *plancontext.PlanningContext, aggregator *Aggregator) (bool, []sqlparser.Expr) {
 	canPush := true
 	var distinctExprs []sqlparser.Expr
@@ -318,6 +343,9 @@ func exprHasVindex(ctx *plancontext.PlanningContext, expr sqlparser.Expr, hasToB
 		return false
 	}
 	vschemaTable := tableInfo.GetVindexTable()
+	if vschemaTable == nil {
+		return false
+	}
 	for _, vindex := range vschemaTable.ColumnVindexes {
 		// TODO: Support composite vindexes (multicol, etc).
 		if len(vindex.Columns) > 1 || hasToBeUnique && !vindex.IsUnique() {
@@ -400,6 +428,11 @@ func pushAggregationThroughApplyJoin(ctx *plancontext.PlanningContext, rootAggr
 
 // pushAggregationThroughHashJoin pushes aggregation through a hash-join in a similar way to pushAggregationThroughApplyJoin
 func pushAggregationThroughHashJoin(ctx *plancontext.PlanningContext, rootAggr *Aggregator, join *HashJoin) (Operator, *ApplyResult) {
+	if len(join.Residual) > 0 {
+		// the residual predicates need the rows of both sides, so we can't aggregate them before the join
+		return nil, nil
+	}
+
 	lhs := createJoinPusher(rootAggr, join.LHS)
 	rhs := createJoinPusher(rootAggr, join.RHS)
 
@@ -587,58 +620,66 @@ func initColReUse(size int) []int {
 
 func extractExpr(expr *sqlparser.AliasedExpr) sqlparser.Expr { return expr.Expr }
 
-func needAvgBreaking(aggrs []Aggr) bool {
+func needAggrSplitting(aggrs []Aggr) bool {
 	for _, aggr := range aggrs {
-		if aggr.OpCode == opcode.AggregateAvg {
+		switch aggr.OpCode {
+		case opcode.AggregateAvg, opcode.AggregateStdPop, opcode.AggregateStdSamp, opcode.AggregateVarSamp:
 			return true
+		case opcode.AggregateVarPop:
+			// the VAR_POP that the statistical aggregations are split into keeps their opcode
+			if aggr.OriginalOpCode == opcode.AggregateUnassigned {
+				return true
+			}
 		}
 	}
 	return false
 }
 
-// splitAvgAggregations takes an aggregator that has AVG aggregations in it and splits
-// these into sum/count expressions that can be spread out to shards
-func splitAvgAggregations(ctx *plancontext.PlanningContext, aggr *Aggregator) (Operator, *ApplyResult) {
+// splitAggregations takes an aggregator that has AVG or statistical aggregations in it and splits
+// these into sum/count/var_pop expressions that can be spread out to shards. The final values are
+// calculated by a projection on top of the aggregator
+func splitAggregations(ctx *plancontext.PlanningContext, aggr *Aggregator) (Operator, *ApplyResult) {
 	proj := newAliasedProjection(aggr)
 
 	var columns []*sqlparser.AliasedExpr
 	var aggregations []Aggr
 
 	for offset, col := range aggr.Columns {
-		avg, ok := col.Expr.(*sqlparser.Avg)
-		if !ok {
+		aggrExpr, extraAggrs, calcExpr := splitAggregation(col.Expr)
+		if aggrExpr == nil {
 			proj.addColumnWithoutPushing(ctx, col, false /* addToGroupBy */)
 			continue
 		}
 
-		if avg.Distinct {
-			panic(vterrors.VT12001("AVG(distinct <>)"))
-		}
-
-		// We have an AVG that we need to split
-		sumExpr := &sqlparser.Sum{Arg: avg.Arg}
-		countExpr := &sqlparser.Count{Args: []sqlparser.Expr{avg.Arg}}
-		calcExpr := &sqlparser.BinaryExpr{
-			Operator: sqlparser.DivOp,
-			Left:     sumExpr,
-			Right:    countExpr,
-		}
-
 		outputColumn := aeWrap(col.Expr)
 		outputColumn.As = sqlparser.NewIdentifierCI(col.ColumnName())
 		proj.addUnexploredExpr(sqlparser.Clone(col), calcExpr)
-		col.Expr = sumExpr
+		col.Expr = aggrExpr
 		found := false
 		for aggrOffset, aggregation := range aggr.Aggregations {
 			if offset == aggregation.ColOffset {
-				// We have found the AVG column. We'll change it to SUM, and then we add a COUNT as well
-				aggr.Aggregations[aggrOffset].OpCode = opcode.AggregateSum
-
-				countExprAlias := aeWrap(countExpr)
-				countAggr := NewAggr(opcode.AggregateCount, countExpr, countExprAlias, sqlparser.String(countExpr))
-				countAggr.ColOffset = len(aggr.Columns) + len(columns)
-				aggregations = append(aggregations, countAggr)
-				columns = append(columns, countExprAlias)
+				// We have found the column to split. We'll change it to SUM or VAR_POP, and then we add the other aggregations
+				split := &aggr.Aggregations[aggrOffset]
+				if _, isVarPop := aggrExpr.(*sqlparser.VarPop); isVarPop {
+					split.OriginalOpCode, split.OpCode = split.OpCode, opcode.AggregateVarPop
+				} else {
+					split.OpCode = opcode.AggregateSum
+				}
+
+				for _, extra := range extraAggrs {
+					extraAlias := aeWrap(extra)
+					code := opcode.AggregateSum
+					if _, isCount := extra.(*sqlparser.Count); isCount {
+						code = opcode.AggregateCount
+						split.CountOffset = len(aggr.Columns) + len(columns)
+					} else {
+						split.SumOffset = len(aggr.Columns) + len(columns)
+					}
+					extraAggr := NewAggr(code, extra, extraAlias, sqlparser.String(extra))
+					extraAggr.ColOffset = len(aggr.Columns) + len(columns)
+					aggregations = append(aggregations, extraAggr)
+					columns = append(columns, extraAlias)
+				}
 				found = true
 				break // no need to search the remaining aggregations
 			}
@@ -652,5 +693,66 @@ func splitAvgAggregations(ctx *plancontext.PlanningContext, aggr *Aggregator) (O
 	aggr.Columns = append(aggr.Columns, columns...)
 	aggr.Aggregations = append(aggr.Aggregations, aggregations...)
 
-	return proj, Rewrote("split avg aggregation")
+	return proj, Rewrote("split avg and statistical aggregations")
+}
+
+// splitAggregation returns the aggregation that replaces an aggregation that has to be split,
+// the other aggregations that are needed to calculate it, and the expression calculating it.
+// It returns nil if the expression doesn't need to be split.
+//
+// AVG is calculated as SUM(x) / COUNT(x). The statistical aggregations are calculated from
+// VAR_POP(x): the shards return their population variance, with the count and the sum of their
+// values, and the vtgate merges them. VAR_SAMP(x) is VAR_POP(x) * COUNT(x) / (COUNT(x)-1), and
+// the standard deviations are the square roots of the variances.
+func splitAggregation(expr sqlparser.Expr) (sqlparser.AggrFunc, []sqlparser.AggrFunc, sqlparser.Expr) {
+	var arg sqlparser.Expr
+	var sample, root bool
+	switch expr := expr.(type) {
+	case *sqlparser.Avg:
+		if expr.Distinct {
+			panic(vterrors.VT12001("AVG(distinct <>)"))
+		}
+		sumExpr := &sqlparser.Sum{Arg: expr.Arg}
+		countExpr := &sqlparser.Count{Args: []sqlparser.Expr{expr.Arg}}
+		calcExpr := &sqlparser.BinaryExpr{
+			Operator: sqlparser.DivOp,
+			Left:     sumExpr,
+			Right:    countExpr,
+		}
+		return sumExpr, []sqlparser.AggrFunc{countExpr}, calcExpr
+	case *sqlparser.Std:
+		arg, root = expr.Arg, true
+	case *sqlparser.StdDev:
+		arg, root = expr.Arg, true
+	case *sqlparser.StdPop:
+		arg, root = expr.Arg, true
+	case *sqlparser.StdSamp:
+		arg, sample, root = expr.Arg, true, true
+	case *sqlparser.Variance:
+		arg = expr.Arg
+	case *sqlparser.VarPop:
+		arg = expr.Arg
+	case *sqlparser.VarSamp:
+		arg, sample = expr.Arg, true
+	default:
+		return nil, nil, nil
+	}
+
+	varPopExpr := &sqlparser.VarPop{Arg: arg}
+	countExpr := &sqlparser.Count{Args: []sqlparser.Expr{arg}}
+	sumExpr := &sqlparser.Sum{Arg: arg}
+
+	var calcExpr sqlparser.Expr = varPopExpr
+	if sample {
+		// a single value has no sample variance: the division by zero returns NULL, like MySQL
+		calcExpr = &sqlparser.BinaryExpr{
+			Operator: sqlparser.DivOp,
+			Left:     &sqlparser.BinaryExpr{Operator: sqlparser.MultOp, Left: varPopExpr, Right: countExpr},
+			Right:    &sqlparser.BinaryExpr{Operator: sqlparser.MinusOp, Left: countExpr, Right: sqlparser.NewIntLiteral("1")},
+		}
+	}
+	if root {
+		calcExpr = &sqlparser.FuncExpr{Name: sqlparser.NewIdentifierCI("sqrt"), Exprs: []sqlparser.Expr{calcExpr}}
+	}
+	return varPopExpr, []sqlparser.AggrFunc{countExpr, sumExpr}, calcExpr
 }
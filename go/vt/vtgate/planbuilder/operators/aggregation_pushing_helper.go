@@ -132,11 +132,14 @@ func (ab *aggBuilder) handleAggr(ctx *plancontext.PlanningContext, aggr Aggr) er
 		return ab.handleAggrWithCountStarMultiplier(ctx, aggr)
 	case opcode.AggregateMax, opcode.AggregateMin, opcode.AggregateAnyValue:
 		return ab.handlePushThroughAggregation(ctx, aggr)
+	case opcode.AggregateBitAnd, opcode.AggregateBitOr:
+		// seeing the same value multiple times doesn't change the result of these
+		return ab.handlePushThroughAggregation(ctx, aggr)
+	case opcode.AggregateBitXor, opcode.AggregateJSONArrayAgg, opcode.AggregateJSONObjectAgg, opcode.AggregateVarPop:
+		// these can't be multiplied with the count(*) from the other side,
+		// so we abort the push and aggregate the columns on the vtgate instead
+		return errAbortAggrPushing
 	case opcode.AggregateGroupConcat:
-		f := aggr.Func.(*sqlparser.GroupConcatExpr)
-		if f.Distinct || len(f.OrderBy) > 0 {
-			panic(vterrors.VT12001("cannot evaluate group concat with distinct or order by"))
-		}
 		// this needs special handling, currently aborting the push of function
 		// and later will try pushing the column instead.
 		// TODO: this should be handled better by pushing the function down.
@@ -19,6 +19,7 @@ package operators
 import (
 	"fmt"
 	"slices"
+	"strconv"
 	"strings"
 
 	"vitess.io/vitess/go/slice"
@@ -422,10 +423,14 @@ func (aggr Aggr) getPushColumn() sqlparser.Expr {
 	case opcode.AggregateCountStar:
 		return sqlparser.NewIntLiteral("1")
 	case opcode.AggregateGroupConcat:
-		if len(aggr.Func.GetArgs()) > 1 {
-			panic(vterrors.VT12001("group_concat with more than 1 column"))
-		}
+		// the other arguments are pushed by pushGroupConcatColumns
 		return aggr.Func.GetArg()
+	case opcode.AggregateJSONArrayAgg:
+		// the engine merges JSON arrays, so every value is wrapped in an array of its own
+		return &sqlparser.JSONArrayExpr{Params: aggr.Func.GetArgs()}
+	case opcode.AggregateJSONObjectAgg:
+		args := aggr.Func.GetArgs()
+		return &sqlparser.JSONObjectExpr{Params: []*sqlparser.JSONObjectParam{{Key: args[0], Value: args[1]}}}
 	default:
 		if len(aggr.Func.GetArgs()) > 1 {
 			panic(vterrors.VT03001(sqlparser.String(aggr.Func)))
@@ -465,6 +470,7 @@ func (a *Aggregator) planOffsetsNotPushed(ctx *plancontext.PlanningContext) {
 	}
 
 	a.pushRemainingGroupingColumnsAndWeightStrings(ctx)
+	a.pushGroupConcatColumns(ctx)
 }
 
 func (a *Aggregator) addIfAggregationColumn(ctx *plancontext.PlanningContext, colIdx int) int {
@@ -532,6 +538,52 @@ func (a *Aggregator) pushRemainingGroupingColumnsAndWeightStrings(ctx *planconte
 	}
 }
 
+// pushGroupConcatColumns pushes the columns needed by the group_concat aggregations that can't just
+// concatenate their first argument: the other arguments, the ORDER BY expressions, and their weight strings.
+func (a *Aggregator) pushGroupConcatColumns(ctx *plancontext.PlanningContext) {
+	for idx, aggr := range a.Aggregations {
+		gcFunc, isGc := aggr.Func.(*sqlparser.GroupConcatExpr)
+		if !isGc || (len(gcFunc.Exprs) == 1 && !aggr.needsAllRows()) {
+			continue
+		}
+
+		args := make([]GroupConcatColumn, 0, len(gcFunc.Exprs))
+		for argIdx, expr := range gcFunc.Exprs {
+			col := GroupConcatColumn{Expr: expr, ColOffset: aggr.ColOffset, WSOffset: -1}
+			if argIdx > 0 {
+				col.ColOffset = a.internalAddColumn(ctx, aeWrap(expr), false)
+			}
+			if gcFunc.Distinct && ctx.NeedsWeightString(expr) {
+				col.WSOffset = a.internalAddWSColumn(ctx, col.ColOffset, aeWrap(weightStringFor(expr)))
+			}
+			args = append(args, col)
+		}
+
+		orderBy := make([]GroupConcatColumn, 0, len(gcFunc.OrderBy))
+		for _, order := range gcFunc.OrderBy {
+			col := GroupConcatColumn{Expr: order.Expr, Desc: order.Direction == sqlparser.DescOrder, WSOffset: -1}
+			if pos, ok := order.Expr.(*sqlparser.Literal); ok && pos.Type == sqlparser.IntVal {
+				// an ORDER BY position refers to the arguments of the group_concat
+				num, err := strconv.Atoi(pos.Val)
+				if err != nil || num < 1 || num > len(args) {
+					panic(vterrors.VT03014(pos.Val, "order clause"))
+				}
+				col.Expr = args[num-1].Expr
+				col.ColOffset = args[num-1].ColOffset
+			} else {
+				col.ColOffset = a.internalAddColumn(ctx, aeWrap(order.Expr), false)
+			}
+			if ctx.NeedsWeightString(col.Expr) {
+				col.WSOffset = a.internalAddWSColumn(ctx, col.ColOffset, aeWrap(weightStringFor(col.Expr)))
+			}
+			orderBy = append(orderBy, col)
+		}
+
+		a.Aggregations[idx].GroupConcatArgs = args
+		a.Aggregations[idx].GroupConcatOrderBy = orderBy
+	}
+}
+
 func (a *Aggregator) internalAddWSColumn(ctx *plancontext.PlanningContext, inOffset int, aliasedExpr *sqlparser.AliasedExpr) int {
 	if a.ResultColumns == 0 && a.Truncate {
 		// if we need to use `internalAddColumn`, it means we are adding columns that are not part of the original list,
@@ -76,6 +76,7 @@ type (
 		LHSExprs        []BindVarExpr  // These are the expressions we are pushing to the left hand side which we'll receive as bind variables
 		RHSExpr         sqlparser.Expr // This the expression that we'll evaluate on the right hand side. This is nil, if the right hand side has nothing.
 		GroupBy         bool           // if this is true, we need to push this down to our inputs with addToGroupBy set to true
+		Lateral         bool           // if this is true, the predicate is part of a lateral derived table or JSON_TABLE on the right hand side
 	}
 
 	// BindVarExpr is an expression needed from one side of a join/subquery, and the argument name for it.
@@ -267,25 +268,23 @@ func (aj *ApplyJoin) AddWSColumn(ctx *plancontext.PlanningContext, offset int, u
 	}
 
 	i := aj.Columns[offset]
-	out := 0
 	if i < 0 {
-		out = aj.LHS.AddWSColumn(ctx, FromLeftOffset(i), underRoute)
-		out = ToLeftOffset(out)
-		aj.JoinColumns.addLeft(wsExpr)
-	} else {
-		out = aj.RHS.AddWSColumn(ctx, FromRightOffset(i), underRoute)
-		out = ToRightOffset(out)
-		aj.JoinColumns.addRight(wsExpr)
-	}
-
-	if out >= 0 {
-		aj.addOffset(out)
+		if out := aj.LHS.AddWSColumn(ctx, FromLeftOffset(i), underRoute); out >= 0 {
+			aj.JoinColumns.addLeft(wsExpr)
+			aj.addOffset(ToLeftOffset(out))
+			return len(aj.Columns) - 1
+		}
 	} else {
-		col := aj.getJoinColumnFor(ctx, aeWrap(wsExpr), wsExpr, !ctx.ContainsAggr(wsExpr))
-		aj.JoinColumns.add(col)
-		aj.planOffsetFor(ctx, col)
+		if out := aj.RHS.AddWSColumn(ctx, FromRightOffset(i), underRoute); out >= 0 {
+			aj.JoinColumns.addRight(wsExpr)
+			aj.addOffset(ToRightOffset(out))
+			return len(aj.Columns) - 1
+		}
 	}
 
+	col := aj.getJoinColumnFor(ctx, aeWrap(wsExpr), wsExpr, !ctx.ContainsAggr(wsExpr))
+	aj.JoinColumns.add(col)
+	aj.planOffsetFor(ctx, col)
 	return len(aj.Columns) - 1
 }
 
@@ -55,7 +55,7 @@ func translateQueryToOpWithMirroring(ctx *plancontext.PlanningContext, stmt sqlp
 	if selStmt, ok := stmt.(sqlparser.SelectStatement); ok {
 		if mi := ctx.SemTable.GetMirrorInfo(); mi.Percent > 0 {
 			mirrorOp := translateQueryToOp(ctx.UseMirror(), selStmt)
-			op = NewPercentBasedMirror(mi.Percent, op, mirrorOp)
+			op = NewPercentBasedMirror(mi, op, mirrorOp)
 		}
 	}
 
@@ -122,17 +122,69 @@ func cloneASTAndSemState[T sqlparser.SQLNode](ctx *plancontext.PlanningContext,
 // findTablesContained returns the TableSet of all the contained
 func findTablesContained(ctx *plancontext.PlanningContext, node sqlparser.SQLNode) (result semantics.TableSet) {
 	_ = sqlparser.Walk(func(node sqlparser.SQLNode) (bool, error) {
-		t, ok := node.(*sqlparser.AliasedTableExpr)
-		if !ok {
-			return true, nil
+		switch t := node.(type) {
+		case *sqlparser.AliasedTableExpr:
+			result = result.Merge(ctx.SemTable.TableSetFor(t))
+		case *sqlparser.JSONTableExpr:
+			result = result.Merge(ctx.SemTable.TableSetForJSONTable(t))
 		}
-		ts := ctx.SemTable.TableSetFor(t)
-		result = result.Merge(ts)
 		return true, nil
 	}, node)
 	return
 }
 
+// breakLateralPredicates rewrites the WHERE predicates of a lateral derived table that depend on the
+// tables preceding it in the FROM clause, so they use arguments for the columns of these tables.
+// The returned columns are used by the join to send these values to the derived table,
+// or to restore the original predicates when the derived table is merged with the tables it depends on.
+func breakLateralPredicates(ctx *plancontext.PlanningContext, stmt sqlparser.TableStatement) (sqlparser.TableStatement, []applyJoinColumn) {
+	inner := findTablesContained(ctx, stmt)
+	sel, ok := stmt.(*sqlparser.Select)
+	if !ok {
+		if hasOuterDependencies(ctx, stmt, inner) {
+			panic(vterrors.VT12001("lateral derived table with UNION referencing outer tables"))
+		}
+		return stmt, nil
+	}
+
+	var lateral []applyJoinColumn
+	sel = cloneASTAndSemState(ctx, sel)
+	if sel.Where != nil {
+		var preds []sqlparser.Expr
+		for _, pred := range sqlparser.SplitAndExpression(nil, sel.Where.Expr) {
+			deps := ctx.SemTable.RecursiveDeps(pred)
+			if deps.IsSolvedBy(inner) {
+				preds = append(preds, pred)
+				continue
+			}
+			col := breakExpressionInLHSandRHS(ctx, pred, deps.Remove(inner))
+			jp := ctx.PredTracker.NewJoinPredicate(col.RHSExpr)
+			col.JoinPredicateID = &jp.ID
+			col.Lateral = true
+			lateral = append(lateral, col)
+			preds = append(preds, jp)
+		}
+		sel.Where.Expr = sqlparser.AndExpressions(preds...)
+	}
+
+	if hasOuterDependencies(ctx, sel, inner) {
+		panic(vterrors.VT12001("lateral derived table referencing outer tables outside of its WHERE clause"))
+	}
+	return sel, lateral
+}
+
+// hasOuterDependencies returns true if any of the columns of the node depend on tables outside the given TableSet
+func hasOuterDependencies(ctx *plancontext.PlanningContext, node sqlparser.SQLNode, inner semantics.TableSet) bool {
+	found := false
+	_ = sqlparser.Walk(func(node sqlparser.SQLNode) (bool, error) {
+		if col, ok := node.(*sqlparser.ColName); ok && !ctx.SemTable.RecursiveDeps(col).IsSolvedBy(inner) {
+			found = true
+		}
+		return !found, nil
+	}, node)
+	return found
+}
+
 // joinPredicateCollector is used to inspect the predicates inside the subquery, looking for any
 // comparisons between the inner and the outer side.
 // They can be used for merging the two parts of the query together
@@ -191,7 +243,7 @@ func translateQueryToOpForUnion(ctx *plancontext.PlanningContext, node sqlparser
 //  2. fkToIgnore: The foreign key constraint to specifically ignore while planning the statement. This field is used in UPDATE CASCADE planning, wherein while planning the child update
 //     query, we need to ignore the parent foreign key constraint that caused the cascade in question.
 func createOpFromStmt(inCtx *plancontext.PlanningContext, stmt sqlparser.Statement, verifyAllFKs bool, fkToIgnore string) Operator {
-	ctx, err := plancontext.CreatePlanningContext(stmt, inCtx.ReservedVars, inCtx.VSchema, inCtx.PlannerVersion)
+	ctx, err := plancontext.CreatePlanningContext(inCtx.Ctx, stmt, inCtx.ReservedVars, inCtx.VSchema, inCtx.PlannerVersion)
 	if err != nil {
 		panic(err)
 	}
@@ -242,6 +294,8 @@ func getOperatorFromTableExpr(ctx *plancontext.PlanningContext, tableExpr sqlpar
 		return getOperatorFromJoinTableExpr(ctx, tableExpr)
 	case *sqlparser.ParenTableExpr:
 		return crossJoin(ctx, tableExpr.Exprs)
+	case *sqlparser.JSONTableExpr:
+		return newJSONTable(ctx, tableExpr)
 	default:
 		panic(vterrors.VT13001(fmt.Sprintf("unable to use: %T table type", tableExpr)))
 	}
@@ -318,13 +372,22 @@ func getOperatorFromAliasedTableExpr(ctx *plancontext.PlanningContext, tableExpr
 			tbl.Select.SetOrderBy(nil)
 		}
 
-		inner := translateQueryToOp(ctx, tbl.Select)
+		stmt := tbl.Select
+		var lateral []applyJoinColumn
+		if tbl.Lateral {
+			stmt, lateral = breakLateralPredicates(ctx, stmt)
+		}
+
+		inner := translateQueryToOp(ctx, stmt)
 		if horizon, ok := inner.(*Horizon); ok {
 			horizon.TableId = &tableID
 			horizon.Alias = tableExpr.As.String()
 			horizon.ColumnAliases = tableExpr.Columns
-			qp := CreateQPFromSelectStatement(ctx, tbl.Select)
+			qp := CreateQPFromSelectStatement(ctx, stmt)
 			horizon.QP = qp
+			horizon.Lateral = lateral
+		} else if len(lateral) > 0 {
+			panic(vterrors.VT13001(fmt.Sprintf("expected a horizon for the lateral derived table, got %T", inner)))
 		}
 
 		return inner
@@ -388,6 +451,9 @@ func crossJoin(ctx *plancontext.PlanningContext, exprs sqlparser.TableExprs) Ope
 	for _, tableExpr := range exprs {
 		op := getOperatorFromTableExpr(ctx, tableExpr, len(exprs) == 1)
 		if output == nil {
+			if len(lateralOf(op)) > 0 {
+				panic(vterrors.VT12001("lateral derived table or JSON_TABLE referencing tables of an outer query"))
+			}
 			output = op
 		} else {
 			output = createJoin(ctx, output, op)
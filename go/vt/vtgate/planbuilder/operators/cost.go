@@ -0,0 +1,282 @@
+/*
+Copyright 2025 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package operators
+
+import (
+	"vitess.io/vitess/go/vt/sqlparser"
+	"vitess.io/vitess/go/vt/vtgate/engine"
+	"vitess.io/vitess/go/vt/vtgate/planbuilder/operators/predicates"
+	"vitess.io/vitess/go/vt/vtgate/planbuilder/plancontext"
+	"vitess.io/vitess/go/vt/vtgate/vindexes"
+)
+
+// The cost model uses the table statistics tracked by the schema tracker to
+// estimate how many rows every operator returns, and how much work it takes
+// to produce them. The statistics are per shard, so the estimates are too.
+// They are only used when all the tables of the compared plans have
+// statistics; otherwise the planner falls back to CostOf.
+
+const (
+	// routeQueryCost is the cost of sending a query to a shard, in rows.
+	// It is multiplied by the cost of the routing, so a scatter query costs
+	// more than a query sent to a single shard.
+	routeQueryCost = 10
+
+	// defaultSelectivity is the fraction of rows we expect a predicate to
+	// keep, when we can't estimate it using the statistics.
+	defaultSelectivity = 0.1
+
+	// rangeSelectivity is the fraction of rows we expect range predicates
+	// such as <, BETWEEN and LIKE to keep.
+	rangeSelectivity = 1.0 / 3
+)
+
+// estimate is the estimated number of rows an operator returns, and the
+// estimated cost of producing them.
+type estimate struct {
+	rows float64
+	cost float64
+}
+
+// cheaper returns true if the first plan is estimated to be cheaper than the second one.
+func cheaper(ctx *plancontext.PlanningContext, a, b Operator) bool {
+	estA, okA := estimateOf(ctx, a)
+	estB, okB := estimateOf(ctx, b)
+	if okA && okB {
+		return estA.cost < estB.cost
+	}
+	return CostOf(a) < CostOf(b)
+}
+
+// estimateOf returns the estimate for an operator tree. It returns false if
+// the statistics of one of the tables are not known.
+func estimateOf(ctx *plancontext.PlanningContext, op Operator) (estimate, bool) {
+	switch op := op.(type) {
+	case *Table:
+		return tableEstimate(ctx, op, nil)
+	case *Filter:
+		if tbl, ok := op.Source.(*Table); ok {
+			return tableEstimate(ctx, tbl, op.Predicates)
+		}
+		est, ok := estimateOf(ctx, op.Source)
+		est.rows *= predicatesSelectivity(ctx, op.Predicates)
+		return est, ok
+	case *Route:
+		est, ok := estimateOf(ctx, op.Source)
+		est.cost += float64(op.Cost() * routeQueryCost)
+		return est, ok
+	case *ApplyJoin:
+		// The right hand side is executed once per row of the left hand side,
+		// and the join predicates have already been pushed to it.
+		lhs, okL := estimateOf(ctx, op.LHS)
+		rhs, okR := estimateOf(ctx, op.RHS)
+		return estimate{
+			rows: lhs.rows * rhs.rows,
+			cost: lhs.cost + lhs.rows*rhs.cost,
+		}, okL && okR
+	case *HashJoin:
+		// Both sides are executed once, and their rows are hashed and probed.
+		lhs, okL := estimateOf(ctx, op.LHS)
+		rhs, okR := estimateOf(ctx, op.RHS)
+		sel := 1.0
+		for _, cmp := range op.JoinComparisons {
+			sel *= equalitySelectivity(ctx, cmp.LHS, cmp.RHS)
+		}
+		sel *= predicatesSelectivity(ctx, op.Residual)
+		return estimate{
+			rows: lhs.rows * rhs.rows * sel,
+			cost: lhs.cost + rhs.cost + lhs.rows + rhs.rows,
+		}, okL && okR
+	}
+
+	// For the other operators, we can't do better than summing up their inputs.
+	inputs := op.Inputs()
+	if len(inputs) == 0 {
+		return estimate{}, false
+	}
+	var est estimate
+	for _, input := range inputs {
+		inputEst, ok := estimateOf(ctx, input)
+		if !ok {
+			return estimate{}, false
+		}
+		est.rows += inputEst.rows
+		est.cost += inputEst.cost
+	}
+	return est, true
+}
+
+// tableEstimate estimates the rows read from a table. We assume that MySQL
+// uses an index for the predicates we can estimate, so only the matching
+// rows are read.
+func tableEstimate(ctx *plancontext.PlanningContext, tbl *Table, filters []sqlparser.Expr) (estimate, bool) {
+	if tbl.VTable == nil || tbl.VTable.Statistics == nil {
+		return estimate{}, false
+	}
+	rows := float64(tbl.VTable.Statistics.RowCount) *
+		predicatesSelectivity(ctx, tbl.QTable.Predicates) *
+		predicatesSelectivity(ctx, filters)
+	return estimate{rows: rows, cost: rows}, true
+}
+
+func predicatesSelectivity(ctx *plancontext.PlanningContext, exprs []sqlparser.Expr) float64 {
+	sel := 1.0
+	for _, expr := range exprs {
+		sel *= selectivity(ctx, expr)
+	}
+	return sel
+}
+
+// selectivity returns the estimated fraction of rows that the predicate keeps
+func selectivity(ctx *plancontext.PlanningContext, expr sqlparser.Expr) float64 {
+	switch expr := expr.(type) {
+	case *predicates.JoinPredicate:
+		return selectivity(ctx, expr.Current())
+	case *sqlparser.AndExpr:
+		return selectivity(ctx, expr.Left) * selectivity(ctx, expr.Right)
+	case *sqlparser.OrExpr:
+		l, r := selectivity(ctx, expr.Left), selectivity(ctx, expr.Right)
+		return l + r - l*r
+	case *sqlparser.NotExpr:
+		return 1 - selectivity(ctx, expr.Expr)
+	case *sqlparser.BetweenExpr:
+		return rangeSelectivity
+	case *sqlparser.ComparisonExpr:
+		switch expr.Operator {
+		case sqlparser.EqualOp, sqlparser.NullSafeEqualOp:
+			return equalitySelectivity(ctx, expr.Left, expr.Right)
+		case sqlparser.NotEqualOp:
+			return 1 - equalitySelectivity(ctx, expr.Left, expr.Right)
+		case sqlparser.InOp:
+			return inSelectivity(ctx, expr)
+		case sqlparser.NotInOp:
+			return 1 - inSelectivity(ctx, expr)
+		case sqlparser.LessThanOp, sqlparser.GreaterThanOp, sqlparser.LessEqualOp, sqlparser.GreaterEqualOp,
+			sqlparser.LikeOp, sqlparser.NotLikeOp:
+			return rangeSelectivity
+		}
+	}
+	return defaultSelectivity
+}
+
+// equalitySelectivity estimates the selectivity of l = r. When both sides
+// are columns, the column with the most distinct values decides.
+func equalitySelectivity(ctx *plancontext.PlanningContext, l, r sqlparser.Expr) float64 {
+	lCard, lOk := columnCardinality(ctx, l)
+	rCard, rOk := columnCardinality(ctx, r)
+	switch {
+	case lOk && rOk:
+		return 1 / max(lCard, rCard)
+	case lOk:
+		return 1 / lCard
+	case rOk:
+		return 1 / rCard
+	default:
+		return defaultSelectivity
+	}
+}
+
+func inSelectivity(ctx *plancontext.PlanningContext, cmp *sqlparser.ComparisonExpr) float64 {
+	card, ok := columnCardinality(ctx, cmp.Left)
+	if !ok {
+		return defaultSelectivity
+	}
+	values := 1
+	if tuple, ok := cmp.Right.(sqlparser.ValTuple); ok {
+		values = len(tuple)
+	}
+	return min(1, float64(values)/card)
+}
+
+// columnCardinality returns the estimated number of distinct values of a column
+func columnCardinality(ctx *plancontext.PlanningContext, expr sqlparser.Expr) (float64, bool) {
+	col, ok := expr.(*sqlparser.ColName)
+	if !ok {
+		return 0, false
+	}
+	ti, err := ctx.SemTable.TableInfoFor(ctx.SemTable.DirectDeps(col))
+	if err != nil {
+		return 0, false
+	}
+	vtable := ti.GetVindexTable()
+	if vtable == nil || vtable.Statistics == nil {
+		return 0, false
+	}
+	if card, ok := vtable.Statistics.ColumnCardinality(col.Name.String()); ok {
+		return float64(card), true
+	}
+	if isUniqueColumn(vtable, col.Name) {
+		return float64(max(vtable.Statistics.RowCount, 1)), true
+	}
+	return 0, false
+}
+
+// isUniqueColumn returns true if the column is the primary key or a unique key of the table on its own
+func isUniqueColumn(vtable *vindexes.BaseTable, col sqlparser.IdentifierCI) bool {
+	if len(vtable.PrimaryKey) == 1 && vtable.PrimaryKey[0].Equal(col) {
+		return true
+	}
+	for _, uk := range vtable.UniqueKeys {
+		if len(uk) != 1 {
+			continue
+		}
+		if ukCol, ok := uk[0].(*sqlparser.ColName); ok && ukCol.Name.Equal(col) {
+			return true
+		}
+	}
+	return false
+}
+
+// scatterIsCheaper returns true when the statistics show that the values of
+// a lookup vindex option match so many rows that the query would likely be
+// sent to most shards anyway. In that case, the lookup is wasted work, and
+// a scatter query is cheaper. Every matching row is assumed to live on
+// a different shard, which makes this check conservative. When the number of
+// shards of the keyspace is known, the lookup is weighed against it, as the
+// cost of a scatter query grows with the number of shards.
+func (vpp *VindexPlusPredicates) scatterIsCheaper(option *VindexOption) bool {
+	if vpp.statistics == nil || !option.FoundVindex.NeedsVCursor() || len(vpp.ColVindex.Columns) == 0 {
+		return false
+	}
+	switch option.OpCode {
+	case engine.Equal, engine.IN, engine.MultiEqual:
+	default:
+		return false
+	}
+
+	card, ok := vpp.statistics.ColumnCardinality(vpp.ColVindex.Columns[0].String())
+	if !ok {
+		return false
+	}
+	rowsPerValue := float64(vpp.statistics.RowCount) / float64(card)
+
+	values := 1
+	for _, expr := range option.ValueExprs {
+		if tuple, ok := expr.(sqlparser.ValTuple); ok {
+			values = max(values, len(tuple))
+		}
+	}
+
+	matchingRows := float64(values) * rowsPerValue
+	if vpp.shards > 0 {
+		// The lookup query, and a query to the shard of every matching row,
+		// against a query to every shard.
+		return 1+matchingRows > float64(vpp.shards)
+	}
+	lookupCost := float64(opcodeCost(option.OpCode)) + matchingRows - 1
+	return lookupCost > float64(opcodeCost(engine.Scatter))
+}
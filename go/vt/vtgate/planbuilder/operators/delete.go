@@ -61,6 +61,7 @@ func (d *Delete) ShortDescription() string {
 }
 
 func createOperatorFromDelete(ctx *plancontext.PlanningContext, deleteStmt *sqlparser.Delete) (op Operator) {
+	errIfDeleteNotSupported(ctx)
 	childFks := ctx.SemTable.GetChildForeignKeysForTargets()
 
 	// We check if delete with input plan is required. DML with input planning is generally
@@ -91,6 +92,25 @@ func createOperatorFromDelete(ctx *plancontext.PlanningContext, deleteStmt *sqlp
 	return createFkCascadeOpForDelete(ctx, op, delClone, childFks, vTbl)
 }
 
+// errIfDeleteNotSupported checks that the targets of the delete are real tables,
+// and not derived tables, which they are when they come from a common table expression.
+func errIfDeleteNotSupported(ctx *plancontext.PlanningContext) {
+	for _, target := range ctx.SemTable.DMLTargets.Constituents() {
+		tblInfo, err := ctx.SemTable.TableInfoFor(target)
+		if err != nil {
+			panic(err)
+		}
+		if _, isATable := tblInfo.(*semantics.RealTable); isATable {
+			continue
+		}
+		name, err := tblInfo.Name()
+		if err != nil {
+			panic(err)
+		}
+		panic(vterrors.VT03004(name.Name.String()))
+	}
+}
+
 func deleteWithInputPlanningRequired(childFks []vindexes.ChildFKInfo, deleteStmt *sqlparser.Delete) bool {
 	if len(deleteStmt.Targets) > 1 {
 		return true
@@ -112,6 +132,7 @@ func deleteWithInputPlanningRequired(childFks []vindexes.ChildFKInfo, deleteStmt
 
 func createDeleteWithInputOp(ctx *plancontext.PlanningContext, del *sqlparser.Delete) (op Operator) {
 	delClone := ctx.SemTable.Clone(del).(*sqlparser.Delete)
+	useClonedTableExprs(ctx, del.TableExprs, delClone.TableExprs)
 	del.Limit = nil
 	del.OrderBy = nil
 
@@ -209,7 +230,7 @@ func createDeleteOperator(ctx *plancontext.PlanningContext, del *sqlparser.Delet
 
 	sqc := &SubQueryBuilder{}
 	if del.Where != nil {
-		op = addWherePredsToSubQueryBuilder(ctx, del.Where.Expr, op, sqc)
+		op = addWherePredsToLimitedDML(ctx, del.Where.Expr, op, sqc, del.Limit)
 	}
 
 	tblID, err := ctx.SemTable.GetTargetTableSetForTableName(del.Targets[0])
@@ -263,6 +284,18 @@ func createDeleteOperator(ctx *plancontext.PlanningContext, del *sqlparser.Delet
 	return sqc.getRootOperator(delOp, nil), vTbl
 }
 
+// addWherePredsToLimitedDML adds the WHERE predicates of an UPDATE or DELETE to the operator.
+// When the DML has a LIMIT, the subqueries of the predicates have to be solved before the
+// ordering and limit are applied, so they are kept right above the tables instead of above the DML
+func addWherePredsToLimitedDML(ctx *plancontext.PlanningContext, where sqlparser.Expr, op Operator, sqc *SubQueryBuilder, limit *sqlparser.Limit) Operator {
+	if limit == nil {
+		return addWherePredsToSubQueryBuilder(ctx, where, op, sqc)
+	}
+	whereSqc := &SubQueryBuilder{}
+	op = addWherePredsToSubQueryBuilder(ctx, where, op, whereSqc)
+	return whereSqc.getRootOperator(op, nil)
+}
+
 func generateOwnedVindexQuery(del *sqlparser.Delete, table TargetTable, ksidCols []sqlparser.IdentifierCI) *sqlparser.Select {
 	var selExprs []sqlparser.SelectExpr
 	for _, col := range ksidCols {
@@ -22,6 +22,7 @@ import (
 
 	"vitess.io/vitess/go/vt/sqlparser"
 	"vitess.io/vitess/go/vt/vterrors"
+	"vitess.io/vitess/go/vt/vtgate/planbuilder/plancontext"
 	"vitess.io/vitess/go/vt/vtgate/semantics"
 	"vitess.io/vitess/go/vt/vtgate/vindexes"
 )
@@ -69,6 +70,30 @@ func sortDmlOps(dmlOps []dmlOp) []dmlOp {
 	return dmlOps
 }
 
+// useClonedTableExprs points the semantic table at the table expressions of the cloned DML, that are used to plan the
+// input of a DMLWithInput. Table expressions holding expressions, like derived tables, are copied when the DML is cloned,
+// and would otherwise no longer be found in the semantic table.
+func useClonedTableExprs(ctx *plancontext.PlanningContext, org, clone sqlparser.TableExprs) {
+	orgTables := aliasedTableExprs(org)
+	cloneTables := aliasedTableExprs(clone)
+	for idx, orgTable := range orgTables {
+		if orgTable == cloneTables[idx] {
+			continue
+		}
+		ctx.SemTable.ReplaceTableSetFor(ctx.SemTable.TableSetFor(orgTable), cloneTables[idx])
+	}
+}
+
+func aliasedTableExprs(tableExprs sqlparser.TableExprs) (tables []*sqlparser.AliasedTableExpr) {
+	_ = sqlparser.Walk(func(node sqlparser.SQLNode) (bool, error) {
+		if ate, ok := node.(*sqlparser.AliasedTableExpr); ok {
+			tables = append(tables, ate)
+		}
+		return true, nil
+	}, tableExprs)
+	return
+}
+
 func shortDesc(target TargetTable, ovq *sqlparser.Select) string {
 	ovqString := ""
 	if ovq != nil {
@@ -39,6 +39,10 @@ type (
 		// Before offset planning
 		JoinComparisons []Comparison
 
+		// Residual are the join predicates that are not equality comparisons between the two sides.
+		// They are evaluated on the pairs of rows that match by the JoinComparisons.
+		Residual []sqlparser.Expr
+
 		// These columns are the output columns of the hash join. While in operator mode we keep track of complex expression,
 		// but once we move to the engine primitives, the hash join only passes through column from either left or right.
 		// anything more complex will be solved by a projection on top of the hash join
@@ -53,6 +57,12 @@ type (
 		// These are the values that will be hashed together
 		LHSKeys, RHSKeys []int
 
+		// ResidualWithOffsets is the AND of the Residual predicates, rewritten to use
+		// offsets into a row built from ResidualColumns. ResidualColumns uses the
+		// same encoding as ColumnOffsets
+		ResidualWithOffsets sqlparser.Expr
+		ResidualColumns     []int
+
 		offset bool
 	}
 
@@ -93,6 +103,8 @@ func (hj *HashJoin) Clone(inputs []Operator) Operator {
 	kopy.LHSKeys = slices.Clone(hj.LHSKeys)
 	kopy.RHSKeys = slices.Clone(hj.RHSKeys)
 	kopy.JoinComparisons = slices.Clone(hj.JoinComparisons)
+	kopy.Residual = slices.Clone(hj.Residual)
+	kopy.ResidualColumns = slices.Clone(hj.ResidualColumns)
 	return &kopy
 }
 
@@ -149,6 +161,7 @@ func (hj *HashJoin) planOffsets(ctx *plancontext.PlanningContext) Operator {
 		rOffset := hj.RHS.AddColumn(ctx, true, false, aeWrap(cmp.RHS))
 		hj.RHSKeys = append(hj.RHSKeys, rOffset)
 	}
+	hj.planResidualOffsets(ctx)
 
 	needsProj := false
 	lID := TableID(hj.LHS)
@@ -204,6 +217,9 @@ func (hj *HashJoin) ShortDescription() string {
 	comparisons := slice.Map(hj.JoinComparisons, func(from Comparison) string {
 		return from.String()
 	})
+	comparisons = append(comparisons, slice.Map(hj.Residual, func(from sqlparser.Expr) string {
+		return sqlparser.String(from)
+	})...)
 	cmp := strings.Join(comparisons, " AND ")
 
 	if len(hj.columns.columns) > 0 {
@@ -254,30 +270,93 @@ func (hj *HashJoin) IsInner() bool {
 }
 
 func (hj *HashJoin) AddJoinPredicate(ctx *plancontext.PlanningContext, expr sqlparser.Expr, pushDown bool) { // TODO: consider whether we should honor the pushDown flag
+	lID := TableID(hj.LHS)
+	rID := TableID(hj.RHS)
+	for _, pred := range sqlparser.SplitAndExpression(nil, expr) {
+		if cmp, ok := hashJoinComparison(ctx, pred, lID, rID); ok {
+			hj.JoinComparisons = append(hj.JoinComparisons, cmp)
+			continue
+		}
+
+		if !ctx.SemTable.RecursiveDeps(pred).IsSolvedBy(lID.Merge(rID)) {
+			panic(vterrors.VT12001(fmt.Sprintf("can't use [%s] with hash joins", sqlparser.String(pred))))
+		}
+		// anything else that can be solved using both sides is evaluated after probing
+		hj.Residual = append(hj.Residual, pred)
+	}
+}
+
+// hashJoinComparison returns the comparison to hash on, if the predicate is an equality
+// between an expression of the LHS and an expression of the RHS
+func hashJoinComparison(ctx *plancontext.PlanningContext, expr sqlparser.Expr, lID, rID semantics.TableSet) (Comparison, bool) {
 	cmp, ok := expr.(*sqlparser.ComparisonExpr)
 	if !ok || !canBeSolvedWithHashJoin(cmp.Operator) {
-		panic(vterrors.VT12001(fmt.Sprintf("can't use [%s] with hash joins", sqlparser.String(expr))))
+		return Comparison{}, false
 	}
 	lExpr := cmp.Left
 	lDeps := ctx.SemTable.RecursiveDeps(lExpr)
 	rExpr := cmp.Right
 	rDeps := ctx.SemTable.RecursiveDeps(rExpr)
-	lID := TableID(hj.LHS)
-	rID := TableID(hj.RHS)
 	if !lDeps.IsSolvedBy(lID) || !rDeps.IsSolvedBy(rID) {
 		// we'll switch and see if things work out then
 		lExpr, rExpr = rExpr, lExpr
 		lDeps, rDeps = rDeps, lDeps
 	}
 
-	if !lDeps.IsSolvedBy(lID) || !rDeps.IsSolvedBy(rID) {
-		panic(vterrors.VT12001(fmt.Sprintf("can't use [%s] with hash joins", sqlparser.String(expr))))
+	if lDeps.IsEmpty() || rDeps.IsEmpty() || !lDeps.IsSolvedBy(lID) || !rDeps.IsSolvedBy(rID) {
+		return Comparison{}, false
 	}
 
-	hj.JoinComparisons = append(hj.JoinComparisons, Comparison{
+	return Comparison{
 		LHS: lExpr,
 		RHS: rExpr,
-	})
+	}, true
+}
+
+// planResidualOffsets rewrites the residual predicates to use offsets into the
+// row made of the ResidualColumns, fetching the columns from the inputs as needed
+func (hj *HashJoin) planResidualOffsets(ctx *plancontext.PlanningContext) {
+	if len(hj.Residual) == 0 {
+		return
+	}
+	lID, rID := TableID(hj.LHS), TableID(hj.RHS)
+	r := new(replacer)
+	pre := func(node, parent sqlparser.SQLNode) bool {
+		expr, ok := node.(sqlparser.Expr)
+		if !ok {
+			return true
+		}
+		deps := ctx.SemTable.RecursiveDeps(expr)
+		check := func(id semantics.TableSet, op Operator, offsetter func(int) int) int {
+			if !deps.IsSolvedBy(id) {
+				return -1
+			}
+			inOffset := op.FindCol(ctx, expr, false)
+			if inOffset == -1 {
+				if !mustFetchFromInput(ctx, expr) {
+					return -1
+				}
+				inOffset = op.AddColumn(ctx, false, false, aeWrap(expr))
+			}
+			hj.ResidualColumns = append(hj.ResidualColumns, offsetter(inOffset))
+			return len(hj.ResidualColumns) - 1
+		}
+
+		if lOffset := check(lID, hj.LHS, lhsOffset); lOffset >= 0 {
+			r.replaceExpr = sqlparser.NewOffset(lOffset, expr)
+			return false
+		}
+
+		if rOffset := check(rID, hj.RHS, rhsOffset); rOffset >= 0 {
+			r.replaceExpr = sqlparser.NewOffset(rOffset, expr)
+			return false
+		}
+
+		return true
+	}
+
+	residual := sqlparser.AndExpressions(hj.Residual...)
+	hj.ResidualWithOffsets = sqlparser.CopyOnRewrite(residual, pre, r.post, ctx.SemTable.CopySemanticInfo).(sqlparser.Expr)
 }
 
 func canBeSolvedWithHashJoin(op sqlparser.ComparisonExprOperator) bool {
@@ -365,7 +444,7 @@ func (hj *HashJoin) JoinPredicate() sqlparser.Expr {
 			Right: from.RHS,
 		}
 	})
-	return sqlparser.AndExpressions(exprs...)
+	return sqlparser.AndExpressions(append(exprs, hj.Residual...)...)
 }
 
 type replacer struct {
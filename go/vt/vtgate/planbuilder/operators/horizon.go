@@ -42,6 +42,9 @@ type Horizon struct {
 	Alias         string
 	ColumnAliases sqlparser.Columns // derived tables can have their column aliases specified outside the subquery
 
+	// Lateral contains the predicates of a lateral derived table that depend on the tables preceding it in the FROM clause
+	Lateral []applyJoinColumn
+
 	// QP contains the QueryProjection for this op
 	QP *QueryProjection
 
@@ -18,6 +18,7 @@ package operators
 
 import (
 	"fmt"
+	"slices"
 	"strings"
 
 	"vitess.io/vitess/go/slice"
@@ -69,6 +70,7 @@ func expandUnionHorizon(ctx *plancontext.PlanningContext, horizon *Horizon, unio
 }
 
 func expandSelectHorizon(ctx *plancontext.PlanningContext, horizon *Horizon, sel *sqlparser.Select) (Operator, *ApplyResult) {
+	pullOutGroupingSubqueries(ctx, horizon, sel)
 	qp := horizon.getQP(ctx)
 	var extracted []string
 
@@ -116,10 +118,78 @@ func expandSelectHorizon(ctx *plancontext.PlanningContext, horizon *Horizon, sel
 	return op, Rewrote(fmt.Sprintf("expand SELECT horizon into (%s)", strings.Join(extracted, ", ")))
 }
 
+// pullOutGroupingSubqueries evaluates the subqueries used in the GROUP BY once, before the query, and
+// replaces the grouping expressions and the select and order by expressions using them with the arguments
+// holding the results. A grouping that only consists of a subquery is a constant, and is removed.
+func pullOutGroupingSubqueries(ctx *plancontext.PlanningContext, horizon *Horizon, sel *sqlparser.Select) {
+	if sel.GroupBy == nil {
+		return
+	}
+	sqc := &SubQueryBuilder{}
+	outerID := TableID(horizon.Source)
+	var grouping []sqlparser.Expr
+	for _, expr := range sel.GroupBy.Exprs {
+		if subq, _, _ := getSubQuery(expr); subq == nil {
+			grouping = append(grouping, expr)
+			continue
+		}
+
+		// we find the expressions using the grouping before the subqueries are replaced in it
+		var uses []*sqlparser.Expr
+		for _, se := range sel.GetColumns() {
+			if ae, ok := se.(*sqlparser.AliasedExpr); ok && ctx.SemTable.EqualsExpr(ae.Expr, expr) {
+				uses = append(uses, &ae.Expr)
+			}
+		}
+		for _, order := range sel.OrderBy {
+			if ctx.SemTable.EqualsExpr(order.Expr, expr) {
+				uses = append(uses, &order.Expr)
+			}
+		}
+
+		newExpr := sqc.pullOutUncorrelatedSubqueries(ctx, expr, outerID, "GROUP BY")
+		for _, use := range uses {
+			*use = newExpr
+		}
+		if _, isArg := newExpr.(*sqlparser.Argument); !isArg {
+			grouping = append(grouping, newExpr)
+		}
+	}
+	if len(sqc.Inner) == 0 {
+		return
+	}
+
+	if len(grouping) < len(sel.GroupBy.Exprs) {
+		// grouping by a constant doesn't change the groups, unless rollups are calculated
+		if sel.GroupBy.WithRollup {
+			panic(vterrors.VT12001("WITH ROLLUP on a subquery"))
+		}
+		sel.OrderBy = slices.DeleteFunc(sel.OrderBy, func(order *sqlparser.Order) bool {
+			_, isArg := order.Expr.(*sqlparser.Argument)
+			return isArg
+		})
+		if len(sel.OrderBy) == 0 {
+			sel.OrderBy = nil
+		}
+	}
+	sel.GroupBy.Exprs = grouping
+	if len(grouping) == 0 {
+		// when only grouping by constants, we get a single group unless there are no rows at all
+		sel.GroupBy = nil
+		sel.AddHaving(&sqlparser.ComparisonExpr{
+			Operator: sqlparser.GreaterThanOp,
+			Left:     &sqlparser.CountStar{},
+			Right:    sqlparser.NewIntLiteral("0"),
+		})
+	}
+	horizon.Source = sqc.getRootOperator(horizon.Source, nil)
+	horizon.QP = nil
+}
+
 func expandOrderBy(ctx *plancontext.PlanningContext, op Operator, qp *QueryProjection, derived string) Operator {
 	var newOrder []OrderBy
 	sqc := &SubQueryBuilder{}
-	proj, ok := op.(*Projection)
+	proj, _ := op.(*Projection)
 
 	for _, expr := range qp.OrderExprs {
 		// Attempt to extract any subqueries within the expression
@@ -133,15 +203,19 @@ func expandOrderBy(ctx *plancontext.PlanningContext, op Operator, qp *QueryProje
 			continue
 		}
 
-		// If the operator is not a projection, we cannot handle subqueries with aggregation if we are unable to push everything into a single route.
-		if !ok {
-			ctx.SemTable.NotSingleRouteErr = vterrors.VT12001("subquery with aggregation in order by")
-			return newOrdering(op, qp.OrderExprs)
-		} else {
-			// Add the new subquery expression to the projection
-			proj.addSubqueryExpr(ctx, aeWrap(newExpr), newExpr, subqs...)
+		if proj == nil {
+			// with aggregation, the subquery is evaluated on the aggregated rows,
+			// so we add a projection on top of the aggregation to evaluate it
+			proj = newAliasedProjection(op)
+			for _, ae := range op.GetColumns(ctx) {
+				proj.addProjExpr(newProjExpr(ae))
+			}
+			op = proj
 		}
 
+		// Add the new subquery expression to the projection
+		proj.addSubqueryExpr(ctx, aeWrap(newExpr), newExpr, subqs...)
+
 		// Replace the original order expression with the new expression containing subqueries
 		newOrder = append(newOrder, OrderBy{
 			Inner: &sqlparser.Order{
@@ -194,10 +268,18 @@ func createProjectionFromSelect(ctx *plancontext.PlanningContext, horizon *Horiz
 	}
 
 	if qp.NeedsAggregation() {
+		if !windowsArePushable(ctx, qp, horizon.src()) {
+			panic(vterrors.VT12001("window functions together with aggregation in a cross-shard query"))
+		}
 		return createProjectionWithAggr(ctx, qp, dt, horizon)
 	}
 
-	projX := createProjectionWithoutAggr(ctx, qp, horizon.src())
+	src := horizon.src()
+	if !windowsArePushable(ctx, qp, src) {
+		src = createWindow(ctx, qp, src)
+	}
+
+	projX := createProjectionWithoutAggr(ctx, qp, src)
 	projX.DT = dt
 	return projX
 }
@@ -19,6 +19,7 @@ package operators
 import (
 	"strconv"
 
+	"vitess.io/vitess/go/slice"
 	vschemapb "vitess.io/vitess/go/vt/proto/vschema"
 	"vitess.io/vitess/go/vt/sqlparser"
 	"vitess.io/vitess/go/vt/vterrors"
@@ -113,6 +114,11 @@ func createOperatorFromInsert(ctx *plancontext.PlanningContext, ins *sqlparser.I
 
 	vTbl, routing := buildVindexTableForDML(ctx, tableInfo, qt, ins, "insert")
 
+	if ins.Action == sqlparser.ReplaceAct && vTbl.Keyspace.Sharded && len(vTbl.PrimaryKey) == 0 && len(vTbl.UniqueKeys) == 0 {
+		// without the keys of the table, we can't find the rows to replace, which might live on another shard.
+		panic(vterrors.VT12001("REPLACE INTO on a sharded table without primary key or unique key information"))
+	}
+
 	deleteBeforeInsert := false
 	if ins.Action == sqlparser.ReplaceAct &&
 		(ctx.SemTable.ForeignKeysPresent() || vTbl.Keyspace.Sharded) &&
@@ -122,30 +128,128 @@ func createOperatorFromInsert(ctx *plancontext.PlanningContext, ins *sqlparser.I
 		deleteBeforeInsert = true
 	}
 
-	insOp := checkAndCreateInsertOperator(ctx, ins, vTbl, routing)
-
 	if !deleteBeforeInsert {
-		return insOp
+		return checkAndCreateInsertOperator(ctx, ins, vTbl, routing)
 	}
 
 	rows, isRows := ins.Rows.(sqlparser.Values)
 	if !isRows {
-		panic(vterrors.VT12001("REPLACE INTO using select statement"))
+		insOp := checkAndCreateInsertOperator(ctx, ins, vTbl, routing)
+		addDeleteBeforeInsertSelect(ctx, ins, vTbl, insOp)
+		return insOp
 	}
 
+	// the rows to delete are found before planning the insert,
+	// as that replaces the values of the auto increment and vindex columns with bind variables.
+	if ins.Columns == nil {
+		if !vTbl.ColumnListAuthoritative {
+			panic(vterrors.VT09004())
+		}
+		ins = populateInsertColumnlist(ins, vTbl)
+	}
+	for _, row := range rows {
+		if len(ins.Columns) != len(row) {
+			panic(vterrors.VT03006())
+		}
+	}
 	pkCompExpr := pkCompExpression(vTbl, ins, rows)
 	uniqKeyCompExprs := uniqKeyCompExpressions(vTbl, ins, rows)
 	whereExpr := getWhereCondExpr(append(uniqKeyCompExprs, pkCompExpr))
 
+	insOp := checkAndCreateInsertOperator(ctx, ins, vTbl, routing)
+	if whereExpr == nil {
+		// none of the keys can clash with the inserted rows.
+		return insOp
+	}
+
 	delStmt := &sqlparser.Delete{
 		Comments:   ins.Comments,
 		TableExprs: sqlparser.TableExprs{sqlparser.Clone(ins.Table)},
-		Where:      sqlparser.NewWhere(sqlparser.WhereClause, whereExpr),
+		Where:      sqlparser.NewWhere(sqlparser.WhereClause, sqlparser.Clone(whereExpr)),
 	}
 	delOp := createOpFromStmt(ctx, delStmt, false, "")
 	return &Sequential{Sources: []Operator{delOp, insOp}}
 }
 
+// addDeleteBeforeInsertSelect makes the insert of a REPLACE INTO ... SELECT delete the rows clashing with the
+// selected rows before inserting them. The selected rows are only known at execution time, so the delete compares
+// the keys of the table with tuple bind variables that the engine fills with the key values of the selected rows.
+func addDeleteBeforeInsertSelect(ctx *plancontext.PlanningContext, ins *sqlparser.Insert, vTbl *vindexes.BaseTable, insOp Operator) {
+	var insSel *InsertSelection
+	_ = Visit(insOp, func(op Operator) error {
+		if is, ok := op.(*InsertSelection); ok {
+			insSel = is
+		}
+		return nil
+	})
+	if insSel == nil {
+		panic(vterrors.VT13001("insert selection not found for REPLACE INTO using select statement"))
+	}
+
+	var keys [][]sqlparser.Expr
+	if len(vTbl.PrimaryKey) > 0 {
+		keys = append(keys, slice.Map(vTbl.PrimaryKey, func(col sqlparser.IdentifierCI) sqlparser.Expr {
+			return sqlparser.NewColName(col.String())
+		}))
+	}
+	keys = append(keys, vTbl.UniqueKeys...)
+
+	var whereExpr sqlparser.Expr
+	for _, key := range keys {
+		var cols sqlparser.ValTuple
+		var offsets []int
+		var comparisons []sqlparser.Expr
+		skipKey := false
+		for _, expr := range key {
+			col, isCol := expr.(*sqlparser.ColName)
+			if !isCol {
+				panic(vterrors.VT12001("REPLACE INTO using select statement on a table with a unique key on an expression"))
+			}
+			idx := ins.Columns.FindColumn(col.Name)
+			if idx != -1 {
+				cols = append(cols, sqlparser.NewColName(col.Name.String()))
+				offsets = append(offsets, idx)
+				continue
+			}
+			def := findDefault(vTbl, col.Name)
+			if def == nil {
+				// default value is empty, nothing to compare as it will always be false.
+				skipKey = true
+				break
+			}
+			comparisons = append(comparisons, sqlparser.NewComparisonExpr(sqlparser.EqualOp, sqlparser.NewColName(col.Name.String()), def, nil))
+		}
+		if skipKey {
+			continue
+		}
+		if len(offsets) > 0 {
+			var lhs sqlparser.Expr = cols
+			if len(cols) == 1 {
+				lhs = cols[0]
+			}
+			bvName := engine.DeleteKeyVarName(len(insSel.DeleteKeyOffsets))
+			comparisons = append(comparisons, sqlparser.NewComparisonExpr(sqlparser.InOp, lhs, sqlparser.NewListArg(bvName), nil))
+			insSel.DeleteKeyOffsets = append(insSel.DeleteKeyOffsets, offsets)
+		}
+		keyExpr := sqlparser.AndExpressions(comparisons...)
+		if whereExpr == nil {
+			whereExpr = keyExpr
+		} else {
+			whereExpr = &sqlparser.OrExpr{Left: whereExpr, Right: keyExpr}
+		}
+	}
+	if whereExpr == nil {
+		return
+	}
+
+	delStmt := &sqlparser.Delete{
+		Comments:   ins.Comments,
+		TableExprs: sqlparser.TableExprs{sqlparser.Clone(ins.Table)},
+		Where:      sqlparser.NewWhere(sqlparser.WhereClause, whereExpr),
+	}
+	insSel.DeleteBeforeInsert = createOpFromStmt(ctx, delStmt, false, "")
+}
+
 func checkAndCreateInsertOperator(ctx *plancontext.PlanningContext, ins *sqlparser.Insert, vTbl *vindexes.BaseTable, routing Routing) Operator {
 	insOp := createInsertOperator(ctx, ins, vTbl, routing)
 
@@ -205,6 +309,9 @@ func pkCompExpression(vTbl *vindexes.BaseTable, ins *sqlparser.Insert, rows sqlp
 		return nil
 	}
 	pIndexes, pColTuple := findPKIndexes(vTbl, ins)
+	if len(pIndexes) == 0 {
+		return nil
+	}
 
 	var pValTuple sqlparser.ValTuple
 	for _, row := range rows {
@@ -245,6 +352,10 @@ func findPKIndexes(vTbl *vindexes.BaseTable, ins *sqlparser.Insert) (pIndexes []
 }
 
 func findDefault(vTbl *vindexes.BaseTable, pCol sqlparser.IdentifierCI) sqlparser.Expr {
+	if vTbl.AutoIncrement != nil && vTbl.AutoIncrement.Column.Equal(pCol) {
+		// a new value is generated for the auto increment column, it does not clash with any existing row.
+		return nil
+	}
 	for _, column := range vTbl.Columns {
 		if column.Name.Equal(pCol) {
 			return column.Default
@@ -28,6 +28,12 @@ type InsertSelection struct {
 	// ForceNonStreaming when true, select first then insert, this is to avoid locking rows by select for insert.
 	ForceNonStreaming bool
 
+	// DeleteBeforeInsert when set, deletes the rows clashing with the selected rows before inserting them.
+	// This is how REPLACE INTO ... SELECT is run on tables where vtgate has to maintain vindexes or foreign keys.
+	DeleteBeforeInsert Operator
+	// DeleteKeyOffsets are the offsets of the key columns in the selected rows, for each key compared against by the delete.
+	DeleteKeyOffsets [][]int
+
 	noColumns
 	noPredicates
 }
@@ -38,9 +44,26 @@ func (is *InsertSelection) Clone(inputs []Operator) Operator {
 	klone := *is
 	klone.LHS = inputs[0]
 	klone.RHS = inputs[1]
+	if len(inputs) > 2 {
+		klone.DeleteBeforeInsert = inputs[2]
+	}
 	return &klone
 }
 
+func (is *InsertSelection) Inputs() []Operator {
+	if is.DeleteBeforeInsert == nil {
+		return is.binaryOperator.Inputs()
+	}
+	return []Operator{is.LHS, is.RHS, is.DeleteBeforeInsert}
+}
+
+func (is *InsertSelection) SetInputs(inputs []Operator) {
+	is.binaryOperator.SetInputs(inputs)
+	if len(inputs) > 2 {
+		is.DeleteBeforeInsert = inputs[2]
+	}
+}
+
 func (is *InsertSelection) ShortDescription() string {
 	if is.ForceNonStreaming {
 		return "NonStreaming"
@@ -32,6 +32,10 @@ type Join struct {
 	// NormalJoinType, StraightJoinType and LeftJoinType.
 	JoinType sqlparser.JoinType
 
+	// Lateral contains the predicates of a lateral derived table or JSON_TABLE on the RHS
+	// that depend on the tables of the LHS
+	Lateral []applyJoinColumn
+
 	noColumns
 }
 
@@ -77,6 +81,7 @@ func createStraightJoin(ctx *plancontext.PlanningContext, join *sqlparser.JoinTa
 	joinOp := &Join{
 		binaryOperator: newBinaryOp(lhs, rhs),
 		JoinType:       join.Join,
+		Lateral:        lateralPredicates(ctx, lhs, rhs),
 	}
 
 	return addJoinPredicates(ctx, join.Condition.On, joinOp)
@@ -93,26 +98,41 @@ func createLeftOuterJoin(ctx *plancontext.PlanningContext, join *sqlparser.JoinT
 		join.Join = sqlparser.NaturalLeftJoinType
 	}
 
-	joinOp := &Join{
-		binaryOperator: newBinaryOp(lhs, rhs),
-		JoinType:       join.Join,
-	}
+	lateral := lateralPredicates(ctx, lhs, rhs)
+	rhsID := TableID(rhs)
 
 	// mark the RHS as outer tables so we know which columns are nullable
-	ctx.OuterTables = ctx.OuterTables.Merge(TableID(rhs))
+	ctx.OuterTables = ctx.OuterTables.Merge(rhsID)
 
 	// for outer joins we have to be careful with the predicates we use
-	var op Operator
-	subq, _, _ := getSubQuery(join.Condition.On)
-	if subq != nil {
-		panic(vterrors.VT12001("subquery in outer join predicate"))
-	}
 	predicate := join.Condition.On
 	sqlparser.RemoveKeyspaceInCol(predicate)
-	joinOp.Predicate = predicate
-	op = joinOp
+	var joinPreds, rhsPreds []sqlparser.Expr
+	sqc := &SubQueryBuilder{}
+	for _, pred := range sqlparser.SplitAndExpression(nil, predicate) {
+		subq, _, _ := getSubQuery(pred)
+		switch {
+		case subq == nil:
+			joinPreds = append(joinPreds, pred)
+		case ctx.SemTable.RecursiveDeps(pred).IsSolvedBy(rhsID):
+			// predicates only using the RHS can be used to filter the RHS before the join
+			rhsPreds = append(rhsPreds, pred)
+		default:
+			joinPreds = append(joinPreds, sqc.pullOutUncorrelatedSubqueries(ctx, pred, TableID(lhs).Merge(rhsID), "outer join predicate"))
+		}
+	}
+	if len(rhsPreds) > 0 {
+		rhs = addJoinPredicates(ctx, sqlparser.AndExpressions(rhsPreds...), rhs)
+	}
+
+	joinOp := &Join{
+		binaryOperator: newBinaryOp(lhs, rhs),
+		JoinType:       join.Join,
+		Lateral:        lateral,
+		Predicate:      sqlparser.AndExpressions(joinPreds...),
+	}
 
-	return op
+	return sqc.getRootOperator(joinOp, nil)
 }
 
 func createInnerJoin(ctx *plancontext.PlanningContext, tableExpr *sqlparser.JoinTableExpr, lhs, rhs Operator) Operator {
@@ -180,6 +200,12 @@ func breakCTEExpressionInLhsAndRhs(ctx *plancontext.PlanningContext, pred sqlpar
 }
 
 func createJoin(ctx *plancontext.PlanningContext, LHS, RHS Operator) Operator {
+	if lateral := lateralPredicates(ctx, LHS, RHS); len(lateral) > 0 {
+		return &Join{
+			binaryOperator: newBinaryOp(LHS, RHS),
+			Lateral:        lateral,
+		}
+	}
 	lqg, lok := LHS.(*QueryGraph)
 	rqg, rok := RHS.(*QueryGraph)
 	if lok && rok {
@@ -195,6 +221,34 @@ func createJoin(ctx *plancontext.PlanningContext, LHS, RHS Operator) Operator {
 	}
 }
 
+// lateralPredicates returns the predicates of a lateral derived table or JSON_TABLE
+// on the RHS of a join that depend on the tables of the LHS
+func lateralPredicates(ctx *plancontext.PlanningContext, lhs, rhs Operator) []applyJoinColumn {
+	if len(lateralOf(lhs)) > 0 {
+		panic(vterrors.VT12001("lateral derived table or JSON_TABLE on the left side of a join"))
+	}
+	lateral := lateralOf(rhs)
+	lhsID := TableID(lhs)
+	for _, col := range lateral {
+		for _, bve := range col.LHSExprs {
+			if !ctx.SemTable.RecursiveDeps(bve.Expr).IsSolvedBy(lhsID) {
+				panic(vterrors.VT12001("lateral derived table or JSON_TABLE referencing tables outside of its join"))
+			}
+		}
+	}
+	return lateral
+}
+
+func lateralOf(op Operator) []applyJoinColumn {
+	switch op := op.(type) {
+	case *Horizon:
+		return op.Lateral
+	case *JSONTable:
+		return op.Lateral
+	}
+	return nil
+}
+
 func (j *Join) AddPredicate(ctx *plancontext.PlanningContext, expr sqlparser.Expr) Operator {
 	return AddPredicate(ctx, j, expr, false, newFilterSinglePredicate)
 }
@@ -0,0 +1,157 @@
+/*
+Copyright 2025 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package operators
+
+import (
+	"vitess.io/vitess/go/slice"
+	"vitess.io/vitess/go/vt/sqlparser"
+	"vitess.io/vitess/go/vt/vterrors"
+	"vitess.io/vitess/go/vt/vtgate/planbuilder/operators/predicates"
+	"vitess.io/vitess/go/vt/vtgate/planbuilder/plancontext"
+	"vitess.io/vitess/go/vt/vtgate/semantics"
+)
+
+// JSONTable is a leaf operator producing the rows of a JSON_TABLE expression.
+// When it is merged into a route, the expression is sent to MySQL as part of the route's query.
+// Otherwise, the rows are generated by vtgate.
+type JSONTable struct {
+	ID  semantics.TableSet
+	AST *sqlparser.JSONTableExpr
+
+	// Doc is the JSON document the rows are produced from. When the document depends on the
+	// tables preceding the JSON_TABLE in the FROM clause, this is a join predicate that is
+	// tracked like the ones pushed to the RHS of an ApplyJoin, and the tables it depends
+	// on are described by Lateral
+	Doc     sqlparser.Expr
+	Lateral []applyJoinColumn
+
+	Columns []*sqlparser.ColName
+
+	nullaryOperator
+}
+
+var _ ColNameColumns = (*JSONTable)(nil)
+
+func newJSONTable(ctx *plancontext.PlanningContext, node *sqlparser.JSONTableExpr) Operator {
+	jt := &JSONTable{
+		ID:  ctx.SemTable.TableSetForJSONTable(node),
+		AST: node,
+		Doc: node.Expr,
+	}
+	deps := ctx.SemTable.RecursiveDeps(node.Expr)
+	if deps.IsEmpty() {
+		// a JSON_TABLE that does not depend on any table is like the dual table,
+		// and can be merged with any route it is joined with
+		return &Route{
+			unaryOperator: newUnaryOp(jt),
+			Routing:       &DualRouting{},
+		}
+	}
+
+	col := breakExpressionInLHSandRHS(ctx, node.Expr, deps)
+	doc := ctx.PredTracker.NewJoinPredicate(col.RHSExpr)
+	col.JoinPredicateID = &doc.ID
+	col.Lateral = true
+	jt.Doc = doc
+	jt.Lateral = []applyJoinColumn{col}
+	return jt
+}
+
+// Introduces implements the Operator interface
+func (jt *JSONTable) introducesTableID() semantics.TableSet {
+	return jt.ID
+}
+
+// Clone implements the Operator interface
+func (jt *JSONTable) Clone([]Operator) Operator {
+	clone := *jt
+	return &clone
+}
+
+// AddPredicate implements the Operator interface. The predicates are evaluated by vtgate on the generated rows.
+// A JSON_TABLE evaluated by vtgate is never merged with a route later on, so the join
+// predicates pushed by an ApplyJoin don't have to be tracked
+func (jt *JSONTable) AddPredicate(_ *plancontext.PlanningContext, expr sqlparser.Expr) Operator {
+	if jp, ok := expr.(*predicates.JoinPredicate); ok {
+		expr = jp.Current()
+	}
+	return newFilter(jt, expr)
+}
+
+// AddColumn implements the Operator interface. The rows of a JSON_TABLE are not grouped,
+// so columns added to the grouping of an aggregation above are added as plain columns
+func (jt *JSONTable) AddColumn(ctx *plancontext.PlanningContext, reuse bool, _ bool, ae *sqlparser.AliasedExpr) int {
+	if reuse {
+		offset := jt.FindCol(ctx, ae.Expr, true)
+		if offset > -1 {
+			return offset
+		}
+	}
+
+	return addColumn(ctx, jt, ae.Expr)
+}
+
+func (*JSONTable) AddWSColumn(*plancontext.PlanningContext, int, bool) int {
+	panic(vterrors.VT13001("did not expect this method to be called"))
+}
+
+func (jt *JSONTable) FindCol(ctx *plancontext.PlanningContext, expr sqlparser.Expr, _ bool) int {
+	for idx, col := range jt.Columns {
+		if ctx.SemTable.EqualsExprWithDeps(expr, col) {
+			return idx
+		}
+	}
+	return -1
+}
+
+func (jt *JSONTable) GetColumns(*plancontext.PlanningContext) []*sqlparser.AliasedExpr {
+	return slice.Map(jt.Columns, colNameToExpr)
+}
+
+func (jt *JSONTable) GetSelectExprs(ctx *plancontext.PlanningContext) []sqlparser.SelectExpr {
+	return transformColumnsToSelectExprs(ctx, jt)
+}
+
+func (jt *JSONTable) GetOrdering(*plancontext.PlanningContext) []OrderBy {
+	return nil
+}
+
+func (jt *JSONTable) GetColNames() []*sqlparser.ColName {
+	return jt.Columns
+}
+
+func (jt *JSONTable) AddCol(col *sqlparser.ColName) {
+	jt.Columns = append(jt.Columns, col)
+}
+
+// CurrentDoc returns the JSON document in the shape it currently has to be evaluated in
+func (jt *JSONTable) CurrentDoc() sqlparser.Expr {
+	if jp, ok := jt.Doc.(*predicates.JoinPredicate); ok {
+		return jp.Current()
+	}
+	return jt.Doc
+}
+
+// TablesUsed implements the Operator interface.
+// JSON_TABLE expressions do not read from any table
+func (jt *JSONTable) TablesUsed(in []string) []string {
+	return in
+}
+
+func (jt *JSONTable) ShortDescription() string {
+	return sqlparser.String(jt.CurrentDoc()) + " AS " + jt.AST.Alias.String()
+}
@@ -22,12 +22,14 @@ import (
 	"vitess.io/vitess/go/vt/sqlparser"
 	"vitess.io/vitess/go/vt/vterrors"
 	"vitess.io/vitess/go/vt/vtgate/planbuilder/plancontext"
+	"vitess.io/vitess/go/vt/vtgate/semantics"
 )
 
 type (
 	PercentBasedMirror struct {
 		binaryOperator
 		Percent float32
+		Compare bool
 	}
 )
 
@@ -41,10 +43,11 @@ func (m *PercentBasedMirror) Target() Operator {
 	return m.RHS
 }
 
-func NewPercentBasedMirror(percent float32, operator, target Operator) *PercentBasedMirror {
+func NewPercentBasedMirror(mi semantics.MirrorInfo, operator, target Operator) *PercentBasedMirror {
 	return &PercentBasedMirror{
 		binaryOperator: newBinaryOp(operator, target),
-		Percent:        percent,
+		Percent:        mi.Percent,
+		Compare:        mi.Compare,
 	}
 }
 
@@ -80,6 +83,9 @@ func (m *PercentBasedMirror) GetSelectExprs(ctx *plancontext.PlanningContext) []
 }
 
 func (m *PercentBasedMirror) ShortDescription() string {
+	if m.Compare {
+		return fmt.Sprintf("PercentBasedMirror (%.02f%%, compare)", m.Percent)
+	}
 	return fmt.Sprintf("PercentBasedMirror (%.02f%%)", m.Percent)
 }
 
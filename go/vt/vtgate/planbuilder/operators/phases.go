@@ -174,6 +174,10 @@ func createDMLWithInput(ctx *plancontext.PlanningContext, op, src Operator, in *
 		panic(vterrors.VT13001("target DELETE table not found"))
 	}
 
+	if _, limited := src.(*Limit); limited {
+		lockTargetRows(src, in.Target.ID)
+	}
+
 	// optimize for case when there is only single column on left hand side.
 	var lhs sqlparser.Expr = leftComp
 	if len(leftComp) == 1 {
@@ -202,6 +206,25 @@ func createDMLWithInput(ctx *plancontext.PlanningContext, op, src Operator, in *
 	return dm, Rewrote("changed Delete to DMLWithInput")
 }
 
+// lockTargetRows locks the rows of the target table read by the input of a DMLWithInput with a LIMIT, since
+// they are the rows changed by the DML later on in the same transaction, and another transaction must not change
+// which rows the LIMIT selects in the meantime. Rows that are filtered out by vtgate, like the ones read on
+// the left hand side of a join, are not changed by the DML and are not locked.
+func lockTargetRows(op Operator, target semantics.TableSet) {
+	switch op := op.(type) {
+	case *Route:
+		if TableID(op).IsOverlapping(target) {
+			op.Lock = sqlparser.ForUpdateLock
+		}
+	case *Projection, *Limit, *Ordering, *SubQueryContainer:
+		for _, input := range op.Inputs() {
+			lockTargetRows(input, target)
+		}
+	case *ApplyJoin:
+		lockTargetRows(op.RHS, target)
+	}
+}
+
 func removePerformanceDistinctAboveRoute(_ *plancontext.PlanningContext, op Operator) Operator {
 	return BottomUp(op, TableID, func(innerOp Operator, _ semantics.TableSet, _ bool) (Operator, *ApplyResult) {
 		d, ok := innerOp.(*Distinct)
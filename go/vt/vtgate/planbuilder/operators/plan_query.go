@@ -190,6 +190,9 @@ func tryTruncateColumnsAt(op Operator, truncateAt int) bool {
 	case *Limit:
 		return tryTruncateColumnsAt(op.Source, truncateAt)
 	case *SubQuery:
+		if op.prependsValue() {
+			return false
+		}
 		for _, offset := range op.Vars {
 			if offset >= truncateAt {
 				return false
@@ -135,7 +135,8 @@ func pushProjectionToOuter(ctx *plancontext.PlanningContext, p *Projection, sq *
 		return p, NoRewrite
 	}
 
-	if !reachedPhase(ctx, subquerySettling) {
+	if !reachedPhase(ctx, subquerySettling) || sq.prependsValue() {
+		// the value of a correlated subquery is only known after the outer query has been executed
 		return p, NoRewrite
 	}
 
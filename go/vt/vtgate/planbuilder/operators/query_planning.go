@@ -279,6 +279,7 @@ func pushOrExpandHorizon(ctx *plancontext.PlanningContext, in *Horizon) (Operato
 		!hasHaving &&
 		!needsOrdering &&
 		!qp.NeedsAggregation() &&
+		windowsArePushable(ctx, qp, rb) &&
 		!isDistinctAST(in.selectStatement()) &&
 		in.selectStatement().GetLimit() == nil
 
@@ -554,7 +555,7 @@ func setUpperLimit(in *Limit) (Operator, *ApplyResult) {
 	var result *ApplyResult
 	shouldVisit := func(op Operator) VisitRule {
 		switch op := op.(type) {
-		case *Join, *ApplyJoin, *SubQueryContainer, *SubQuery:
+		case *Join, *ApplyJoin, *SubQueryContainer, *SubQuery, *Window:
 			// we can't push limits down on either side
 			return SkipChildren
 		case *Aggregator:
@@ -765,7 +766,7 @@ func tryPushFilter(ctx *plancontext.PlanningContext, in *Filter) (Operator, *App
 		outerTableID := TableID(src.Outer)
 		for _, pred := range in.Predicates {
 			deps := ctx.SemTable.RecursiveDeps(pred)
-			if !deps.IsSolvedBy(outerTableID) {
+			if !deps.IsSolvedBy(outerTableID) || (src.prependsValue() && containsValueColumn(pred, src)) {
 				return in, NoRewrite
 			}
 		}
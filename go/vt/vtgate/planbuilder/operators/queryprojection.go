@@ -21,7 +21,6 @@ import (
 	"fmt"
 	"slices"
 	"sort"
-	"strings"
 
 	"vitess.io/vitess/go/vt/sqlparser"
 	"vitess.io/vitess/go/vt/vterrors"
@@ -42,6 +41,7 @@ type (
 		// If you change the contents here, please update the toString() method
 		SelectExprs  []SelectExpr
 		HasAggr      bool
+		HasWindow    bool
 		Distinct     bool
 		WithRollup   bool
 		groupByExprs []GroupBy
@@ -85,9 +85,34 @@ type (
 		SubQueryExpression []*SubQuery // Subqueries associated with this aggregation
 
 		PushedDown bool // Whether the aggregation has been pushed down to the next layer
+
+		// The arguments and the ORDER BY expressions of a group_concat,
+		// used when the rows of the group are concatenated on the vtgate
+		GroupConcatArgs    []GroupConcatColumn
+		GroupConcatOrderBy []GroupConcatColumn
+
+		// The offsets of the count and the sum of the argument of the VAR_POP that a
+		// statistical aggregation is split into, used to merge the variances of the shards
+		CountOffset int
+		SumOffset   int
+	}
+
+	// GroupConcatColumn is a column used by a group_concat that is evaluated on the vtgate
+	GroupConcatColumn struct {
+		Expr      sqlparser.Expr
+		Desc      bool
+		ColOffset int
+		WSOffset  int
 	}
 )
 
+// needsAllRows returns true for the group_concat aggregations that have to remove
+// duplicates or sort the values, which can only be done with the rows of all shards
+func (aggr Aggr) needsAllRows() bool {
+	gcFunc, ok := aggr.Func.(*sqlparser.GroupConcatExpr)
+	return ok && (gcFunc.Distinct || len(gcFunc.OrderBy) > 0)
+}
+
 func (aggr Aggr) NeedsWeightString(ctx *plancontext.PlanningContext) bool {
 	return aggr.OpCode.NeedsComparableValues() && ctx.NeedsWeightString(aggr.Func.GetArg())
 }
@@ -186,6 +211,9 @@ func (qp *QueryProjection) addSelectExpressions(ctx *plancontext.PlanningContext
 				col.Aggr = true
 				qp.HasAggr = true
 			}
+			if sqlparser.ContainsWindowFunc(selExp.Expr) {
+				qp.HasWindow = true
+			}
 
 			qp.SelectExprs = append(qp.SelectExprs, col)
 		case *sqlparser.StarExpr:
@@ -687,11 +715,6 @@ func checkForInvalidGroupingExpressions(ctx *plancontext.PlanningContext, expr s
 		if ctx.IsAggr(node) {
 			panic(vterrors.VT03005(sqlparser.String(expr)))
 		}
-		_, isSubQ := node.(*sqlparser.Subquery)
-		arg, isArg := node.(*sqlparser.Argument)
-		if isSubQ || (isArg && strings.HasPrefix(arg.Name, "__sq")) {
-			panic(vterrors.VT12001("subqueries in GROUP BY"))
-		}
 		return true, nil
 	}, expr)
 }
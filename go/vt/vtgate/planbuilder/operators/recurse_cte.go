@@ -182,7 +182,12 @@ func (r *RecurseCTE) planOffsets(ctx *plancontext.PlanningContext) Operator {
 			}
 
 			for offset, column := range columns {
-				if lhsExpr.Expr.Name.EqualString(column.ColumnName()) {
+				name := column.ColumnName()
+				if offset < len(r.Def.Columns) {
+					// the columns are named by the column list of the CTE
+					name = r.Def.Columns[offset].String()
+				}
+				if lhsExpr.Expr.Name.EqualString(name) {
 					r.Vars[lhsExpr.Name] = offset
 					continue outer
 				}
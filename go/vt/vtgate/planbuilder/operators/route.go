@@ -67,6 +67,11 @@ type (
 
 		// during planning, we store the alternatives found for this route in this slice
 		Options []*VindexOption
+
+		// statistics of the table, if they are known. They are used to avoid lookups that don't narrow down the shards
+		statistics *vindexes.TableStatistics
+		// shards is the number of shards of the keyspace of the table, or zero if it is unknown
+		shards int
 	}
 
 	// VindexOption stores the information needed to know if we have all the information needed to use a vindex
@@ -331,6 +336,9 @@ func (vpp *VindexPlusPredicates) bestOption() *VindexOption {
 	var keepOptions []*VindexOption
 	for _, option := range vpp.Options {
 		if option.Ready {
+			if vpp.scatterIsCheaper(option) {
+				continue
+			}
 			if best == nil || less(option.Cost, best.Cost) {
 				best = option
 			}
@@ -56,9 +56,63 @@ func optimizeJoin(ctx *plancontext.PlanningContext, op *Join) (Operator, *ApplyR
 	if newOp := op.tryCompact(ctx); newOp != nil {
 		return newOp, Rewrote("merged query graphs")
 	}
+	if len(op.Lateral) > 0 {
+		return lateralJoin(ctx, op)
+	}
 	return mergeOrJoin(ctx, op.LHS, op.RHS, sqlparser.SplitAndExpression(nil, op.Predicate), op.JoinType)
 }
 
+// lateralJoin plans a join with a lateral derived table or JSON_TABLE on the RHS.
+// The RHS is evaluated for every row of the LHS unless the two sides can be merged,
+// so the sides are never switched and no hash join is considered.
+func lateralJoin(ctx *plancontext.PlanningContext, op *Join) (Operator, *ApplyResult) {
+	join := NewApplyJoin(ctx, Clone(op.LHS), Clone(op.RHS), nil, op.JoinType, false)
+	join.JoinPredicates.columns = append(join.JoinPredicates.columns, op.Lateral...)
+	if isJSONTable(op.RHS) {
+		return mergeOrJoinJSONTable(ctx, join, op.Predicate)
+	}
+
+	join.AddJoinPredicate(ctx, op.Predicate, true)
+	if r, res := tryMergeApplyJoin(join, ctx); res != NoRewrite {
+		return r, Rewrote("merge lateral derived table into route")
+	}
+	return join, Rewrote("logical join to applyJoin with a lateral derived table")
+}
+
+// mergeOrJoinJSONTable pushes a JSON_TABLE evaluating a document from the LHS of the join
+// into the route of the LHS, so it is evaluated by MySQL. When the LHS is not a single route,
+// the JSON_TABLE is evaluated by vtgate for every row of the LHS.
+func mergeOrJoinJSONTable(ctx *plancontext.PlanningContext, join *ApplyJoin, predicate sqlparser.Expr) (Operator, *ApplyResult) {
+	r, ok := join.LHS.(*Route)
+	if !ok {
+		join.AddJoinPredicate(ctx, predicate, true)
+		return join, Rewrote("logical join to applyJoin with a lateral JSON_TABLE")
+	}
+
+	for _, col := range join.JoinPredicates.columns {
+		ctx.PredTracker.Set(*col.JoinPredicateID, col.Original)
+	}
+	join.LHS = r.Source
+	// the join predicates are part of the query sent to MySQL
+	join.AddJoinPredicate(ctx, predicate, false)
+	return &Route{
+		unaryOperator: newUnaryOp(join),
+		Routing:       r.Routing,
+		Conditions:    r.Conditions,
+	}, Rewrote("merge lateral JSON_TABLE into route")
+}
+
+// isJSONTable returns true if the operator produces the rows of a JSON_TABLE evaluated by vtgate
+func isJSONTable(op Operator) bool {
+	switch op := op.(type) {
+	case *JSONTable:
+		return true
+	case *Filter:
+		return isJSONTable(op.Source)
+	}
+	return false
+}
+
 func optimizeQueryGraph(ctx *plancontext.PlanningContext, op *QueryGraph) (result Operator, changed *ApplyResult) {
 	switch ctx.PlannerVersion {
 	case querypb.ExecuteOptions_Gen4Left2Right:
@@ -250,7 +304,7 @@ func findBestJoin(
 				continue
 			}
 			plan := getJoinFor(ctx, planCache, lhs, rhs, joinPredicates)
-			if bestPlan == nil || CostOf(plan) < CostOf(bestPlan) {
+			if bestPlan == nil || cheaper(ctx, plan, bestPlan) {
 				bestPlan = plan
 				// remember which plans we based on, so we can remove them later
 				lIdx = i
@@ -319,9 +373,55 @@ func mergeOrJoin(ctx *plancontext.PlanningContext, lhs, rhs Operator, joinPredic
 		join.AddJoinPredicate(ctx, pred, true)
 	}
 
+	if hashJoin := hashJoinAlternative(ctx, lhs, rhs, joinPredicates, joinType); hashJoin != nil && cheaper(ctx, hashJoin, join) {
+		ctx.SemTable.QuerySignature.HashJoin = true
+		return hashJoin, Rewrote("use a hash join because it is estimated to be cheaper than a nested loop join")
+	}
+
 	return join, Rewrote("logical join to applyJoin ")
 }
 
+// hashJoinAlternative returns a hash join between the two inputs if the
+// statistics of their tables are known, so the cost of the hash join can be
+// compared to the cost of the nested loop join.
+func hashJoinAlternative(ctx *plancontext.PlanningContext, lhs, rhs Operator, joinPredicates []sqlparser.Expr, joinType sqlparser.JoinType) Operator {
+	lID, rID := TableID(lhs), TableID(rhs)
+	comparisons := 0
+	for _, pred := range sqlparser.SplitAndExpression(nil, sqlparser.AndExpressions(joinPredicates...)) {
+		cmp, ok := hashJoinComparison(ctx, pred, lID, rID)
+		if !ok {
+			if !ctx.SemTable.RecursiveDeps(pred).IsSolvedBy(lID.Merge(rID)) {
+				return nil
+			}
+			// evaluated as a residual predicate after probing
+			continue
+		}
+		if _, found := ctx.TypeForExpr(cmp.LHS); !found {
+			return nil
+		}
+		if _, found := ctx.TypeForExpr(cmp.RHS); !found {
+			return nil
+		}
+		comparisons++
+	}
+	if comparisons == 0 {
+		// without an equality to hash on, every row of the LHS would be compared to every row of the RHS
+		return nil
+	}
+	if _, ok := estimateOf(ctx, lhs); !ok {
+		return nil
+	}
+	if _, ok := estimateOf(ctx, rhs); !ok {
+		return nil
+	}
+
+	join := NewHashJoin(Clone(lhs), Clone(rhs), !joinType.IsInner())
+	for _, pred := range joinPredicates {
+		join.AddJoinPredicate(ctx, pred, true)
+	}
+	return join
+}
+
 func operatorsToRoutes(a, b Operator) (*Route, *Route) {
 	aRoute, ok := a.(*Route)
 	if !ok {
@@ -91,6 +91,11 @@ func newShardedRouting(ctx *plancontext.PlanningContext, vtable *vindexes.BaseTa
 	if isRt {
 		vindexHint = rt.GetVindexHint()
 	}
+	// The number of shards is only needed to weigh the lookups against the statistics.
+	shards := 0
+	if vtable.Statistics != nil {
+		shards = ctx.VSchema.ShardCount(ctx.Ctx, vtable.Keyspace.Name)
+	}
 	for _, columnVindex := range vtable.ColumnVindexes {
 		if vindexHint != nil {
 			switch vindexHint.Type {
@@ -110,7 +115,7 @@ func newShardedRouting(ctx *plancontext.PlanningContext, vtable *vindexes.BaseTa
 		if columnVindex.IsBackfilling() {
 			continue
 		}
-		routing.VindexPreds = append(routing.VindexPreds, &VindexPlusPredicates{ColVindex: columnVindex, TableID: id})
+		routing.VindexPreds = append(routing.VindexPreds, &VindexPlusPredicates{ColVindex: columnVindex, TableID: id, statistics: vtable.Statistics, shards: shards})
 	}
 	return routing
 }
@@ -213,7 +218,7 @@ func (tr *ShardedRouting) resetRoutingLogic(ctx *plancontext.PlanningContext) Ro
 	tr.RouteOpCode = engine.Scatter
 	tr.Selected = nil
 	for i, vp := range tr.VindexPreds {
-		tr.VindexPreds[i] = &VindexPlusPredicates{ColVindex: vp.ColVindex, TableID: vp.TableID}
+		tr.VindexPreds[i] = &VindexPlusPredicates{ColVindex: vp.ColVindex, TableID: vp.TableID, statistics: vp.statistics, shards: vp.shards}
 	}
 
 	var routing Routing = tr
@@ -364,7 +369,11 @@ func (tr *ShardedRouting) planLikeOp(ctx *plancontext.PlanningContext, node *sql
 }
 
 func (tr *ShardedRouting) Cost() int {
-	switch tr.RouteOpCode {
+	return opcodeCost(tr.RouteOpCode)
+}
+
+func opcodeCost(opcode engine.Opcode) int {
+	switch opcode {
 	case engine.EqualUnique:
 		return 1
 	case engine.Equal, engine.SubShard:
@@ -18,6 +18,7 @@ package operators
 
 import (
 	"fmt"
+	"io"
 	"maps"
 	"slices"
 
@@ -55,6 +56,19 @@ type SubQuery struct {
 
 	// IsArgument is set to true if the subquery puts the
 	IsArgument bool
+
+	// pulledOut is set for the uncorrelated subqueries that are evaluated before the outer query,
+	// like the ones in the predicate of an outer join. The arguments holding their results stay
+	// in the outer query, so they can't be merged or pushed down.
+	pulledOut bool
+
+	// RowFilter is the predicate evaluated on every row of the outer query for correlated
+	// subqueries that can't be executed as a semi join, with the subquery replaced by arguments.
+	RowFilter sqlparser.Expr
+
+	// RowValue is the IN comparison evaluated on every row of the outer query for correlated
+	// IN subqueries in the SELECT list, with the subquery replaced by arguments.
+	RowValue sqlparser.Expr
 }
 
 func (sq *SubQuery) planOffsets(ctx *plancontext.PlanningContext) Operator {
@@ -69,6 +83,12 @@ func (sq *SubQuery) planOffsets(ctx *plancontext.PlanningContext) Operator {
 			sq.Vars[lhsExpr.Name] = offset
 		}
 	}
+	if sq.RowFilter != nil {
+		sq.RowFilter = useOffsets(ctx, sq.RowFilter, sq)
+	}
+	if sq.RowValue != nil {
+		sq.RowValue = useOffsets(ctx, sq.RowValue, sq)
+	}
 	return nil
 }
 
@@ -177,6 +197,15 @@ func (sq *SubQuery) AddPredicate(ctx *plancontext.PlanningContext, expr sqlparse
 }
 
 func (sq *SubQuery) AddColumn(ctx *plancontext.PlanningContext, reuseExisting bool, addToGroupBy bool, ae *sqlparser.AliasedExpr) int {
+	if sq.prependsValue() {
+		if sq.isValueColumn(ae.Expr) {
+			return 0
+		}
+		if containsValueColumn(ae.Expr, sq) {
+			panic(vterrors.VT12001("correlated subquery inside an expression that can't be evaluated on vtgate"))
+		}
+		return sq.Outer.AddColumn(ctx, reuseExisting, addToGroupBy, ae) + 1
+	}
 	ae = sqlparser.Clone(ae)
 	// we need to rewrite the column name to an argument if it's the same as the subquery column name
 	ae.Expr = rewriteColNameToArgument(ctx, ae.Expr, []*SubQuery{sq}, sq)
@@ -184,21 +213,86 @@ func (sq *SubQuery) AddColumn(ctx *plancontext.PlanningContext, reuseExisting bo
 }
 
 func (sq *SubQuery) AddWSColumn(ctx *plancontext.PlanningContext, offset int, underRoute bool) int {
+	if sq.prependsValue() {
+		if offset == 0 {
+			panic(vterrors.VT12001("weight_string of the value of a correlated subquery"))
+		}
+		return sq.Outer.AddWSColumn(ctx, offset-1, underRoute) + 1
+	}
 	return sq.Outer.AddWSColumn(ctx, offset, underRoute)
 }
 
 func (sq *SubQuery) FindCol(ctx *plancontext.PlanningContext, expr sqlparser.Expr, underRoute bool) int {
+	if sq.prependsValue() {
+		if sq.isValueColumn(expr) {
+			return 0
+		}
+		offset := sq.Outer.FindCol(ctx, expr, underRoute)
+		if offset < 0 {
+			return offset
+		}
+		return offset + 1
+	}
 	return sq.Outer.FindCol(ctx, expr, underRoute)
 }
 
 func (sq *SubQuery) GetColumns(ctx *plancontext.PlanningContext) []*sqlparser.AliasedExpr {
+	if sq.prependsValue() {
+		return append([]*sqlparser.AliasedExpr{aeWrap(sqlparser.NewColName(sq.ArgName))}, sq.Outer.GetColumns(ctx)...)
+	}
 	return sq.Outer.GetColumns(ctx)
 }
 
 func (sq *SubQuery) GetSelectExprs(ctx *plancontext.PlanningContext) []sqlparser.SelectExpr {
+	if sq.prependsValue() {
+		return transformColumnsToSelectExprs(ctx, sq)
+	}
 	return sq.Outer.GetSelectExprs(ctx)
 }
 
+// prependsValue returns true for the correlated subqueries used as values. They are executed
+// for every row of the outer query, and their value is added as the first column of the rows.
+func (sq *SubQuery) prependsValue() bool {
+	return sq.IsArgument && len(sq.Predicates) > 0
+}
+
+// isValueColumn returns true if the expression is the column holding the value of the subquery.
+// The value of an IN subquery is the result of the comparison with its rows.
+func (sq *SubQuery) isValueColumn(expr sqlparser.Expr) bool {
+	if sq.FilterType.NeedsListArg() {
+		cmp, ok := expr.(*sqlparser.ComparisonExpr)
+		return ok && (cmp.Operator == sqlparser.InOp || cmp.Operator == sqlparser.NotInOp) && sq.refersToSubquery(cmp.Right)
+	}
+	return sq.refersToSubquery(expr)
+}
+
+// refersToSubquery returns true if the expression is the subquery, or the column or argument replacing it
+func (sq *SubQuery) refersToSubquery(expr sqlparser.Expr) bool {
+	switch expr := expr.(type) {
+	case *sqlparser.ColName:
+		return expr.Qualifier.IsEmpty() && expr.Name.EqualString(sq.ArgName)
+	case *sqlparser.Argument:
+		return expr.Name == sq.ArgName || (sq.HasValuesName != "" && expr.Name == sq.HasValuesName)
+	case *sqlparser.Subquery:
+		return sqlparser.Equals.RefOfSubquery(expr, sq.originalSubquery)
+	case *sqlparser.ExistsExpr:
+		return sqlparser.Equals.RefOfSubquery(expr.Subquery, sq.originalSubquery)
+	}
+	return false
+}
+
+func containsValueColumn(expr sqlparser.Expr, sq *SubQuery) bool {
+	found := false
+	_ = sqlparser.Walk(func(node sqlparser.SQLNode) (bool, error) {
+		if e, ok := node.(sqlparser.Expr); ok && sq.refersToSubquery(e) {
+			found = true
+			return false, io.EOF
+		}
+		return true, nil
+	}, expr)
+	return found
+}
+
 // GetMergePredicates returns the predicates that we can use to try to merge this subquery with the outer query.
 func (sq *SubQuery) GetMergePredicates() []sqlparser.Expr {
 	if sq.OuterPredicate != nil {
@@ -210,24 +304,61 @@ func (sq *SubQuery) GetMergePredicates() []sqlparser.Expr {
 func (sq *SubQuery) settle(ctx *plancontext.PlanningContext, outer Operator) Operator {
 	// We can allow uncorrelated queries even when subquery isn't the top level construct,
 	// like if its underneath an Aggregator, because they will be pulled out and run separately.
-	if !sq.TopLevel && sq.correlated {
-		panic(subqueryNotAtTopErr)
-	}
-	if sq.correlated && sq.FilterType != opcode.PulloutExists {
-		panic(correlatedSubqueryErr)
+	// Correlated subqueries are executed once per row of the outer query, with the columns
+	// used by the join predicates sent as arguments. When the outer query is used anywhere
+	// else in the subquery, we don't have a way to send it over.
+	if sq.correlated && len(sq.Predicates) == 0 {
+		if !sq.TopLevel {
+			panic(subqueryNotAtTopErr)
+		}
+		if sq.FilterType != opcode.PulloutExists {
+			panic(correlatedSubqueryErr)
+		}
 	}
 	if sq.IsArgument {
-		if len(sq.GetMergePredicates()) > 0 {
-			// this means that we have a correlated subquery on our hands
-			panic(correlatedSubqueryErr)
+		switch {
+		case len(sq.Predicates) == 0:
+			sq.SubqueryValueName = sq.ArgName
+		case sq.FilterType.NeedsListArg():
+			sq.checkCorrelation(ctx, outer)
+			sq.SubqueryValueName = sq.ArgName
+			sq.RowValue = sq.rewriteComparison(ctx)
+		case sq.FilterType == opcode.PulloutExists:
+			sq.checkCorrelation(ctx, outer)
+			sq.HasValuesName = sq.ArgName
+			sq.addLimit()
+		default:
+			sq.checkCorrelation(ctx, outer)
+			sq.SubqueryValueName = sq.ArgName
 		}
-		sq.SubqueryValueName = sq.ArgName
 		return outer
 	}
 	return sq.settleFilter(ctx, outer)
 }
 
-var correlatedSubqueryErr = vterrors.VT12001("correlated subquery is only supported for EXISTS")
+// checkCorrelation fails the planning if the subquery can't be executed once per row of the outer query,
+// using arguments for the columns of the outer query it depends on.
+func (sq *SubQuery) checkCorrelation(ctx *plancontext.PlanningContext, outer Operator) {
+	tables := TableID(outer).Merge(TableID(sq.Subquery))
+	for _, pred := range sq.Predicates {
+		if !ctx.SemTable.RecursiveDeps(pred).IsSolvedBy(tables) {
+			panic(vterrors.VT12001("correlated subquery using tables from a query other than its outer query"))
+		}
+	}
+	joinColumns, err := sq.GetJoinColumns(ctx, outer)
+	if err != nil {
+		panic(err)
+	}
+	for _, jc := range joinColumns {
+		for _, lhsExpr := range jc.LHSExprs {
+			if ctx.ContainsAggr(lhsExpr.Expr) {
+				panic(vterrors.VT12001("correlated subquery using an aggregation of the outer query"))
+			}
+		}
+	}
+}
+
+var correlatedSubqueryErr = vterrors.VT12001("correlated subquery using the outer query outside of its predicates")
 var subqueryNotAtTopErr = vterrors.VT12001("unmergable subquery can not be inside complex expression")
 
 func (sq *SubQuery) addLimit() {
@@ -237,47 +368,17 @@ func (sq *SubQuery) addLimit() {
 
 func (sq *SubQuery) settleFilter(ctx *plancontext.PlanningContext, outer Operator) Operator {
 	if len(sq.Predicates) > 0 {
-		if sq.FilterType != opcode.PulloutExists {
-			panic(correlatedSubqueryErr)
+		if sq.FilterType == opcode.PulloutExists && sq.TopLevel {
+			sq.addLimit()
+			return outer
 		}
-		sq.addLimit()
-		return outer
+		return sq.settleCorrelatedFilter(ctx, outer)
 	}
 
 	hasValuesArg := func() string {
-		s := ctx.ReservedVars.ReserveVariable(string(sqlparser.HasValueSubQueryBaseName))
-		sq.HasValuesName = s
-		return s
-	}
-	post := func(cursor *sqlparser.CopyOnWriteCursor) {
-		node := cursor.Node()
-		// For IN and NOT IN type filters, we have to add a Expression that checks if we got any rows back or not
-		// for correctness. That expression should be ANDed with the expression that has the IN/NOT IN comparison.
-		if compExpr, isCompExpr := node.(*sqlparser.ComparisonExpr); sq.FilterType.NeedsListArg() && isCompExpr {
-			if listArg, isListArg := compExpr.Right.(sqlparser.ListArg); isListArg && listArg.String() == sq.ArgName {
-				if sq.FilterType == opcode.PulloutIn {
-					cursor.Replace(sqlparser.AndExpressions(sqlparser.NewArgument(hasValuesArg()), compExpr))
-				} else {
-					cursor.Replace(&sqlparser.OrExpr{
-						Left:  sqlparser.NewNotExpr(sqlparser.NewArgument(hasValuesArg())),
-						Right: compExpr,
-					})
-				}
-			}
-		}
-		if _, ok := node.(*sqlparser.Subquery); !ok {
-			return
-		}
-
-		var arg sqlparser.Expr
-		if sq.FilterType.NeedsListArg() {
-			arg = sqlparser.NewListArg(sq.ArgName)
-		} else {
-			arg = sqlparser.NewArgument(sq.ArgName)
-		}
-		cursor.Replace(arg)
+		return sq.hasValuesArg(ctx)
 	}
-	rhsPred := sqlparser.CopyOnRewrite(sq.Original, dontEnterSubqueries, post, ctx.SemTable.CopySemanticInfo).(sqlparser.Expr)
+	rhsPred := sq.rewriteOriginal(ctx)
 
 	var predicates []sqlparser.Expr
 	switch sq.FilterType {
@@ -309,6 +410,105 @@ func (sq *SubQuery) settleFilter(ctx *plancontext.PlanningContext, outer Operato
 	return newFilter(outer, predicates...)
 }
 
+// settleCorrelatedFilter plans a correlated subquery that is used to filter the outer query,
+// but that can't be executed as a semi join. The subquery is executed for every row of the
+// outer query, and the original predicate is evaluated on vtgate with the result of the subquery.
+func (sq *SubQuery) settleCorrelatedFilter(ctx *plancontext.PlanningContext, outer Operator) Operator {
+	sq.checkCorrelation(ctx, outer)
+	sq.RowFilter = sq.rewriteOriginal(ctx)
+	switch sq.FilterType {
+	case opcode.PulloutExists:
+		sq.addLimit()
+	case opcode.PulloutNotExists:
+		sq.addLimit()
+		sq.FilterType = opcode.PulloutExists // the NOT is kept in the row filter
+	default:
+		sq.SubqueryValueName = sq.ArgName
+	}
+	return outer
+}
+
+// rewriteComparison returns the IN comparison using the subquery in the original expression,
+// with the subquery replaced by the arguments holding its result
+func (sq *SubQuery) rewriteComparison(ctx *plancontext.PlanningContext) sqlparser.Expr {
+	var cmp *sqlparser.ComparisonExpr
+	_ = sqlparser.Walk(func(node sqlparser.SQLNode) (bool, error) {
+		if c, ok := node.(*sqlparser.ComparisonExpr); ok && sq.refersToSubquery(c.Right) {
+			cmp = c
+			return false, io.EOF
+		}
+		return true, nil
+	}, sq.Original)
+	if cmp == nil {
+		panic(vterrors.VT13001("could not find the IN comparison of the subquery"))
+	}
+	_ = sqlparser.Walk(func(node sqlparser.SQLNode) (bool, error) {
+		if _, ok := node.(*sqlparser.Subquery); ok {
+			panic(vterrors.VT12001("correlated IN subquery compared with a subquery in the SELECT list"))
+		}
+		return true, nil
+	}, cmp.Left)
+
+	value := &sqlparser.ComparisonExpr{
+		Operator: cmp.Operator,
+		Left:     cmp.Left,
+		Right:    sqlparser.NewListArg(sq.ArgName),
+	}
+	if sq.FilterType == opcode.PulloutIn {
+		return sqlparser.AndExpressions(sqlparser.NewArgument(sq.hasValuesArg(ctx)), value)
+	}
+	return &sqlparser.OrExpr{
+		Left:  sqlparser.NewNotExpr(sqlparser.NewArgument(sq.hasValuesArg(ctx))),
+		Right: value,
+	}
+}
+
+func (sq *SubQuery) hasValuesArg(ctx *plancontext.PlanningContext) string {
+	if sq.HasValuesName == "" {
+		sq.HasValuesName = ctx.ReservedVars.ReserveVariable(string(sqlparser.HasValueSubQueryBaseName))
+	}
+	return sq.HasValuesName
+}
+
+// rewriteOriginal returns the original predicate, with the subquery replaced by the arguments holding its result
+func (sq *SubQuery) rewriteOriginal(ctx *plancontext.PlanningContext) sqlparser.Expr {
+	pre := func(node, _ sqlparser.SQLNode) bool {
+		switch node.(type) {
+		case *sqlparser.Subquery, *sqlparser.ExistsExpr:
+			return false
+		}
+		return true
+	}
+	post := func(cursor *sqlparser.CopyOnWriteCursor) {
+		node := cursor.Node()
+		// For IN and NOT IN type filters, we have to add a Expression that checks if we got any rows back or not
+		// for correctness. That expression should be ANDed with the expression that has the IN/NOT IN comparison.
+		if compExpr, isCompExpr := node.(*sqlparser.ComparisonExpr); sq.FilterType.NeedsListArg() && isCompExpr {
+			if listArg, isListArg := compExpr.Right.(sqlparser.ListArg); isListArg && listArg.String() == sq.ArgName {
+				if sq.FilterType == opcode.PulloutIn {
+					cursor.Replace(sqlparser.AndExpressions(sqlparser.NewArgument(sq.hasValuesArg(ctx)), compExpr))
+				} else {
+					cursor.Replace(&sqlparser.OrExpr{
+						Left:  sqlparser.NewNotExpr(sqlparser.NewArgument(sq.hasValuesArg(ctx))),
+						Right: compExpr,
+					})
+				}
+			}
+		}
+		switch node.(type) {
+		case *sqlparser.ExistsExpr:
+			cursor.Replace(sqlparser.NewArgument(sq.hasValuesArg(ctx)))
+		case *sqlparser.Subquery:
+			if sq.FilterType.NeedsListArg() {
+				cursor.Replace(sqlparser.NewListArg(sq.ArgName))
+			} else {
+				cursor.Replace(sqlparser.NewArgument(sq.ArgName))
+			}
+		}
+	}
+	return sqlparser.CopyOnRewrite(sq.Original, pre, post, ctx.SemTable.CopySemanticInfo).(sqlparser.Expr)
+}
+
 func dontEnterSubqueries(node, _ sqlparser.SQLNode) bool {
 	if _, ok := node.(*sqlparser.Subquery); ok {
 		return false
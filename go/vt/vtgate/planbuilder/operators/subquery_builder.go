@@ -18,6 +18,7 @@ package operators
 
 import (
 	"vitess.io/vitess/go/vt/sqlparser"
+	"vitess.io/vitess/go/vt/vterrors"
 	"vitess.io/vitess/go/vt/vtgate/engine/opcode"
 	"vitess.io/vitess/go/vt/vtgate/planbuilder/plancontext"
 	"vitess.io/vitess/go/vt/vtgate/semantics"
@@ -167,6 +168,8 @@ func createSubquery(
 	original = cloneASTAndSemState(ctx, original)
 	originalSq := cloneASTAndSemState(ctx, subq)
 	subqID := findTablesContained(ctx, subq.Select)
+	// the tables of the parent of a nested subquery include the ones of all its subqueries
+	outerID = outerID.Remove(subqID)
 	totalID := subqID.Merge(outerID)
 	sqc := &SubQueryBuilder{totalID: totalID, subqID: subqID, outerID: outerID}
 
@@ -205,6 +208,8 @@ func createSubqueryFromPath(
 	original = cloneASTAndSemState(ctx, original)
 	originalSq := sqlparser.GetNodeFromPath(original, path).(*sqlparser.Subquery)
 	subqID := findTablesContained(ctx, originalSq.Select)
+	// the tables of the parent of a nested subquery include the ones of all its subqueries
+	outerID = outerID.Remove(subqID)
 	totalID := subqID.Merge(outerID)
 	sqc := &SubQueryBuilder{totalID: totalID, subqID: subqID, outerID: outerID}
 
@@ -355,6 +360,44 @@ func (sqb *SubQueryBuilder) pullOutValueSubqueries(
 	return sqe.new, newSubqs
 }
 
+// pullOutUncorrelatedSubqueries replaces the subqueries in an expression with the arguments holding their results.
+// The subqueries are evaluated once, before the outer query, so they can't depend on it.
+// The clause is used in the error returned for correlated subqueries.
+func (sqb *SubQueryBuilder) pullOutUncorrelatedSubqueries(
+	ctx *plancontext.PlanningContext,
+	expr sqlparser.Expr,
+	outerID semantics.TableSet,
+	clause string,
+) sqlparser.Expr {
+	for {
+		subq, parentExpr, _ := getSubQuery(expr)
+		if subq == nil {
+			return expr
+		}
+		if !ctx.SemTable.RecursiveDeps(subq).IsEmpty() {
+			panic(vterrors.VT12001("correlated subquery in " + clause))
+		}
+		_, _, path := getSubQuery(parentExpr)
+		sq := createSubqueryOp(ctx, parentExpr, parentExpr, subq, outerID, ctx.GetReservedArgumentFor(subq), path)
+		sq.IsArgument = true
+		sq.pulledOut = true
+		rewritten := sq.rewriteOriginal(ctx)
+		if sq.FilterType == opcode.PulloutNotExists {
+			// the NOT stays in the rewritten expression
+			sq.FilterType = opcode.PulloutExists
+		}
+		sqb.Inner = append(sqb.Inner, sq)
+
+		expr = sqlparser.Rewrite(expr, func(cursor *sqlparser.Cursor) bool {
+			if cursor.Node() == parentExpr {
+				cursor.Replace(rewritten)
+				return false
+			}
+			return true
+		}, nil).(sqlparser.Expr)
+	}
+}
+
 type subqueryExtraction struct {
 	new         sqlparser.Expr
 	subq        []*sqlparser.Subquery
@@ -65,7 +65,24 @@ func isMergeable(ctx *plancontext.PlanningContext, query sqlparser.TableStatemen
 			return false
 		}
 
-		return true
+		// window functions are only safe to merge if every partition is guaranteed to live on a single shard
+		mergeable := true
+		_ = sqlparser.Walk(func(e sqlparser.SQLNode) (bool, error) {
+			if _, isSubq := e.(*sqlparser.Subquery); isSubq {
+				return false, nil
+			}
+			over := sqlparser.GetOverClause(e)
+			if over == nil {
+				return true, nil
+			}
+			if over.WindowSpec == nil || !slices.ContainsFunc(over.WindowSpec.PartitionClause, validVindex) {
+				mergeable = false
+				return false, io.EOF
+			}
+			return false, nil
+		}, node.SelectExprs)
+
+		return mergeable
 	case *sqlparser.Union:
 		return isMergeable(ctx, node.Left, op) && isMergeable(ctx, node.Right, op)
 	default:
@@ -465,6 +482,10 @@ func pushOrMergeSubQueryContainer(ctx *plancontext.PlanningContext, in *SubQuery
 	var remaining []*SubQuery
 	var result *ApplyResult
 	for _, inner := range in.Inner {
+		if inner.pulledOut {
+			remaining = append(remaining, inner)
+			continue
+		}
 		newOuter, _result := pushOrMerge(ctx, in.Outer, inner)
 		if _result == NoRewrite {
 			remaining = append(remaining, inner)
@@ -129,6 +129,11 @@ func updateWithInputPlanningRequired(
 	if isMultiTargetUpdate(ctx, updateStmt) {
 		return true
 	}
+	// Without an ORDER BY, the rows changed by a limited update are not guaranteed to be the ones
+	// the owned vindex query selected. Selecting the rows first and updating them by primary key avoids that.
+	if updateStmt.Limit != nil && len(updateStmt.OrderBy) == 0 && updatesVindexColumnByPK(ctx, updateStmt) {
+		return true
+	}
 	// If there are no foreign keys, we don't need to use delete with input.
 	if len(childFks) == 0 && len(parentFks) == 0 {
 		return false
@@ -149,6 +154,29 @@ func isMultiTargetUpdate(ctx *plancontext.PlanningContext, updateStmt *sqlparser
 	return targetTS.NumberOfTables() > 1
 }
 
+// updatesVindexColumnByPK returns true if the update changes a column of one of the vindexes of its target table,
+// and the rows of the table can be updated by primary key
+func updatesVindexColumnByPK(ctx *plancontext.PlanningContext, updateStmt *sqlparser.Update) bool {
+	for _, ue := range updateStmt.Exprs {
+		tblInfo, err := ctx.SemTable.TableInfoForExpr(ue.Name)
+		if err != nil {
+			panic(err)
+		}
+		vTbl := tblInfo.GetVindexTable()
+		if vTbl == nil || !vTbl.Keyspace.Sharded || len(vTbl.PrimaryKey) == 0 {
+			continue
+		}
+		for _, cv := range vTbl.ColumnVindexes {
+			for _, col := range cv.Columns {
+				if ue.Name.Name.Equal(col) {
+					return true
+				}
+			}
+		}
+	}
+	return false
+}
+
 type updColumn struct {
 	updCol *sqlparser.ColName
 	jc     applyJoinColumn
@@ -158,6 +186,7 @@ type updList []updColumn
 
 func createUpdateWithInputOp(ctx *plancontext.PlanningContext, upd *sqlparser.Update) (op Operator) {
 	updClone := ctx.SemTable.Clone(upd).(*sqlparser.Update)
+	useClonedTableExprs(ctx, upd.TableExprs, updClone.TableExprs)
 	upd.Limit = nil
 
 	// Prepare the update expressions list
@@ -320,7 +349,7 @@ func createUpdateOperator(ctx *plancontext.PlanningContext, updStmt *sqlparser.U
 
 	sqc := &SubQueryBuilder{}
 	if updStmt.Where != nil {
-		op = addWherePredsToSubQueryBuilder(ctx, updStmt.Where.Expr, op, sqc)
+		op = addWherePredsToLimitedDML(ctx, updStmt.Where.Expr, op, sqc, updStmt.Limit)
 	}
 
 	outerID := TableID(op)
@@ -0,0 +1,305 @@
+/*
+Copyright 2025 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package operators
+
+import (
+	"io"
+	"slices"
+	"strings"
+
+	"vitess.io/vitess/go/slice"
+	"vitess.io/vitess/go/vt/sqlparser"
+	"vitess.io/vitess/go/vt/vterrors"
+	"vitess.io/vitess/go/vt/vtgate/planbuilder/plancontext"
+)
+
+type (
+	// Window evaluates window functions on the vtgate. It is used when the window functions
+	// can't be sent to the shards, because the rows of a single partition can live on more than one shard.
+	// The input is sorted by the partition and order expressions of the window specification,
+	// and the output contains one column per window function followed by the columns of the input.
+	Window struct {
+		unaryOperator
+
+		Funcs []*WindowFunc
+		Spec  *sqlparser.WindowSpecification
+
+		// These are only filled in during offset planning
+		PartitionBy []WindowKey
+		OrderBy     []WindowKey
+	}
+
+	// WindowFunc is a single window function evaluated by the Window operator
+	WindowFunc struct {
+		Original *sqlparser.AliasedExpr
+		Func     sqlparser.Expr
+
+		// These are only filled in during offset planning
+		ArgOffset     int
+		DefaultOffset int
+	}
+
+	// WindowKey is a partition or order column used by the Window operator
+	WindowKey struct {
+		Inner    sqlparser.Expr
+		Offset   int
+		WSOffset int
+	}
+)
+
+// windowsArePushable returns true if the window functions in the SELECT list can be evaluated by MySQL.
+// This is the case when the query goes to a single shard, or when every window is partitioned
+// by a column with a unique vindex, so all the rows of a partition live on the same shard.
+func windowsArePushable(ctx *plancontext.PlanningContext, qp *QueryProjection, src Operator) bool {
+	if !qp.HasWindow {
+		return true
+	}
+	if sqc, ok := src.(*SubQueryContainer); ok {
+		src = sqc.Outer
+	}
+	rb, isRoute := src.(*Route)
+	if !isRoute {
+		return false
+	}
+	if rb.IsSingleShard() {
+		return true
+	}
+
+	for _, se := range qp.SelectExprs {
+		ae, err := se.GetAliasedExpr()
+		if err != nil {
+			return false
+		}
+		pushable := true
+		_ = sqlparser.Walk(func(node sqlparser.SQLNode) (bool, error) {
+			if _, isSubq := node.(*sqlparser.Subquery); isSubq {
+				return false, nil
+			}
+			if !sqlparser.IsWindowFunc(node) {
+				return true, nil
+			}
+			over := sqlparser.GetOverClause(node)
+			if over.WindowSpec == nil || !partitionedByUniqueVindex(ctx, rb, over.WindowSpec.PartitionClause) {
+				pushable = false
+				return false, io.EOF
+			}
+			return false, nil
+		}, ae.Expr)
+		if !pushable {
+			return false
+		}
+	}
+	return true
+}
+
+func partitionedByUniqueVindex(ctx *plancontext.PlanningContext, op Operator, partitionBy []sqlparser.Expr) bool {
+	for _, expr := range partitionBy {
+		vindex := findColumnVindex(ctx, op, expr)
+		if vindex != nil && vindex.IsUnique() {
+			return true
+		}
+	}
+	return false
+}
+
+// createWindow creates a Window operator for all the window functions used in the SELECT list.
+// The input is sorted so all rows of a partition arrive together, ordered by the window's ORDER BY.
+func createWindow(ctx *plancontext.PlanningContext, qp *QueryProjection, src Operator) *Window {
+	w := &Window{}
+	for _, se := range qp.SelectExprs {
+		ae, err := se.GetAliasedExpr()
+		if err != nil {
+			panic(err)
+		}
+		_ = sqlparser.Walk(func(node sqlparser.SQLNode) (bool, error) {
+			if _, isSubq := node.(*sqlparser.Subquery); isSubq {
+				return false, nil
+			}
+			if !sqlparser.IsWindowFunc(node) {
+				return true, nil
+			}
+			w.addFunc(ctx, node.(sqlparser.Expr))
+			return false, nil
+		}, ae.Expr)
+	}
+
+	var order []OrderBy
+	for _, expr := range w.Spec.PartitionClause {
+		order = append(order, OrderBy{
+			Inner:          &sqlparser.Order{Expr: expr, Direction: sqlparser.AscOrder},
+			SimplifiedExpr: expr,
+		})
+	}
+	for _, o := range w.Spec.OrderClause {
+		order = append(order, OrderBy{
+			Inner:          o,
+			SimplifiedExpr: o.Expr,
+		})
+	}
+	if len(order) > 0 {
+		src = newOrdering(src, order)
+	}
+	w.Source = src
+	return w
+}
+
+func (w *Window) addFunc(ctx *plancontext.PlanningContext, fnc sqlparser.Expr) {
+	over := sqlparser.GetOverClause(fnc)
+	switch {
+	case over.WindowSpec == nil:
+		panic(vterrors.VT12001("named window in a cross-shard query"))
+	case w.Spec == nil:
+		w.Spec = over.WindowSpec
+	case !sqlparser.Equals.RefOfWindowSpecification(w.Spec, over.WindowSpec):
+		panic(vterrors.VT12001("window functions with different window specifications in a cross-shard query"))
+	}
+
+	for _, f := range w.Funcs {
+		if ctx.SemTable.EqualsExprWithDeps(f.Func, fnc) {
+			return
+		}
+	}
+	w.Funcs = append(w.Funcs, &WindowFunc{
+		Original:      aeWrap(fnc),
+		Func:          fnc,
+		ArgOffset:     -1,
+		DefaultOffset: -1,
+	})
+}
+
+// windowFuncArguments returns the argument and the default value expressions of a window function
+func windowFuncArguments(fnc sqlparser.Expr) (arg, def sqlparser.Expr) {
+	switch fnc := fnc.(type) {
+	case *sqlparser.LagLeadExpr:
+		return fnc.Expr, fnc.Default
+	case *sqlparser.FirstOrLastValueExpr:
+		return fnc.Expr, nil
+	case *sqlparser.NTHValueExpr:
+		return fnc.Expr, nil
+	case *sqlparser.Count:
+		if len(fnc.Args) == 1 {
+			return fnc.Args[0], nil
+		}
+	case sqlparser.AggrFunc:
+		return fnc.GetArg(), nil
+	}
+	return nil, nil
+}
+
+func (w *Window) planOffsets(ctx *plancontext.PlanningContext) Operator {
+	addKey := func(expr sqlparser.Expr) WindowKey {
+		key := WindowKey{
+			Inner:    expr,
+			Offset:   w.Source.AddColumn(ctx, true, false, aeWrap(expr)),
+			WSOffset: -1,
+		}
+		if ctx.NeedsWeightString(expr) {
+			key.WSOffset = w.Source.AddWSColumn(ctx, key.Offset, false)
+		}
+		return key
+	}
+
+	for _, expr := range w.Spec.PartitionClause {
+		w.PartitionBy = append(w.PartitionBy, addKey(expr))
+	}
+	for _, order := range w.Spec.OrderClause {
+		w.OrderBy = append(w.OrderBy, addKey(order.Expr))
+	}
+
+	for _, f := range w.Funcs {
+		arg, def := windowFuncArguments(f.Func)
+		if arg != nil {
+			f.ArgOffset = w.Source.AddColumn(ctx, true, false, aeWrap(arg))
+		}
+		if def != nil {
+			f.DefaultOffset = w.Source.AddColumn(ctx, true, false, aeWrap(def))
+		}
+	}
+	return nil
+}
+
+func (w *Window) Clone(inputs []Operator) Operator {
+	kopy := *w
+	kopy.Source = inputs[0]
+	kopy.Funcs = slice.Map(w.Funcs, func(f *WindowFunc) *WindowFunc {
+		fKopy := *f
+		return &fKopy
+	})
+	kopy.PartitionBy = slices.Clone(w.PartitionBy)
+	kopy.OrderBy = slices.Clone(w.OrderBy)
+	return &kopy
+}
+
+func (w *Window) AddPredicate(ctx *plancontext.PlanningContext, expr sqlparser.Expr) Operator {
+	// predicates can't go below the window functions, since they would change the rows in the partitions
+	return newFilter(w, expr)
+}
+
+func (w *Window) AddColumn(ctx *plancontext.PlanningContext, reuse bool, gb bool, expr *sqlparser.AliasedExpr) int {
+	if reuse {
+		offset := w.FindCol(ctx, expr.Expr, false)
+		if offset >= 0 {
+			return offset
+		}
+	}
+	if sqlparser.IsWindowFunc(expr.Expr) {
+		panic(vterrors.VT13001("window function was not planned: " + sqlparser.String(expr)))
+	}
+	return len(w.Funcs) + w.Source.AddColumn(ctx, reuse, gb, expr)
+}
+
+func (w *Window) AddWSColumn(ctx *plancontext.PlanningContext, offset int, underRoute bool) int {
+	if offset < len(w.Funcs) {
+		panic(vterrors.VT12001("weight_string of a window function result"))
+	}
+	return len(w.Funcs) + w.Source.AddWSColumn(ctx, offset-len(w.Funcs), underRoute)
+}
+
+func (w *Window) FindCol(ctx *plancontext.PlanningContext, expr sqlparser.Expr, underRoute bool) int {
+	for i, f := range w.Funcs {
+		if ctx.SemTable.EqualsExprWithDeps(f.Func, expr) {
+			return i
+		}
+	}
+	offset := w.Source.FindCol(ctx, expr, underRoute)
+	if offset < 0 {
+		return offset
+	}
+	return len(w.Funcs) + offset
+}
+
+func (w *Window) GetColumns(ctx *plancontext.PlanningContext) []*sqlparser.AliasedExpr {
+	columns := slice.Map(w.Funcs, func(f *WindowFunc) *sqlparser.AliasedExpr {
+		return f.Original
+	})
+	return append(columns, w.Source.GetColumns(ctx)...)
+}
+
+func (w *Window) GetSelectExprs(ctx *plancontext.PlanningContext) []sqlparser.SelectExpr {
+	return transformColumnsToSelectExprs(ctx, w)
+}
+
+func (w *Window) GetOrdering(ctx *plancontext.PlanningContext) []OrderBy {
+	return w.Source.GetOrdering(ctx)
+}
+
+func (w *Window) ShortDescription() string {
+	funcs := slice.Map(w.Funcs, func(f *WindowFunc) string {
+		return sqlparser.String(f.Func)
+	})
+	return strings.Join(funcs, ", ")
+}
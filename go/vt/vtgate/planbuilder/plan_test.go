@@ -133,6 +133,33 @@ func (s *planTestSuite) TestForeignKeyPlanning() {
 	s.testFile("foreignkey_cases.json", vw, false)
 }
 
+// TestStatisticsPlanning tests the planning of queries when the table statistics are known.
+func (s *planTestSuite) TestStatisticsPlanning() {
+	vw := s.statisticsVSchemaWrapper()
+	s.testFile("statistics_cases.json", vw, false)
+}
+
+// TestStatisticsPlanningShardCount tests the planning of queries when the table statistics
+// and the number of shards of the keyspace are known.
+func (s *planTestSuite) TestStatisticsPlanningShardCount() {
+	vw := s.statisticsVSchemaWrapper()
+	vw.ShardCounts = map[string]int{"user": 256}
+	s.testFile("statistics_shard_count_cases.json", vw, false)
+}
+
+func (s *planTestSuite) statisticsVSchemaWrapper() *vschemawrapper.VSchemaWrapper {
+	env := vtenv.NewTestEnv()
+	vschema := loadSchema(s.T(), "vschemas/schema.json", true)
+	vw, err := vschemawrapper.NewVschemaWrapper(env, vschema, TestBuilder)
+	require.NoError(s.T(), err)
+
+	s.addPKs(vschema, "user", []string{"user", "music"})
+	s.setStatistics(vschema, "user", "user", 1_000_000, map[string]uint64{"id": 1_000_000, "col": 1_000, "intcol": 1_000, "name": 2, "costly": 10_000})
+	s.setStatistics(vschema, "user", "user_extra", 1_000_000, map[string]uint64{"user_id": 1_000_000, "col": 1_000})
+	s.setStatistics(vschema, "user", "music", 100, map[string]uint64{"user_id": 100, "intcol": 50})
+	return vw
+}
+
 // TestForeignKeyChecksOn tests the planning when the session variable for foreign_key_checks is set to ON.
 func (s *planTestSuite) TestForeignKeyChecksOn() {
 	env := vtenv.NewTestEnv()
@@ -248,6 +275,11 @@ func (s *planTestSuite) addPKs(vschema *vindexes.VSchema, ks string, tbls []stri
 	}
 }
 
+func (s *planTestSuite) setStatistics(vschema *vindexes.VSchema, ks, tbl string, rowCount uint64, cardinality map[string]uint64) {
+	require.NoError(s.T(),
+		vschema.SetTableStatistics(ks, tbl, &vindexes.TableStatistics{RowCount: rowCount, Cardinality: cardinality}))
+}
+
 func (s *planTestSuite) addPKsProvided(vschema *vindexes.VSchema, ks string, tbls []string, pks []string) {
 	for _, tbl := range tbls {
 		require.NoError(s.T(),
@@ -601,6 +633,42 @@ func (s *planTestSuite) TestMirrorPlanning() {
 	s.testFile("mirror_cases.json", vw, false)
 }
 
+func (s *planTestSuite) TestTenantIsolationPlanning() {
+	env := vtenv.NewTestEnv()
+	vschema := loadSchema(s.T(), "vschemas/multi_tenant_schema.json", true)
+	vw, err := vschemawrapper.NewVschemaWrapper(env, vschema, TestBuilder)
+	require.NoError(s.T(), err)
+	vw.TenantIsolation = true
+
+	s.testFile("tenant_isolation_cases.json", vw, false)
+}
+
+// shardTargetVSchema selects a keyspace other than the default one of the
+// test wrapper.
+type shardTargetVSchema struct {
+	*vschemawrapper.VSchemaWrapper
+	keyspace string
+}
+
+func (vw *shardTargetVSchema) SelectedKeyspace() (*vindexes.Keyspace, error) {
+	return vw.V.Keyspaces[vw.keyspace].Keyspace, nil
+}
+
+func (s *planTestSuite) TestTenantIsolationShardTarget() {
+	env := vtenv.NewTestEnv()
+	vschema := loadSchema(s.T(), "vschemas/multi_tenant_schema.json", true)
+	vw, err := vschemawrapper.NewVschemaWrapper(env, vschema, TestBuilder)
+	require.NoError(s.T(), err)
+	vw.TenantIsolation = true
+	vw.Dest = key.DestinationShard("-80")
+
+	_, err = TestBuilder("select * from orders", &shardTargetVSchema{VSchemaWrapper: vw, keyspace: "tenant"}, "")
+	require.EqualError(s.T(), err, "cannot target the shards of the multi-tenant keyspace tenant while the tenant isolation is enabled")
+
+	_, err = TestBuilder("select * from countries", &shardTargetVSchema{VSchemaWrapper: vw, keyspace: "main"}, "")
+	require.NoError(s.T(), err)
+}
+
 func (s *planTestSuite) TestOneMirror() {
 	reset := operators.EnableDebugPrinting()
 	defer reset()
@@ -17,6 +17,7 @@ limitations under the License.
 package plancontext
 
 import (
+	"context"
 	"io"
 
 	"vitess.io/vitess/go/sqltypes"
@@ -35,6 +36,10 @@ type PlanningContext struct {
 	SemTable     *semantics.SemTable
 	VSchema      VSchema
 
+	// Ctx is the context of the query being planned, for the lookups of the
+	// VSchema that are not served from memory, like the shard count.
+	Ctx context.Context
+
 	PlannerVersion querypb.ExecuteOptions_PlannerVersion
 
 	// If we during planning have turned this expression into an argument name,
@@ -81,7 +86,9 @@ type PlanningContext struct {
 // It analyzes the SQL statement within the given virtual schema context,
 // handling default keyspace settings and semantic analysis.
 // Returns an error if semantic analysis fails.
-func CreatePlanningContext(stmt sqlparser.Statement,
+func CreatePlanningContext(
+	ctx context.Context,
+	stmt sqlparser.Statement,
 	reservedVars *sqlparser.ReservedVars,
 	vschema VSchema,
 	version querypb.ExecuteOptions_PlannerVersion,
@@ -100,6 +107,7 @@ func CreatePlanningContext(stmt sqlparser.Statement,
 	vschema.PlannerWarning(semTable.Warning)
 
 	return &PlanningContext{
+		Ctx:               ctx,
 		ReservedVars:      reservedVars,
 		SemTable:          semTable,
 		VSchema:           vschema,
@@ -267,7 +275,8 @@ func (ctx *PlanningContext) NeedsWeightString(e sqlparser.Expr) bool {
 func (ctx *PlanningContext) IsAggr(e sqlparser.SQLNode) bool {
 	switch node := e.(type) {
 	case sqlparser.AggrFunc:
-		return true
+		// aggregations used as window functions do not aggregate rows
+		return !sqlparser.IsWindowFunc(node)
 	case *sqlparser.FuncExpr:
 		return node.Name.EqualsAnyString(ctx.VSchema.GetAggregateUDFs())
 	}
@@ -283,6 +292,9 @@ func (ctx *PlanningContext) ContainsAggr(e sqlparser.SQLNode) (hasAggr bool) {
 			// so we don't need to worry about aggregation in the original
 			return false, nil
 		case sqlparser.AggrFunc:
+			if sqlparser.IsWindowFunc(node) {
+				return true, nil
+			}
 			hasAggr = true
 			return false, io.EOF
 		case *sqlparser.Subquery:
@@ -341,6 +341,16 @@ func (v *vschema) IsViewsEnabled() bool {
 	panic("implement me")
 }
 
+func (v *vschema) IsTenantIsolationEnabled() bool {
+	// TODO implement me
+	panic("implement me")
+}
+
+func (v *vschema) ShardCount(ctx context.Context, keyspace string) int {
+	// TODO implement me
+	panic("implement me")
+}
+
 func (v *vschema) GetUDV(name string) *querypb.BindVariable {
 	// TODO implement me
 	panic("implement me")
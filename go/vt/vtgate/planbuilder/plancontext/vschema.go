@@ -83,6 +83,13 @@ type VSchema interface {
 	// IsViewsEnabled returns true if Vitess manages the views.
 	IsViewsEnabled() bool
 
+	// IsTenantIsolationEnabled returns true if the queries to multi-tenant
+	// keyspaces must be restricted to the tenant of the session.
+	IsTenantIsolationEnabled() bool
+
+	// ShardCount returns the number of shards of the keyspace, or zero if it is unknown.
+	ShardCount(ctx context.Context, keyspace string) int
+
 	// GetUDV returns user defined value from the variable passed.
 	GetUDV(name string) *querypb.BindVariable
 
@@ -17,6 +17,7 @@ limitations under the License.
 package planbuilder
 
 import (
+	"context"
 	"fmt"
 	"strconv"
 
@@ -28,16 +29,16 @@ import (
 )
 
 func gen4Planner(query string, plannerVersion querypb.ExecuteOptions_PlannerVersion) stmtPlanner {
-	return func(stmt sqlparser.Statement, reservedVars *sqlparser.ReservedVars, vschema plancontext.VSchema) (*planResult, error) {
+	return func(ctx context.Context, stmt sqlparser.Statement, reservedVars *sqlparser.ReservedVars, vschema plancontext.VSchema) (*planResult, error) {
 		switch stmt := stmt.(type) {
 		case sqlparser.SelectStatement:
-			return gen4SelectStmtPlanner(query, plannerVersion, stmt, reservedVars, vschema)
+			return gen4SelectStmtPlanner(ctx, query, plannerVersion, stmt, reservedVars, vschema)
 		case *sqlparser.Update:
-			return gen4UpdateStmtPlanner(plannerVersion, stmt, reservedVars, vschema)
+			return gen4UpdateStmtPlanner(ctx, plannerVersion, stmt, reservedVars, vschema)
 		case *sqlparser.Delete:
-			return gen4DeleteStmtPlanner(plannerVersion, stmt, reservedVars, vschema)
+			return gen4DeleteStmtPlanner(ctx, plannerVersion, stmt, reservedVars, vschema)
 		case *sqlparser.Insert:
-			return gen4InsertStmtPlanner(plannerVersion, stmt, reservedVars, vschema)
+			return gen4InsertStmtPlanner(ctx, plannerVersion, stmt, reservedVars, vschema)
 		default:
 			return nil, vterrors.VT12001(fmt.Sprintf("%T", stmt))
 		}
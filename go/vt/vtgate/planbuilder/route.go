@@ -46,7 +46,7 @@ func WireupRoute(ctx *plancontext.PlanningContext, eroute *engine.Route, sel sql
 	}
 	reservedVars := sqlparser.NewReservedVars("vtg", reserved)
 
-	lookupPrimitive, err := gen4SelectStmtPlanner(query, querypb.ExecuteOptions_Gen4, stmt.(sqlparser.SelectStatement), reservedVars, ctx.VSchema)
+	lookupPrimitive, err := gen4SelectStmtPlanner(ctx.Ctx, query, querypb.ExecuteOptions_Gen4, stmt.(sqlparser.SelectStatement), reservedVars, ctx.VSchema)
 	if err != nil {
 		return nil, vterrors.Wrapf(err, "failed to plan the lookup query: [%s]", query)
 	}
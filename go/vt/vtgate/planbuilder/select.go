@@ -17,6 +17,7 @@ limitations under the License.
 package planbuilder
 
 import (
+	"context"
 	"fmt"
 
 	"vitess.io/vitess/go/vt/key"
@@ -31,6 +32,7 @@ import (
 )
 
 func gen4SelectStmtPlanner(
+	ctx context.Context,
 	query string,
 	plannerVersion querypb.ExecuteOptions_PlannerVersion,
 	stmt sqlparser.SelectStatement,
@@ -56,14 +58,14 @@ func gen4SelectStmtPlanner(
 		}
 
 		if sel.SQLCalcFoundRows && sel.Limit != nil {
-			return gen4planSQLCalcFoundRows(vschema, sel, query, reservedVars)
+			return gen4planSQLCalcFoundRows(ctx, vschema, sel, query, reservedVars)
 		}
 		// if there was no limit, we can safely ignore the SQLCalcFoundRows directive
 		sel.SQLCalcFoundRows = false
 	}
 
 	getPlan := func(selStatement sqlparser.SelectStatement) (engine.Primitive, []string, error) {
-		return newBuildSelectPlan(selStatement, reservedVars, vschema, plannerVersion)
+		return newBuildSelectPlan(ctx, selStatement, reservedVars, vschema, plannerVersion)
 	}
 
 	plan, tablesUsed, err := getPlan(stmt)
@@ -99,7 +101,7 @@ func gen4SelectStmtPlanner(
 	return newPlanResult(plan, tablesUsed...), nil
 }
 
-func gen4planSQLCalcFoundRows(vschema plancontext.VSchema, sel *sqlparser.Select, query string, reservedVars *sqlparser.ReservedVars) (*planResult, error) {
+func gen4planSQLCalcFoundRows(ctx context.Context, vschema plancontext.VSchema, sel *sqlparser.Select, query string, reservedVars *sqlparser.ReservedVars) (*planResult, error) {
 	ksName := ""
 	if ks, _ := vschema.SelectedKeyspace(); ks != nil {
 		ksName = ks.Name
@@ -111,7 +113,7 @@ func gen4planSQLCalcFoundRows(vschema plancontext.VSchema, sel *sqlparser.Select
 	// record any warning as planner warning.
 	vschema.PlannerWarning(semTable.Warning)
 
-	plan, tablesUsed, err := buildSQLCalcFoundRowsPlan(query, sel, reservedVars, vschema)
+	plan, tablesUsed, err := buildSQLCalcFoundRowsPlan(ctx, query, sel, reservedVars, vschema)
 	if err != nil {
 		return nil, err
 	}
@@ -119,12 +121,13 @@ func gen4planSQLCalcFoundRows(vschema plancontext.VSchema, sel *sqlparser.Select
 }
 
 func buildSQLCalcFoundRowsPlan(
+	ctx context.Context,
 	originalQuery string,
 	sel *sqlparser.Select,
 	reservedVars *sqlparser.ReservedVars,
 	vschema plancontext.VSchema,
 ) (engine.Primitive, []string, error) {
-	limitPlan, _, err := newBuildSelectPlan(sel, reservedVars, vschema, Gen4)
+	limitPlan, _, err := newBuildSelectPlan(ctx, sel, reservedVars, vschema, Gen4)
 	if err != nil {
 		return nil, nil, err
 	}
@@ -165,7 +168,7 @@ func buildSQLCalcFoundRowsPlan(
 
 	reservedVars2 := sqlparser.NewReservedVars("vtg", reserved2)
 
-	countPlan, tablesUsed, err := newBuildSelectPlan(sel2, reservedVars2, vschema, Gen4)
+	countPlan, tablesUsed, err := newBuildSelectPlan(ctx, sel2, reservedVars2, vschema, Gen4)
 	if err != nil {
 		return nil, nil, err
 	}
@@ -196,18 +199,19 @@ func gen4PredicateRewrite(stmt sqlparser.Statement, getPlan func(selStatement sq
 }
 
 func newBuildSelectPlan(
+	ctx context.Context,
 	selStmt sqlparser.SelectStatement,
 	reservedVars *sqlparser.ReservedVars,
 	vschema plancontext.VSchema,
 	version querypb.ExecuteOptions_PlannerVersion,
 ) (plan engine.Primitive, tablesUsed []string, err error) {
-	ctx, err := plancontext.CreatePlanningContext(selStmt, reservedVars, vschema, version)
+	pCtx, err := plancontext.CreatePlanningContext(ctx, selStmt, reservedVars, vschema, version)
 	if err != nil {
 		return nil, nil, err
 	}
 
-	if ks, ok := ctx.SemTable.CanTakeSelectUnshardedShortcut(); ok {
-		plan, tablesUsed, err = selectUnshardedShortcut(ctx, selStmt, ks)
+	if ks, ok := pCtx.SemTable.CanTakeSelectUnshardedShortcut(); ok {
+		plan, tablesUsed, err = selectUnshardedShortcut(pCtx, selStmt, ks)
 		if err != nil {
 			return nil, nil, err
 		}
@@ -215,16 +219,16 @@ func newBuildSelectPlan(
 		return plan, tablesUsed, err
 	}
 
-	if ctx.SemTable.NotUnshardedErr != nil {
-		return nil, nil, ctx.SemTable.NotUnshardedErr
+	if pCtx.SemTable.NotUnshardedErr != nil {
+		return nil, nil, pCtx.SemTable.NotUnshardedErr
 	}
 
-	op, err := createSelectOperator(ctx, selStmt)
+	op, err := createSelectOperator(pCtx, selStmt)
 	if err != nil {
 		return nil, nil, err
 	}
 
-	plan, err = transformToPrimitive(ctx, op)
+	plan, err = transformToPrimitive(pCtx, op)
 	if err != nil {
 		return nil, nil, err
 	}
@@ -0,0 +1,421 @@
+/*
+Copyright 2025 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package planbuilder
+
+import (
+	"context"
+	"fmt"
+
+	querypb "vitess.io/vitess/go/vt/proto/query"
+	vschemapb "vitess.io/vitess/go/vt/proto/vschema"
+	vtrpcpb "vitess.io/vitess/go/vt/proto/vtrpc"
+	"vitess.io/vitess/go/vt/sqlparser"
+	"vitess.io/vitess/go/vt/vterrors"
+	"vitess.io/vitess/go/vt/vtgate/engine"
+	"vitess.io/vitess/go/vt/vtgate/evalengine"
+	"vitess.io/vitess/go/vt/vtgate/planbuilder/plancontext"
+	"vitess.io/vitess/go/vt/vtgate/vindexes"
+)
+
+// tenantIsolation restricts a statement to the rows of the tenant of the
+// session, for the tables of the keyspaces that have a multi-tenant spec:
+//
+//   - every table gets a predicate comparing its tenant id column to the
+//     tenant of the session, in the WHERE clause, or in the ON condition
+//     when it is on the inner side of an outer join.
+//   - the rows inserted without a tenant id get the tenant of the session,
+//     and the ones with a tenant id are checked against it when executing.
+//   - the ON DUPLICATE KEY UPDATE assignments only change the rows of the
+//     tenant of the session.
+//   - statements changing the tenant of a row are rejected, and so are the
+//     REPLACE and LOAD DATA statements, which can overwrite the rows of other
+//     tenants.
+//
+// The tenant of the session is bound by the engine.TenantIsolation primitive
+// wrapping the plan, so the plan can be cached and shared by all tenants.
+type tenantIsolation struct {
+	vschema plancontext.VSchema
+
+	// ctes are the names of the common table expressions of the statement,
+	// which must not be mistaken for tables.
+	ctes map[string]bool
+
+	// found is true once a multi-tenant table was found, and colType is the
+	// type of its tenant id column.
+	found   bool
+	colType querypb.Type
+
+	// values are the tenant ids written explicitly by an INSERT.
+	values []sqlparser.Expr
+}
+
+// buildTenantIsolatedPlan builds the plan of the statement, restricted to the
+// tenant of the session when vtgate enforces the tenant isolation.
+func buildTenantIsolatedPlan(ctx context.Context, stmt sqlparser.Statement, reservedVars *sqlparser.ReservedVars, vschema plancontext.VSchema, f stmtPlanner) (*planResult, error) {
+	if !vschema.IsTenantIsolationEnabled() {
+		return buildRoutePlan(ctx, stmt, reservedVars, vschema, f)
+	}
+	if vschema.ShardDestination() != nil {
+		// The statements targeting shards are sent as they are, without the
+		// tenant predicates.
+		if ks, err := vschema.SelectedKeyspace(); err == nil && multiTenantSpec(vschema, ks.Name) != nil {
+			return nil, vterrors.Errorf(vtrpcpb.Code_PERMISSION_DENIED, "cannot target the shards of the multi-tenant keyspace %s while the tenant isolation is enabled", ks.Name)
+		}
+	}
+
+	ti := &tenantIsolation{vschema: vschema, ctes: map[string]bool{}}
+	if err := ti.rewrite(stmt); err != nil {
+		return nil, err
+	}
+	if !ti.found {
+		return buildRoutePlan(ctx, stmt, reservedVars, vschema, f)
+	}
+
+	// The values are translated before planning, which can rewrite the rows.
+	cfg := &evalengine.Config{
+		Collation:   vschema.ConnCollation(),
+		Environment: vschema.Environment(),
+	}
+	values := make([]evalengine.Expr, 0, len(ti.values))
+	for _, expr := range ti.values {
+		value, err := evalengine.Translate(expr, cfg)
+		if err != nil {
+			return nil, vterrors.VT12001(fmt.Sprintf("tenant id value in a multi-tenant table: %s", sqlparser.String(expr)))
+		}
+		values = append(values, value)
+	}
+
+	res, err := buildRoutePlan(ctx, stmt, reservedVars, vschema, f)
+	if err != nil {
+		return nil, err
+	}
+	res.primitive = &engine.TenantIsolation{
+		Type:   ti.colType,
+		Values: values,
+		Input:  res.primitive,
+	}
+	return res, nil
+}
+
+// rewrite adds the tenant restrictions to the statement and all its
+// subqueries.
+func (ti *tenantIsolation) rewrite(stmt sqlparser.Statement) error {
+	var queries []sqlparser.SQLNode
+	_ = sqlparser.Walk(func(node sqlparser.SQLNode) (bool, error) {
+		switch node := node.(type) {
+		case *sqlparser.CommonTableExpr:
+			ti.ctes[node.ID.String()] = true
+		case *sqlparser.Select, *sqlparser.Update, *sqlparser.Delete:
+			queries = append(queries, node)
+		}
+		return true, nil
+	}, stmt)
+
+	if ins, ok := stmt.(*sqlparser.Insert); ok {
+		if err := ti.rewriteInsert(ins); err != nil {
+			return err
+		}
+	}
+
+	for _, query := range queries {
+		var err error
+		switch query := query.(type) {
+		case *sqlparser.Select:
+			err = ti.rewriteSelect(query)
+		case *sqlparser.Update:
+			err = ti.rewriteUpdate(query)
+		case *sqlparser.Delete:
+			err = ti.rewriteDelete(query)
+		}
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (ti *tenantIsolation) rewriteSelect(sel *sqlparser.Select) error {
+	predicates, err := ti.fromPredicates(sel.From)
+	if err != nil {
+		return err
+	}
+	if len(predicates) > 0 {
+		sel.AddWhere(sqlparser.AndExpressions(predicates...))
+	}
+	return nil
+}
+
+func (ti *tenantIsolation) rewriteUpdate(upd *sqlparser.Update) error {
+	predicates, err := ti.fromPredicates(upd.TableExprs)
+	if err != nil {
+		return err
+	}
+	if len(predicates) == 0 {
+		return nil
+	}
+	for _, ue := range upd.Exprs {
+		if setsTenantColumn(ue.Name, predicates) {
+			return vterrors.Errorf(vtrpcpb.Code_PERMISSION_DENIED, "cannot change the tenant of the rows of a multi-tenant table: %s", sqlparser.String(ue))
+		}
+	}
+	upd.AddWhere(sqlparser.AndExpressions(predicates...))
+	return nil
+}
+
+func (ti *tenantIsolation) rewriteDelete(del *sqlparser.Delete) error {
+	predicates, err := ti.fromPredicates(del.TableExprs)
+	if err != nil {
+		return err
+	}
+	if len(predicates) > 0 {
+		del.AddWhere(sqlparser.AndExpressions(predicates...))
+	}
+	return nil
+}
+
+// rewriteInsert fills the tenant id of the inserted rows, or records the
+// explicit ones to check them when executing. The ON DUPLICATE KEY UPDATE
+// assignments keep the values of the existing rows of other tenants.
+func (ti *tenantIsolation) rewriteInsert(ins *sqlparser.Insert) error {
+	tableName, ok := ins.Table.Expr.(sqlparser.TableName)
+	if !ok {
+		return nil
+	}
+	col, ok, err := ti.tenantColumn(tableName)
+	if err != nil || !ok {
+		return err
+	}
+	if ins.Action == sqlparser.ReplaceAct {
+		// The existing rows are deleted before the tenant of the new ones is
+		// checked, whatever their tenant.
+		return vterrors.VT12001("REPLACE into a multi-tenant table")
+	}
+	if len(ins.Columns) == 0 {
+		return vterrors.VT12001("INSERT without a column list into a multi-tenant table")
+	}
+
+	for _, ue := range ins.OnDup {
+		if ue.Name.Name.Equal(col) {
+			return vterrors.Errorf(vtrpcpb.Code_PERMISSION_DENIED, "cannot change the tenant of the rows of a multi-tenant table: %s", sqlparser.String(ue))
+		}
+		// The tenant id of the existing row is never changed, so the
+		// condition holds for all the assignments of the row.
+		ue.Expr = sqlparser.NewFuncExpr("if",
+			&sqlparser.ComparisonExpr{
+				Operator: sqlparser.EqualOp,
+				Left:     sqlparser.NewColName(col.String()),
+				Right:    sqlparser.NewArgument(sqlparser.TenantIDName),
+			},
+			ue.Expr,
+			sqlparser.Clone(ue.Name),
+		)
+	}
+
+	idx := ins.Columns.FindColumn(col)
+	switch rows := ins.Rows.(type) {
+	case sqlparser.Values:
+		if idx >= 0 {
+			for _, row := range rows {
+				ti.values = append(ti.values, row[idx])
+			}
+			return nil
+		}
+		ins.Columns = append(ins.Columns, col)
+		for i := range rows {
+			rows[i] = append(rows[i], sqlparser.NewArgument(sqlparser.TenantIDName))
+		}
+	case *sqlparser.Select:
+		if idx >= 0 {
+			return vterrors.VT12001("INSERT ... SELECT with the tenant id column into a multi-tenant table")
+		}
+		ins.Columns = append(ins.Columns, col)
+		rows.AddSelectExpr(&sqlparser.AliasedExpr{Expr: sqlparser.NewArgument(sqlparser.TenantIDName)})
+	default:
+		return vterrors.VT12001("INSERT with a UNION or VALUES statement into a multi-tenant table")
+	}
+	return nil
+}
+
+// checkTenantIsolatedLoad rejects the LOAD DATA statements into multi-tenant
+// tables when vtgate enforces the tenant isolation, since the rows of the file
+// are neither filled with nor checked against the tenant of the session.
+func checkTenantIsolatedLoad(stmt *sqlparser.Load, vschema plancontext.VSchema) error {
+	if !vschema.IsTenantIsolationEnabled() {
+		return nil
+	}
+	ti := &tenantIsolation{vschema: vschema, ctes: map[string]bool{}}
+	_, found, err := ti.tenantColumn(stmt.Table)
+	if err != nil {
+		return err
+	}
+	if !found && !stmt.Local {
+		// The statement is sent as is to the selected keyspace, whatever the
+		// table, which may be unknown to the vschema.
+		if ks, err := vschema.SelectedKeyspace(); err == nil {
+			found = multiTenantSpec(vschema, ks.Name) != nil
+		}
+	}
+	if found {
+		return vterrors.VT12001("LOAD DATA into a multi-tenant table")
+	}
+	return nil
+}
+
+// fromPredicates returns the tenant predicates of the tables of a FROM
+// clause that belong in the WHERE clause. The predicates of the tables on
+// the inner side of outer joins are added to the ON condition instead.
+func (ti *tenantIsolation) fromPredicates(exprs []sqlparser.TableExpr) ([]sqlparser.Expr, error) {
+	var predicates []sqlparser.Expr
+	for _, expr := range exprs {
+		preds, err := ti.tablePredicates(expr)
+		if err != nil {
+			return nil, err
+		}
+		predicates = append(predicates, preds...)
+	}
+	return predicates, nil
+}
+
+func (ti *tenantIsolation) tablePredicates(expr sqlparser.TableExpr) ([]sqlparser.Expr, error) {
+	switch expr := expr.(type) {
+	case *sqlparser.AliasedTableExpr:
+		// Derived tables are restricted by the rewrite of their own query.
+		tableName, ok := expr.Expr.(sqlparser.TableName)
+		if !ok {
+			return nil, nil
+		}
+		col, ok, err := ti.tenantColumn(tableName)
+		if err != nil || !ok {
+			return nil, err
+		}
+		qualifier, err := expr.TableName()
+		if err != nil {
+			return nil, err
+		}
+		return []sqlparser.Expr{&sqlparser.ComparisonExpr{
+			Operator: sqlparser.EqualOp,
+			Left:     sqlparser.NewColNameWithQualifier(col.String(), qualifier),
+			Right:    sqlparser.NewArgument(sqlparser.TenantIDName),
+		}}, nil
+	case *sqlparser.ParenTableExpr:
+		return ti.fromPredicates(expr.Exprs)
+	case *sqlparser.JoinTableExpr:
+		left, err := ti.tablePredicates(expr.LeftExpr)
+		if err != nil {
+			return nil, err
+		}
+		right, err := ti.tablePredicates(expr.RightExpr)
+		if err != nil {
+			return nil, err
+		}
+		switch expr.Join {
+		case sqlparser.LeftJoinType, sqlparser.NaturalLeftJoinType:
+			return left, addToJoinCondition(expr, right)
+		case sqlparser.RightJoinType, sqlparser.NaturalRightJoinType:
+			return right, addToJoinCondition(expr, left)
+		default:
+			return append(left, right...), nil
+		}
+	}
+	return nil, nil
+}
+
+// addToJoinCondition adds the tenant predicates of the inner side of an
+// outer join to its ON condition.
+func addToJoinCondition(join *sqlparser.JoinTableExpr, predicates []sqlparser.Expr) error {
+	if len(predicates) == 0 {
+		return nil
+	}
+	if join.Condition == nil || join.Condition.On == nil {
+		return vterrors.VT12001("outer join without an ON condition on a multi-tenant table")
+	}
+	join.Condition.On = sqlparser.AndExpressions(append([]sqlparser.Expr{join.Condition.On}, predicates...)...)
+	return nil
+}
+
+// tenantColumn returns the tenant id column of the table, if it belongs to a
+// keyspace with a multi-tenant spec. Reference tables are shared by all the
+// tenants, and are not restricted.
+func (ti *tenantIsolation) tenantColumn(tableName sqlparser.TableName) (sqlparser.IdentifierCI, bool, error) {
+	if tableName.Qualifier.IsEmpty() && (ti.ctes[tableName.Name.String()] || tableName.Name.String() == "dual") {
+		return sqlparser.IdentifierCI{}, false, nil
+	}
+	table, vindex, _, _, _, err := ti.vschema.FindTableOrVindex(tableName)
+	if err == nil && table == nil && vindex != nil {
+		// The vindex functions only return the keyspace ids of their values.
+		return sqlparser.IdentifierCI{}, false, nil
+	}
+	if err != nil || table == nil || table.Keyspace == nil {
+		// The planner reports the unknown tables, unless they may belong to a
+		// multi-tenant keyspace, whose rows must never be read unrestricted.
+		ks := tableName.Qualifier.String()
+		if ks == "" {
+			if selected, err := ti.vschema.SelectedKeyspace(); err == nil {
+				ks = selected.Name
+			}
+		}
+		if multiTenantSpec(ti.vschema, ks) != nil {
+			return sqlparser.IdentifierCI{}, false, vterrors.Errorf(vtrpcpb.Code_PERMISSION_DENIED, "cannot restrict the table %s of the multi-tenant keyspace %s to the tenant of the session", sqlparser.String(tableName), ks)
+		}
+		return sqlparser.IdentifierCI{}, false, nil
+	}
+	if table.Type == vindexes.TypeReference || table.Type == vindexes.TypeSequence {
+		return sqlparser.IdentifierCI{}, false, nil
+	}
+	spec := multiTenantSpec(ti.vschema, table.Keyspace.Name)
+	if spec == nil {
+		return sqlparser.IdentifierCI{}, false, nil
+	}
+
+	colType := spec.TenantIdColumnType
+	switch colType {
+	case querypb.Type_INT64, querypb.Type_VARCHAR:
+	default:
+		return sqlparser.IdentifierCI{}, false, vterrors.VT12001(fmt.Sprintf("tenant id column of type %s", colType))
+	}
+	if ti.found && ti.colType != colType {
+		return sqlparser.IdentifierCI{}, false, vterrors.VT12001("multi-tenant tables with different tenant id column types in the same query")
+	}
+	ti.found = true
+	ti.colType = colType
+	return sqlparser.NewIdentifierCI(spec.TenantIdColumnName), true, nil
+}
+
+// multiTenantSpec returns the multi-tenant spec of the keyspace, or nil if it
+// does not have one.
+func multiTenantSpec(vschema plancontext.VSchema, keyspace string) *vschemapb.MultiTenantSpec {
+	ks := vschema.GetVSchema().Keyspaces[keyspace]
+	if ks == nil || ks.MultiTenantSpec == nil || ks.MultiTenantSpec.TenantIdColumnName == "" {
+		return nil
+	}
+	return ks.MultiTenantSpec
+}
+
+// setsTenantColumn returns true if the column is the tenant id column of one
+// of the tables with the given tenant predicates.
+func setsTenantColumn(col *sqlparser.ColName, predicates []sqlparser.Expr) bool {
+	for _, pred := range predicates {
+		tenantCol := pred.(*sqlparser.ComparisonExpr).Left.(*sqlparser.ColName)
+		if !col.Name.Equal(tenantCol.Name) {
+			continue
+		}
+		if col.Qualifier.IsEmpty() || col.Qualifier.Name == tenantCol.Qualifier.Name {
+			return true
+		}
+	}
+	return false
+}
@@ -17,9 +17,10 @@ limitations under the License.
 package planbuilder
 
 import (
+	"context"
+
 	querypb "vitess.io/vitess/go/vt/proto/query"
 	"vitess.io/vitess/go/vt/sqlparser"
-	"vitess.io/vitess/go/vt/vterrors"
 	"vitess.io/vitess/go/vt/vtgate/engine"
 	"vitess.io/vitess/go/vt/vtgate/planbuilder/operators"
 	"vitess.io/vitess/go/vt/vtgate/planbuilder/plancontext"
@@ -27,54 +28,51 @@ import (
 )
 
 func gen4UpdateStmtPlanner(
+	ctx context.Context,
 	version querypb.ExecuteOptions_PlannerVersion,
 	updStmt *sqlparser.Update,
 	reservedVars *sqlparser.ReservedVars,
 	vschema plancontext.VSchema,
 ) (*planResult, error) {
-	if updStmt.With != nil {
-		return nil, vterrors.VT12001("WITH expression in UPDATE statement")
-	}
-
-	ctx, err := plancontext.CreatePlanningContext(updStmt, reservedVars, vschema, version)
+	pCtx, err := plancontext.CreatePlanningContext(ctx, updStmt, reservedVars, vschema, version)
 	if err != nil {
 		return nil, err
 	}
 
-	err = queryRewrite(ctx, updStmt)
+	err = queryRewrite(pCtx, updStmt)
 	if err != nil {
 		return nil, err
 	}
 
 	// If there are non-literal foreign key updates, we have to run the query with foreign key checks off.
-	if ctx.SemTable.HasNonLiteralForeignKeyUpdate(updStmt.Exprs) {
+	if pCtx.SemTable.HasNonLiteralForeignKeyUpdate(updStmt.Exprs) {
 		// Since we are running the query with foreign key checks off, we have to verify all the foreign keys validity on vtgate.
-		ctx.VerifyAllFKs = true
+		pCtx.VerifyAllFKs = true
 	}
 
 	// Remove all the foreign keys that don't require any handling.
-	err = ctx.SemTable.RemoveNonRequiredForeignKeys(ctx.VerifyAllFKs, vindexes.UpdateAction)
+	err = pCtx.SemTable.RemoveNonRequiredForeignKeys(pCtx.VerifyAllFKs, vindexes.UpdateAction)
 	if err != nil {
 		return nil, err
 	}
-	if ks, tables := ctx.SemTable.SingleUnshardedKeyspace(); ks != nil {
-		if !ctx.SemTable.ForeignKeysPresent() {
+	if ks, tables := pCtx.SemTable.SingleUnshardedKeyspace(); ks != nil {
+		if !pCtx.SemTable.ForeignKeysPresent() {
 			plan := updateUnshardedShortcut(updStmt, ks, tables)
 			setCommentDirectivesOnPlan(plan, updStmt)
 			return newPlanResult(plan, operators.QualifiedTables(ks, tables)...), nil
 		}
 	}
 
-	if ctx.SemTable.NotUnshardedErr != nil {
-		return nil, ctx.SemTable.NotUnshardedErr
+	if pCtx.SemTable.NotUnshardedErr != nil {
+		return nil, pCtx.SemTable.NotUnshardedErr
 	}
 
-	op, err := operators.PlanQuery(ctx, updStmt)
+	op, err := operators.PlanQuery(pCtx, updStmt)
 	if err != nil {
 		return nil, err
 	}
 
-	plan, err := transformToPrimitive(ctx, op)
+	plan, err := transformToPrimitive(pCtx, op)
 	if err != nil {
 		return nil, err
 	}
@@ -48,7 +48,7 @@ func buildVExplainPlan(
 	case sqlparser.TraceVExplainType:
 		return buildVExplainTracePlan(ctx, vexplainStmt.Statement, reservedVars, vschema, cfg)
 	case sqlparser.KeysVExplainType:
-		return buildVExplainKeysPlan(vexplainStmt.Statement, vschema)
+		return buildVExplainKeysPlan(ctx, vexplainStmt.Statement, vschema)
 	}
 	return nil, vterrors.Errorf(vtrpcpb.Code_INTERNAL, "[BUG] unexpected vtexplain type: %s", vexplainStmt.Type.ToString())
 }
@@ -112,12 +112,12 @@ func getJsonResultPlan(v any, colName string) (*planResult, error) {
 	return newPlanResult(engine.NewRowsPrimitive(rows, fields)), nil
 }
 
-func buildVExplainKeysPlan(statement sqlparser.Statement, vschema plancontext.VSchema) (*planResult, error) {
-	ctx, err := plancontext.CreatePlanningContext(statement, sqlparser.NewReservedVars("", sqlparser.BindVars{}), vschema, querypb.ExecuteOptions_Gen4)
+func buildVExplainKeysPlan(ctx context.Context, statement sqlparser.Statement, vschema plancontext.VSchema) (*planResult, error) {
+	pCtx, err := plancontext.CreatePlanningContext(ctx, statement, sqlparser.NewReservedVars("", sqlparser.BindVars{}), vschema, querypb.ExecuteOptions_Gen4)
 	if err != nil {
 		return nil, err
 	}
-	result := operators.GetVExplainKeys(ctx, statement)
+	result := operators.GetVExplainKeys(pCtx, statement)
 	return getJsonResultPlan(result, "ColumnUsage")
 }
 
@@ -138,28 +138,28 @@ func buildVExplainLoggingPlan(ctx context.Context, explain *sqlparser.VExplainSt
 }
 
 // buildExplainStmtPlan takes an EXPLAIN query and if possible sends the whole query to a single shard
-func buildExplainStmtPlan(stmt sqlparser.Statement, reservedVars *sqlparser.ReservedVars, vschema plancontext.VSchema) (*planResult, error) {
+func buildExplainStmtPlan(ctx context.Context, stmt sqlparser.Statement, reservedVars *sqlparser.ReservedVars, vschema plancontext.VSchema) (*planResult, error) {
 	explain := stmt.(*sqlparser.ExplainStmt)
 	switch explain.Statement.(type) {
 	case sqlparser.SelectStatement, *sqlparser.Update, *sqlparser.Delete, *sqlparser.Insert:
-		return explainPlan(explain, reservedVars, vschema)
+		return explainPlan(ctx, explain, reservedVars, vschema)
 	default:
 		return buildOtherReadAndAdmin(sqlparser.String(explain), vschema)
 	}
 }
 
-func explainPlan(explain *sqlparser.ExplainStmt, reservedVars *sqlparser.ReservedVars, vschema plancontext.VSchema) (*planResult, error) {
-	ctx, err := plancontext.CreatePlanningContext(explain.Statement, reservedVars, vschema, Gen4)
+func explainPlan(ctx context.Context, explain *sqlparser.ExplainStmt, reservedVars *sqlparser.ReservedVars, vschema plancontext.VSchema) (*planResult, error) {
+	pCtx, err := plancontext.CreatePlanningContext(ctx, explain.Statement, reservedVars, vschema, Gen4)
 	if err != nil {
 		return nil, err
 	}
 
-	ks := ctx.SemTable.SingleKeyspace()
+	ks := pCtx.SemTable.SingleKeyspace()
 	if ks == nil {
 		return nil, vterrors.VT03031()
 	}
 
-	if err = queryRewrite(ctx, explain.Statement); err != nil {
+	if err = queryRewrite(pCtx, explain.Statement); err != nil {
 		return nil, err
 	}
 
@@ -167,7 +167,7 @@ func explainPlan(explain *sqlparser.ExplainStmt, reservedVars *sqlparser.Reserve
 	sqlparser.RemoveKeyspace(explain.Statement)
 
 	var tables []string
-	for _, table := range ctx.SemTable.Tables {
+	for _, table := range pCtx.SemTable.Tables {
 		name, err := table.Name()
 		if err != nil {
 			// this is just for reporting which tables we are touching
@@ -48,6 +48,7 @@ import (
 	"vitess.io/vitess/go/vt/utils"
 	"vitess.io/vitess/go/vt/vtenv"
 	"vitess.io/vitess/go/vt/vterrors"
+	econtext "vitess.io/vitess/go/vt/vtgate/executorcontext"
 	"vitess.io/vitess/go/vt/vttls"
 )
 
@@ -79,6 +80,12 @@ var (
 	mysqlDrainOnTerm         bool
 
 	mysqlServerFlushDelay = 100 * time.Millisecond
+
+	mysqlServerCompressionAlgorithms = []string{mysql.CompressionZlib, mysql.CompressionZstd}
+
+	mysqlServerMaxOpenCursors = 10
+
+	mysqlServerAllowLocalInfile bool
 )
 
 func registerPluginFlags(fs *pflag.FlagSet) {
@@ -103,6 +110,9 @@ func registerPluginFlags(fs *pflag.FlagSet) {
 	fs.BoolVar(&mysqlConnBufferPooling, "mysql-server-pool-conn-read-buffers", mysqlConnBufferPooling, "If set, the server will pool incoming connection read buffers")
 	fs.DurationVar(&mysqlKeepAlivePeriod, "mysql-server-keepalive-period", mysqlKeepAlivePeriod, "TCP period between keep-alives")
 	utils.SetFlagDurationVar(fs, &mysqlServerFlushDelay, "mysql-server-flush-delay", mysqlServerFlushDelay, "Delay after which buffered response will be flushed to the client.")
+	fs.StringSliceVar(&mysqlServerCompressionAlgorithms, "mysql-server-compression-algorithms", mysqlServerCompressionAlgorithms, "Algorithms of the compressed protocol that clients can ask for on the TCP listener. Options: zlib, zstd. Empty disables compression.")
+	fs.IntVar(&mysqlServerMaxOpenCursors, "mysql-server-max-open-cursors", mysqlServerMaxOpenCursors, "Maximum number of cursors opened by COM_STMT_EXECUTE that a connection can have at the same time. Zero means no limit.")
+	fs.BoolVar(&mysqlServerAllowLocalInfile, "mysql-server-allow-local-infile", mysqlServerAllowLocalInfile, "Allow the clients to send files with LOAD DATA LOCAL INFILE.")
 	utils.SetFlagStringVar(fs, &mysqlDefaultWorkloadName, "mysql-default-workload", mysqlDefaultWorkloadName, "Default session workload (OLTP, OLAP, DBA)")
 	fs.BoolVar(&mysqlDrainOnTerm, "mysql-server-drain-onterm", mysqlDrainOnTerm, "If set, the server waits for --onterm-timeout for already connected clients to complete their in flight work")
 }
@@ -229,6 +239,7 @@ func (vh *vtgateHandler) ComQuery(c *mysql.Conn, query string, callback func(*sq
 	defer span.Finish()
 
 	ctx = callinfo.MysqlCallInfo(ctx, c)
+	ctx = econtext.WithLocalInfileReader(ctx, c.ReadLocalInfile)
 
 	// Fill in the ImmediateCallerID with the UserData returned by
 	// the AuthServer plugin for that user. If nothing was
@@ -286,6 +297,7 @@ func (vh *vtgateHandler) ComQueryMulti(c *mysql.Conn, sql string, callback func(
 	defer span.Finish()
 
 	ctx = callinfo.MysqlCallInfo(ctx, c)
+	ctx = econtext.WithLocalInfileReader(ctx, c.ReadLocalInfile)
 
 	// Fill in the ImmediateCallerID with the UserData returned by
 	// the AuthServer plugin for that user. If nothing was
@@ -408,8 +420,24 @@ func (vh *vtgateHandler) ComPrepare(c *mysql.Conn, query string) ([]*querypb.Fie
 }
 
 func (vh *vtgateHandler) ComStmtExecute(c *mysql.Conn, prepare *mysql.PrepareData, callback func(*sqltypes.Result) error) error {
+	session := vh.session(c)
+
+	// The execution of a cursor is suspended between the COM_STMT_FETCH
+	// commands, while the connection runs its other commands. Outside of a
+	// transaction or a reserved connection, the execution streams its rows on
+	// a copy of the session, and is only canceled when the cursor is closed.
+	// Otherwise, it could not share the connections of the session with the
+	// other commands, so its result is read before the cursor is opened.
+	cursor := prepare.CursorType&mysql.CursorTypeReadOnly != 0
+	streamCursor := cursor && session.Autocommit && !session.InTransaction && !session.InReservedConn
+
 	ctx, cancel := context.WithCancel(context.Background())
-	c.UpdateCancelCtx(cancel)
+	if streamCursor {
+		defer cancel()
+		session = session.CloneVT()
+	} else {
+		c.UpdateCancelCtx(cancel)
+	}
 
 	if mysqlQueryTimeout != 0 {
 		ctx, cancel = context.WithTimeout(ctx, mysqlQueryTimeout)
@@ -430,18 +458,38 @@ func (vh *vtgateHandler) ComStmtExecute(c *mysql.Conn, prepare *mysql.PrepareDat
 		"VTGate MySQL Connector" /* subcomponent: part of the client */)
 	ctx = callerid.NewContext(ctx, ef, im)
 
-	session := vh.session(c)
-	if !session.InTransaction {
+	// The session must not be read once the result of a cursor is returned,
+	// as the connection may then run other commands.
+	inTransaction := session.InTransaction
+	if !inTransaction {
 		vh.busyConnections.Add(1)
 	}
+	released := false
 	defer func() {
-		if !session.InTransaction {
+		if !released && !inTransaction {
 			vh.busyConnections.Add(-1)
 		}
 	}()
 
-	if session.Options.Workload == querypb.ExecuteOptions_OLAP {
+	// The connection is idle while the cursor is open, the execution only
+	// resumes on COM_STMT_FETCH. It is no longer busy once the first result
+	// is returned, as for a query that returned.
+	if cursor {
+		fetch := callback
+		callback = func(qr *sqltypes.Result) error {
+			if !released {
+				released = true
+				if !inTransaction {
+					vh.busyConnections.Add(-1)
+				}
+			}
+			return fetch(qr)
+		}
+	}
+
+	if streamCursor || (session.Options.Workload == querypb.ExecuteOptions_OLAP && !cursor) {
 		_, err := vh.vtg.StreamExecute(ctx, vh, session, prepare.PrepareStmt, prepare.BindVars, callback)
+		inTransaction = session.InTransaction
 		if err != nil {
 			return sqlerror.NewSQLErrorFromError(err)
 		}
@@ -449,6 +497,7 @@ func (vh *vtgateHandler) ComStmtExecute(c *mysql.Conn, prepare *mysql.PrepareDat
 		return nil
 	}
 	_, qr, err := vh.vtg.Execute(ctx, vh, session, prepare.PrepareStmt, prepare.BindVars, true)
+	inTransaction = session.InTransaction
 	if err != nil {
 		return sqlerror.NewSQLErrorFromError(err)
 	}
@@ -605,6 +654,12 @@ func initMySQLProtocol(vtgate *VTGate) *mysqlServer {
 		log.Exitf("-mysql-tcp-version must be one of [tcp, tcp4, tcp6]")
 	}
 
+	for _, algorithm := range mysqlServerCompressionAlgorithms {
+		if !mysql.IsValidCompressionAlgorithm(algorithm) {
+			log.Exitf("-mysql-server-compression-algorithms must only contain [zlib, zstd], got %s", algorithm)
+		}
+	}
+
 	// Create a Listener.
 	var err error
 	srv := &mysqlServer{}
@@ -634,6 +689,9 @@ func initMySQLProtocol(vtgate *VTGate) *mysqlServer {
 			_ = initTLSConfig(context.Background(), srv, mysqlSslCert, mysqlSslKey, mysqlSslCa, mysqlSslCrl, mysqlSslServerCA, mysqlServerRequireSecureTransport, tlsVersion)
 		}
 		srv.tcpListener.AllowClearTextWithoutTLS.Store(mysqlAllowClearTextWithoutTLS)
+		srv.tcpListener.CompressionAlgorithms = mysqlServerCompressionAlgorithms
+		srv.tcpListener.MaxOpenCursors = mysqlServerMaxOpenCursors
+		srv.tcpListener.AllowLocalInfile = mysqlServerAllowLocalInfile
 		// Check for the connection threshold
 		if mysqlSlowConnectWarnThreshold != 0 {
 			log.Infof("setting mysql slow connection threshold to %v", mysqlSlowConnectWarnThreshold)
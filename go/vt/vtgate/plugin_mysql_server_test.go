@@ -904,3 +904,102 @@ func TestGracefulShutdownWithTransaction(t *testing.T) {
 
 	require.True(t, mysqlConn.IsMarkedForClose())
 }
+
+func TestComStmtExecuteCursor(t *testing.T) {
+	executor, sbc1, _, _, _ := createExecutorEnv(t)
+
+	vh := newVtgateHandler(&VTGate{executor: executor, timings: timings, rowsReturned: rowsReturned, rowsAffected: rowsAffected, queryTextCharsProcessed: queryTextCharsProcessed})
+	th := &testHandler{}
+	listener, err := mysql.NewListener("tcp", "127.0.0.1:", mysql.NewAuthServerNone(), th, 0, 0, false, false, 0, 0)
+	require.NoError(t, err)
+	defer listener.Close()
+
+	mysqlConn := mysql.GetTestServerConn(listener)
+	mysqlConn.ConnectionID = 1
+	mysqlConn.UserData = &mysql.StaticUserData{}
+	vh.connections[1] = mysqlConn
+
+	result := sqltypes.MakeTestResult(sqltypes.MakeTestFields("id", "int64"), "1", "2")
+	prepare := &mysql.PrepareData{
+		PrepareStmt: "select id from user where id = 1",
+		BindVars:    map[string]*querypb.BindVariable{},
+		CursorType:  mysql.CursorTypeReadOnly,
+	}
+	comQuery := func(query string) {
+		t.Helper()
+		err := vh.ComQuery(mysqlConn, query, func(*sqltypes.Result) error { return nil })
+		require.NoError(t, err)
+	}
+
+	// openCursor executes the statement like the cursor of the connection
+	// does: the execution is suspended after each result, until the next
+	// fetch. The results are returned once the execution is done.
+	openCursor := func(fetch func()) []*sqltypes.Result {
+		t.Helper()
+		results := make(chan *sqltypes.Result)
+		resume := make(chan struct{})
+		done := make(chan error, 1)
+		go func() {
+			done <- vh.ComStmtExecute(mysqlConn, prepare, func(qr *sqltypes.Result) error {
+				results <- qr
+				<-resume
+				return nil
+			})
+		}()
+		var got []*sqltypes.Result
+		for {
+			select {
+			case qr := <-results:
+				got = append(got, qr)
+				fetch()
+				resume <- struct{}{}
+			case err := <-done:
+				require.NoError(t, err)
+				return got
+			}
+		}
+	}
+
+	t.Run("transaction", func(t *testing.T) {
+		comQuery("begin")
+		comQuery("select id from user where id = 1")
+		sbc1.SetResults([]*sqltypes.Result{result})
+		queries := len(sbc1.Queries)
+
+		// The result is read before the cursor is opened, so that the
+		// other queries of the transaction run between the fetches.
+		got := openCursor(func() {
+			comQuery("select id from user where id = 1")
+			assert.True(t, vh.session(mysqlConn).InTransaction)
+		})
+		require.Len(t, got, 1)
+		utils.MustMatch(t, result.Rows, got[0].Rows)
+		assert.Len(t, sbc1.Queries, queries+2)
+
+		comQuery("commit")
+		assert.EqualValues(t, 1, sbc1.CommitCount.Load())
+		assert.False(t, vh.session(mysqlConn).InTransaction)
+	})
+
+	t.Run("autocommit", func(t *testing.T) {
+		session := vh.session(mysqlConn)
+		sbc1.SetResults([]*sqltypes.Result{result})
+
+		// The execution streams its rows on a copy of the session, the
+		// session of the connection is only used by the other commands.
+		fetches := 0
+		got := openCursor(func() {
+			fetches++
+			// The connection is not busy while the cursor is open.
+			assert.Zero(t, vh.busyConnections.Load())
+			comQuery("begin")
+			comQuery("rollback")
+		})
+		assert.Greater(t, fetches, 1)
+		assert.Equal(t, fetches, len(got))
+		assert.Same(t, session, vh.session(mysqlConn))
+		assert.False(t, session.InTransaction)
+		assert.Empty(t, session.ShardSessions)
+		assert.Zero(t, vh.busyConnections.Load())
+	})
+}
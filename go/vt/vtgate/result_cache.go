@@ -0,0 +1,403 @@
+/*
+Copyright 2025 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package vtgate
+
+import (
+	"context"
+	"encoding/binary"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"vitess.io/vitess/go/cache/theine"
+	"vitess.io/vitess/go/sqltypes"
+	"vitess.io/vitess/go/stats"
+	"vitess.io/vitess/go/vt/log"
+	binlogdatapb "vitess.io/vitess/go/vt/proto/binlogdata"
+	querypb "vitess.io/vitess/go/vt/proto/query"
+	topodatapb "vitess.io/vitess/go/vt/proto/topodata"
+	vtgatepb "vitess.io/vitess/go/vt/proto/vtgate"
+	"vitess.io/vitess/go/vt/sqlparser"
+	"vitess.io/vitess/go/vt/vtgate/engine"
+	"vitess.io/vitess/go/vt/vtgate/vindexes"
+	"vitess.io/vitess/go/vt/vthash"
+)
+
+/*
+
+The resultCache keeps the results of read-only queries to replica and rdonly
+tablets in memory, so that repeated queries, e.g. from dashboards, are served
+by vtgate without going to the tablets.
+
+Caching is opt-in per query: a plan is cached for the TTL given by the
+CACHE_TTL_MS directive, or else for the smallest result_cache_ttl_ms of the
+tables it reads in the vschema, if all of them have one. Results are keyed on
+the plan key of the query and its bind variables, and bounded by memory.
+
+Cached results expire after their TTL. When invalidation is enabled, the
+resultCache also streams the row changes of the keyspaces it has results for,
+and drops the results of the tables that changed. Each table has a generation,
+which is incremented on every change of the table. A result records the sum
+of the generations of its tables before the query was executed, and is stale
+as soon as that sum changes. Invalidation is best effort: changes made while
+a stream is (re)connecting are missed, and the TTL always bounds staleness.
+
+*/
+
+var (
+	resultCacheHits          = stats.NewCounter("ResultCacheHits", "Result cache hits")
+	resultCacheMisses        = stats.NewCounter("ResultCacheMisses", "Result cache misses")
+	resultCacheInvalidations = stats.NewCountersWithSingleLabel("ResultCacheInvalidations", "Result cache invalidations per keyspace", "Keyspace")
+)
+
+// resultCacheStreamer streams the changes of a keyspace to invalidate the
+// result cache. It is implemented by the vstreamManager.
+type resultCacheStreamer interface {
+	VStream(ctx context.Context, tabletType topodatapb.TabletType, vgtid *binlogdatapb.VGtid,
+		filter *binlogdatapb.Filter, flags *vtgatepb.VStreamFlags, send func(events []*binlogdatapb.VEvent) error) error
+}
+
+type resultCache struct {
+	store *theine.Store[theine.HashKey256, *cachedResult]
+
+	// mu protects the fields below
+	mu sync.RWMutex
+	// tableGenerations are the generations of the tables, by keyspace.table
+	tableGenerations map[string]uint64
+	// keyspaceGenerations are incremented for changes to all the tables of a
+	// keyspace, like DDLs or a failed invalidation stream
+	keyspaceGenerations map[string]uint64
+	// streamer is nil unless invalidation is enabled
+	streamer resultCacheStreamer
+	ctx      context.Context
+	cancel   context.CancelFunc
+	streams  map[string]bool
+	// retryDelay is the time to wait before restarting a stream that ended
+	retryDelay time.Duration
+}
+
+// cachedResult is a result in the resultCache.
+type cachedResult struct {
+	result     *sqltypes.Result
+	expires    time.Time
+	tables     []string
+	generation uint64
+}
+
+// CachedSize implements the cacheval interface of the theine.Store.
+func (cr *cachedResult) CachedSize(alloc bool) int64 {
+	size := cr.result.CachedSize(true)
+	if alloc {
+		size += int64(64)
+	}
+	return size
+}
+
+func newResultCache(memory int64) *resultCache {
+	// Unlike the plan cache, results are admitted on their first execution:
+	// the queries are only cached when they opt in.
+	return &resultCache{
+		store:               theine.NewStore[theine.HashKey256, *cachedResult](memory, false),
+		tableGenerations:    map[string]uint64{},
+		keyspaceGenerations: map[string]uint64{},
+		retryDelay:          5 * time.Second,
+	}
+}
+
+// enableInvalidation makes the resultCache stream the changes of the
+// keyspaces it caches results for, until ctx is done or the cache is closed.
+func (rc *resultCache) enableInvalidation(ctx context.Context, streamer resultCacheStreamer) {
+	rc.mu.Lock()
+	defer rc.mu.Unlock()
+	rc.streamer = streamer
+	rc.ctx, rc.cancel = context.WithCancel(ctx)
+	rc.streams = map[string]bool{}
+}
+
+// execute returns the cached result of the query with the given key, or
+// executes it and caches its result for the TTL of the plan.
+func (rc *resultCache) execute(key theine.HashKey256, plan *engine.Plan, exec func() (*sqltypes.Result, error)) (*sqltypes.Result, error) {
+	if cached, ok := rc.store.Get(key, 0); ok {
+		if time.Now().Before(cached.expires) && rc.generation(cached.tables) == cached.generation {
+			resultCacheHits.Add(1)
+			return cached.result.ShallowCopy(), nil
+		}
+		rc.store.Delete(key)
+	}
+	resultCacheMisses.Add(1)
+
+	// The generation must be read before executing the query, so that a
+	// change made while the query runs invalidates its result.
+	rc.watch(plan.TablesUsed)
+	generation := rc.generation(plan.TablesUsed)
+	qr, err := exec()
+	if err != nil {
+		return nil, err
+	}
+	rc.store.Set(key, &cachedResult{
+		result:     qr.Copy(),
+		expires:    time.Now().Add(plan.ResultCacheTTL),
+		tables:     plan.TablesUsed,
+		generation: generation,
+	}, 0, 0)
+	return qr, nil
+}
+
+// generation returns the sum of the generations of the given tables.
+func (rc *resultCache) generation(tables []string) uint64 {
+	rc.mu.RLock()
+	defer rc.mu.RUnlock()
+	var generation uint64
+	for _, table := range tables {
+		keyspace, _, _ := strings.Cut(table, ".")
+		generation += rc.tableGenerations[table] + rc.keyspaceGenerations[keyspace]
+	}
+	return generation
+}
+
+// invalidate drops the cached results of the given tables of the keyspace, or
+// of all its tables if none is given.
+func (rc *resultCache) invalidate(keyspace string, tables ...string) {
+	rc.mu.Lock()
+	defer rc.mu.Unlock()
+	if len(tables) == 0 {
+		rc.keyspaceGenerations[keyspace]++
+	}
+	for _, table := range tables {
+		rc.tableGenerations[keyspace+"."+table]++
+	}
+	resultCacheInvalidations.Add(keyspace, 1)
+}
+
+// watch starts the invalidation streams of the keyspaces of the given tables,
+// unless they are running or invalidation is disabled.
+func (rc *resultCache) watch(tables []string) {
+	// Most calls find nothing to start, which only needs the read lock.
+	rc.mu.RLock()
+	started := rc.streamer == nil || slices.IndexFunc(tables, func(table string) bool {
+		keyspace, _, _ := strings.Cut(table, ".")
+		return !rc.streams[keyspace]
+	}) < 0
+	rc.mu.RUnlock()
+	if started {
+		return
+	}
+
+	rc.mu.Lock()
+	defer rc.mu.Unlock()
+	for _, table := range tables {
+		keyspace, _, _ := strings.Cut(table, ".")
+		if rc.streams[keyspace] {
+			continue
+		}
+		rc.streams[keyspace] = true
+		go rc.stream(keyspace)
+	}
+}
+
+// stream invalidates the results of the tables of the keyspace as their rows
+// change, restarting the stream until the cache is closed.
+func (rc *resultCache) stream(keyspace string) {
+	vgtid := &binlogdatapb.VGtid{ShardGtids: []*binlogdatapb.ShardGtid{{
+		Keyspace: keyspace,
+		Gtid:     "current",
+	}}}
+	flags := &vtgatepb.VStreamFlags{ExcludeKeyspaceFromTableName: true}
+	for {
+		err := rc.streamer.VStream(rc.ctx, topodatapb.TabletType_REPLICA, vgtid, nil, flags, func(events []*binlogdatapb.VEvent) error {
+			var tables []string
+			for _, event := range events {
+				switch event.Type {
+				case binlogdatapb.VEventType_ROW:
+					tables = append(tables, event.RowEvent.TableName)
+				case binlogdatapb.VEventType_DDL:
+					rc.invalidate(keyspace)
+				}
+			}
+			if len(tables) > 0 {
+				rc.invalidate(keyspace, tables...)
+			}
+			return nil
+		})
+
+		// The changes made until the stream is restarted are lost.
+		rc.invalidate(keyspace)
+		if rc.ctx.Err() != nil {
+			return
+		}
+		log.Warningf("result cache invalidation stream for keyspace %s ended, restarting in %v: %v", keyspace, rc.retryDelay, err)
+		select {
+		case <-rc.ctx.Done():
+			return
+		case <-time.After(rc.retryDelay):
+		}
+	}
+}
+
+func (rc *resultCache) close() {
+	rc.mu.Lock()
+	defer rc.mu.Unlock()
+	if rc.cancel != nil {
+		rc.cancel()
+	}
+	rc.store.Close()
+}
+
+// resultCacheTTL returns how long the results of the plan can be cached: the
+// TTL of the CACHE_TTL_MS directive, or else the smallest TTL of the tables
+// used by the plan, if they all have one.
+func resultCacheTTL(stmt sqlparser.Statement, qh sqlparser.QueryHints, plan *engine.Plan, vschema *vindexes.VSchema) time.Duration {
+	if plan.QueryType != sqlparser.StmtSelect || !isCacheableSelect(stmt) {
+		return 0
+	}
+	if qh.CacheTTL != nil {
+		return time.Duration(*qh.CacheTTL) * time.Millisecond
+	}
+	if len(plan.TablesUsed) == 0 || vschema == nil {
+		return 0
+	}
+
+	var ttl time.Duration
+	for _, name := range plan.TablesUsed {
+		keyspace, table, _ := strings.Cut(name, ".")
+		ks, ok := vschema.Keyspaces[keyspace]
+		if !ok {
+			return 0
+		}
+		tbl, ok := ks.Tables[table]
+		if !ok || tbl.ResultCacheTTL <= 0 {
+			return 0
+		}
+		if ttl == 0 || tbl.ResultCacheTTL < ttl {
+			ttl = tbl.ResultCacheTTL
+		}
+	}
+	return ttl
+}
+
+// nonDeterministicFuncs are the functions whose results depend on more than
+// their arguments: the time, a random generator, or the session, or that have
+// side effects, like waiting.
+var nonDeterministicFuncs = map[string]bool{
+	"benchmark":      true,
+	"connection_id":  true,
+	"curdate":        true,
+	"current_date":   true,
+	"current_role":   true,
+	"current_user":   true,
+	"found_rows":     true,
+	"last_insert_id": true,
+	"rand":           true,
+	"random_bytes":   true,
+	"row_count":      true,
+	"session_user":   true,
+	"sleep":          true,
+	"system_user":    true,
+	"unix_timestamp": true,
+	"user":           true,
+	"utc_date":       true,
+	"uuid":           true,
+	"uuid_short":     true,
+}
+
+// isCacheableSelect returns false for the selects whose results depend on
+// more than their query and bind variables, or that have side effects.
+func isCacheableSelect(stmt sqlparser.Statement) bool {
+	if _, ok := stmt.(sqlparser.SelectStatement); !ok {
+		return false
+	}
+	cacheable := true
+	_ = sqlparser.Walk(func(node sqlparser.SQLNode) (bool, error) {
+		switch node := node.(type) {
+		case *sqlparser.Select:
+			if node.Lock != sqlparser.NoLock || node.Into != nil {
+				cacheable = false
+			}
+		case *sqlparser.Union:
+			if node.Lock != sqlparser.NoLock || node.Into != nil {
+				cacheable = false
+			}
+		case *sqlparser.Nextval, *sqlparser.LockingFunc, *sqlparser.CurTimeFuncExpr, *sqlparser.Variable:
+			cacheable = false
+		case *sqlparser.FuncExpr:
+			if nonDeterministicFuncs[node.Name.Lowered()] {
+				cacheable = false
+			}
+		case *sqlparser.Argument:
+			if isSessionArgument(node.Name) {
+				cacheable = false
+			}
+		}
+		return cacheable, nil
+	}, stmt)
+	return cacheable
+}
+
+// isSessionArgument returns true for the bind variables that the normalizer
+// substitutes for the session dependent functions and variables, like
+// LAST_INSERT_ID(), @@sysvars and @uservars. The tenant and the keyspace of
+// the session are part of the cache key.
+func isSessionArgument(name string) bool {
+	switch name {
+	case sqlparser.LastInsertIDName:
+		return true
+	case sqlparser.TenantIDName, sqlparser.DBVarName:
+		return false
+	}
+	return strings.HasPrefix(name, "__vt")
+}
+
+// resultCacheKey returns the key of the result of the query with the given
+// plan key and bind variables. The tenant and the caller are part of the key,
+// so that the cached results are only served to the sessions that are allowed
+// to read them.
+func resultCacheKey(planKey engine.PlanKey, tenant, caller string, bindVars map[string]*querypb.BindVariable) theine.HashKey256 {
+	hasher := vthash.New256()
+	hash := planKey.Hash()
+	_, _ = hasher.Write(hash[:])
+	writeString := func(s string) {
+		_, _ = hasher.Write(binary.AppendUvarint(nil, uint64(len(s))))
+		_, _ = hasher.WriteString(s)
+	}
+	writeValue := func(typ querypb.Type, value []byte) {
+		_, _ = hasher.Write(binary.AppendUvarint(nil, uint64(typ)))
+		_, _ = hasher.Write(binary.AppendUvarint(nil, uint64(len(value))))
+		_, _ = hasher.Write(value)
+	}
+
+	writeString(tenant)
+	writeString(caller)
+	names := make([]string, 0, len(bindVars))
+	for name := range bindVars {
+		names = append(names, name)
+	}
+	slices.Sort(names)
+	for _, name := range names {
+		bv := bindVars[name]
+		writeString(name)
+		writeValue(bv.Type, bv.Value)
+		_, _ = hasher.Write(binary.AppendUvarint(nil, uint64(len(bv.Values))))
+		for _, v := range bv.Values {
+			writeValue(v.Type, v.Value)
+		}
+	}
+
+	var key theine.HashKey256
+	hasher.Sum(key[:0])
+	return key
+}
@@ -0,0 +1,409 @@
+/*
+Copyright 2025 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package vtgate
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"vitess.io/vitess/go/sqltypes"
+	"vitess.io/vitess/go/test/utils"
+	binlogdatapb "vitess.io/vitess/go/vt/proto/binlogdata"
+	querypb "vitess.io/vitess/go/vt/proto/query"
+	topodatapb "vitess.io/vitess/go/vt/proto/topodata"
+	vschemapb "vitess.io/vitess/go/vt/proto/vschema"
+	vtgatepb "vitess.io/vitess/go/vt/proto/vtgate"
+	"vitess.io/vitess/go/vt/sqlparser"
+	"vitess.io/vitess/go/vt/vtgate/engine"
+	econtext "vitess.io/vitess/go/vt/vtgate/executorcontext"
+	"vitess.io/vitess/go/vt/vtgate/vindexes"
+)
+
+func TestResultCacheTTL(t *testing.T) {
+	vschema := vindexes.BuildVSchema(&vschemapb.SrvVSchema{
+		Keyspaces: map[string]*vschemapb.Keyspace{
+			"ks": {
+				Tables: map[string]*vschemapb.Table{
+					"t1": {ResultCacheTtlMs: 1000},
+					"t2": {ResultCacheTtlMs: 500},
+					"t3": {},
+				},
+			},
+		},
+	}, sqlparser.NewTestParser())
+
+	tcases := []struct {
+		query  string
+		tables []string
+		ttl    time.Duration
+	}{{
+		query: "select /*vt+ CACHE_TTL_MS=200 */ * from t3",
+		ttl:   200 * time.Millisecond,
+	}, {
+		query:  "select /*vt+ CACHE_TTL_MS=0 */ * from t1",
+		tables: []string{"ks.t1"},
+	}, {
+		query:  "select * from t1",
+		tables: []string{"ks.t1"},
+		ttl:    time.Second,
+	}, {
+		query:  "select * from t1 join t2",
+		tables: []string{"ks.t1", "ks.t2"},
+		ttl:    500 * time.Millisecond,
+	}, {
+		query:  "select * from t1 union select * from t2",
+		tables: []string{"ks.t1", "ks.t2"},
+		ttl:    500 * time.Millisecond,
+	}, {
+		query:  "select * from t1 join t3",
+		tables: []string{"ks.t1", "ks.t3"},
+	}, {
+		query:  "select * from t1 join unknown",
+		tables: []string{"ks.t1", "ks.unknown"},
+	}, {
+		query: "select 1 from dual",
+	}, {
+		query:  "select /*vt+ CACHE_TTL_MS=200 */ * from t1 for update",
+		tables: []string{"ks.t1"},
+	}, {
+		query:  "select * from t1 lock in share mode",
+		tables: []string{"ks.t1"},
+	}, {
+		query:  "select * from t1 where id = (select id from t2 for update)",
+		tables: []string{"ks.t1", "ks.t2"},
+	}, {
+		query:  "select /*vt+ CACHE_TTL_MS=200 */ get_lock('a', 1) from t1",
+		tables: []string{"ks.t1"},
+	}, {
+		query:  "select * from t1 into outfile 'x'",
+		tables: []string{"ks.t1"},
+	}, {
+		query:  "update /*vt+ CACHE_TTL_MS=200 */ t1 set a = 1",
+		tables: []string{"ks.t1"},
+	}}
+	parser := sqlparser.NewTestParser()
+	for _, tcase := range tcases {
+		t.Run(tcase.query, func(t *testing.T) {
+			stmt, err := parser.Parse(tcase.query)
+			require.NoError(t, err)
+			qh, err := sqlparser.BuildQueryHints(stmt)
+			require.NoError(t, err)
+			plan := &engine.Plan{
+				QueryType:  sqlparser.ASTToStatementType(stmt),
+				TablesUsed: tcase.tables,
+			}
+			assert.Equal(t, tcase.ttl, resultCacheTTL(stmt, qh, plan, vschema))
+		})
+	}
+}
+
+func TestIsCacheableSelect(t *testing.T) {
+	tcases := []struct {
+		query     string
+		cacheable bool
+	}{{
+		query:     "select a from t1 where b = :b",
+		cacheable: true,
+	}, {
+		query:     "select a, database() from t1",
+		cacheable: true,
+	}, {
+		query:     "select a from t1 where tenant_id = :__vttenant_id and db = :__vtdbname",
+		cacheable: true,
+	}, {
+		query: "select now() from t1",
+	}, {
+		query: "select a from t1 where b > current_timestamp(3)",
+	}, {
+		query: "select a from t1 where b = curdate()",
+	}, {
+		query: "select rand() from t1",
+	}, {
+		query: "select a from t1 order by rand()",
+	}, {
+		query: "select uuid() from t1",
+	}, {
+		query: "select connection_id() from t1",
+	}, {
+		query: "select last_insert_id() from t1",
+	}, {
+		query: "select a from t1 where id = :__lastInsertId",
+	}, {
+		query: "select user() from t1",
+	}, {
+		query: "select a from t1 where owner = current_user()",
+	}, {
+		query: "select session_user() from t1",
+	}, {
+		query: "select system_user() from t1",
+	}, {
+		query: "select current_role() from t1",
+	}, {
+		query: "select sleep(1) from t1",
+	}, {
+		query: "select benchmark(10, md5('a')) from t1",
+	}, {
+		query: "select random_bytes(16) from t1",
+	}, {
+		query: "select @@sql_mode from t1",
+	}, {
+		query: "select a from t1 where b = :__vtautocommit",
+	}, {
+		query: "select @x from t1",
+	}, {
+		query: "select a from t1 where b = :__vtudvx",
+	}, {
+		query: "select a from t1 where id in (select id from t2 where b = @x)",
+	}, {
+		query: "select a from t1 union select uuid() from t2",
+	}}
+	parser := sqlparser.NewTestParser()
+	for _, tcase := range tcases {
+		t.Run(tcase.query, func(t *testing.T) {
+			stmt, err := parser.Parse(tcase.query)
+			require.NoError(t, err)
+			assert.Equal(t, tcase.cacheable, isCacheableSelect(stmt))
+		})
+	}
+}
+
+func TestResultCacheExecute(t *testing.T) {
+	rc := newResultCache(1024 * 1024)
+	defer rc.close()
+
+	plan := &engine.Plan{
+		ResultCacheTTL: time.Minute,
+		TablesUsed:     []string{"ks.t1", "ks.t2"},
+	}
+	executions := 0
+	exec := func() (*sqltypes.Result, error) {
+		executions++
+		return sqltypes.MakeTestResult(sqltypes.MakeTestFields("id", "int64"), "1"), nil
+	}
+
+	key := resultCacheKey(engine.PlanKey{Query: "select id from t1, t2"}, "", "", nil)
+	hits, misses := resultCacheHits.Get(), resultCacheMisses.Get()
+	for range 3 {
+		qr, err := rc.execute(key, plan, exec)
+		require.NoError(t, err)
+		assert.Equal(t, `[[INT64(1)]]`, fmt.Sprintf("%v", qr.Rows))
+	}
+	assert.Equal(t, 1, executions)
+	assert.EqualValues(t, 2, resultCacheHits.Get()-hits)
+	assert.EqualValues(t, 1, resultCacheMisses.Get()-misses)
+
+	// a change to any of the tables invalidates the result
+	rc.invalidate("ks", "t2")
+	_, err := rc.execute(key, plan, exec)
+	require.NoError(t, err)
+	assert.Equal(t, 2, executions)
+
+	// and so does a change to the whole keyspace
+	rc.invalidate("ks")
+	_, err = rc.execute(key, plan, exec)
+	require.NoError(t, err)
+	assert.Equal(t, 3, executions)
+
+	// changes to other tables do not
+	rc.invalidate("ks", "t3")
+	rc.invalidate("other")
+	_, err = rc.execute(key, plan, exec)
+	require.NoError(t, err)
+	assert.Equal(t, 3, executions)
+
+	// expired results are executed again
+	expiring := &engine.Plan{ResultCacheTTL: time.Millisecond}
+	expiringKey := resultCacheKey(engine.PlanKey{Query: "select 1 from dual"}, "", "", nil)
+	_, err = rc.execute(expiringKey, expiring, exec)
+	require.NoError(t, err)
+	time.Sleep(5 * time.Millisecond)
+	_, err = rc.execute(expiringKey, expiring, exec)
+	require.NoError(t, err)
+	assert.Equal(t, 5, executions)
+
+	// errors are not cached
+	failingKey := resultCacheKey(engine.PlanKey{Query: "select id from t3"}, "", "", nil)
+	for range 2 {
+		_, err = rc.execute(failingKey, plan, func() (*sqltypes.Result, error) {
+			executions++
+			return nil, errors.New("failed")
+		})
+		require.EqualError(t, err, "failed")
+	}
+	assert.Equal(t, 7, executions)
+}
+
+func TestResultCacheKey(t *testing.T) {
+	planKey := engine.PlanKey{Query: "select * from t1 where id = :id", TabletType: topodatapb.TabletType_REPLICA}
+	bindVars := map[string]*querypb.BindVariable{
+		"id":  sqltypes.Int64BindVariable(1),
+		"ids": sqltypes.TestBindVariable([]any{1, 2}),
+	}
+	key := resultCacheKey(planKey, "", "user", bindVars)
+	assert.Equal(t, key, resultCacheKey(planKey, "", "user", map[string]*querypb.BindVariable{
+		"ids": sqltypes.TestBindVariable([]any{1, 2}),
+		"id":  sqltypes.Int64BindVariable(1),
+	}))
+
+	otherPlanKey := planKey
+	otherPlanKey.TabletType = topodatapb.TabletType_RDONLY
+	assert.NotEqual(t, key, resultCacheKey(otherPlanKey, "", "user", bindVars))
+	assert.NotEqual(t, key, resultCacheKey(planKey, "1", "user", bindVars))
+	assert.NotEqual(t, key, resultCacheKey(planKey, "", "other", bindVars))
+	assert.NotEqual(t, key, resultCacheKey(planKey, "", "user", map[string]*querypb.BindVariable{
+		"id":  sqltypes.Int64BindVariable(2),
+		"ids": sqltypes.TestBindVariable([]any{1, 2}),
+	}))
+	assert.NotEqual(t, key, resultCacheKey(planKey, "", "user", map[string]*querypb.BindVariable{
+		"id":  sqltypes.Int64BindVariable(1),
+		"ids": sqltypes.TestBindVariable([]any{1, 2, 3}),
+	}))
+	assert.NotEqual(t, key, resultCacheKey(planKey, "", "user", map[string]*querypb.BindVariable{
+		"id":  sqltypes.StringBindVariable("1"),
+		"ids": sqltypes.TestBindVariable([]any{1, 2}),
+	}))
+}
+
+type fakeResultCacheStreamer struct {
+	events chan []*binlogdatapb.VEvent
+	vgtids chan *binlogdatapb.VGtid
+}
+
+func (f *fakeResultCacheStreamer) VStream(ctx context.Context, tabletType topodatapb.TabletType, vgtid *binlogdatapb.VGtid, filter *binlogdatapb.Filter, flags *vtgatepb.VStreamFlags, send func(events []*binlogdatapb.VEvent) error) error {
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case f.vgtids <- vgtid:
+	}
+	for {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case events, ok := <-f.events:
+			if !ok {
+				return errors.New("stream ended")
+			}
+			if err := send(events); err != nil {
+				return err
+			}
+		}
+	}
+}
+
+func TestResultCacheInvalidation(t *testing.T) {
+	ctx := utils.LeakCheckContext(t)
+	rc := newResultCache(1024 * 1024)
+	rc.retryDelay = time.Millisecond
+	defer rc.close()
+
+	streamer := &fakeResultCacheStreamer{
+		events: make(chan []*binlogdatapb.VEvent),
+		vgtids: make(chan *binlogdatapb.VGtid, 1),
+	}
+	rc.enableInvalidation(ctx, streamer)
+
+	plan := &engine.Plan{
+		ResultCacheTTL: time.Minute,
+		TablesUsed:     []string{"ks.t1"},
+	}
+	executions := 0
+	exec := func() (*sqltypes.Result, error) {
+		executions++
+		return &sqltypes.Result{}, nil
+	}
+	key := resultCacheKey(engine.PlanKey{Query: "select id from t1"}, "", "", nil)
+
+	_, err := rc.execute(key, plan, exec)
+	require.NoError(t, err)
+	vgtid := <-streamer.vgtids
+	utils.MustMatch(t, &binlogdatapb.VGtid{ShardGtids: []*binlogdatapb.ShardGtid{{Keyspace: "ks", Gtid: "current"}}}, vgtid)
+
+	streamer.events <- []*binlogdatapb.VEvent{{
+		Type:     binlogdatapb.VEventType_ROW,
+		RowEvent: &binlogdatapb.RowEvent{TableName: "t2"},
+	}}
+	_, err = rc.execute(key, plan, exec)
+	require.NoError(t, err)
+	assert.Equal(t, 1, executions)
+
+	streamer.events <- []*binlogdatapb.VEvent{{
+		Type:     binlogdatapb.VEventType_ROW,
+		RowEvent: &binlogdatapb.RowEvent{TableName: "t1"},
+	}}
+	// the event is processed before the stream receives the next one
+	streamer.events <- nil
+	_, err = rc.execute(key, plan, exec)
+	require.NoError(t, err)
+	assert.Equal(t, 2, executions)
+
+	// the results of the keyspace are invalidated when the stream ends
+	close(streamer.events)
+	<-streamer.vgtids
+	_, err = rc.execute(key, plan, exec)
+	require.NoError(t, err)
+	assert.Equal(t, 3, executions)
+}
+
+func TestExecutorResultCache(t *testing.T) {
+	ctx := utils.LeakCheckContext(t)
+	executor, primary, replica := createExecutorEnvWithPrimaryReplicaConn(t, ctx, 0)
+	require.NotNil(t, executor.resultCache)
+
+	execute := func(target, query string) {
+		t.Helper()
+		session := econtext.NewSafeSession(&vtgatepb.Session{TargetString: target, Autocommit: true})
+		_, err := executor.Execute(ctx, nil, "TestExecutorResultCache", session, query, nil, false)
+		require.NoError(t, err)
+	}
+
+	// results of the replica are cached
+	execute(KsTestUnsharded+"@replica", "select /*vt+ CACHE_TTL_MS=60000 */ id from music_user_map where id = 1")
+	execute(KsTestUnsharded+"@replica", "select /*vt+ CACHE_TTL_MS=60000 */ id from music_user_map where id = 1")
+	assert.EqualValues(t, 1, replica.ExecCount.Load())
+
+	// different bind variables are cached separately
+	executor.config.Normalize = true
+	execute(KsTestUnsharded+"@replica", "select /*vt+ CACHE_TTL_MS=60000 */ id from music_user_map where id = 2")
+	execute(KsTestUnsharded+"@replica", "select /*vt+ CACHE_TTL_MS=60000 */ id from music_user_map where id = 3")
+	execute(KsTestUnsharded+"@replica", "select /*vt+ CACHE_TTL_MS=60000 */ id from music_user_map where id = 2")
+	assert.EqualValues(t, 3, replica.ExecCount.Load())
+
+	// queries without a TTL are not cached
+	execute(KsTestUnsharded+"@replica", "select id from music_user_map where id = 2")
+	assert.EqualValues(t, 4, replica.ExecCount.Load())
+
+	// nor are the results of the primary
+	execute(KsTestUnsharded, "select /*vt+ CACHE_TTL_MS=60000 */ id from music_user_map where id = 2")
+	execute(KsTestUnsharded, "select /*vt+ CACHE_TTL_MS=60000 */ id from music_user_map where id = 2")
+	assert.EqualValues(t, 2, primary.ExecCount.Load())
+
+	// the vschema enables the cache per table
+	executor.VSchema().Keyspaces[KsTestUnsharded].Tables["cached"] = &vindexes.BaseTable{
+		Name:           sqlparser.NewIdentifierCS("cached"),
+		Keyspace:       executor.VSchema().Keyspaces[KsTestUnsharded].Keyspace,
+		ResultCacheTTL: time.Minute,
+	}
+	execute(KsTestUnsharded+"@replica", "select id from cached where id = 2")
+	execute(KsTestUnsharded+"@replica", "select id from cached where id = 2")
+	assert.EqualValues(t, 5, replica.ExecCount.Load())
+}
@@ -0,0 +1,200 @@
+/*
+Copyright 2025 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package schema
+
+import (
+	"context"
+	"encoding/json"
+	"strings"
+	"sync"
+	"time"
+
+	"vitess.io/vitess/go/vt/log"
+	querypb "vitess.io/vitess/go/vt/proto/query"
+	"vitess.io/vitess/go/vt/vtgate/vindexes"
+	"vitess.io/vitess/go/vt/vttablet/queryservice"
+)
+
+const (
+	// tableRowsQuery returns the estimated row count of the tables. Note that
+	// MySQL caches these values for information_schema_stats_expiry seconds.
+	tableRowsQuery = "select table_name, table_rows from information_schema.`tables` where table_schema = database() and table_type = 'BASE TABLE'"
+
+	// indexCardinalityQuery returns the cardinality of the first column of
+	// the indexes, as updated by ANALYZE TABLE.
+	indexCardinalityQuery = "select table_name, column_name, max(cardinality) from information_schema.statistics where table_schema = database() and seq_in_index = 1 and column_name is not null group by table_name, column_name"
+
+	// histogramQuery returns the histograms created by
+	// ANALYZE TABLE ... UPDATE HISTOGRAM. It fails on MySQL 5.7.
+	histogramQuery = "select table_name, column_name, histogram from information_schema.column_statistics where schema_name = database()"
+)
+
+// statisticsMap keeps the table statistics of the keyspaces, and when they
+// were last loaded.
+type statisticsMap struct {
+	mu              sync.Mutex
+	refreshInterval time.Duration
+	m               map[keyspaceStr]map[tableNameStr]*vindexes.TableStatistics
+	loaded          map[keyspaceStr]time.Time
+}
+
+// TrackStatistics makes the tracker load the row count and the column
+// cardinality estimates of the tables, and refresh them every refreshInterval.
+// It must be called before Start.
+func (t *Tracker) TrackStatistics(refreshInterval time.Duration) {
+	t.statistics = &statisticsMap{
+		refreshInterval: refreshInterval,
+		m:               map[keyspaceStr]map[tableNameStr]*vindexes.TableStatistics{},
+		loaded:          map[keyspaceStr]time.Time{},
+	}
+}
+
+// statisticsDue returns true when the statistics of the keyspace need to be
+// refreshed.
+func (t *Tracker) statisticsDue(ks keyspaceStr) bool {
+	if t.statistics == nil {
+		return false
+	}
+	t.statistics.mu.Lock()
+	defer t.statistics.mu.Unlock()
+	return time.Since(t.statistics.loaded[ks]) >= t.statistics.refreshInterval
+}
+
+// loadStatistics loads the statistics of the tables of the keyspace.
+func (t *Tracker) loadStatistics(conn queryservice.QueryService, target *querypb.Target) error {
+	if t.statistics == nil {
+		return nil
+	}
+
+	// We record the attempt before loading, so that a failing tablet is
+	// not queried on every health check.
+	t.statistics.mu.Lock()
+	t.statistics.loaded[target.Keyspace] = time.Now()
+	t.statistics.mu.Unlock()
+
+	stats, err := fetchStatistics(t.ctx, conn, target)
+	if err != nil {
+		return err
+	}
+
+	t.statistics.mu.Lock()
+	defer t.statistics.mu.Unlock()
+	t.statistics.m[target.Keyspace] = stats
+	log.Infof("finished loading statistics for keyspace %s. Found %d tables", target.Keyspace, len(stats))
+	return nil
+}
+
+// tableStatistics returns the statistics of the tables of the keyspace.
+func (t *Tracker) tableStatistics(ks keyspaceStr) map[tableNameStr]*vindexes.TableStatistics {
+	if t.statistics == nil {
+		return nil
+	}
+	t.statistics.mu.Lock()
+	defer t.statistics.mu.Unlock()
+	return t.statistics.m[ks]
+}
+
+func fetchStatistics(ctx context.Context, conn queryservice.QueryService, target *querypb.Target) (map[tableNameStr]*vindexes.TableStatistics, error) {
+	stats := map[tableNameStr]*vindexes.TableStatistics{}
+	tableStats := func(name string) *vindexes.TableStatistics {
+		ts, ok := stats[name]
+		if !ok {
+			ts = &vindexes.TableStatistics{Cardinality: map[string]uint64{}}
+			stats[name] = ts
+		}
+		return ts
+	}
+	setCardinality := func(table, column string, card uint64) {
+		ts := tableStats(table)
+		column = strings.ToLower(column)
+		ts.Cardinality[column] = max(ts.Cardinality[column], card)
+	}
+
+	qr, err := conn.Execute(ctx, target, tableRowsQuery, nil, 0, 0, nil)
+	if err != nil {
+		return nil, err
+	}
+	for _, row := range qr.Rows {
+		rows, err := row[1].ToUint64()
+		if err != nil {
+			// TABLE_ROWS is NULL for the tables that were never analyzed.
+			continue
+		}
+		tableStats(row[0].ToString()).RowCount = rows
+	}
+
+	qr, err = conn.Execute(ctx, target, indexCardinalityQuery, nil, 0, 0, nil)
+	if err != nil {
+		return nil, err
+	}
+	for _, row := range qr.Rows {
+		card, err := row[2].ToUint64()
+		if err != nil {
+			continue
+		}
+		setCardinality(row[0].ToString(), row[1].ToString(), card)
+	}
+
+	qr, err = conn.Execute(ctx, target, histogramQuery, nil, 0, 0, nil)
+	if err != nil {
+		// The histograms are optional, and they don't exist in MySQL 5.7.
+		log.Infof("not loading the column histograms of keyspace %s: %v", target.Keyspace, err)
+		return stats, nil
+	}
+	for _, row := range qr.Rows {
+		card, ok := histogramCardinality(row[2].Raw())
+		if !ok {
+			continue
+		}
+		setCardinality(row[0].ToString(), row[1].ToString(), card)
+	}
+	return stats, nil
+}
+
+// histogramCardinality returns the number of distinct values of
+// a MySQL histogram. A singleton histogram has a bucket per value, and
+// the last element of an equi-height bucket is its number of distinct
+// values.
+func histogramCardinality(data []byte) (uint64, bool) {
+	var histogram struct {
+		Buckets [][]json.RawMessage `json:"buckets"`
+		Type    string              `json:"histogram-type"`
+	}
+	if err := json.Unmarshal(data, &histogram); err != nil {
+		return 0, false
+	}
+
+	switch histogram.Type {
+	case "singleton":
+		return uint64(len(histogram.Buckets)), true
+	case "equi-height":
+		var card uint64
+		for _, bucket := range histogram.Buckets {
+			if len(bucket) != 4 {
+				return 0, false
+			}
+			var distinct uint64
+			if err := json.Unmarshal(bucket[3], &distinct); err != nil {
+				return 0, false
+			}
+			card += distinct
+		}
+		return card, true
+	default:
+		return 0, false
+	}
+}
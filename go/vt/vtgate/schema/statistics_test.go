@@ -0,0 +1,120 @@
+/*
+Copyright 2025 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package schema
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"vitess.io/vitess/go/sqltypes"
+	querypb "vitess.io/vitess/go/vt/proto/query"
+	topodatapb "vitess.io/vitess/go/vt/proto/topodata"
+	"vitess.io/vitess/go/vt/sqlparser"
+	"vitess.io/vitess/go/vt/vtgate/vindexes"
+	"vitess.io/vitess/go/vt/vttablet/sandboxconn"
+)
+
+// TestStatisticsLoading tests that the tracker loads the row counts, the index cardinalities and the histograms
+// of the tables, and attaches them to the tables.
+func TestStatisticsLoading(t *testing.T) {
+	target := &querypb.Target{
+		Keyspace:   keyspace,
+		Shard:      "-80",
+		TabletType: topodatapb.TabletType_PRIMARY,
+		Cell:       cell,
+	}
+	tablet := &topodatapb.Tablet{
+		Keyspace: target.Keyspace,
+		Shard:    target.Shard,
+		Type:     target.TabletType,
+	}
+
+	sbc := sandboxconn.NewSandboxConn(tablet)
+	sbc.SetSchemaResult([]sandboxconn.SchemaResult{
+		tables(
+			tbl("t1", "CREATE TABLE `t1` (`id` bigint, `col` int, `kind` int, PRIMARY KEY (`id`), KEY (`col`))"),
+			tbl("t2", "CREATE TABLE `t2` (`id` bigint, PRIMARY KEY (`id`))"),
+		),
+	})
+	sbc.SetResults([]*sqltypes.Result{
+		sqltypes.MakeTestResult(sqltypes.MakeTestFields("table_name|table_rows", "varchar|uint64"),
+			"t1|1000",
+			"t2|null",
+		),
+		sqltypes.MakeTestResult(sqltypes.MakeTestFields("table_name|column_name|max(cardinality)", "varchar|varchar|int64"),
+			"t1|id|1000",
+			"t1|Col|100",
+		),
+		sqltypes.MakeTestResult(sqltypes.MakeTestFields("table_name|column_name|histogram", "varchar|varchar|json"),
+			`t1|kind|{"buckets": [[1, 0.5], [2, 1.0]], "histogram-type": "singleton"}`,
+			`t1|col|{"buckets": [[1, 10, 0.5, 40], [11, 20, 1.0, 50]], "histogram-type": "equi-height"}`,
+		),
+	})
+
+	tracker := NewTracker(nil, false, false, sqlparser.NewTestParser())
+	tracker.TrackStatistics(time.Hour)
+	require.NoError(t, tracker.LoadKeyspace(sbc, target))
+	assert.False(t, tracker.statisticsDue(keyspace))
+
+	tbls := tracker.Tables(keyspace)
+	require.Contains(t, tbls, "t1")
+	assert.Equal(t, &vindexes.TableStatistics{
+		RowCount: 1000,
+		Cardinality: map[string]uint64{
+			"id":   1000,
+			"col":  100,
+			"kind": 2,
+		},
+	}, tbls["t1"].Statistics)
+	require.Contains(t, tbls, "t2")
+	assert.Nil(t, tbls["t2"].Statistics)
+
+	// the statistics are only attached to the copies handed out by the tracker
+	assert.Nil(t, tracker.tables.get(keyspace, "t1").Statistics)
+}
+
+func TestHistogramCardinality(t *testing.T) {
+	tcases := []struct {
+		histogram string
+		card      uint64
+		ok        bool
+	}{{
+		histogram: `{"buckets": [["YQ==", 0.3], ["Yg==", 0.7], ["Yw==", 1.0]], "data-type": "string", "histogram-type": "singleton"}`,
+		card:      3,
+		ok:        true,
+	}, {
+		histogram: `{"buckets": [[1, 10, 0.5, 10], [11, 100, 0.9, 80], [101, 200, 1.0, 100]], "data-type": "int", "histogram-type": "equi-height"}`,
+		card:      190,
+		ok:        true,
+	}, {
+		histogram: `{"buckets": [[1, 10, 0.5]], "histogram-type": "equi-height"}`,
+	}, {
+		histogram: `{"buckets": [], "histogram-type": "unknown"}`,
+	}, {
+		histogram: `not json`,
+	}}
+	for _, tcase := range tcases {
+		t.Run(tcase.histogram, func(t *testing.T) {
+			card, ok := histogramCardinality([]byte(tcase.histogram))
+			assert.Equal(t, tcase.ok, ok)
+			assert.Equal(t, tcase.card, card)
+		})
+	}
+}
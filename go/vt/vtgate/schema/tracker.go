@@ -55,6 +55,10 @@ type (
 		tracked      map[keyspaceStr]*updateController
 		consumeDelay time.Duration
 
+		// statistics are the table statistics used by the planner cost model.
+		// They are nil when the statistics are not tracked.
+		statistics *statisticsMap
+
 		parser *sqlparser.Parser
 	}
 )
@@ -96,6 +100,11 @@ func (t *Tracker) LoadKeyspace(conn queryservice.QueryService, target *querypb.T
 	if err != nil {
 		return err
 	}
+	// The statistics are only estimates, so failing to load them does not
+	// prevent the schema of the keyspace from being used.
+	if err := t.loadStatistics(conn, target); err != nil {
+		log.Warningf("error loading the table statistics of keyspace %s: %v", target.Keyspace, err)
+	}
 
 	t.setLoaded(target.Keyspace, true)
 	return nil
@@ -215,14 +224,20 @@ func (t *Tracker) getKeyspaceUpdateController(th *discovery.TabletHealth) *updat
 
 	ksUpdater, exists := t.tracked[th.Target.Keyspace]
 	if !exists {
-		ksUpdater = t.newUpdateController()
+		ksUpdater = t.newUpdateController(th.Target.Keyspace)
 		t.tracked[th.Target.Keyspace] = ksUpdater
 	}
 	return ksUpdater
 }
 
-func (t *Tracker) newUpdateController() *updateController {
-	return &updateController{update: t.updateSchema, reloadKeyspace: t.initKeyspace, signal: t.signal, consumeDelay: t.consumeDelay}
+func (t *Tracker) newUpdateController(ks keyspaceStr) *updateController {
+	return &updateController{
+		update:         t.updateSchema,
+		reloadKeyspace: t.initKeyspace,
+		statisticsDue:  func() bool { return t.statisticsDue(ks) },
+		signal:         t.signal,
+		consumeDelay:   t.consumeDelay,
+	}
 }
 
 // setLoaded sets the loaded status for the given keyspace.
@@ -290,7 +305,20 @@ func (t *Tracker) Tables(ks string) map[string]*vindexes.TableInfo {
 		return map[string]*vindexes.TableInfo{} // we know nothing about this KS, so that is the info we can give out
 	}
 
-	return maps.Clone(m)
+	stats := t.tableStatistics(ks)
+	if len(stats) == 0 {
+		return maps.Clone(m)
+	}
+	tables := make(map[string]*vindexes.TableInfo, len(m))
+	for name, tblInfo := range m {
+		if ts, ok := stats[name]; ok {
+			withStats := *tblInfo
+			withStats.Statistics = ts
+			tblInfo = &withStats
+		}
+		tables[name] = tblInfo
+	}
+	return tables
 }
 
 // Views returns all known views in the keyspace with their definition.
@@ -331,11 +359,16 @@ func (t *Tracker) updateSchema(th *discovery.TabletHealth) bool {
 		success = t.updatedViewSchema(th)
 	}
 
-	if !success || !th.Stats.UdfsChanged {
-		return success
+	if success && th.Stats.UdfsChanged {
+		success = t.loadUDFs(th.Conn, th.Target) == nil
 	}
 
-	return t.loadUDFs(th.Conn, th.Target) == nil
+	if success && t.statisticsDue(th.Target.Keyspace) {
+		if err := t.loadStatistics(th.Conn, th.Target); err != nil {
+			log.Warningf("error refreshing the table statistics of keyspace %s: %v", th.Target.Keyspace, err)
+		}
+	}
+	return success
 }
 
 func (t *Tracker) updatedTableSchema(th *discovery.TabletHealth) bool {
@@ -489,7 +522,7 @@ func (t *Tracker) RegisterSignalReceiver(f func()) {
 
 // AddNewKeyspace adds keyspace to the tracker.
 func (t *Tracker) AddNewKeyspace(conn queryservice.QueryService, target *querypb.Target) error {
-	updateController := t.newUpdateController()
+	updateController := t.newUpdateController(target.Keyspace)
 	t.tracked[target.Keyspace] = updateController
 	err := t.LoadKeyspace(conn, target)
 	if err != nil {
@@ -38,6 +38,7 @@ type (
 		consumeDelay   time.Duration
 		update         func(th *discovery.TabletHealth) bool
 		reloadKeyspace func(th *discovery.TabletHealth) error
+		statisticsDue  func() bool
 		signal         func()
 		loaded         bool
 
@@ -147,8 +148,10 @@ func (u *updateController) add(th *discovery.TabletHealth) {
 		return
 	}
 
-	// If the keyspace schema is loaded and there is no schema change detected. Then there is nothing to process.
-	if len(th.Stats.TableSchemaChanged) == 0 && len(th.Stats.ViewSchemaChanged) == 0 && !th.Stats.UdfsChanged && u.loaded {
+	// If the keyspace schema is loaded and there is no schema change detected. Then there is nothing to process,
+	// unless the table statistics need to be refreshed.
+	if len(th.Stats.TableSchemaChanged) == 0 && len(th.Stats.ViewSchemaChanged) == 0 && !th.Stats.UdfsChanged && u.loaded &&
+		(u.statisticsDue == nil || !u.statisticsDue()) {
 		return
 	}
 
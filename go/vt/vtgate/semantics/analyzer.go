@@ -479,7 +479,9 @@ func (a *analyzer) noteQuerySignature(node sqlparser.SQLNode) {
 			a.sig.RecursiveCTE = true
 		}
 	case sqlparser.AggrFunc:
-		a.sig.Aggregation = true
+		if !sqlparser.IsWindowFunc(node) {
+			a.sig.Aggregation = true
+		}
 	case *sqlparser.Delete, *sqlparser.Update, *sqlparser.Insert:
 		a.sig.DML = true
 	}
@@ -955,9 +955,6 @@ func TestInvalidQueries(t *testing.T) {
 	}, {
 		sql:  "select is_free_lock('xyz') from user",
 		serr: "is_free_lock('xyz') allowed only with dual",
-	}, {
-		sql: "SELECT * FROM JSON_TABLE('[ {\"c1\": null} ]','$[*]' COLUMNS( c1 INT PATH '$.c1' ERROR ON ERROR )) as jt",
-		err: &JSONTablesError{},
 	}, {
 		sql:             "select does_not_exist from t1",
 		notUnshardedErr: "column 'does_not_exist' not found in table 't1'",
@@ -1148,6 +1145,14 @@ func TestJoinPredicateDependencies(t *testing.T) {
 		query:           "select 1 from (select id from t1 union select id from t) x join t2 on x.id = t2.uid",
 		recursiveExpect: MergeTableSets(TS0, TS1, TS2),
 		directExpect:    MergeTableSets(TS2, TS3),
+	}, {
+		query:           "select 1 from t2 join json_table(t2.name, '$[*]' columns(id int path '$.id')) jt on jt.id = t2.uid",
+		recursiveExpect: MergeTableSets(TS0, TS1),
+		directExpect:    MergeTableSets(TS0, TS1),
+	}, {
+		query:           "select 1 from t2 join lateral (select id from t1 where t1.id = t2.uid) x on x.id = t2.uid",
+		recursiveExpect: MergeTableSets(TS0, TS1),
+		directExpect:    MergeTableSets(TS0, TS2),
 	}}
 	for _, query := range queries {
 		t.Run(query.query, func(t *testing.T) {
@@ -36,26 +36,12 @@ func (a *analyzer) checkForInvalidConstructs(cursor *sqlparser.Cursor) error {
 		return &LockOnlyWithDualError{Node: node}
 	case *sqlparser.Union:
 		return checkUnion(node)
-	case *sqlparser.JSONTableExpr:
-		return &JSONTablesError{}
-	case *sqlparser.DerivedTable:
-		return checkDerived(node)
 	case *sqlparser.AssignmentExpr:
 		return vterrors.VT12001("Assignment expression")
-	case *sqlparser.ComparisonExpr:
-		if node.Modifier != sqlparser.Missing {
-			return NotSingleRouteErr{Inner: &UnsupportedConstruct{errString: "ANY/ALL/SOME comparison operator"}}
-		}
 	case *sqlparser.Subquery:
 		return a.checkSubqueryColumns(cursor.Parent(), node)
-	case *sqlparser.Insert:
-		if !a.singleUnshardedKeyspace && node.Action == sqlparser.ReplaceAct {
-			return ShardedError{Inner: &UnsupportedConstruct{errString: "REPLACE INTO with sharded keyspace"}}
-		}
 	case *sqlparser.OverClause:
-		if !a.singleUnshardedKeyspace {
-			return NotSingleShardError{Inner: &UnsupportedConstruct{errString: "OVER CLAUSE with sharded keyspace"}}
-		}
+		return a.checkOverClause(node)
 	}
 
 	return nil
@@ -94,9 +80,15 @@ func (a *analyzer) checkSubqueryColumns(parent sqlparser.SQLNode, subq *sqlparse
 	return nil
 }
 
-func checkDerived(node *sqlparser.DerivedTable) error {
-	if node.Lateral {
-		return vterrors.VT12001("lateral derived tables")
+// checkOverClause checks that the window specification is one we can evaluate across shards.
+// Named windows and explicit frame clauses are only supported when the query goes to a single shard.
+func (a *analyzer) checkOverClause(node *sqlparser.OverClause) error {
+	if a.singleUnshardedKeyspace {
+		return nil
+	}
+	spec := node.WindowSpec
+	if !node.WindowName.IsEmpty() || spec == nil || !spec.Name.IsEmpty() || spec.FrameClause != nil {
+		return NotSingleShardError{Inner: &UnsupportedConstruct{errString: "OVER CLAUSE with sharded keyspace"}}
 	}
 	return nil
 }
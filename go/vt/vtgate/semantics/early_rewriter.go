@@ -50,7 +50,17 @@ func (r *earlyRewriter) down(cursor *sqlparser.Cursor) error {
 	case *sqlparser.NotExpr:
 		rewriteNotExpr(cursor, node)
 	case *sqlparser.ComparisonExpr:
+		if node.Modifier != sqlparser.Missing {
+			cursor.ReplaceAndRevisit(rewriteComparisonModifier(node, false))
+			return nil
+		}
 		return handleComparisonExpr(cursor, node)
+	case *sqlparser.AliasedExpr:
+		keepColumnNameOfComparisonModifiers(node)
+	case *sqlparser.Where:
+		node.Expr = rewriteComparisonModifiersInFilter(node.Expr)
+	case *sqlparser.JoinCondition:
+		node.On = rewriteComparisonModifiersInFilter(node.On)
 	case *sqlparser.With:
 		if !node.Recursive {
 			return r.handleWith(node)
@@ -172,10 +182,16 @@ func rewriteNotExpr(cursor *sqlparser.Cursor, node *sqlparser.NotExpr) {
 	if cmp.Operator == sqlparser.NullSafeEqualOp {
 		return
 	}
-	cmp.Operator = cmp.Operator.Inverse()
+	invertComparison(cmp)
 	cursor.Replace(cmp)
 }
 
+// invertComparison changes the comparison into its negation. NOT (x > ALL (subquery)) is x <= ANY (subquery)
+func invertComparison(cmp *sqlparser.ComparisonExpr) {
+	cmp.Operator = cmp.Operator.Inverse()
+	cmp.Modifier = cmp.Modifier.Inverse()
+}
+
 func (r *earlyRewriter) handleJoinTableExprUp(join *sqlparser.JoinTableExpr) error {
 	// this rewriting is done in the `up` phase, because we need the scope to have been
 	// filled in with the available tables
@@ -876,6 +892,141 @@ func handleComparisonExpr(cursor *sqlparser.Cursor, node *sqlparser.ComparisonEx
 	return nil
 }
 
+// keepColumnNameOfComparisonModifiers aliases select expressions that contain ANY/ALL/SOME comparisons,
+// so the column keeps its name after the comparisons have been rewritten
+func keepColumnNameOfComparisonModifiers(ae *sqlparser.AliasedExpr) {
+	if !ae.As.IsEmpty() {
+		return
+	}
+	found := false
+	_ = sqlparser.Walk(func(node sqlparser.SQLNode) (bool, error) {
+		if cmp, ok := node.(*sqlparser.ComparisonExpr); ok && cmp.Modifier != sqlparser.Missing {
+			found = true
+		}
+		return !found, nil
+	}, ae.Expr)
+	if found {
+		ae.As = sqlparser.NewIdentifierCI(sqlparser.String(ae.Expr))
+	}
+}
+
+// rewriteComparisonModifiersInFilter rewrites the ANY/ALL/SOME comparisons that are predicates of a WHERE, HAVING
+// or ON clause. A NULL predicate filters out the row just like a FALSE one, which allows for simpler rewrites.
+func rewriteComparisonModifiersInFilter(expr sqlparser.Expr) sqlparser.Expr {
+	switch node := expr.(type) {
+	case *sqlparser.AndExpr:
+		node.Left = rewriteComparisonModifiersInFilter(node.Left)
+		node.Right = rewriteComparisonModifiersInFilter(node.Right)
+	case *sqlparser.NotExpr:
+		cmp, ok := node.Expr.(*sqlparser.ComparisonExpr)
+		if ok && cmp.Modifier != sqlparser.Missing && cmp.Operator != sqlparser.NullSafeEqualOp {
+			invertComparison(cmp)
+			return rewriteComparisonModifier(cmp, true)
+		}
+	case *sqlparser.ComparisonExpr:
+		if node.Modifier != sqlparser.Missing {
+			return rewriteComparisonModifier(node, true)
+		}
+	}
+	return expr
+}
+
+// rewriteComparisonModifier rewrites a comparison with the ANY/ALL/SOME modifier into IN, NOT IN, EXISTS and MIN/MAX
+// subqueries, which we know how to plan across shards.
+// The rewritten expressions are NULL whenever the original comparison is. When filter is true, the expression is
+// only used as a predicate, and it is enough for the rewritten expression to be true whenever the original one is.
+func rewriteComparisonModifier(cmp *sqlparser.ComparisonExpr, filter bool) sqlparser.Expr {
+	subq, ok := cmp.Right.(*sqlparser.Subquery)
+	if !ok {
+		return cmp
+	}
+
+	switch {
+	case cmp.Operator == sqlparser.EqualOp && cmp.Modifier == sqlparser.Any:
+		return &sqlparser.ComparisonExpr{Operator: sqlparser.InOp, Left: cmp.Left, Right: subq}
+	case cmp.Operator == sqlparser.NotEqualOp && cmp.Modifier == sqlparser.All:
+		return &sqlparser.ComparisonExpr{Operator: sqlparser.NotInOp, Left: cmp.Left, Right: subq}
+	}
+
+	// compare compares the left hand side with the column of the subquery, exposed through a derived table
+	compare := func() sqlparser.Expr {
+		return &sqlparser.ComparisonExpr{
+			Operator: cmp.Operator,
+			Left:     sqlparser.Clone(cmp.Left),
+			Right:    sqlparser.NewColNameWithQualifier(anyAllColumn, sqlparser.NewTableName(anyAllTable)),
+		}
+	}
+	isNull := func() sqlparser.Expr {
+		return &sqlparser.IsExpr{Left: compare(), Right: sqlparser.IsNullOp}
+	}
+	null := &sqlparser.NullVal{}
+
+	if cmp.Modifier == sqlparser.Any {
+		if filter {
+			if aggr := minMaxForAny(cmp.Operator); aggr != nil {
+				return &sqlparser.ComparisonExpr{
+					Operator: cmp.Operator,
+					Left:     cmp.Left,
+					Right:    sqlparser.NewSubquery(selectFromSubquery(subq, aggr, nil)),
+				}
+			}
+			return existsInSubquery(subq, compare())
+		}
+		// true if any comparison is true, otherwise NULL if any comparison is NULL
+		return &sqlparser.OrExpr{
+			Left:  existsInSubquery(subq, compare()),
+			Right: &sqlparser.AndExpr{Left: existsInSubquery(subq, isNull()), Right: null},
+		}
+	}
+
+	if filter {
+		return sqlparser.NewNotExpr(existsInSubquery(subq, &sqlparser.IsExpr{Left: compare(), Right: sqlparser.IsNotTrueOp}))
+	}
+	// false if any comparison is false, otherwise NULL if any comparison is NULL
+	return &sqlparser.AndExpr{
+		Left:  sqlparser.NewNotExpr(existsInSubquery(subq, sqlparser.NewNotExpr(compare()))),
+		Right: &sqlparser.OrExpr{Left: sqlparser.NewNotExpr(existsInSubquery(subq, isNull())), Right: null},
+	}
+}
+
+const (
+	anyAllTable  = "__sq"
+	anyAllColumn = "__sq_col"
+)
+
+// minMaxForAny returns the aggregation of the subquery column that the left hand side of
+// `x op ANY (subquery)` can be compared with instead, or nil if there is none
+func minMaxForAny(op sqlparser.ComparisonExprOperator) sqlparser.Expr {
+	col := sqlparser.NewColNameWithQualifier(anyAllColumn, sqlparser.NewTableName(anyAllTable))
+	switch op {
+	case sqlparser.LessThanOp, sqlparser.LessEqualOp:
+		return &sqlparser.Max{Arg: col}
+	case sqlparser.GreaterThanOp, sqlparser.GreaterEqualOp:
+		return &sqlparser.Min{Arg: col}
+	}
+	return nil
+}
+
+func existsInSubquery(subq *sqlparser.Subquery, predicate sqlparser.Expr) sqlparser.Expr {
+	return sqlparser.NewExistsExpr(sqlparser.NewSubquery(selectFromSubquery(subq, sqlparser.NewIntLiteral("1"), predicate)))
+}
+
+// selectFromSubquery creates `select expr from (subquery) as __sq(__sq_col) where predicate`.
+// Using a derived table keeps the columns of the left hand side of the comparison from being
+// bound to the tables of the subquery
+func selectFromSubquery(subq *sqlparser.Subquery, expr, predicate sqlparser.Expr) *sqlparser.Select {
+	derived := sqlparser.NewAliasedTableExpr(sqlparser.NewDerivedTable(false, sqlparser.Clone(subq.Select)), anyAllTable)
+	derived.Columns = sqlparser.Columns{sqlparser.NewIdentifierCI(anyAllColumn)}
+	sel := &sqlparser.Select{
+		SelectExprs: &sqlparser.SelectExprs{Exprs: []sqlparser.SelectExpr{sqlparser.NewAliasedExpr(expr, "")}},
+		From:        []sqlparser.TableExpr{derived},
+	}
+	if predicate != nil {
+		sel.AddWhere(predicate)
+	}
+	return sel
+}
+
 func (r *earlyRewriter) expandStar(cursor *sqlparser.Cursor, node *sqlparser.SelectExprs) error {
 	currentScope := r.scoper.currentScope()
 	selExprs := new(sqlparser.SelectExprs)
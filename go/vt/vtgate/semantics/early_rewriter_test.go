@@ -861,6 +861,49 @@ func TestRewriteNot(t *testing.T) {
 	}
 }
 
+func TestRewriteComparisonModifiers(t *testing.T) {
+	tcases := []struct {
+		sql      string
+		expected string
+	}{{
+		sql:      "select 1 from t1 where a = any (select b from t2)",
+		expected: "select 1 from t1 where a in (select b from t2)",
+	}, {
+		sql:      "select 1 from t1 where a <> all (select b from t2)",
+		expected: "select 1 from t1 where a not in (select b from t2)",
+	}, {
+		sql:      "select 1 from t1 where a > some (select b from t2)",
+		expected: "select 1 from t1 where a > (select min(__sq.__sq_col) from (select b from t2) as __sq(__sq_col))",
+	}, {
+		sql:      "select 1 from t1 where a <= any (select b from t2)",
+		expected: "select 1 from t1 where a <= (select max(__sq.__sq_col) from (select b from t2) as __sq(__sq_col))",
+	}, {
+		sql:      "select 1 from t1 where a <> any (select b from t2)",
+		expected: "select 1 from t1 where exists (select 1 from (select b from t2) as __sq(__sq_col) where a != __sq.__sq_col)",
+	}, {
+		sql:      "select 1 from t1 where a > all (select b from t2)",
+		expected: "select 1 from t1 where not exists (select 1 from (select b from t2) as __sq(__sq_col) where a > __sq.__sq_col is not true)",
+	}, {
+		sql:      "select 1 from t1 join t2 on t1.a < all (select b from t2)",
+		expected: "select 1 from t1 join t2 on not exists (select 1 from (select b from t2) as __sq(__sq_col) where t1.a < __sq.__sq_col is not true)",
+	}, {
+		sql:      "select a > any (select b from t2) from t1",
+		expected: "select exists (select 1 from (select b from t2) as __sq(__sq_col) where a > __sq.__sq_col) or exists (select 1 from (select b from t2) as __sq(__sq_col) where a > __sq.__sq_col is null) and null as `a > any (select b from t2)` from t1",
+	}, {
+		sql:      "select 1 from t1 where (a = all (select b from t2)) is null",
+		expected: "select 1 from t1 where (not exists (select 1 from (select b from t2) as __sq(__sq_col) where a != __sq.__sq_col) and (not exists (select 1 from (select b from t2) as __sq(__sq_col) where a = __sq.__sq_col is null) or null)) is null",
+	}}
+	for _, tcase := range tcases {
+		t.Run(tcase.sql, func(t *testing.T) {
+			ast, err := sqlparser.NewTestParser().Parse(tcase.sql)
+			require.NoError(t, err)
+			_, err = Analyze(ast, "user", fakeSchemaInfo())
+			require.NoError(t, err)
+			assert.Equal(t, tcase.expected, sqlparser.String(ast))
+		})
+	}
+}
+
 func TestOrderByDerivedTable(t *testing.T) {
 	ks := &vindexes.Keyspace{
 		Name:    "main",
@@ -44,7 +44,6 @@ type (
 	NotSequenceTableError          struct{ Table string }
 	NextWithMultipleTablesError    struct{ CountTables int }
 	LockOnlyWithDualError          struct{ Node *sqlparser.LockingFunc }
-	JSONTablesError                struct{ Table string }
 	QualifiedOrderInUnionError     struct{ Table string }
 	BuggyError                     struct{ Msg string }
 	UnsupportedConstruct           struct{ errString string }
@@ -208,13 +207,6 @@ func (e *QualifiedOrderInUnionError) Error() string {
 	return eprintf(e, "Table `%s` from one of the SELECTs cannot be used in global ORDER clause", e.Table)
 }
 
-// JSONTablesError
-func (e *JSONTablesError) Error() string {
-	return eprintf(e, "json_table expressions")
-}
-
-func (e *JSONTablesError) unsupported() {}
-
 // BuggyError is used for checking conditions that should never occur
 func (e *BuggyError) Error() string {
 	return eprintf(e, e.Msg)
@@ -0,0 +1,129 @@
+/*
+Copyright 2025 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package semantics
+
+import (
+	"strings"
+
+	vtrpcpb "vitess.io/vitess/go/vt/proto/vtrpc"
+	"vitess.io/vitess/go/vt/sqlparser"
+	"vitess.io/vitess/go/vt/vterrors"
+	"vitess.io/vitess/go/vt/vtgate/evalengine"
+	"vitess.io/vitess/go/vt/vtgate/vindexes"
+)
+
+// JSONTable contains the information about the columns of a JSON_TABLE expression used in the FROM clause
+type JSONTable struct {
+	ASTNode *sqlparser.JSONTableExpr
+	id      TableSet
+	columns []ColumnInfo
+}
+
+var _ TableInfo = (*JSONTable)(nil)
+
+func newJSONTable(node *sqlparser.JSONTableExpr, id TableSet, org originable) (*JSONTable, error) {
+	jt := &JSONTable{ASTNode: node, id: id}
+	jt.addColumns(node.Columns, org)
+	for i, col := range jt.columns {
+		for _, other := range jt.columns[:i] {
+			if strings.EqualFold(col.Name, other.Name) {
+				return nil, vterrors.NewErrorf(vtrpcpb.Code_INVALID_ARGUMENT, vterrors.DupFieldName, "Duplicate column name '%s'", col.Name)
+			}
+		}
+	}
+	return jt, nil
+}
+
+// addColumns adds the columns of the definitions, flattening the ones of the nested paths
+func (jt *JSONTable) addColumns(defs []*sqlparser.JtColumnDefinition, org originable) {
+	for _, def := range defs {
+		switch {
+		case def.JtOrdinal != nil:
+			jt.columns = append(jt.columns, ColumnInfo{
+				Name: def.JtOrdinal.Name.String(),
+				Type: evalengine.JSONTableOrdinalityType,
+			})
+		case def.JtPath != nil:
+			jt.columns = append(jt.columns, ColumnInfo{
+				Name: def.JtPath.Name.String(),
+				Type: evalengine.TypeForColumnType(def.JtPath.Type, org.collationEnv()),
+			})
+		case def.JtNestedPath != nil:
+			jt.addColumns(def.JtNestedPath.Columns, org)
+		}
+	}
+}
+
+// dependencies implements the TableInfo interface
+func (jt *JSONTable) dependencies(colName string, _ originable) (dependencies, error) {
+	for _, col := range jt.columns {
+		if strings.EqualFold(col.Name, colName) {
+			return createCertain(jt.id, jt.id, col.Type), nil
+		}
+	}
+	return &nothing{}, nil
+}
+
+// IsInfSchema implements the TableInfo interface
+func (jt *JSONTable) IsInfSchema() bool {
+	return false
+}
+
+func (jt *JSONTable) matches(name sqlparser.TableName) bool {
+	return jt.ASTNode.Alias.String() == name.Name.String() && name.Qualifier.IsEmpty()
+}
+
+func (jt *JSONTable) authoritative() bool {
+	return true
+}
+
+// Name implements the TableInfo interface
+func (jt *JSONTable) Name() (sqlparser.TableName, error) {
+	return sqlparser.NewTableName(jt.ASTNode.Alias.String()), nil
+}
+
+// GetAliasedTableExpr implements the TableInfo interface.
+// JSON_TABLE expressions are not aliased table expressions, so this returns nil
+func (jt *JSONTable) GetAliasedTableExpr() *sqlparser.AliasedTableExpr {
+	return nil
+}
+
+func (jt *JSONTable) canShortCut() shortCut {
+	return canShortCut
+}
+
+// GetVindexTable implements the TableInfo interface
+func (jt *JSONTable) GetVindexTable() *vindexes.BaseTable {
+	return nil
+}
+
+func (jt *JSONTable) getColumns(bool) []ColumnInfo {
+	return jt.columns
+}
+
+func (jt *JSONTable) getTableSet(_ originable) TableSet {
+	return jt.id
+}
+
+func (jt *JSONTable) getExprFor(s string) (sqlparser.Expr, error) {
+	return nil, vterrors.VT03022(s, "field list")
+}
+
+// GetMirrorRule implements TableInfo.
+func (jt *JSONTable) GetMirrorRule() *vindexes.MirrorRule {
+	return nil
+}
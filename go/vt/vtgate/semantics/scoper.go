@@ -175,6 +175,10 @@ func (s *scoper) enterJoinScope(cursor *sqlparser.Cursor) {
 		// To create this special context, we will find the parent scope of the select statement involved.
 		currScope := s.currentScope()
 		stmtScope := currScope.findParentScopeOfStatement()
+		if isLateral(cursor.Node()) {
+			// lateral derived tables and JSON_TABLE expressions can also see the tables that precede them in the FROM clause
+			stmtScope = currScope
+		}
 		nScope := newScope(stmtScope)
 		if stmtScope == nil {
 			// TODO: this feels hacky. revisit with a better plan
@@ -185,6 +189,24 @@ func (s *scoper) enterJoinScope(cursor *sqlparser.Cursor) {
 	}
 }
 
+// isLateral returns true if the table expression contains a lateral derived table or a JSON_TABLE expression
+func isLateral(node sqlparser.SQLNode) bool {
+	lateral := false
+	_ = sqlparser.Walk(func(node sqlparser.SQLNode) (bool, error) {
+		switch node := node.(type) {
+		case *sqlparser.JSONTableExpr:
+			lateral = true
+		case *sqlparser.DerivedTable:
+			lateral = lateral || node.Lateral
+			return false, nil
+		case sqlparser.Expr:
+			return false, nil
+		}
+		return !lateral, nil
+	}, node)
+	return lateral
+}
+
 func (s *scoper) pushSelectScope(node *sqlparser.Select) {
 	currScope := newScope(s.currentScope())
 	currScope.stmtScope = true
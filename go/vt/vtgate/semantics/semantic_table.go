@@ -94,6 +94,9 @@ type (
 	// operators.
 	MirrorInfo struct {
 		Percent float32
+		// Compare is set if any of the mirror rules involved asks for the
+		// results of the mirror target to be compared with the source.
+		Compare bool
 	}
 
 	// SemTable contains semantic analysis information about the query.
@@ -587,6 +590,26 @@ func (st *SemTable) TableSetFor(t *sqlparser.AliasedTableExpr) TableSet {
 	return EmptyTableSet()
 }
 
+// TableSetForJSONTable returns the bitmask for this JSON_TABLE expression
+func (st *SemTable) TableSetForJSONTable(t *sqlparser.JSONTableExpr) TableSet {
+	for idx, t2 := range st.Tables {
+		if jt, ok := t2.(*JSONTable); ok && jt.ASTNode == t {
+			return SingleTableSet(idx)
+		}
+	}
+	return EmptyTableSet()
+}
+
+// ReplaceJSONTableFor replaces the JSON_TABLE expression of the given single TableSet
+func (st *SemTable) ReplaceJSONTableFor(id TableSet, t *sqlparser.JSONTableExpr) {
+	if st == nil || id.NumberOfTables() != 1 {
+		return
+	}
+	if jt, ok := st.Tables[id.TableOffset()].(*JSONTable); ok {
+		jt.ASTNode = t
+	}
+}
+
 // ReplaceTableSetFor replaces the given single TabletSet with the new *sqlparser.AliasedTableExpr
 func (st *SemTable) ReplaceTableSetFor(id TableSet, t *sqlparser.AliasedTableExpr) {
 	if st == nil {
@@ -1013,7 +1036,8 @@ func (st *SemTable) ForeachExprEquality(e sqlparser.Expr, f func(expr sqlparser.
 //
 // The idea here is that if you have two tables with mirror rules both involved
 // in a query, and one of those rules is 1% while the other is 100%, to mirror
-// the query with 1% chance.
+// the query with 1% chance. Results are compared if any of the rules asks for
+// it.
 func mirrorInfo(tableInfos []TableInfo) MirrorInfo {
 	mi := MirrorInfo{}
 	for _, t := range tableInfos {
@@ -1021,6 +1045,7 @@ func mirrorInfo(tableInfos []TableInfo) MirrorInfo {
 			if mi.Percent == 0 || mr.Percent < mi.Percent {
 				mi.Percent = mr.Percent
 			}
+			mi.Compare = mi.Compare || mr.Compare
 		}
 	}
 	return mi
@@ -132,6 +132,8 @@ func (tc *tableCollector) up(cursor *sqlparser.Cursor) error {
 		return tc.visitAliasedTableExpr(node)
 	case *sqlparser.Union:
 		return tc.visitUnion(node)
+	case *sqlparser.JSONTableExpr:
+		return tc.visitJSONTableExpr(node)
 	case *sqlparser.RowAlias:
 		ins, ok := cursor.Parent().(*sqlparser.Insert)
 		if !ok {
@@ -153,6 +155,17 @@ func (tc *tableCollector) visitAliasedTableExpr(node *sqlparser.AliasedTableExpr
 	return nil
 }
 
+func (tc *tableCollector) visitJSONTableExpr(node *sqlparser.JSONTableExpr) error {
+	tableInfo, err := newJSONTable(node, SingleTableSet(len(tc.Tables)), tc.org)
+	if err != nil {
+		return err
+	}
+
+	tc.Tables = append(tc.Tables, tableInfo)
+	scope := tc.scoper.currentScope()
+	return scope.addTable(tableInfo)
+}
+
 func (tc *tableCollector) visitUnion(union *sqlparser.Union) error {
 	firstSelect, err := sqlparser.GetFirstSelect(union)
 	if err != nil {
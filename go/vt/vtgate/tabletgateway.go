@@ -24,6 +24,7 @@ import (
 	"runtime/debug"
 	"slices"
 	"sort"
+	"strings"
 	"sync"
 	"sync/atomic"
 	"time"
@@ -59,8 +60,10 @@ var (
 
 	// configuration flags for the tablet balancer
 	balancerEnabled     bool
+	balancerMode        = balancer.ModeCell
 	balancerVtgateCells []string
 	balancerKeyspaces   []string
+	balancerLagPenalty  = 10 * time.Second
 
 	logCollations = logutil.NewThrottledLogger("CollationInconsistent", 1*time.Minute)
 )
@@ -73,6 +76,8 @@ func init() {
 		fs.BoolVar(&balancerEnabled, "enable-balancer", false, "Enable the tablet balancer to evenly spread query load for a given tablet type")
 		fs.StringSliceVar(&balancerVtgateCells, "balancer-vtgate-cells", []string{}, "When in balanced mode, a comma-separated list of cells that contain vtgates (required)")
 		fs.StringSliceVar(&balancerKeyspaces, "balancer-keyspaces", []string{}, "When in balanced mode, a comma-separated list of keyspaces for which to use the balancer (optional)")
+		fs.StringVar(&balancerMode, "balancer-mode", balancerMode, fmt.Sprintf("When in balanced mode, the algorithm used to pick tablets, one of: %s", strings.Join(balancer.Modes, ", ")))
+		fs.DurationVar(&balancerLagPenalty, "balancer-replication-lag-penalty", balancerLagPenalty, "When in a load-aware balanced mode, the replication lag at which a tablet is considered twice as loaded (0 to disable)")
 	})
 }
 
@@ -167,10 +172,19 @@ func (gw *TabletGateway) setupBuffering(ctx context.Context) {
 }
 
 func (gw *TabletGateway) setupBalancer(ctx context.Context) {
-	if len(balancerVtgateCells) == 0 {
-		log.Exitf("balancer-vtgate-cells is required for balanced mode")
+	if balancerMode == balancer.ModeCell {
+		if len(balancerVtgateCells) == 0 {
+			log.Exitf("balancer-vtgate-cells is required for balanced mode")
+		}
+		gw.balancer = balancer.NewTabletBalancer(gw.localCell, balancerVtgateCells)
+		return
+	}
+
+	b, err := balancer.NewLoadBalancer(ctx, gw.hc, balancerMode, gw.localCell, balancerLagPenalty)
+	if err != nil {
+		log.Exitf("invalid balancer-mode: %v", err)
 	}
-	gw.balancer = balancer.NewTabletBalancer(gw.localCell, balancerVtgateCells)
+	gw.balancer = b
 }
 
 // QueryServiceByAlias satisfies the Gateway interface
@@ -279,7 +293,7 @@ func (gw *TabletGateway) DebugBalancerHandler(w http.ResponseWriter, r *http.Req
 // withRetry also adds shard information to errors returned from the inner QueryService, so
 // withShardError should not be combined with withRetry.
 func (gw *TabletGateway) withRetry(ctx context.Context, target *querypb.Target, _ queryservice.QueryService,
-	_ string, inTransaction bool, inner func(ctx context.Context, target *querypb.Target, conn queryservice.QueryService) (bool, error)) error {
+	_ string, inTransaction bool, streaming bool, inner func(ctx context.Context, target *querypb.Target, conn queryservice.QueryService) (bool, error)) error {
 
 	// for transactions, we connect to a specific tablet instead of letting gateway choose one
 	if inTransaction && target.TabletType != topodatapb.TabletType_PRIMARY {
@@ -406,10 +420,18 @@ func (gw *TabletGateway) withRetry(ctx context.Context, target *querypb.Target,
 
 		gw.updateDefaultConnCollation(tabletLastUsed)
 
+		var queryDone func(error)
+		if gw.balancer != nil {
+			queryDone = gw.balancer.TrackQuery(th, streaming)
+		}
+
 		startTime := time.Now()
 		var canRetry bool
 		canRetry, err = inner(ctx, target, th.Conn)
 		gw.updateStats(target, startTime, err)
+		if queryDone != nil {
+			queryDone(err)
+		}
 		if canRetry {
 			invalidTablets[topoproto.TabletAliasString(tabletLastUsed.Alias)] = true
 			continue
@@ -421,7 +443,7 @@ func (gw *TabletGateway) withRetry(ctx context.Context, target *querypb.Target,
 
 // withShardError adds shard information to errors returned from the inner QueryService.
 func (gw *TabletGateway) withShardError(ctx context.Context, target *querypb.Target, conn queryservice.QueryService,
-	_ string, _ bool, inner func(ctx context.Context, target *querypb.Target, conn queryservice.QueryService) (bool, error)) error {
+	_ string, _ bool, _ bool, inner func(ctx context.Context, target *querypb.Target, conn queryservice.QueryService) (bool, error)) error {
 	_, err := inner(ctx, target, conn)
 	return NewShardError(err, target)
 }
@@ -106,6 +106,11 @@ var phaseMessage = map[commitPhase]string{
 // Begin begins a new transaction. If one is already in progress, it commits it
 // and starts a new one.
 func (txc *TxConn) Begin(ctx context.Context, session *econtext.SafeSession, txAccessModes []sqlparser.TxAccessMode) error {
+	if session.InLockTables() {
+		// MySQL releases the table locks of a connection that begins a transaction,
+		// so the session would silently lose the locks on its reserved connections.
+		return vterrors.VT12001("transaction while the session holds table locks, UNLOCK TABLES first")
+	}
 	if session.InTransaction() {
 		if err := txc.Commit(ctx, session); err != nil {
 			return err
@@ -490,6 +495,16 @@ func (txc *TxConn) ReleaseLock(ctx context.Context, session *econtext.SafeSessio
 	return qs.Release(ctx, ls.Target, 0, ls.ReservedId)
 }
 
+// ReleaseLockTables clears the table locks of the session. The reserved connections are released
+// if they were only needed for the table locks, and nothing else was kept on them since.
+func (txc *TxConn) ReleaseLockTables(ctx context.Context, session *econtext.SafeSession) error {
+	if !session.ResetLockTables() || session.InTransaction() || session.HasSystemVariables() || session.GetOptions().GetHasCreatedTempTables() {
+		return nil
+	}
+	defer session.SetReservedConn(false)
+	return txc.Release(ctx, session)
+}
+
 // ReleaseAll releases all the shard sessions and lock session.
 func (txc *TxConn) ReleaseAll(ctx context.Context, session *econtext.SafeSession) error {
 	if !session.InTransaction() && !session.InReservedConn() && !session.InLockSession() {
@@ -0,0 +1,58 @@
+/*
+Copyright 2025 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package vindexes
+
+import (
+	"fmt"
+	"strings"
+)
+
+// TableStatistics are the estimated statistics of a table, as reported by
+// the tablets of a shard. They are used by the planner to estimate the
+// number of rows read and returned by the queries.
+type TableStatistics struct {
+	// RowCount is the estimated number of rows of the table in a shard.
+	RowCount uint64 `json:"row_count"`
+	// Cardinality is the estimated number of distinct values of a column in
+	// a shard, by lower case column name. Only the columns that have an
+	// index or a histogram are known.
+	Cardinality map[string]uint64 `json:"cardinality,omitempty"`
+}
+
+// ColumnCardinality returns the estimated number of distinct values of
+// a column, and whether it is known.
+func (ts *TableStatistics) ColumnCardinality(column string) (uint64, bool) {
+	card, ok := ts.Cardinality[strings.ToLower(column)]
+	if !ok || card == 0 {
+		return 0, false
+	}
+	return min(card, max(ts.RowCount, 1)), true
+}
+
+// SetTableStatistics is for testing only.
+func (vschema *VSchema) SetTableStatistics(ksname, tblName string, stats *TableStatistics) error {
+	ks, ok := vschema.Keyspaces[ksname]
+	if !ok {
+		return fmt.Errorf("keyspace %s not found in vschema", ksname)
+	}
+	tbl, ok := ks.Tables[tblName]
+	if !ok {
+		return fmt.Errorf("table %s not found in keyspace %s", tblName, ksname)
+	}
+	tbl.Statistics = stats
+	return nil
+}
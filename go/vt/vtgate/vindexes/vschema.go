@@ -84,6 +84,9 @@ type MirrorRule struct {
 	Error   error
 	Percent float32    `json:"percent,omitempty"`
 	Table   *BaseTable `json:"table,omitempty"`
+	// Compare is set if the results of the mirror target should be
+	// compared with those of the source.
+	Compare bool `json:"compare,omitempty"`
 }
 
 // MarshalJSON returns a JSON representation of MirrorRule.
@@ -94,9 +97,11 @@ func (mr *MirrorRule) MarshalJSON() ([]byte, error) {
 	return json.Marshal(struct {
 		Percent float32
 		Table   *BaseTable
+		Compare bool `json:",omitempty"`
 	}{
 		Percent: mr.Percent,
 		Table:   mr.Table,
+		Compare: mr.Compare,
 	})
 }
 
@@ -156,6 +161,14 @@ type BaseTable struct {
 	// MySQL error message: ERROR 3756 (HY000): The primary key cannot be a functional index
 	PrimaryKey sqlparser.Columns  `json:"primary_key,omitempty"`
 	UniqueKeys [][]sqlparser.Expr `json:"unique_keys,omitempty"`
+
+	// Statistics are the row count and cardinality estimates reported
+	// by the schema tracker. They are nil when they are not known.
+	Statistics *TableStatistics `json:"statistics,omitempty"`
+
+	// ResultCacheTTL is how long vtgate caches the results of the read-only
+	// queries to the table on replica and rdonly tablets. Zero disables it.
+	ResultCacheTTL time.Duration `json:"result_cache_ttl,omitempty"`
 }
 
 // GetTableName gets the sqlparser.TableName for the vindex Table.
@@ -187,6 +200,7 @@ type TableInfo struct {
 	Columns     []Column
 	ForeignKeys []*sqlparser.ForeignKeyDefinition
 	Indexes     []*sqlparser.IndexDefinition
+	Statistics  *TableStatistics
 }
 
 // IsUnique is used to tell whether the ColumnVindex
@@ -735,6 +749,15 @@ func buildTables(ks *vschemapb.Keyspace, vschema *VSchema, ksvschema *KeyspaceSc
 			}
 			t.Pinned = decoded
 		}
+		if table.ResultCacheTtlMs < 0 {
+			return vterrors.Errorf(
+				vtrpcpb.Code_INVALID_ARGUMENT,
+				"invalid result cache ttl %d for table: %s",
+				table.ResultCacheTtlMs,
+				tname,
+			)
+		}
+		t.ResultCacheTTL = time.Duration(table.ResultCacheTtlMs) * time.Millisecond
 
 		// If keyspace is sharded, then any table that's not a reference or pinned must have vindexes.
 		if keyspace.Sharded && t.Type != TypeReference && table.Pinned == "" && len(table.ColumnVindexes) == 0 {
@@ -1282,6 +1305,7 @@ func buildMirrorRule(source *vschemapb.SrvVSchema, vschema *VSchema, parser *sql
 		vschema.MirrorRules[rule.FromTable] = &MirrorRule{
 			Table:   t,
 			Percent: rule.Percent,
+			Compare: rule.Compare,
 		}
 
 		//
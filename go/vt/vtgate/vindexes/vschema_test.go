@@ -25,6 +25,7 @@ import (
 	"reflect"
 	"strings"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
@@ -595,6 +596,37 @@ func TestVSchemaColumnsFail(t *testing.T) {
 	require.EqualError(t, got.Keyspaces["unsharded"].Error, "duplicate column name 'c1' for table: t1")
 }
 
+func TestVSchemaResultCacheTTL(t *testing.T) {
+	good := vschemapb.SrvVSchema{
+		Keyspaces: map[string]*vschemapb.Keyspace{
+			"unsharded": {
+				Tables: map[string]*vschemapb.Table{
+					"t1": {
+						ResultCacheTtlMs: 1500},
+					"t2": {}}}}}
+
+	got := BuildVSchema(&good, sqlparser.NewTestParser())
+
+	t1, err := got.FindTable("unsharded", "t1")
+	require.NoError(t, err)
+	assert.Equal(t, 1500*time.Millisecond, t1.ResultCacheTTL)
+	t2, err := got.FindTable("unsharded", "t2")
+	require.NoError(t, err)
+	assert.Zero(t, t2.ResultCacheTTL)
+}
+
+func TestVSchemaResultCacheTTLFail(t *testing.T) {
+	bad := vschemapb.SrvVSchema{
+		Keyspaces: map[string]*vschemapb.Keyspace{
+			"unsharded": {
+				Tables: map[string]*vschemapb.Table{
+					"t1": {
+						ResultCacheTtlMs: -1}}}}}
+
+	got := BuildVSchema(&bad, sqlparser.NewTestParser())
+	require.EqualError(t, got.Keyspaces["unsharded"].Error, "invalid result cache ttl -1 for table: t1")
+}
+
 func TestVSchemaPinned(t *testing.T) {
 	good := vschemapb.SrvVSchema{
 		Keyspaces: map[string]*vschemapb.Keyspace{
@@ -793,11 +825,12 @@ func TestVSchemaMirrorRules(t *testing.T) {
 					FromTable: "ks1.ks1t6@stone",
 					ToTable:   "ks2.ks2t6",
 				},
-				// OK, sharded => sharded.
+				// OK, sharded => sharded, with result comparison.
 				{
 					FromTable: "ks3.ks3t1",
 					ToTable:   "ks4.ks4t1",
 					Percent:   50,
+					Compare:   true,
 				},
 				// OK, unsharded => sharded.
 				{
@@ -965,6 +998,7 @@ func TestVSchemaMirrorRules(t *testing.T) {
 			"ks3.ks3t1": {
 				Table:   ks4t1,
 				Percent: 50,
+				Compare: true,
 			},
 			"ks1.ks1t7": {
 				Table:   ks4t1,
@@ -250,6 +250,7 @@ func (vm *VSchemaManager) updateTableInfo(vschema *vindexes.VSchema, ks *vindexe
 			log.Errorf("unable to find table %s in %s", tblName, ksName)
 			continue
 		}
+		rTbl.Statistics = tblInfo.Statistics
 		for _, fkDef := range tblInfo.ForeignKeys {
 			// Ignore internal tables as part of foreign key references.
 			if schema.IsInternalOperationTableName(fkDef.ReferenceDefinition.ReferencedTable.Name.String()) {
@@ -76,11 +76,25 @@ var (
 	// plan cache related flag
 	queryPlanCacheMemory int64 = 32 * 1024 * 1024 // 32mb
 
+	// result cache related flags
+	resultCacheMemory       int64 = 32 * 1024 * 1024 // 32mb
+	resultCacheInvalidation bool
+
+	// query consolidator related flags
+	enableConsolidator         bool
+	enableConsolidatorReplicas bool
+	consolidatorMaxResultSize  int64 = 16 * 1024 * 1024 // 16mb
+
 	maxMemoryRows   = 300000
 	warnMemoryRows  = 30000
 	maxPayloadSize  int
 	warnPayloadSize int
 
+	// spillMemoryBudget is the number of bytes of rows that the sorts, hash joins
+	// and aggregations of a query can hold in memory before spilling them to disk.
+	spillMemoryBudget int64
+	spillDir          string
+
 	noScatter          bool
 	enableShardRouting bool
 
@@ -151,6 +165,7 @@ var (
 	enableSchemaChangeSignal = true
 	enableViews              = true
 	enableUdfs               bool
+	tableStatisticsInterval  time.Duration
 
 	// vtgate views flags
 	queryTimeout int
@@ -168,6 +183,10 @@ var (
 	warmingReadsPercent      = 0
 	warmingReadsQueryTimeout = 5 * time.Second
 	warmingReadsConcurrency  = 500
+
+	// tenantIDSource is where the tenant of a session comes from, to restrict
+	// the queries to multi-tenant keyspaces to that tenant.
+	tenantIDSource string
 )
 
 func registerFlags(fs *pflag.FlagSet) {
@@ -177,7 +196,14 @@ func registerFlags(fs *pflag.FlagSet) {
 	fs.IntVar(&truncateErrorLen, "truncate-error-len", truncateErrorLen, "truncate errors sent to client if they are longer than this value (0 means do not truncate)")
 	fs.IntVar(&streamBufferSize, "stream_buffer_size", streamBufferSize, "the number of bytes sent from vtgate for each stream call. It's recommended to keep this value in sync with vttablet's query-server-config-stream-buffer-size.")
 	utils.SetFlagInt64Var(fs, &queryPlanCacheMemory, "gate-query-cache-memory", queryPlanCacheMemory, "gate server query cache size in bytes, maximum amount of memory to be cached. vtgate analyzes every incoming query and generate a query plan, these plans are being cached in a lru cache. This config controls the capacity of the lru cache.")
+	fs.Int64Var(&resultCacheMemory, "result-cache-memory", resultCacheMemory, "Maximum amount of memory, in bytes, used to cache the results of the queries to replica and rdonly tablets that opt in with the CACHE_TTL_MS comment directive or the result_cache_ttl_ms of their tables in the vschema. 0 disables the result cache.")
+	fs.BoolVar(&resultCacheInvalidation, "result-cache-invalidation", resultCacheInvalidation, "Invalidate the cached results of a table when its rows change, by streaming the changes of the keyspaces with cached results from their replica tablets. Otherwise the cached results only expire after their TTL.")
+	fs.BoolVar(&enableConsolidator, "gate-enable-consolidator", enableConsolidator, "Enable the vtgate query consolidator, which shares the result of a read-only query in flight with the identical queries, with the same bind variables and target, that arrive before it completes. The CONSOLIDATOR comment directive overrides it for a query, except that it cannot enable it for the queries to primary tablets.")
+	fs.BoolVar(&enableConsolidatorReplicas, "gate-enable-consolidator-replicas", enableConsolidatorReplicas, "Enable the vtgate query consolidator only for the queries to replica and rdonly tablets.")
+	fs.Int64Var(&consolidatorMaxResultSize, "gate-consolidator-max-result-size", consolidatorMaxResultSize, "Maximum size, in bytes, of a result shared by the vtgate query consolidator. The queries waiting for a larger result execute themselves. 0 means no limit.")
 	utils.SetFlagIntVar(fs, &maxMemoryRows, "max-memory-rows", maxMemoryRows, "Maximum number of rows that will be held in memory for intermediate results as well as the final result.")
+	fs.Int64Var(&spillMemoryBudget, "spill-memory-budget", spillMemoryBudget, "Number of bytes of rows that the sorts, hash joins and aggregations of a query can hold in memory before writing them to temporary files. When set, these primitives are no longer limited by max-memory-rows. 0 disables spilling to disk.")
+	fs.StringVar(&spillDir, "spill-dir", spillDir, "Directory for the temporary files of the queries that exceed the spill-memory-budget. Defaults to the system's temporary directory.")
 	utils.SetFlagIntVar(fs, &warnMemoryRows, "warn-memory-rows", warnMemoryRows, "Warning threshold for in-memory results. A row count higher than this amount will cause the VtGateWarnings.ResultsExceeded counter to be incremented.")
 	utils.SetFlagStringVar(fs, &defaultDDLStrategy, "ddl-strategy", defaultDDLStrategy, "Set default strategy for DDL statements. Override with @@ddl_strategy session variable")
 	utils.SetFlagStringVar(fs, &dbDDLPlugin, "dbddl-plugin", dbDDLPlugin, "controls how to handle CREATE/DROP DATABASE. use it if you are using your own database provisioning service")
@@ -201,7 +227,9 @@ func registerFlags(fs *pflag.FlagSet) {
 	utils.SetFlagDurationVar(fs, &messageStreamGracePeriod, "message-stream-grace-period", messageStreamGracePeriod, "the amount of time to give for a vttablet to resume if it ends a message stream, usually because of a reparent.")
 	fs.BoolVar(&enableViews, "enable-views", enableViews, "Enable views support in vtgate.")
 	fs.BoolVar(&enableUdfs, "track-udfs", enableUdfs, "Track UDFs in vtgate.")
+	fs.DurationVar(&tableStatisticsInterval, "table-statistics-refresh-interval", tableStatisticsInterval, "If set, the schema tracker loads the row count and column cardinality estimates of the tables, refreshes them at this interval, and the planner uses them to compare the cost of its plans. Requires the schema tracker.")
 	fs.BoolVar(&allowKillStmt, "allow-kill-statement", allowKillStmt, "Allows the execution of kill statement")
+	fs.StringVar(&tenantIDSource, "tenant-id-source", tenantIDSource, "Restrict the queries to keyspaces with a multi_tenant_spec to the rows of the tenant of the session, taken from: session (the @@tenant_id session variable), caller (the username of the authenticated caller). Empty disables the restriction.")
 	fs.IntVar(&warmingReadsPercent, "warming-reads-percent", 0, "Percentage of reads on the primary to forward to replicas. Useful for keeping buffer pools warm")
 	fs.IntVar(&warmingReadsConcurrency, "warming-reads-concurrency", 500, "Number of concurrent warming reads allowed")
 	fs.DurationVar(&warmingReadsQueryTimeout, "warming-reads-query-timeout", 5*time.Second, "Timeout of warming read queries")
@@ -342,6 +370,11 @@ func Init(
 	if _, err := schema.ParseDDLStrategy(defaultDDLStrategy); err != nil {
 		log.Fatalf("Invalid value for -ddl-strategy: %v", err.Error())
 	}
+	switch tenantIDSource {
+	case "", econtext.TenantIDFromSession, econtext.TenantIDFromCaller:
+	default:
+		log.Fatalf("Invalid value for -tenant-id-source: %v", tenantIDSource)
+	}
 	tc := NewTxConn(gw, dynamicConfig)
 	// ScatterConn depends on TxConn to perform forced rollbacks.
 	sc := NewScatterConn("VttabletCall", tc, gw)
@@ -369,6 +402,9 @@ func Init(
 	var st *vtschema.Tracker
 	if enableSchemaChangeSignal {
 		st = vtschema.NewTracker(gw.hc.Subscribe(schemaTrackerHcName), enableViews, enableUdfs, env.Parser())
+		if tableStatisticsInterval > 0 {
+			st.TrackStatistics(tableStatisticsInterval)
+		}
 		addKeyspacesToTracker(ctx, srvResolver, st, gw)
 		si = st
 	}
@@ -384,6 +420,9 @@ func Init(
 	}
 
 	executor := NewExecutor(ctx, env, serv, cell, resolver, eConfig, warnShardedOnly, plans, si, pv, dynamicConfig)
+	if resultCacheInvalidation && executor.resultCache != nil {
+		executor.resultCache.enableInvalidation(ctx, vsm)
+	}
 
 	if err := executor.defaultQueryLogger(); err != nil {
 		log.Fatalf("error initializing query logger: %v", err)
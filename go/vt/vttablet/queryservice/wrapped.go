@@ -31,10 +31,11 @@ var _ QueryService = &wrappedService{}
 
 // WrapperFunc defines the signature for the wrapper function used by Wrap.
 // Parameter ordering is as follows: original parameters, connection, method name, additional parameters and inner func.
+// The streaming parameter is true for the methods that stream their results.
 // The inner function returns err and canRetry.
 // If canRetry is true, the error is specific to the current vttablet and can be retried elsewhere.
 // The flag will be false if there was no error.
-type WrapperFunc func(ctx context.Context, target *querypb.Target, conn QueryService, name string, inTransaction bool, inner func(context.Context, *querypb.Target, QueryService) (canRetry bool, err error)) error
+type WrapperFunc func(ctx context.Context, target *querypb.Target, conn QueryService, name string, inTransaction bool, streaming bool, inner func(context.Context, *querypb.Target, QueryService) (canRetry bool, err error)) error
 
 // Wrap returns a wrapped version of the original QueryService implementation.
 // This lets you avoid repeating boiler-plate code by consolidating it in the
@@ -110,7 +111,7 @@ type wrappedService struct {
 }
 
 func (ws *wrappedService) Begin(ctx context.Context, target *querypb.Target, options *querypb.ExecuteOptions) (state TransactionState, err error) {
-	err = ws.wrapper(ctx, target, ws.impl, "Begin", false, func(ctx context.Context, target *querypb.Target, conn QueryService) (bool, error) {
+	err = ws.wrapper(ctx, target, ws.impl, "Begin", false, false, func(ctx context.Context, target *querypb.Target, conn QueryService) (bool, error) {
 		var innerErr error
 		state, innerErr = conn.Begin(ctx, target, options)
 		return canRetry(ctx, innerErr), innerErr
@@ -120,7 +121,7 @@ func (ws *wrappedService) Begin(ctx context.Context, target *querypb.Target, opt
 
 func (ws *wrappedService) Commit(ctx context.Context, target *querypb.Target, transactionID int64) (int64, error) {
 	var rID int64
-	err := ws.wrapper(ctx, target, ws.impl, "Commit", true, func(ctx context.Context, target *querypb.Target, conn QueryService) (bool, error) {
+	err := ws.wrapper(ctx, target, ws.impl, "Commit", true, false, func(ctx context.Context, target *querypb.Target, conn QueryService) (bool, error) {
 		var innerErr error
 		rID, innerErr = conn.Commit(ctx, target, transactionID)
 		return canRetry(ctx, innerErr), innerErr
@@ -133,7 +134,7 @@ func (ws *wrappedService) Commit(ctx context.Context, target *querypb.Target, tr
 
 func (ws *wrappedService) Rollback(ctx context.Context, target *querypb.Target, transactionID int64) (int64, error) {
 	var rID int64
-	err := ws.wrapper(ctx, target, ws.impl, "Rollback", true, func(ctx context.Context, target *querypb.Target, conn QueryService) (bool, error) {
+	err := ws.wrapper(ctx, target, ws.impl, "Rollback", true, false, func(ctx context.Context, target *querypb.Target, conn QueryService) (bool, error) {
 		var innerErr error
 		rID, innerErr = conn.Rollback(ctx, target, transactionID)
 		return canRetry(ctx, innerErr), innerErr
@@ -145,7 +146,7 @@ func (ws *wrappedService) Rollback(ctx context.Context, target *querypb.Target,
 }
 
 func (ws *wrappedService) Prepare(ctx context.Context, target *querypb.Target, transactionID int64, dtid string) error {
-	err := ws.wrapper(ctx, target, ws.impl, "Prepare", true, func(ctx context.Context, target *querypb.Target, conn QueryService) (bool, error) {
+	err := ws.wrapper(ctx, target, ws.impl, "Prepare", true, false, func(ctx context.Context, target *querypb.Target, conn QueryService) (bool, error) {
 		innerErr := conn.Prepare(ctx, target, transactionID, dtid)
 		return canRetry(ctx, innerErr), innerErr
 	})
@@ -153,7 +154,7 @@ func (ws *wrappedService) Prepare(ctx context.Context, target *querypb.Target, t
 }
 
 func (ws *wrappedService) CommitPrepared(ctx context.Context, target *querypb.Target, dtid string) (err error) {
-	err = ws.wrapper(ctx, target, ws.impl, "CommitPrepared", true, func(ctx context.Context, target *querypb.Target, conn QueryService) (bool, error) {
+	err = ws.wrapper(ctx, target, ws.impl, "CommitPrepared", true, false, func(ctx context.Context, target *querypb.Target, conn QueryService) (bool, error) {
 		innerErr := conn.CommitPrepared(ctx, target, dtid)
 		return canRetry(ctx, innerErr), innerErr
 	})
@@ -161,7 +162,7 @@ func (ws *wrappedService) CommitPrepared(ctx context.Context, target *querypb.Ta
 }
 
 func (ws *wrappedService) RollbackPrepared(ctx context.Context, target *querypb.Target, dtid string, originalID int64) (err error) {
-	err = ws.wrapper(ctx, target, ws.impl, "RollbackPrepared", true, func(ctx context.Context, target *querypb.Target, conn QueryService) (bool, error) {
+	err = ws.wrapper(ctx, target, ws.impl, "RollbackPrepared", true, false, func(ctx context.Context, target *querypb.Target, conn QueryService) (bool, error) {
 		innerErr := conn.RollbackPrepared(ctx, target, dtid, originalID)
 		return canRetry(ctx, innerErr), innerErr
 	})
@@ -169,7 +170,7 @@ func (ws *wrappedService) RollbackPrepared(ctx context.Context, target *querypb.
 }
 
 func (ws *wrappedService) CreateTransaction(ctx context.Context, target *querypb.Target, dtid string, participants []*querypb.Target) (err error) {
-	err = ws.wrapper(ctx, target, ws.impl, "CreateTransaction", true, func(ctx context.Context, target *querypb.Target, conn QueryService) (bool, error) {
+	err = ws.wrapper(ctx, target, ws.impl, "CreateTransaction", true, false, func(ctx context.Context, target *querypb.Target, conn QueryService) (bool, error) {
 		innerErr := conn.CreateTransaction(ctx, target, dtid, participants)
 		return canRetry(ctx, innerErr), innerErr
 	})
@@ -177,7 +178,7 @@ func (ws *wrappedService) CreateTransaction(ctx context.Context, target *querypb
 }
 
 func (ws *wrappedService) StartCommit(ctx context.Context, target *querypb.Target, transactionID int64, dtid string) (state querypb.StartCommitState, err error) {
-	err = ws.wrapper(ctx, target, ws.impl, "StartCommit", true, func(ctx context.Context, target *querypb.Target, conn QueryService) (bool, error) {
+	err = ws.wrapper(ctx, target, ws.impl, "StartCommit", true, false, func(ctx context.Context, target *querypb.Target, conn QueryService) (bool, error) {
 		var innerErr error
 		state, innerErr = conn.StartCommit(ctx, target, transactionID, dtid)
 		return canRetry(ctx, innerErr), innerErr
@@ -186,7 +187,7 @@ func (ws *wrappedService) StartCommit(ctx context.Context, target *querypb.Targe
 }
 
 func (ws *wrappedService) SetRollback(ctx context.Context, target *querypb.Target, dtid string, transactionID int64) (err error) {
-	err = ws.wrapper(ctx, target, ws.impl, "SetRollback", true, func(ctx context.Context, target *querypb.Target, conn QueryService) (bool, error) {
+	err = ws.wrapper(ctx, target, ws.impl, "SetRollback", true, false, func(ctx context.Context, target *querypb.Target, conn QueryService) (bool, error) {
 		innerErr := conn.SetRollback(ctx, target, dtid, transactionID)
 		return canRetry(ctx, innerErr), innerErr
 	})
@@ -194,7 +195,7 @@ func (ws *wrappedService) SetRollback(ctx context.Context, target *querypb.Targe
 }
 
 func (ws *wrappedService) ConcludeTransaction(ctx context.Context, target *querypb.Target, dtid string) (err error) {
-	err = ws.wrapper(ctx, target, ws.impl, "ConcludeTransaction", true, func(ctx context.Context, target *querypb.Target, conn QueryService) (bool, error) {
+	err = ws.wrapper(ctx, target, ws.impl, "ConcludeTransaction", true, false, func(ctx context.Context, target *querypb.Target, conn QueryService) (bool, error) {
 		innerErr := conn.ConcludeTransaction(ctx, target, dtid)
 		return canRetry(ctx, innerErr), innerErr
 	})
@@ -202,7 +203,7 @@ func (ws *wrappedService) ConcludeTransaction(ctx context.Context, target *query
 }
 
 func (ws *wrappedService) ReadTransaction(ctx context.Context, target *querypb.Target, dtid string) (metadata *querypb.TransactionMetadata, err error) {
-	err = ws.wrapper(ctx, target, ws.impl, "ReadTransaction", false, func(ctx context.Context, target *querypb.Target, conn QueryService) (bool, error) {
+	err = ws.wrapper(ctx, target, ws.impl, "ReadTransaction", false, false, func(ctx context.Context, target *querypb.Target, conn QueryService) (bool, error) {
 		var innerErr error
 		metadata, innerErr = conn.ReadTransaction(ctx, target, dtid)
 		return canRetry(ctx, innerErr), innerErr
@@ -211,7 +212,7 @@ func (ws *wrappedService) ReadTransaction(ctx context.Context, target *querypb.T
 }
 
 func (ws *wrappedService) UnresolvedTransactions(ctx context.Context, target *querypb.Target, abandonAgeSeconds int64) (transactions []*querypb.TransactionMetadata, err error) {
-	err = ws.wrapper(ctx, target, ws.impl, "UnresolvedTransactions", false, func(ctx context.Context, target *querypb.Target, conn QueryService) (bool, error) {
+	err = ws.wrapper(ctx, target, ws.impl, "UnresolvedTransactions", false, false, func(ctx context.Context, target *querypb.Target, conn QueryService) (bool, error) {
 		var innerErr error
 		transactions, innerErr = conn.UnresolvedTransactions(ctx, target, abandonAgeSeconds)
 		return canRetry(ctx, innerErr), innerErr
@@ -221,7 +222,7 @@ func (ws *wrappedService) UnresolvedTransactions(ctx context.Context, target *qu
 
 func (ws *wrappedService) Execute(ctx context.Context, target *querypb.Target, query string, bindVars map[string]*querypb.BindVariable, transactionID, reservedID int64, options *querypb.ExecuteOptions) (qr *sqltypes.Result, err error) {
 	inDedicatedConn := transactionID != 0 || reservedID != 0
-	err = ws.wrapper(ctx, target, ws.impl, "Execute", inDedicatedConn, func(ctx context.Context, target *querypb.Target, conn QueryService) (bool, error) {
+	err = ws.wrapper(ctx, target, ws.impl, "Execute", inDedicatedConn, false, func(ctx context.Context, target *querypb.Target, conn QueryService) (bool, error) {
 		var innerErr error
 		qr, innerErr = conn.Execute(ctx, target, query, bindVars, transactionID, reservedID, options)
 		// You cannot retry if you're in a transaction.
@@ -234,7 +235,7 @@ func (ws *wrappedService) Execute(ctx context.Context, target *querypb.Target, q
 // StreamExecute implements the QueryService interface
 func (ws *wrappedService) StreamExecute(ctx context.Context, target *querypb.Target, query string, bindVars map[string]*querypb.BindVariable, transactionID int64, reservedID int64, options *querypb.ExecuteOptions, callback func(*sqltypes.Result) error) error {
 	inDedicatedConn := transactionID != 0 || reservedID != 0
-	err := ws.wrapper(ctx, target, ws.impl, "StreamExecute", inDedicatedConn, func(ctx context.Context, target *querypb.Target, conn QueryService) (bool, error) {
+	err := ws.wrapper(ctx, target, ws.impl, "StreamExecute", inDedicatedConn, true, func(ctx context.Context, target *querypb.Target, conn QueryService) (bool, error) {
 		streamingStarted := false
 		innerErr := conn.StreamExecute(ctx, target, query, bindVars, transactionID, reservedID, options, func(qr *sqltypes.Result) error {
 			streamingStarted = true
@@ -249,7 +250,7 @@ func (ws *wrappedService) StreamExecute(ctx context.Context, target *querypb.Tar
 
 func (ws *wrappedService) BeginExecute(ctx context.Context, target *querypb.Target, preQueries []string, query string, bindVars map[string]*querypb.BindVariable, reservedID int64, options *querypb.ExecuteOptions) (state TransactionState, qr *sqltypes.Result, err error) {
 	inDedicatedConn := reservedID != 0
-	err = ws.wrapper(ctx, target, ws.impl, "BeginExecute", inDedicatedConn, func(ctx context.Context, target *querypb.Target, conn QueryService) (bool, error) {
+	err = ws.wrapper(ctx, target, ws.impl, "BeginExecute", inDedicatedConn, false, func(ctx context.Context, target *querypb.Target, conn QueryService) (bool, error) {
 		var innerErr error
 		state, qr, innerErr = conn.BeginExecute(ctx, target, preQueries, query, bindVars, reservedID, options)
 		return canRetry(ctx, innerErr) && !inDedicatedConn, innerErr
@@ -260,7 +261,7 @@ func (ws *wrappedService) BeginExecute(ctx context.Context, target *querypb.Targ
 // BeginStreamExecute implements the QueryService interface
 func (ws *wrappedService) BeginStreamExecute(ctx context.Context, target *querypb.Target, preQueries []string, query string, bindVars map[string]*querypb.BindVariable, reservedID int64, options *querypb.ExecuteOptions, callback func(*sqltypes.Result) error) (state TransactionState, err error) {
 	inDedicatedConn := reservedID != 0
-	err = ws.wrapper(ctx, target, ws.impl, "BeginStreamExecute", inDedicatedConn, func(ctx context.Context, target *querypb.Target, conn QueryService) (bool, error) {
+	err = ws.wrapper(ctx, target, ws.impl, "BeginStreamExecute", inDedicatedConn, true, func(ctx context.Context, target *querypb.Target, conn QueryService) (bool, error) {
 		var innerErr error
 		state, innerErr = conn.BeginStreamExecute(ctx, target, preQueries, query, bindVars, reservedID, options, callback)
 		return canRetry(ctx, innerErr) && !inDedicatedConn, innerErr
@@ -269,14 +270,14 @@ func (ws *wrappedService) BeginStreamExecute(ctx context.Context, target *queryp
 }
 
 func (ws *wrappedService) MessageStream(ctx context.Context, target *querypb.Target, name string, callback func(*sqltypes.Result) error) error {
-	return ws.wrapper(ctx, target, ws.impl, "MessageStream", false, func(ctx context.Context, target *querypb.Target, conn QueryService) (bool, error) {
+	return ws.wrapper(ctx, target, ws.impl, "MessageStream", false, true, func(ctx context.Context, target *querypb.Target, conn QueryService) (bool, error) {
 		innerErr := conn.MessageStream(ctx, target, name, callback)
 		return canRetry(ctx, innerErr), innerErr
 	})
 }
 
 func (ws *wrappedService) MessageAck(ctx context.Context, target *querypb.Target, name string, ids []*querypb.Value) (count int64, err error) {
-	err = ws.wrapper(ctx, target, ws.impl, "MessageAck", false, func(ctx context.Context, target *querypb.Target, conn QueryService) (bool, error) {
+	err = ws.wrapper(ctx, target, ws.impl, "MessageAck", false, false, func(ctx context.Context, target *querypb.Target, conn QueryService) (bool, error) {
 		var innerErr error
 		count, innerErr = conn.MessageAck(ctx, target, name, ids)
 		return canRetry(ctx, innerErr), innerErr
@@ -285,35 +286,35 @@ func (ws *wrappedService) MessageAck(ctx context.Context, target *querypb.Target
 }
 
 func (ws *wrappedService) VStream(ctx context.Context, request *binlogdatapb.VStreamRequest, send func([]*binlogdatapb.VEvent) error) error {
-	return ws.wrapper(ctx, request.Target, ws.impl, "VStream", false, func(ctx context.Context, target *querypb.Target, conn QueryService) (bool, error) {
+	return ws.wrapper(ctx, request.Target, ws.impl, "VStream", false, true, func(ctx context.Context, target *querypb.Target, conn QueryService) (bool, error) {
 		innerErr := conn.VStream(ctx, request, send)
 		return false, innerErr
 	})
 }
 
 func (ws *wrappedService) VStreamRows(ctx context.Context, request *binlogdatapb.VStreamRowsRequest, send func(*binlogdatapb.VStreamRowsResponse) error) error {
-	return ws.wrapper(ctx, request.Target, ws.impl, "VStreamRows", false, func(ctx context.Context, target *querypb.Target, conn QueryService) (bool, error) {
+	return ws.wrapper(ctx, request.Target, ws.impl, "VStreamRows", false, true, func(ctx context.Context, target *querypb.Target, conn QueryService) (bool, error) {
 		innerErr := conn.VStreamRows(ctx, request, send)
 		return false, innerErr
 	})
 }
 
 func (ws *wrappedService) VStreamTables(ctx context.Context, request *binlogdatapb.VStreamTablesRequest, send func(response *binlogdatapb.VStreamTablesResponse) error) error {
-	return ws.wrapper(ctx, request.Target, ws.impl, "VStreamTables", false, func(ctx context.Context, target *querypb.Target, conn QueryService) (bool, error) {
+	return ws.wrapper(ctx, request.Target, ws.impl, "VStreamTables", false, true, func(ctx context.Context, target *querypb.Target, conn QueryService) (bool, error) {
 		innerErr := conn.VStreamTables(ctx, request, send)
 		return false, innerErr
 	})
 }
 
 func (ws *wrappedService) VStreamResults(ctx context.Context, target *querypb.Target, query string, send func(*binlogdatapb.VStreamResultsResponse) error) error {
-	return ws.wrapper(ctx, target, ws.impl, "VStreamResults", false, func(ctx context.Context, target *querypb.Target, conn QueryService) (bool, error) {
+	return ws.wrapper(ctx, target, ws.impl, "VStreamResults", false, true, func(ctx context.Context, target *querypb.Target, conn QueryService) (bool, error) {
 		innerErr := conn.VStreamResults(ctx, target, query, send)
 		return false, innerErr
 	})
 }
 
 func (ws *wrappedService) StreamHealth(ctx context.Context, callback func(*querypb.StreamHealthResponse) error) error {
-	return ws.wrapper(ctx, nil, ws.impl, "StreamHealth", false, func(ctx context.Context, target *querypb.Target, conn QueryService) (bool, error) {
+	return ws.wrapper(ctx, nil, ws.impl, "StreamHealth", false, true, func(ctx context.Context, target *querypb.Target, conn QueryService) (bool, error) {
 		innerErr := conn.StreamHealth(ctx, callback)
 		return canRetry(ctx, innerErr), innerErr
 	})
@@ -325,7 +326,7 @@ func (ws *wrappedService) HandlePanic(err *error) {
 
 // ReserveBeginExecute implements the QueryService interface
 func (ws *wrappedService) ReserveBeginExecute(ctx context.Context, target *querypb.Target, preQueries []string, postBeginQueries []string, sql string, bindVariables map[string]*querypb.BindVariable, options *querypb.ExecuteOptions) (state ReservedTransactionState, res *sqltypes.Result, err error) {
-	err = ws.wrapper(ctx, target, ws.impl, "ReserveBeginExecute", false, func(ctx context.Context, target *querypb.Target, conn QueryService) (bool, error) {
+	err = ws.wrapper(ctx, target, ws.impl, "ReserveBeginExecute", false, false, func(ctx context.Context, target *querypb.Target, conn QueryService) (bool, error) {
 		var err error
 		state, res, err = conn.ReserveBeginExecute(ctx, target, preQueries, postBeginQueries, sql, bindVariables, options)
 		return canRetry(ctx, err), err
@@ -336,7 +337,7 @@ func (ws *wrappedService) ReserveBeginExecute(ctx context.Context, target *query
 
 // ReserveBeginStreamExecute implements the QueryService interface
 func (ws *wrappedService) ReserveBeginStreamExecute(ctx context.Context, target *querypb.Target, preQueries []string, postBeginQueries []string, sql string, bindVariables map[string]*querypb.BindVariable, options *querypb.ExecuteOptions, callback func(*sqltypes.Result) error) (state ReservedTransactionState, err error) {
-	err = ws.wrapper(ctx, target, ws.impl, "ReserveBeginStreamExecute", false, func(ctx context.Context, target *querypb.Target, conn QueryService) (bool, error) {
+	err = ws.wrapper(ctx, target, ws.impl, "ReserveBeginStreamExecute", false, true, func(ctx context.Context, target *querypb.Target, conn QueryService) (bool, error) {
 		var innerErr error
 		state, innerErr = conn.ReserveBeginStreamExecute(ctx, target, preQueries, postBeginQueries, sql, bindVariables, options, callback)
 		return canRetry(ctx, innerErr), innerErr
@@ -347,7 +348,7 @@ func (ws *wrappedService) ReserveBeginStreamExecute(ctx context.Context, target
 // ReserveExecute implements the QueryService interface
 func (ws *wrappedService) ReserveExecute(ctx context.Context, target *querypb.Target, preQueries []string, sql string, bindVariables map[string]*querypb.BindVariable, transactionID int64, options *querypb.ExecuteOptions) (state ReservedState, res *sqltypes.Result, err error) {
 	inDedicatedConn := transactionID != 0
-	err = ws.wrapper(ctx, target, ws.impl, "ReserveExecute", inDedicatedConn, func(ctx context.Context, target *querypb.Target, conn QueryService) (bool, error) {
+	err = ws.wrapper(ctx, target, ws.impl, "ReserveExecute", inDedicatedConn, false, func(ctx context.Context, target *querypb.Target, conn QueryService) (bool, error) {
 		var err error
 		state, res, err = conn.ReserveExecute(ctx, target, preQueries, sql, bindVariables, transactionID, options)
 		return canRetry(ctx, err) && !inDedicatedConn, err
@@ -359,7 +360,7 @@ func (ws *wrappedService) ReserveExecute(ctx context.Context, target *querypb.Ta
 // ReserveStreamExecute implements the QueryService interface
 func (ws *wrappedService) ReserveStreamExecute(ctx context.Context, target *querypb.Target, preQueries []string, sql string, bindVariables map[string]*querypb.BindVariable, transactionID int64, options *querypb.ExecuteOptions, callback func(*sqltypes.Result) error) (state ReservedState, err error) {
 	inDedicatedConn := transactionID != 0
-	err = ws.wrapper(ctx, target, ws.impl, "ReserveStreamExecute", inDedicatedConn, func(ctx context.Context, target *querypb.Target, conn QueryService) (bool, error) {
+	err = ws.wrapper(ctx, target, ws.impl, "ReserveStreamExecute", inDedicatedConn, true, func(ctx context.Context, target *querypb.Target, conn QueryService) (bool, error) {
 		var innerErr error
 		state, innerErr = conn.ReserveStreamExecute(ctx, target, preQueries, sql, bindVariables, transactionID, options, callback)
 		return canRetry(ctx, innerErr) && !inDedicatedConn, innerErr
@@ -369,14 +370,14 @@ func (ws *wrappedService) ReserveStreamExecute(ctx context.Context, target *quer
 
 func (ws *wrappedService) Release(ctx context.Context, target *querypb.Target, transactionID, reservedID int64) error {
 	inDedicatedConn := transactionID != 0 || reservedID != 0
-	return ws.wrapper(ctx, target, ws.impl, "Release", inDedicatedConn, func(ctx context.Context, target *querypb.Target, conn QueryService) (bool, error) {
+	return ws.wrapper(ctx, target, ws.impl, "Release", inDedicatedConn, false, func(ctx context.Context, target *querypb.Target, conn QueryService) (bool, error) {
 		// No point retrying Release.
 		return false, conn.Release(ctx, target, transactionID, reservedID)
 	})
 }
 
 func (ws *wrappedService) GetSchema(ctx context.Context, target *querypb.Target, tableType querypb.SchemaTableType, tableNames []string, callback func(schemaRes *querypb.GetSchemaResponse) error) (err error) {
-	err = ws.wrapper(ctx, target, ws.impl, "GetSchema", false, func(ctx context.Context, target *querypb.Target, conn QueryService) (bool, error) {
+	err = ws.wrapper(ctx, target, ws.impl, "GetSchema", false, false, func(ctx context.Context, target *querypb.Target, conn QueryService) (bool, error) {
 		innerErr := conn.GetSchema(ctx, target, tableType, tableNames, callback)
 		return canRetry(ctx, innerErr), innerErr
 	})
@@ -384,7 +385,7 @@ func (ws *wrappedService) GetSchema(ctx context.Context, target *querypb.Target,
 }
 
 func (ws *wrappedService) Close(ctx context.Context) error {
-	return ws.wrapper(ctx, nil, ws.impl, "Close", false, func(ctx context.Context, target *querypb.Target, conn QueryService) (bool, error) {
+	return ws.wrapper(ctx, nil, ws.impl, "Close", false, false, func(ctx context.Context, target *querypb.Target, conn QueryService) (bool, error) {
 		// No point retrying Close.
 		return false, conn.Close(ctx)
 	})
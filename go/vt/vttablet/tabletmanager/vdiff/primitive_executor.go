@@ -91,6 +91,11 @@ func (pe *primitiveExecutor) next() ([]sqltypes.Value, error) {
 	return row, nil
 }
 
+// unread puts back a row returned by next, so that the next call returns it again.
+func (pe *primitiveExecutor) unread(row []sqltypes.Value) {
+	pe.rows = append([][]sqltypes.Value{row}, pe.rows...)
+}
+
 // drain fastforward's a shard to process (and ignore) everything from its results stream and return a count of the
 // discarded rows.
 func (pe *primitiveExecutor) drain(ctx context.Context) (int64, error) {
@@ -0,0 +1,414 @@
+/*
+Copyright 2025 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package vdiff
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strconv"
+	"sync"
+
+	"vitess.io/vitess/go/sqltypes"
+	"vitess.io/vitess/go/vt/binlog/binlogplayer"
+	"vitess.io/vitess/go/vt/key"
+	"vitess.io/vitess/go/vt/sqlparser"
+	"vitess.io/vitess/go/vt/topo"
+	"vitess.io/vitess/go/vt/topo/topoproto"
+	"vitess.io/vitess/go/vt/vterrors"
+
+	querypb "vitess.io/vitess/go/vt/proto/query"
+	tabletmanagerdatapb "vitess.io/vitess/go/vt/proto/tabletmanagerdata"
+)
+
+// chunkRows is the number of rows in the primary key ranges that the tables
+// are split in by the checksum and sampling modes.
+const chunkRows = 100000
+
+/*
+	The checksum and sampling modes diff a table one range of primary key values at a time.
+	The ranges are computed on the target, by walking the primary key index.
+		* With the checksum mode, the rows of every range are checksummed by the source and target
+			MySQL servers, and only the ranges whose checksums differ are diffed row by row. The source
+			tablets must be replicas: their replication and the workflow are stopped at the same position
+			while the table is diffed, so the checksums and the row diffs see the same rows.
+		* With the sampling mode, only a random sample of the ranges is diffed.
+	The ranges that are diffed row by row use the consistent snapshots of a regular diff, with
+	the start of the range as the lastpk of the streams, and stop at the end of the range.
+*/
+
+// tableChunker splits a table in ranges of primary key values, and computes their checksums.
+type tableChunker struct {
+	td *tableDiffer
+
+	// targetPKs are the primary key columns of the target table.
+	targetPKs []sqlparser.Expr
+	// targetCols are the target expressions that are checksummed.
+	targetCols  []sqlparser.Expr
+	targetFrom  sqlparser.TableExprs
+	targetWhere sqlparser.Expr
+
+	// The source fields are set by initChecksum.
+	sourcePKs   []sqlparser.Expr
+	sourceCols  []sqlparser.Expr
+	sourceFrom  sqlparser.TableExprs
+	sourceWhere sqlparser.Expr
+}
+
+// rangeChecksum is the checksum of the rows of a primary key range: the sums,
+// modulo 2^64, of the two halves of the MD5 digests of the rows. Unlike a XOR,
+// the sums do not cancel out the digests of duplicate rows, and the checksums
+// of the source shards can be added up in any order.
+type rangeChecksum struct {
+	rows     int64
+	checksum [2]uint64
+}
+
+// add adds the checksum of other rows to the checksum.
+func (rc *rangeChecksum) add(other rangeChecksum) {
+	rc.rows += other.rows
+	rc.checksum[0] += other.checksum[0]
+	rc.checksum[1] += other.checksum[1]
+}
+
+// newTableChunker returns a tableChunker for the table, or an error if the
+// table cannot be diffed one primary key range at a time.
+func (td *tableDiffer) newTableChunker() (*tableChunker, error) {
+	tp := td.tablePlan
+	if len(tp.aggregates) != 0 {
+		return nil, fmt.Errorf("the workflow filter of table %s has aggregate functions", td.table.Name)
+	}
+	if !slices.Equal(tp.pkCols, tp.sourcePkCols) {
+		return nil, fmt.Errorf("table %s has different primary keys on the source and target", td.table.Name)
+	}
+	targetSelect, err := td.parseSelect(tp.targetQuery)
+	if err != nil {
+		return nil, err
+	}
+	tc := &tableChunker{
+		td: td,
+		targetFrom: sqlparser.TableExprs{&sqlparser.AliasedTableExpr{
+			Expr: sqlparser.TableName{
+				Name:      sqlparser.NewIdentifierCS(td.table.Name),
+				Qualifier: sqlparser.NewIdentifierCS(tp.dbName),
+			},
+		}},
+	}
+	if targetSelect.Where != nil {
+		tc.targetWhere = targetSelect.Where.Expr
+	}
+	for _, col := range targetSelect.GetColumns() {
+		tc.targetCols = append(tc.targetCols, col.(*sqlparser.AliasedExpr).Expr)
+	}
+	for _, i := range tp.pkCols {
+		tc.targetPKs = append(tc.targetPKs, tc.targetCols[i])
+	}
+	return tc, nil
+}
+
+// initChecksum prepares the checksum of the source rows, and returns an error if
+// the rows of a range cannot be checksummed by the source MySQL servers.
+func (tc *tableChunker) initChecksum() error {
+	td := tc.td
+	sourceSelect, err := td.parseSelect(td.tablePlan.sourceQuery)
+	if err != nil {
+		return err
+	}
+	for _, col := range sourceSelect.GetColumns() {
+		expr := col.(*sqlparser.AliasedExpr).Expr
+		if hasVStreamerFunc(expr) {
+			return fmt.Errorf("column %s of table %s is computed by vstreamer", sqlparser.String(expr), td.table.Name)
+		}
+		tc.sourceCols = append(tc.sourceCols, expr)
+	}
+	for _, i := range td.tablePlan.pkCols {
+		col, ok := tc.sourceCols[i].(*sqlparser.ColName)
+		if !ok {
+			return fmt.Errorf("primary key column %s of table %s is an expression on the source", sqlparser.String(tc.sourceCols[i]), td.table.Name)
+		}
+		tc.sourcePKs = append(tc.sourcePKs, col)
+	}
+	tc.sourceFrom = sourceSelect.From
+	if sourceSelect.Where == nil {
+		return nil
+	}
+	for _, expr := range sqlparser.SplitAndExpression(nil, sourceSelect.Where.Expr) {
+		if fn, ok := expr.(*sqlparser.FuncExpr); ok && fn.Name.EqualString("in_keyrange") {
+			// in_keyrange() is evaluated by vstreamer, so it can only be left out of the
+			// checksum if it selects all the rows of every source shard.
+			if err := tc.checkKeyRangeFilter(fn); err != nil {
+				return err
+			}
+			continue
+		}
+		if hasVStreamerFunc(expr) {
+			return fmt.Errorf("the workflow filter %s of table %s is evaluated by vstreamer", sqlparser.String(expr), td.table.Name)
+		}
+		tc.sourceWhere = sqlparser.AndExpressions(tc.sourceWhere, expr)
+	}
+	return nil
+}
+
+// checkKeyRangeFilter returns an error if the key range of the in_keyrange() filter
+// does not hold the key ranges of all the source shards.
+func (tc *tableChunker) checkKeyRangeFilter(fn *sqlparser.FuncExpr) error {
+	if len(fn.Exprs) == 0 {
+		return fmt.Errorf("unexpected filter: %s", sqlparser.String(fn))
+	}
+	lit, ok := fn.Exprs[len(fn.Exprs)-1].(*sqlparser.Literal)
+	if !ok || lit.Type != sqlparser.StrVal {
+		return fmt.Errorf("unexpected filter: %s", sqlparser.String(fn))
+	}
+	krs, err := key.ParseShardingSpec(lit.Val)
+	if err != nil {
+		return err
+	}
+	if len(krs) != 1 {
+		return fmt.Errorf("unexpected key range in filter: %s", sqlparser.String(fn))
+	}
+	for shard := range tc.td.wd.ct.sources {
+		_, shardKeyRange, err := topo.ValidateShardName(shard)
+		if err != nil {
+			return err
+		}
+		if shardKeyRange == nil {
+			shardKeyRange = key.NewCompleteKeyRange()
+		}
+		if !key.KeyRangeContainsKeyRange(krs[0], shardKeyRange) {
+			return fmt.Errorf("the workflow filter %s only selects part of the rows of source shard %s",
+				sqlparser.String(fn), shard)
+		}
+	}
+	return nil
+}
+
+// hasVStreamerFunc returns true if the expression uses a function that only vstreamer implements.
+func hasVStreamerFunc(expr sqlparser.Expr) bool {
+	found := false
+	_ = sqlparser.Walk(func(node sqlparser.SQLNode) (bool, error) {
+		if fn, ok := node.(*sqlparser.FuncExpr); ok && (fn.Name.EqualString("in_keyrange") || fn.Name.EqualString("keyspace_id")) {
+			found = true
+			return false, nil
+		}
+		return true, nil
+	}, expr)
+	return found
+}
+
+func (td *tableDiffer) parseSelect(query string) (*sqlparser.Select, error) {
+	statement, err := td.wd.ct.vde.parser.Parse(query)
+	if err != nil {
+		return nil, err
+	}
+	sel, ok := statement.(*sqlparser.Select)
+	if !ok {
+		return nil, fmt.Errorf("unexpected: %v", sqlparser.String(statement))
+	}
+	return sel, nil
+}
+
+// endQuery returns the query that finds the last primary key value of the range
+// that starts after the start value, on the target.
+func (tc *tableChunker) endQuery(start []sqltypes.Value) (string, error) {
+	bindVars := make(map[string]*querypb.BindVariable)
+	sel := &sqlparser.Select{
+		From:  tc.targetFrom,
+		Where: sqlparser.NewWhere(sqlparser.WhereClause, sqlparser.AndExpressions(tc.targetWhere, pkRangeExpr(tc.targetPKs, start, nil, bindVars))),
+		Limit: &sqlparser.Limit{
+			Offset:   sqlparser.NewIntLiteral(strconv.Itoa(chunkRows - 1)),
+			Rowcount: sqlparser.NewIntLiteral("1"),
+		},
+	}
+	for _, pk := range tc.targetPKs {
+		sel.AddSelectExpr(&sqlparser.AliasedExpr{Expr: pk})
+		sel.OrderBy = append(sel.OrderBy, &sqlparser.Order{Expr: pk, Direction: sqlparser.AscOrder})
+	}
+	return sqlparser.NewParsedQuery(sel).GenerateQuery(bindVars, nil)
+}
+
+// checksumQuery returns the query that computes the checksum of the rows
+// of the range, using the given columns.
+func checksumQuery(cols, pks []sqlparser.Expr, from sqlparser.TableExprs, where sqlparser.Expr, start, end []sqltypes.Value) (string, error) {
+	// quote() returns NULL unquoted, and escapes the quotes of the values, so the
+	// values cannot run into each other.
+	args := []sqlparser.Expr{sqlparser.NewStrLiteral(",")}
+	for _, col := range cols {
+		args = append(args, sqlparser.NewFuncExpr("quote", col))
+	}
+	bindVars := make(map[string]*querypb.BindVariable)
+	rows := &sqlparser.Select{
+		From:  from,
+		Where: sqlparser.NewWhere(sqlparser.WhereClause, sqlparser.AndExpressions(where, pkRangeExpr(pks, start, end, bindVars))),
+	}
+	rows.AddSelectExpr(&sqlparser.AliasedExpr{
+		Expr: sqlparser.NewFuncExpr("md5", sqlparser.NewFuncExpr("concat_ws", args...)),
+		As:   sqlparser.NewIdentifierCI("row_hash"),
+	})
+
+	sel := &sqlparser.Select{
+		From: sqlparser.TableExprs{&sqlparser.AliasedTableExpr{
+			Expr: &sqlparser.DerivedTable{Select: rows},
+			As:   sqlparser.NewIdentifierCS("range_rows"),
+		}},
+	}
+	sel.AddSelectExpr(&sqlparser.AliasedExpr{Expr: &sqlparser.CountStar{}, As: sqlparser.NewIdentifierCI("row_count")})
+	// The 32 hex digits of the digest are split in two unsigned 64-bit integers.
+	for i, half := range [][]sqlparser.Expr{
+		{sqlparser.NewIntLiteral("1"), sqlparser.NewIntLiteral("16")},
+		{sqlparser.NewIntLiteral("17")},
+	} {
+		value := &sqlparser.CastExpr{
+			Expr: sqlparser.NewFuncExpr("conv",
+				sqlparser.NewFuncExpr("substr", append([]sqlparser.Expr{sqlparser.NewColName("row_hash")}, half...)...),
+				sqlparser.NewIntLiteral("16"),
+				sqlparser.NewIntLiteral("10")),
+			Type: &sqlparser.ConvertType{Type: "unsigned"},
+		}
+		sel.AddSelectExpr(&sqlparser.AliasedExpr{
+			Expr: &sqlparser.BinaryExpr{
+				Operator: sqlparser.ModOp,
+				Left:     &sqlparser.Sum{Arg: value},
+				Right:    sqlparser.NewIntLiteral("18446744073709551616"),
+			},
+			As: sqlparser.NewIdentifierCI(fmt.Sprintf("checksum%d", i)),
+		})
+	}
+	return sqlparser.NewParsedQuery(sel).GenerateQuery(bindVars, nil)
+}
+
+// pkRangeExpr returns the condition for the primary key values to be after start,
+// and up to end. A nil start or end is not bounded.
+func pkRangeExpr(pks []sqlparser.Expr, start, end []sqltypes.Value, bindVars map[string]*querypb.BindVariable) sqlparser.Expr {
+	bound := func(op sqlparser.ComparisonExprOperator, values []sqltypes.Value, prefix string) sqlparser.Expr {
+		if values == nil {
+			return nil
+		}
+		var left, right sqlparser.ValTuple
+		for i, pk := range pks {
+			name := fmt.Sprintf("%s%d", prefix, i)
+			bindVars[name] = sqltypes.ValueBindVariable(values[i])
+			left = append(left, pk)
+			right = append(right, sqlparser.NewArgument(name))
+		}
+		if len(pks) == 1 {
+			return &sqlparser.ComparisonExpr{Operator: op, Left: left[0], Right: right[0]}
+		}
+		return &sqlparser.ComparisonExpr{Operator: op, Left: left, Right: right}
+	}
+	return sqlparser.AndExpressions(
+		bound(sqlparser.GreaterThanOp, start, "start"),
+		bound(sqlparser.LessEqualOp, end, "end"),
+	)
+}
+
+// nextEnd returns the last primary key value of the range that starts after the
+// start value, or nil if the range holds the rest of the table.
+func (tc *tableChunker) nextEnd(dbClient binlogplayer.DBClient, start []sqltypes.Value) ([]sqltypes.Value, error) {
+	query, err := tc.endQuery(start)
+	if err != nil {
+		return nil, err
+	}
+	qr, err := dbClient.ExecuteFetch(query, 1)
+	if err != nil {
+		return nil, err
+	}
+	if len(qr.Rows) == 0 {
+		return nil, nil
+	}
+	return qr.Rows[0], nil
+}
+
+// compareChecksums computes the checksum of the rows of the range on the target and
+// on all the source shards, and returns the number of rows of the range if the
+// checksums match.
+func (tc *tableChunker) compareChecksums(ctx context.Context, dbClient binlogplayer.DBClient, start, end []sqltypes.Value) (int64, bool, error) {
+	query, err := checksumQuery(tc.targetCols, tc.targetPKs, tc.targetFrom, tc.targetWhere, start, end)
+	if err != nil {
+		return 0, false, err
+	}
+	qr, err := dbClient.ExecuteFetch(query, 1)
+	if err != nil {
+		return 0, false, err
+	}
+	target, err := parseRangeChecksum(qr)
+	if err != nil {
+		return 0, false, err
+	}
+
+	query, err = checksumQuery(tc.sourceCols, tc.sourcePKs, tc.sourceFrom, tc.sourceWhere, start, end)
+	if err != nil {
+		return 0, false, err
+	}
+	var (
+		mu     sync.Mutex
+		source rangeChecksum
+	)
+	if err := tc.td.forEachSource(func(ms *migrationSource) error {
+		res, err := tc.td.wd.ct.tmc.ExecuteFetchAsApp(ctx, ms.tablet, true, &tabletmanagerdatapb.ExecuteFetchAsAppRequest{
+			Query:   []byte(query),
+			MaxRows: 1,
+		})
+		if err != nil {
+			return vterrors.Wrapf(err, "failed to checksum rows of table %s on source tablet %s",
+				tc.td.table.Name, topoproto.TabletAliasString(ms.tablet.Alias))
+		}
+		rc, err := parseRangeChecksum(sqltypes.Proto3ToResult(res))
+		if err != nil {
+			return err
+		}
+		mu.Lock()
+		defer mu.Unlock()
+		source.add(rc)
+		return nil
+	}); err != nil {
+		return 0, false, err
+	}
+	return target.rows, source == target, nil
+}
+
+func parseRangeChecksum(qr *sqltypes.Result) (rangeChecksum, error) {
+	if len(qr.Rows) != 1 || len(qr.Rows[0]) != 3 {
+		return rangeChecksum{}, fmt.Errorf("unexpected checksum result: %v", qr.Rows)
+	}
+	var rc rangeChecksum
+	var err error
+	if rc.rows, err = qr.Rows[0][0].ToInt64(); err != nil {
+		return rangeChecksum{}, err
+	}
+	// The sums are NULL for an empty range, and DECIMAL values otherwise.
+	for i := range rc.checksum {
+		if value := qr.Rows[0][i+1]; !value.IsNull() {
+			if rc.checksum[i], err = value.ToCastUint64(); err != nil {
+				return rangeChecksum{}, err
+			}
+		}
+	}
+	return rc, nil
+}
+
+// pkRow returns a row of the diff queries that holds the primary key values, so
+// that it can be used as the lastpk of the streams, or compared with the streamed rows.
+func (tc *tableChunker) pkRow(pkValues []sqltypes.Value) []sqltypes.Value {
+	if pkValues == nil {
+		return nil
+	}
+	row := make([]sqltypes.Value, len(tc.targetCols))
+	for i, col := range tc.td.tablePlan.pkCols {
+		row[col] = pkValues[i]
+	}
+	return row
+}
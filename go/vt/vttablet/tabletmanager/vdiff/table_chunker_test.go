@@ -0,0 +1,150 @@
+/*
+Copyright 2025 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package vdiff
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"vitess.io/vitess/go/sqltypes"
+	"vitess.io/vitess/go/vt/sqlparser"
+
+	tabletmanagerdatapb "vitess.io/vitess/go/vt/proto/tabletmanagerdata"
+)
+
+func newTestChunkerTableDiffer(sourceQuery, targetQuery string, pkCols []int, sourceShards ...string) *tableDiffer {
+	sources := make(map[string]*migrationSource)
+	for _, shard := range sourceShards {
+		sources[shard] = &migrationSource{shardStreamer: &shardStreamer{shard: shard}}
+	}
+	return &tableDiffer{
+		wd: &workflowDiffer{
+			ct: &controller{
+				vde:     &Engine{parser: sqlparser.NewTestParser()},
+				sources: sources,
+			},
+		},
+		table: &tabletmanagerdatapb.TableDefinition{Name: "t1"},
+		tablePlan: &tablePlan{
+			dbName:       "vt_ks",
+			sourceQuery:  sourceQuery,
+			targetQuery:  targetQuery,
+			pkCols:       pkCols,
+			sourcePkCols: pkCols,
+		},
+	}
+}
+
+func TestTableChunkerQueries(t *testing.T) {
+	testcases := []struct {
+		name           string
+		sourceQuery    string
+		targetQuery    string
+		pkCols         []int
+		start, end     []sqltypes.Value
+		endQuery       string
+		sourceChecksum string
+		targetChecksum string
+	}{{
+		name:           "single column primary key",
+		sourceQuery:    "select c1, c2 from t1 order by c1 asc",
+		targetQuery:    "select c1, c2 from t1 order by c1 asc",
+		pkCols:         []int{0},
+		end:            []sqltypes.Value{sqltypes.NewInt64(10)},
+		endQuery:       "select c1 from vt_ks.t1 order by c1 asc limit 99999, 1",
+		sourceChecksum: "select count(*) as row_count, sum(cast(conv(substr(row_hash, 1, 16), 16, 10) as unsigned)) % 18446744073709551616 as checksum0, sum(cast(conv(substr(row_hash, 17), 16, 10) as unsigned)) % 18446744073709551616 as checksum1 from (select md5(concat_ws(',', quote(c1), quote(c2))) as row_hash from t1 where c1 <= 10) as range_rows",
+		targetChecksum: "select count(*) as row_count, sum(cast(conv(substr(row_hash, 1, 16), 16, 10) as unsigned)) % 18446744073709551616 as checksum0, sum(cast(conv(substr(row_hash, 17), 16, 10) as unsigned)) % 18446744073709551616 as checksum1 from (select md5(concat_ws(',', quote(c1), quote(c2))) as row_hash from vt_ks.t1 where c1 <= 10) as range_rows",
+	}, {
+		name:           "multi column primary key",
+		sourceQuery:    "select c1, c2, c3 from t1 order by c1 asc, c2 asc",
+		targetQuery:    "select c1, c2, c3 from t1 order by c1 asc, c2 asc",
+		pkCols:         []int{0, 1},
+		start:          []sqltypes.Value{sqltypes.NewInt64(1), sqltypes.NewVarChar("a")},
+		end:            []sqltypes.Value{sqltypes.NewInt64(3), sqltypes.NewVarChar("b")},
+		endQuery:       "select c1, c2 from vt_ks.t1 where (c1, c2) > (1, 'a') order by c1 asc, c2 asc limit 99999, 1",
+		sourceChecksum: "select count(*) as row_count, sum(cast(conv(substr(row_hash, 1, 16), 16, 10) as unsigned)) % 18446744073709551616 as checksum0, sum(cast(conv(substr(row_hash, 17), 16, 10) as unsigned)) % 18446744073709551616 as checksum1 from (select md5(concat_ws(',', quote(c1), quote(c2), quote(c3))) as row_hash from t1 where (c1, c2) > (1, 'a') and (c1, c2) <= (3, 'b')) as range_rows",
+		targetChecksum: "select count(*) as row_count, sum(cast(conv(substr(row_hash, 1, 16), 16, 10) as unsigned)) % 18446744073709551616 as checksum0, sum(cast(conv(substr(row_hash, 17), 16, 10) as unsigned)) % 18446744073709551616 as checksum1 from (select md5(concat_ws(',', quote(c1), quote(c2), quote(c3))) as row_hash from vt_ks.t1 where (c1, c2) > (1, 'a') and (c1, c2) <= (3, 'b')) as range_rows",
+	}, {
+		name:           "workflow filter",
+		sourceQuery:    "select c1, c2 + 1 as c2 from t1 where tenant = 5 and in_keyrange('-80') order by c1 asc",
+		targetQuery:    "select c1, c2 from t1 where tenant = 5 order by c1 asc",
+		pkCols:         []int{0},
+		start:          []sqltypes.Value{sqltypes.NewInt64(1)},
+		endQuery:       "select c1 from vt_ks.t1 where tenant = 5 and c1 > 1 order by c1 asc limit 99999, 1",
+		sourceChecksum: "select count(*) as row_count, sum(cast(conv(substr(row_hash, 1, 16), 16, 10) as unsigned)) % 18446744073709551616 as checksum0, sum(cast(conv(substr(row_hash, 17), 16, 10) as unsigned)) % 18446744073709551616 as checksum1 from (select md5(concat_ws(',', quote(c1), quote(c2 + 1))) as row_hash from t1 where tenant = 5 and c1 > 1) as range_rows",
+		targetChecksum: "select count(*) as row_count, sum(cast(conv(substr(row_hash, 1, 16), 16, 10) as unsigned)) % 18446744073709551616 as checksum0, sum(cast(conv(substr(row_hash, 17), 16, 10) as unsigned)) % 18446744073709551616 as checksum1 from (select md5(concat_ws(',', quote(c1), quote(c2))) as row_hash from vt_ks.t1 where tenant = 5 and c1 > 1) as range_rows",
+	}}
+	for _, tcase := range testcases {
+		t.Run(tcase.name, func(t *testing.T) {
+			td := newTestChunkerTableDiffer(tcase.sourceQuery, tcase.targetQuery, tcase.pkCols, "-40", "40-80")
+			tc, err := td.newTableChunker()
+			require.NoError(t, err)
+			require.NoError(t, tc.initChecksum())
+
+			query, err := tc.endQuery(tcase.start)
+			require.NoError(t, err)
+			assert.Equal(t, tcase.endQuery, query)
+
+			query, err = checksumQuery(tc.sourceCols, tc.sourcePKs, tc.sourceFrom, tc.sourceWhere, tcase.start, tcase.end)
+			require.NoError(t, err)
+			assert.Equal(t, tcase.sourceChecksum, query)
+
+			query, err = checksumQuery(tc.targetCols, tc.targetPKs, tc.targetFrom, tc.targetWhere, tcase.start, tcase.end)
+			require.NoError(t, err)
+			assert.Equal(t, tcase.targetChecksum, query)
+		})
+	}
+}
+
+func TestTableChunkerUnsupported(t *testing.T) {
+	td := newTestChunkerTableDiffer("select c1, c2 from t1 order by c1 asc", "select c1, c2 from t1 order by c1 asc", []int{0}, "0")
+	td.tablePlan.sourcePkCols = []int{1}
+	_, err := td.newTableChunker()
+	assert.EqualError(t, err, "table t1 has different primary keys on the source and target")
+
+	// The source shard holds rows that are not part of the target shard.
+	td = newTestChunkerTableDiffer("select c1, c2 from t1 where in_keyrange('-80') order by c1 asc", "select c1, c2 from t1 order by c1 asc", []int{0}, "0")
+	tc, err := td.newTableChunker()
+	require.NoError(t, err)
+	assert.EqualError(t, tc.initChecksum(), "the workflow filter in_keyrange('-80') only selects part of the rows of source shard 0")
+
+	td = newTestChunkerTableDiffer("select keyspace_id() as c1, c2 from t1 order by c1 asc", "select c1, c2 from t1 order by c1 asc", []int{0}, "0")
+	tc, err = td.newTableChunker()
+	require.NoError(t, err)
+	assert.EqualError(t, tc.initChecksum(), "column keyspace_id() of table t1 is computed by vstreamer")
+}
+
+func TestParseRangeChecksum(t *testing.T) {
+	qr := sqltypes.MakeTestResult(sqltypes.MakeTestFields("row_count|checksum0|checksum1", "int64|decimal|decimal"), "10|18446744073709551615|1234")
+	rc, err := parseRangeChecksum(qr)
+	require.NoError(t, err)
+	assert.Equal(t, rangeChecksum{rows: 10, checksum: [2]uint64{18446744073709551615, 1234}}, rc)
+
+	// The checksums of the source shards wrap around.
+	rc.add(rangeChecksum{rows: 5, checksum: [2]uint64{2, 1}})
+	assert.Equal(t, rangeChecksum{rows: 15, checksum: [2]uint64{1, 1235}}, rc)
+
+	qr = sqltypes.MakeTestResult(sqltypes.MakeTestFields("row_count|checksum0|checksum1", "int64|decimal|decimal"), "0|null|null")
+	rc, err = parseRangeChecksum(qr)
+	require.NoError(t, err)
+	assert.Equal(t, rangeChecksum{}, rc)
+
+	_, err = parseRangeChecksum(&sqltypes.Result{})
+	assert.Error(t, err)
+}
@@ -93,6 +93,16 @@ type tableDiffer struct {
 	table        *tabletmanagerdatapb.TableDefinition
 	lastSourcePK *querypb.QueryResult
 	lastTargetPK *querypb.QueryResult
+	// endRow, if set, holds the primary key values of the last row to diff.
+	// It is used to diff a single primary key range of the table.
+	endRow []sqltypes.Value
+	// sourceExecutor and targetExecutor, if set, stream the rows of the
+	// snapshot that is shared by the diffs of all the primary key ranges.
+	sourceExecutor *primitiveExecutor
+	targetExecutor *primitiveExecutor
+	// replicationStopped is set while the replication of the source tablets and
+	// the workflow are stopped by stopReplication.
+	replicationStopped bool
 
 	// wgShardStreamers is used, with a cancellable context, to wait for all shard streamers
 	// to finish after each diff is complete.
@@ -112,6 +122,20 @@ func (td *tableDiffer) initialize(ctx context.Context) error {
 	vdiffEngine.snapshotMu.Lock()
 	defer vdiffEngine.snapshotMu.Unlock()
 
+	if td.replicationStopped {
+		// The rows of the table do not change while the replication is stopped, so
+		// the tablets are streamed without being synchronized again.
+		td.shardStreamsCtx, td.shardStreamsCancel = context.WithCancel(ctx)
+		if err := td.startSourceDataStreams(td.shardStreamsCtx); err != nil {
+			return err
+		}
+		if err := td.startTargetDataStream(td.shardStreamsCtx); err != nil {
+			return err
+		}
+		td.setupRowSorters()
+		return nil
+	}
+
 	dbClient := td.wd.ct.dbClientFactory()
 	if err := dbClient.Connect(); err != nil {
 		return err
@@ -319,6 +343,111 @@ func (td *tableDiffer) syncSourceStreams(ctx context.Context) error {
 	return nil
 }
 
+// checkReplicaSources returns an error if one of the source tablets is a primary,
+// whose replication cannot be stopped.
+func (td *tableDiffer) checkReplicaSources() error {
+	for _, source := range td.wd.ct.sources {
+		if source.tablet.Type == topodatapb.TabletType_PRIMARY {
+			return fmt.Errorf("source tablet %s is a primary, whose replication cannot be stopped",
+				topoproto.TabletAliasString(source.tablet.Alias))
+		}
+	}
+	return nil
+}
+
+// stopReplication stops the replication of the source tablets, and the workflow once
+// the target has caught up with them, so that the rows of the table do not change on
+// either side while its primary key ranges are checksummed and diffed. It returns the
+// function that restarts the replication and the workflow.
+func (td *tableDiffer) stopReplication(ctx context.Context) (func(), error) {
+	ct := td.wd.ct
+	vdiffEngine := ct.vde
+	vdiffEngine.snapshotMu.Lock()
+	defer vdiffEngine.snapshotMu.Unlock()
+
+	dbClient := ct.dbClientFactory()
+	if err := dbClient.Connect(); err != nil {
+		return nil, err
+	}
+	defer dbClient.Close()
+
+	lockName := fmt.Sprintf("%s/%s", vdiffEngine.thisTablet.Keyspace, ct.workflow)
+	log.Infof("Locking workflow %s", lockName)
+	ctx, unlock, lockErr := ct.ts.LockName(ctx, lockName, "vdiff")
+	if lockErr != nil {
+		log.Errorf("Locking workfkow %s failed: %v", lockName, lockErr)
+		return nil, lockErr
+	}
+
+	var err error
+	defer func() {
+		unlock(&err)
+		if err != nil {
+			log.Errorf("Unlocking workflow %s failed: %v", lockName, err)
+		}
+	}()
+
+	var mu sync.Mutex
+	// stoppedSources holds whether the semi-sync replication was enabled on the
+	// source tablets whose replication is stopped.
+	stoppedSources := make(map[*migrationSource]bool)
+	restart := func() {
+		// We use a new context as we want to reset the state even
+		// when the parent context has timed out or been canceled.
+		restartCtx, restartCancel := context.WithTimeout(context.Background(), BackgroundOperationTimeout)
+		defer restartCancel()
+		for source, semiSync := range stoppedSources {
+			if err := ct.tmc.StartReplication(restartCtx, source.tablet, semiSync); err != nil {
+				log.Errorf("error restarting the replication of tablet %s: %v", topoproto.TabletAliasString(source.tablet.Alias), err)
+			}
+		}
+		if err := td.restartTargetVReplicationStreams(restartCtx); err != nil {
+			log.Errorf("error restarting target streams: %v", err)
+		}
+	}
+
+	timeout := time.Duration(ct.options.CoreOptions.TimeoutSeconds * int64(time.Second))
+	waitCtx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+	if err = td.stopTargetVReplicationStreams(ctx, dbClient); err == nil {
+		err = td.forEachSource(func(source *migrationSource) error {
+			status, err := ct.tmc.FullStatus(waitCtx, source.tablet)
+			if err != nil {
+				return err
+			}
+			pos, err := ct.tmc.StopReplicationMinimum(waitCtx, source.tablet, replication.EncodePosition(source.position), timeout)
+			if err != nil {
+				return vterrors.Wrapf(err, "StopReplicationMinimum for tablet %v", topoproto.TabletAliasString(source.tablet.Alias))
+			}
+			mu.Lock()
+			defer mu.Unlock()
+			stoppedSources[source] = status.SemiSyncReplicaEnabled
+			source.snapshotPosition = pos
+			return nil
+		})
+	}
+	if err == nil {
+		err = td.syncTargetStreams(ctx)
+	}
+	if err == nil && !topoproto.TabletAliasEqual(ct.targetShardStreamer.tablet.Alias, vdiffEngine.thisTablet.Alias) {
+		// The target rows are checksummed on this tablet, and streamed from the
+		// target tablet.
+		var pos string
+		if pos, err = ct.tmc.PrimaryPosition(waitCtx, vdiffEngine.thisTablet); err == nil {
+			err = ct.tmc.WaitForPosition(waitCtx, ct.targetShardStreamer.tablet, pos)
+		}
+	}
+	if err != nil {
+		restart()
+		return nil, err
+	}
+	td.replicationStopped = true
+	return func() {
+		td.replicationStopped = false
+		restart()
+	}, nil
+}
+
 func (td *tableDiffer) syncTargetStreams(ctx context.Context) error {
 	defer td.wd.ct.TableDiffPhaseTimings.Record(fmt.Sprintf("%s.%s", td.table.Name, syncingTargets), time.Now())
 	ct := td.wd.ct
@@ -501,36 +630,16 @@ func (td *tableDiffer) diff(ctx context.Context, coreOpts *tabletmanagerdatapb.V
 	// We need to continue were we left off when appropriate. This can be an
 	// auto-retry on error, or a manual retry via the resume command.
 	// Otherwise the existing state will be empty and we start from scratch.
-	query, err := sqlparser.ParseAndBind(sqlGetVDiffTable,
-		sqltypes.Int64BindVariable(td.wd.ct.id),
-		sqltypes.StringBindVariable(td.table.Name),
-	)
+	mismatch, dr, err := td.getDiffState(dbClient)
 	if err != nil {
 		return nil, err
 	}
-	cs, err := dbClient.ExecuteFetch(query, -1)
-	if err != nil {
-		return nil, err
-	}
-	if len(cs.Rows) == 0 {
-		return nil, fmt.Errorf("no state found for vdiff table %s for vdiff_id %d on tablet %v",
-			td.table.Name, td.wd.ct.id, td.wd.ct.vde.thisTablet.Alias)
-	} else if len(cs.Rows) > 1 {
-		return nil, fmt.Errorf("invalid state found for vdiff table %s (multiple records) for vdiff_id %d on tablet %v",
-			td.table.Name, td.wd.ct.id, td.wd.ct.vde.thisTablet.Alias)
-	}
-	curState := cs.Named().Row()
-	mismatch := curState.AsBool("mismatch", false)
-	dr := &DiffReport{}
-	if rpt := curState.AsBytes("report", []byte("{}")); json.Valid(rpt) {
-		if err = json.Unmarshal(rpt, dr); err != nil {
-			return nil, err
-		}
-	}
-	dr.TableName = td.table.Name
 
-	sourceExecutor := newPrimitiveExecutor(ctx, td.sourcePrimitive, "source")
-	targetExecutor := newPrimitiveExecutor(ctx, td.targetPrimitive, "target")
+	sourceExecutor, targetExecutor := td.sourceExecutor, td.targetExecutor
+	if sourceExecutor == nil {
+		sourceExecutor = newPrimitiveExecutor(ctx, td.sourcePrimitive, "source")
+		targetExecutor = newPrimitiveExecutor(ctx, td.targetPrimitive, "target")
+	}
 	var sourceRow, lastProcessedRow, targetRow []sqltypes.Value
 	advanceSource := true
 	advanceTarget := true
@@ -575,14 +684,14 @@ func (td *tableDiffer) diff(ctx context.Context, coreOpts *tabletmanagerdatapb.V
 			return dr, nil
 		}
 		if advanceSource {
-			sourceRow, err = sourceExecutor.next()
+			sourceRow, err = td.nextRow(sourceExecutor)
 			if err != nil {
 				log.Error(err)
 				return nil, err
 			}
 		}
 		if advanceTarget {
-			targetRow, err = targetExecutor.next()
+			targetRow, err = td.nextRow(targetExecutor)
 			if err != nil {
 				log.Error(err)
 				return nil, err
@@ -603,7 +712,7 @@ func (td *tableDiffer) diff(ctx context.Context, coreOpts *tabletmanagerdatapb.V
 			dr.ExtraRowsTargetDiffs = append(dr.ExtraRowsTargetDiffs, diffRow)
 
 			// Drain target, update count.
-			count, err := targetExecutor.drain(ctx)
+			count, err := td.drain(ctx, targetExecutor)
 			if err != nil {
 				return nil, err
 			}
@@ -619,7 +728,7 @@ func (td *tableDiffer) diff(ctx context.Context, coreOpts *tabletmanagerdatapb.V
 				return nil, vterrors.Wrap(err, "unexpected error generating diff")
 			}
 			dr.ExtraRowsSourceDiffs = append(dr.ExtraRowsSourceDiffs, diffRow)
-			count, err := sourceExecutor.drain(ctx)
+			count, err := td.drain(ctx, sourceExecutor)
 			if err != nil {
 				return nil, err
 			}
@@ -694,6 +803,97 @@ func (td *tableDiffer) diff(ctx context.Context, coreOpts *tabletmanagerdatapb.V
 	}
 }
 
+// startSnapshotStreams takes a consistent snapshot of the table, starting after
+// the last primary key, and starts streaming its rows for the diffs of the
+// primary key ranges.
+func (td *tableDiffer) startSnapshotStreams(ctx context.Context) error {
+	if err := td.initialize(ctx); err != nil {
+		return err
+	}
+	td.sourceExecutor = newPrimitiveExecutor(td.shardStreamsCtx, td.sourcePrimitive, "source")
+	td.targetExecutor = newPrimitiveExecutor(td.shardStreamsCtx, td.targetPrimitive, "target")
+	return nil
+}
+
+// stopSnapshotStreams stops streaming the rows of the snapshot, if any.
+func (td *tableDiffer) stopSnapshotStreams() {
+	if td.shardStreamsCancel != nil {
+		td.shardStreamsCancel()
+	}
+	td.wgShardStreamers.Wait()
+	td.sourceExecutor, td.targetExecutor = nil, nil
+}
+
+// getDiffState returns the mismatch flag and the diff report saved for the table.
+func (td *tableDiffer) getDiffState(dbClient binlogplayer.DBClient) (bool, *DiffReport, error) {
+	query, err := sqlparser.ParseAndBind(sqlGetVDiffTable,
+		sqltypes.Int64BindVariable(td.wd.ct.id),
+		sqltypes.StringBindVariable(td.table.Name),
+	)
+	if err != nil {
+		return false, nil, err
+	}
+	cs, err := dbClient.ExecuteFetch(query, -1)
+	if err != nil {
+		return false, nil, err
+	}
+	if len(cs.Rows) == 0 {
+		return false, nil, fmt.Errorf("no state found for vdiff table %s for vdiff_id %d on tablet %v",
+			td.table.Name, td.wd.ct.id, td.wd.ct.vde.thisTablet.Alias)
+	} else if len(cs.Rows) > 1 {
+		return false, nil, fmt.Errorf("invalid state found for vdiff table %s (multiple records) for vdiff_id %d on tablet %v",
+			td.table.Name, td.wd.ct.id, td.wd.ct.vde.thisTablet.Alias)
+	}
+	curState := cs.Named().Row()
+	mismatch := curState.AsBool("mismatch", false)
+	dr := &DiffReport{}
+	if rpt := curState.AsBytes("report", []byte("{}")); json.Valid(rpt) {
+		if err = json.Unmarshal(rpt, dr); err != nil {
+			return false, nil, err
+		}
+	}
+	dr.TableName = td.table.Name
+	return mismatch, dr, nil
+}
+
+// nextRow returns the next row of the executor, or nil when the rows are past the
+// end of the primary key range that is being diffed. The first row past the end is
+// kept in the executor, for the diff of the next range.
+func (td *tableDiffer) nextRow(pe *primitiveExecutor) ([]sqltypes.Value, error) {
+	row, err := pe.next()
+	if err != nil || row == nil || td.endRow == nil {
+		return row, err
+	}
+	c, err := td.compare(row, td.endRow, td.tablePlan.comparePKs, false)
+	if err != nil {
+		return nil, err
+	}
+	if c > 0 {
+		pe.unread(row)
+		return nil, nil
+	}
+	return row, nil
+}
+
+// drain discards the rest of the rows of the executor that have to be diffed,
+// and returns their count.
+func (td *tableDiffer) drain(ctx context.Context, pe *primitiveExecutor) (int64, error) {
+	if td.endRow == nil {
+		return pe.drain(ctx)
+	}
+	var count int64
+	for {
+		row, err := td.nextRow(pe)
+		if err != nil {
+			return 0, err
+		}
+		if row == nil {
+			return count, nil
+		}
+		count++
+	}
+}
+
 func (td *tableDiffer) compare(sourceRow, targetRow []sqltypes.Value, cols []compareColInfo, compareOnlyNonPKs bool) (int, error) {
 	for _, col := range cols {
 		if col.isPK && compareOnlyNonPKs {
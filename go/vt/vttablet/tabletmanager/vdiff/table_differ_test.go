@@ -17,17 +17,20 @@ limitations under the License.
 package vdiff
 
 import (
+	"context"
 	"fmt"
 	"testing"
 
 	"github.com/stretchr/testify/require"
 
+	"vitess.io/vitess/go/mysql/collations"
 	"vitess.io/vitess/go/sqltypes"
 	"vitess.io/vitess/go/stats"
 	"vitess.io/vitess/go/vt/binlog/binlogplayer"
 
 	querypb "vitess.io/vitess/go/vt/proto/query"
 	tabletmanagerdatapb "vitess.io/vitess/go/vt/proto/tabletmanagerdata"
+	topodatapb "vitess.io/vitess/go/vt/proto/topodata"
 )
 
 func TestUpdateTableProgress(t *testing.T) {
@@ -129,3 +132,51 @@ func TestUpdateTableProgress(t *testing.T) {
 		})
 	}
 }
+
+// TestNextRowRanges tests that the rows of a snapshot stream are split into
+// consecutive primary key ranges, without losing the first row of a range.
+func TestNextRowRanges(t *testing.T) {
+	td := &tableDiffer{
+		wd: &workflowDiffer{collationEnv: collations.MySQL8()},
+		tablePlan: &tablePlan{
+			comparePKs: []compareColInfo{{colIndex: 0, isPK: true}},
+		},
+	}
+	pe := &primitiveExecutor{resultch: make(chan *sqltypes.Result, 1)}
+	pe.resultch <- sqltypes.MakeTestResult(sqltypes.MakeTestFields("c1", "int64"), "1", "2", "3", "4", "5")
+	close(pe.resultch)
+
+	next := func() string {
+		row, err := td.nextRow(pe)
+		require.NoError(t, err)
+		if row == nil {
+			return "end"
+		}
+		return row[0].ToString()
+	}
+
+	td.endRow = []sqltypes.Value{sqltypes.NewInt64(2)}
+	require.Equal(t, "1", next())
+	require.Equal(t, "2", next())
+	require.Equal(t, "end", next())
+	require.Equal(t, "end", next())
+
+	td.endRow = []sqltypes.Value{sqltypes.NewInt64(4)}
+	count, err := td.drain(context.Background(), pe)
+	require.NoError(t, err)
+	require.EqualValues(t, 2, count)
+
+	td.endRow = nil
+	require.Equal(t, "5", next())
+	require.Equal(t, "end", next())
+}
+
+func TestCheckReplicaSources(t *testing.T) {
+	td := newTestChunkerTableDiffer("select c1 from t1 order by c1 asc", "select c1 from t1 order by c1 asc", []int{0}, "-80", "80-")
+	td.wd.ct.sources["-80"].tablet = &topodatapb.Tablet{Alias: &topodatapb.TabletAlias{Cell: "zone1", Uid: 100}, Type: topodatapb.TabletType_REPLICA}
+	td.wd.ct.sources["80-"].tablet = &topodatapb.Tablet{Alias: &topodatapb.TabletAlias{Cell: "zone1", Uid: 200}, Type: topodatapb.TabletType_RDONLY}
+	require.NoError(t, td.checkReplicaSources())
+
+	td.wd.ct.sources["80-"].tablet.Type = topodatapb.TabletType_PRIMARY
+	require.EqualError(t, td.checkReplicaSources(), "source tablet zone1-0000000200 is a primary, whose replication cannot be stopped")
+}
@@ -20,6 +20,7 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"math/rand/v2"
 	"reflect"
 	"slices"
 	"strings"
@@ -194,6 +195,49 @@ func (wd *workflowDiffer) doReconcileExtraRows(dr *DiffReport, maxExtraRowsToCom
 }
 
 func (wd *workflowDiffer) diffTable(ctx context.Context, dbClient binlogplayer.DBClient, td *tableDiffer) error {
+	log.Infof("Starting differ on table %s for vdiff %s", td.table.Name, wd.ct.uuid)
+	if err := td.updateTableState(ctx, dbClient, StartedState); err != nil {
+		return err
+	}
+
+	var (
+		diffReport *DiffReport
+		err        error
+	)
+	if coreOpts := wd.opts.CoreOptions; coreOpts.Checksum || isSampling(coreOpts.SamplePct) {
+		diffReport, err = wd.diffTableRanges(ctx, dbClient, td)
+	} else {
+		diffReport, err = wd.diffTableRows(ctx, dbClient, td)
+	}
+	if err != nil {
+		return err
+	}
+	log.Infof("Table diff done on table %s for vdiff %s with report: %+v", td.table.Name, wd.ct.uuid, diffReport)
+
+	if diffReport.ExtraRowsSource > 0 || diffReport.ExtraRowsTarget > 0 {
+		if err := wd.reconcileExtraRows(diffReport, wd.opts.CoreOptions.MaxExtraRowsToCompare, wd.opts.ReportOptions.MaxSampleRows); err != nil {
+			log.Errorf("Encountered an error reconciling extra rows found for table %s for vdiff %s: %v", td.table.Name, wd.ct.uuid, err)
+			return vterrors.Wrap(err, "failed to reconcile extra rows")
+		}
+	}
+
+	if diffReport.MismatchedRows > 0 || diffReport.ExtraRowsTarget > 0 || diffReport.ExtraRowsSource > 0 {
+		if err := updateTableMismatch(dbClient, wd.ct.id, td.table.Name); err != nil {
+			return err
+		}
+	}
+
+	log.Infof("Completed reconciliation on table %s for vdiff %s with updated report: %+v", td.table.Name, wd.ct.uuid, diffReport)
+	if err := td.updateTableStateAndReport(ctx, dbClient, CompletedState, diffReport); err != nil {
+		return err
+	}
+	return nil
+}
+
+// diffTableRows diffs the rows of the table, using consistent snapshots of the
+// source and target. The snapshots are renewed, and the diff resumed, if it runs
+// for longer than the max diff duration.
+func (wd *workflowDiffer) diffTableRows(ctx context.Context, dbClient binlogplayer.DBClient, td *tableDiffer) (*DiffReport, error) {
 	cancelShardStreams := func() {
 		if td.shardStreamsCancel != nil {
 			td.shardStreamsCancel()
@@ -227,17 +271,12 @@ func (wd *workflowDiffer) diffTable(ctx context.Context, dbClient binlogplayer.D
 		maxDiffRuntime = time.Duration(wd.ct.options.CoreOptions.MaxDiffSeconds) * time.Second
 	}
 
-	log.Infof("Starting differ on table %s for vdiff %s", td.table.Name, wd.ct.uuid)
-	if err := td.updateTableState(ctx, dbClient, StartedState); err != nil {
-		return err
-	}
-
 	for {
 		select {
 		case <-ctx.Done():
-			return vterrors.Errorf(vtrpcpb.Code_CANCELED, "context has expired")
+			return nil, vterrors.Errorf(vtrpcpb.Code_CANCELED, "context has expired")
 		case <-wd.ct.done:
-			return ErrVDiffStoppedByUser
+			return nil, ErrVDiffStoppedByUser
 		default:
 		}
 
@@ -255,39 +294,192 @@ func (wd *workflowDiffer) diffTable(ctx context.Context, dbClient binlogplayer.D
 			time.Sleep(30 * time.Second)
 		}
 		if err := td.initialize(ctx); err != nil { // Setup the consistent snapshots
-			return err
+			return nil, err
 		}
 		log.Infof("Table initialization done on table %s for vdiff %s", td.table.Name, wd.ct.uuid)
 		diffTimer = time.NewTimer(maxDiffRuntime)
 		diffReport, diffErr = td.diff(ctx, wd.opts.CoreOptions, wd.opts.ReportOptions, diffTimer.C)
 		if diffErr == nil { // We finished the diff successfully
-			break
+			return diffReport, nil
 		}
 		log.Errorf("Encountered an error diffing table %s for vdiff %s: %v", td.table.Name, wd.ct.uuid, diffErr)
 		if !errors.Is(diffErr, ErrMaxDiffDurationExceeded) { // We only want to retry if we hit the max-diff-duration
-			return diffErr
+			return nil, diffErr
 		}
 	}
-	log.Infof("Table diff done on table %s for vdiff %s with report: %+v", td.table.Name, wd.ct.uuid, diffReport)
+}
 
-	if diffReport.ExtraRowsSource > 0 || diffReport.ExtraRowsTarget > 0 {
-		if err := wd.reconcileExtraRows(diffReport, wd.opts.CoreOptions.MaxExtraRowsToCompare, wd.opts.ReportOptions.MaxSampleRows); err != nil {
-			log.Errorf("Encountered an error reconciling extra rows found for table %s for vdiff %s: %v", td.table.Name, wd.ct.uuid, err)
-			return vterrors.Wrap(err, "failed to reconcile extra rows")
+// diffTableRanges diffs the table one primary key range at a time, for the checksum
+// and sampling modes. With the checksum mode, only the ranges whose checksums differ
+// on the source and target are diffed row by row. With the sampling mode, only a
+// random sample of the ranges is diffed.
+//
+// With the sampling mode, all the ranges are diffed from a single consistent snapshot
+// of the table, taken when the first range has to be diffed row by row, so that the
+// workflow is only stopped once. The rows of the ranges that are skipped afterwards
+// are read from the snapshot and discarded. A new snapshot is only taken, from the
+// last diffed row, when the diff takes longer than the max diff duration.
+//
+// With the checksum mode, the replication of the source tablets and the workflow are
+// stopped at the same position while the table is diffed, so that the checksums and
+// the rows of the ranges all come from the same state of the table. The ranges that
+// are skipped are then not read: the rows are streamed again from the next range
+// that is diffed.
+func (wd *workflowDiffer) diffTableRanges(ctx context.Context, dbClient binlogplayer.DBClient, td *tableDiffer) (*DiffReport, error) {
+	coreOpts := wd.opts.CoreOptions
+	sampling := isSampling(coreOpts.SamplePct)
+	checksum := coreOpts.Checksum
+	tc, err := td.newTableChunker()
+	if err != nil {
+		wd.logTableNote(ctx, dbClient, fmt.Sprintf("Diffing all the rows of table %s: %v", td.table.Name, err))
+		return wd.diffTableRows(ctx, dbClient, td)
+	}
+	if checksum {
+		err := tc.initChecksum()
+		if err == nil {
+			if err = td.selectTablets(ctx); err != nil {
+				return nil, err
+			}
+			err = td.checkReplicaSources()
+		}
+		if err != nil {
+			if !sampling {
+				wd.logTableNote(ctx, dbClient, fmt.Sprintf("Diffing all the rows of table %s, as they cannot be checksummed: %v", td.table.Name, err))
+				return wd.diffTableRows(ctx, dbClient, td)
+			}
+			wd.logTableNote(ctx, dbClient, fmt.Sprintf("Diffing a sample of the rows of table %s, as they cannot be checksummed: %v", td.table.Name, err))
+			checksum = false
 		}
 	}
 
-	if diffReport.MismatchedRows > 0 || diffReport.ExtraRowsTarget > 0 || diffReport.ExtraRowsSource > 0 {
-		if err := updateTableMismatch(dbClient, wd.ct.id, td.table.Name); err != nil {
-			return err
+	_, dr, err := td.getDiffState(dbClient)
+	if err != nil {
+		return nil, err
+	}
+	// Resume from where we left off, if we did.
+	var start []sqltypes.Value
+	if td.lastTargetPK != nil {
+		start = sqltypes.Proto3ToResult(td.lastTargetPK).Rows[0]
+	}
+	maxDiffRuntime := time.Duration(24 * time.Hour * 365) // 1 year (effectively forever)
+	if coreOpts.MaxDiffSeconds > 0 {
+		maxDiffRuntime = time.Duration(coreOpts.MaxDiffSeconds) * time.Second
+	}
+	if checksum {
+		restartReplication, err := td.stopReplication(ctx)
+		if err != nil {
+			return nil, err
 		}
+		defer restartReplication()
 	}
+	var diffTimer *time.Timer
+	defer func() {
+		if diffTimer != nil {
+			diffTimer.Stop()
+		}
+		td.stopSnapshotStreams()
+		td.endRow = nil
+	}()
+	var ranges, diffedRanges int64
+	for {
+		select {
+		case <-ctx.Done():
+			return nil, vterrors.Errorf(vtrpcpb.Code_CANCELED, "context has expired")
+		case <-wd.ct.done:
+			return nil, ErrVDiffStoppedByUser
+		default:
+		}
 
-	log.Infof("Completed reconciliation on table %s for vdiff %s with updated report: %+v", td.table.Name, wd.ct.uuid, diffReport)
-	if err := td.updateTableStateAndReport(ctx, dbClient, CompletedState, diffReport); err != nil {
-		return err
+		end, err := tc.nextEnd(dbClient, start)
+		if err != nil {
+			return nil, err
+		}
+		ranges++
+		diffRows := !sampling || rand.Int64N(100) < coreOpts.SamplePct
+		if diffRows && checksum {
+			rows, match, err := tc.compareChecksums(ctx, dbClient, start, end)
+			if err != nil {
+				return nil, err
+			}
+			if match {
+				dr.ProcessedRows += rows
+				dr.MatchingRows += rows
+				diffRows = false
+			}
+		}
+		if diffRows {
+			diffedRanges++
+			// The diff picks up the report saved for the table, so it has to be up to date.
+			if err := td.updateTableProgress(dbClient, dr, tc.pkRow(start)); err != nil {
+				return nil, err
+			}
+			if td.sourceExecutor == nil {
+				td.lastSourcePK, td.lastTargetPK = nil, nil
+				if start != nil {
+					lastPK := td.lastPKFromRow(tc.pkRow(start))
+					td.lastTargetPK, td.lastSourcePK = lastPK.Target, lastPK.Source
+					if td.lastSourcePK == nil {
+						td.lastSourcePK = td.lastTargetPK
+					}
+				}
+			}
+			td.endRow = tc.pkRow(end)
+			for {
+				if td.sourceExecutor == nil {
+					if err := td.startSnapshotStreams(ctx); err != nil {
+						return nil, err
+					}
+					log.Infof("Table initialization done on table %s for vdiff %s", td.table.Name, wd.ct.uuid)
+					diffTimer = time.NewTimer(maxDiffRuntime)
+				}
+				dr, err = td.diff(ctx, coreOpts, wd.opts.ReportOptions, diffTimer.C)
+				if err == nil {
+					break
+				}
+				if !errors.Is(err, ErrMaxDiffDurationExceeded) {
+					return nil, err
+				}
+				// The diff saved the last diffed row, from which the new snapshot
+				// resumes the range.
+				log.Infof("Restarting the diff of table %s for vdiff %s with a new snapshot", td.table.Name, wd.ct.uuid)
+				td.stopSnapshotStreams()
+				// Give the underlying resources (mainly MySQL) a moment to catch up.
+				time.Sleep(30 * time.Second)
+			}
+		} else if td.sourceExecutor != nil && td.replicationStopped {
+			// The rows of the next range that is diffed are streamed again.
+			td.stopSnapshotStreams()
+		} else if td.sourceExecutor != nil {
+			// Skip the rows of the range in the snapshot.
+			td.endRow = tc.pkRow(end)
+			if _, err := td.drain(ctx, td.sourceExecutor); err != nil {
+				return nil, err
+			}
+			if _, err := td.drain(ctx, td.targetExecutor); err != nil {
+				return nil, err
+			}
+		}
+		if err := td.updateTableProgress(dbClient, dr, tc.pkRow(end)); err != nil {
+			return nil, err
+		}
+		if end == nil {
+			break
+		}
+		start = end
 	}
-	return nil
+	wd.logTableNote(ctx, dbClient, fmt.Sprintf("Diffed the rows of %d out of %d primary key ranges of table %s", diffedRanges, ranges, td.table.Name))
+	return dr, nil
+}
+
+// logTableNote logs a message about the diff of a table, and saves it in the vdiff log.
+func (wd *workflowDiffer) logTableNote(ctx context.Context, dbClient binlogplayer.DBClient, message string) {
+	log.Infof("%s for vdiff %s", message, wd.ct.uuid)
+	insertVDiffLog(ctx, dbClient, wd.ct.id, message)
+}
+
+// isSampling returns true if only a sample of the rows has to be diffed.
+func isSampling(samplePct int64) bool {
+	return samplePct > 0 && samplePct < 100
 }
 
 func (wd *workflowDiffer) diff(ctx context.Context) (err error) {
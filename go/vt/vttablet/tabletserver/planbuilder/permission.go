@@ -68,6 +68,14 @@ func BuildPermissions(stmt sqlparser.Statement) []Permission {
 		for _, t := range node.TableNames {
 			permissions = buildTableNamePermissions(t, tableacl.ADMIN, nil, permissions)
 		}
+	case *sqlparser.LockTables:
+		for _, t := range node.Tables {
+			role := tableacl.READER
+			if t.Lock == sqlparser.Write || t.Lock == sqlparser.LowPriorityWrite {
+				role = tableacl.WRITER
+			}
+			permissions = buildTableExprPermissions(t.Table, role, nil, permissions)
+		}
 	case *sqlparser.Analyze:
 		permissions = buildTableNamePermissions(node.Table, tableacl.WRITER, nil, permissions)
 	case *sqlparser.OtherAdmin, *sqlparser.CallProc, *sqlparser.Begin, *sqlparser.Commit, *sqlparser.Rollback,
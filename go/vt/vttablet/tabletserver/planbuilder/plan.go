@@ -80,6 +80,8 @@ const (
 	PlanShowMigrationLogs
 	PlanShowThrottledApps
 	PlanShowThrottlerStatus
+	// PlanLockTables is for LOCK TABLES statements, which need a reserved connection
+	PlanLockTables
 	NumPlans
 )
 
@@ -116,6 +118,7 @@ var planName = []string{
 	"ShowMigrationLogs",
 	"ShowThrottledApps",
 	"ShowThrottlerStatus",
+	"LockTables",
 }
 
 func (pt PlanType) String() string {
@@ -249,6 +252,8 @@ func Build(env *vtenv.Environment, statement sqlparser.Statement, tables map[str
 		plan = &Plan{PlanID: PlanLoad}
 	case *sqlparser.Flush:
 		plan, err = analyzeFlush(stmt, tables)
+	case *sqlparser.LockTables:
+		plan = &Plan{PlanID: PlanLockTables, NeedsReservedConn: true}
 	case *sqlparser.UnlockTables:
 		plan = &Plan{PlanID: PlanUnlockTables}
 	case *sqlparser.CallProc:
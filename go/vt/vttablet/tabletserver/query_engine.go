@@ -109,7 +109,7 @@ func (ep *TabletPlan) IsValid(hasReservedCon, hasSysSettings bool) error {
 
 func isValid(planType planbuilder.PlanType, hasReservedCon bool, hasSysSettings bool) error {
 	switch planType {
-	case planbuilder.PlanSelectLockFunc, planbuilder.PlanDDL, planbuilder.PlanFlush:
+	case planbuilder.PlanSelectLockFunc, planbuilder.PlanDDL, planbuilder.PlanFlush, planbuilder.PlanLockTables:
 		if hasReservedCon {
 			return nil
 		}
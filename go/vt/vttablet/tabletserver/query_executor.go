@@ -221,6 +221,8 @@ func (qre *QueryExecutor) Execute() (reply *sqltypes.Result, err error) {
 		return qre.execShowThrottledApps()
 	case p.PlanShowThrottlerStatus:
 		return qre.execShowThrottlerStatus()
+	case p.PlanLockTables:
+		return nil, vterrors.Errorf(vtrpcpb.Code_FAILED_PRECONDITION, "lock tables should be executed with a reserved connection")
 	case p.PlanUnlockTables:
 		return nil, vterrors.Errorf(vtrpcpb.Code_FAILED_PRECONDITION, "unlock tables should be executed with an existing connection")
 	case p.PlanSet:
@@ -297,7 +299,7 @@ func (qre *QueryExecutor) txConnExec(conn *StatefulConnection) (*sqltypes.Result
 		return qre.txFetch(conn, true)
 	case p.PlanUpdateLimit, p.PlanDeleteLimit:
 		return qre.execDMLLimit(conn)
-	case p.PlanOtherRead, p.PlanOtherAdmin, p.PlanFlush, p.PlanUnlockTables:
+	case p.PlanOtherRead, p.PlanOtherAdmin, p.PlanFlush, p.PlanLockTables, p.PlanUnlockTables:
 		return qre.execStatefulConn(conn, qre.query, true)
 	case p.PlanSavepoint:
 		return qre.execSavepointQuery(conn, qre.query, qre.plan.FullStmt)